@@ -0,0 +1,104 @@
+package accountnumber
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// An account number is laid out like an IBAN, HB12 0001 0123456789 is the bank code, two check digits,
+// the branch code and a random serial, so numbers cannot be guessed from one another and typos are caught
+const (
+	BankCode = "HB"
+	// HeadOfficeBranch is the branch every account is opened in for now
+	HeadOfficeBranch = "0001"
+
+	branchLength = 4
+	serialLength = 10
+	Length       = len(BankCode) + 2 + branchLength + serialLength
+)
+
+var ErrInvalid = errors.New("invalid account number")
+
+var serialLimit = new(big.Int).Exp(big.NewInt(10), big.NewInt(serialLength), nil)
+
+// New generates a number in the branch with a random serial
+func New(branch string) (string, error) {
+	if len(branch) != branchLength || !isDigits(branch) {
+		return "", fmt.Errorf("branch code must be %d digits: %q", branchLength, branch)
+	}
+
+	serial, err := rand.Int(rand.Reader, serialLimit)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate serial: %w", err)
+	}
+
+	bban := branch + fmt.Sprintf("%0*d", serialLength, serial)
+
+	return BankCode + checkDigits(bban) + bban, nil
+}
+
+// Validate reports numbers which are not laid out like one of ours, or whose check digits do not match
+func Validate(number string) error {
+	if len(number) != Length {
+		return fmt.Errorf("%w: must be %d characters", ErrInvalid, Length)
+	}
+
+	if !strings.HasPrefix(number, BankCode) {
+		return fmt.Errorf("%w: must start with %s", ErrInvalid, BankCode)
+	}
+
+	if !isDigits(number[len(BankCode):]) {
+		return fmt.Errorf("%w: must be digits after %s", ErrInvalid, BankCode)
+	}
+
+	if mod97(rearrange(number)) != 1 {
+		return fmt.Errorf("%w: check digits do not match", ErrInvalid)
+	}
+
+	return nil
+}
+
+// Branch returns the branch code of a valid number
+func Branch(number string) string {
+	start := len(BankCode) + 2
+	return number[start : start+branchLength]
+}
+
+// checkDigits are chosen so that the rearranged number is 1 modulo 97, as in ISO 13616
+func checkDigits(bban string) string {
+	return fmt.Sprintf("%02d", 98-mod97(rearrange(BankCode+"00"+bban)))
+}
+
+// rearrange moves the bank code and the check digits to the end, and turns letters into numbers, A is 10 and Z is 35
+func rearrange(number string) string {
+	var sb strings.Builder
+	for _, r := range number[len(BankCode)+2:] + number[:len(BankCode)+2] {
+		if 'A' <= r && r <= 'Z' {
+			fmt.Fprintf(&sb, "%d", r-'A'+10)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// mod97 computes the remainder digit by digit, the number does not fit in an int64
+func mod97(digits string) int {
+	remainder := 0
+	for _, r := range digits {
+		remainder = (remainder*10 + int(r-'0')) % 97
+	}
+	return remainder
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package accountnumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for range 100 {
+		number, err := New(HeadOfficeBranch)
+		require.NoError(t, err)
+		require.Len(t, number, Length)
+		require.NoError(t, Validate(number))
+		require.Equal(t, HeadOfficeBranch, Branch(number))
+
+		require.False(t, seen[number])
+		seen[number] = true
+	}
+
+	_, err := New("01")
+	require.Error(t, err)
+
+	_, err = New("00a1")
+	require.Error(t, err)
+}
+
+func TestCheckDigits(t *testing.T) {
+	// HB is 17 11, so the rearranged number is 00010123456789 1711 00
+	require.Equal(t, "58", checkDigits("00010123456789"))
+	require.NoError(t, Validate("HB5800010123456789"))
+}
+
+func TestValidate(t *testing.T) {
+	number, err := New(HeadOfficeBranch)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		number string
+	}{
+		{name: "TooShort", number: number[:Length-1]},
+		{name: "TooLong", number: number + "0"},
+		{name: "BankCode", number: "XX" + number[2:]},
+		{name: "Lowercase", number: "hb" + number[2:]},
+		{name: "Letters", number: number[:Length-1] + "A"},
+		{name: "Typo", number: number[:Length-1] + string('0'+(number[Length-1]-'0'+1)%10)},
+		{name: "Swapped", number: number[:6] + number[7:8] + number[6:7] + number[8:]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.number == number {
+				t.Skip("the swap left the number unchanged")
+			}
+			require.ErrorIs(t, Validate(tc.number), ErrInvalid)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// Channel is the postgres notification channel NotifyAccountActivity sends the account ids on
+const Channel = "account_activity"
+
+const reconnectDelay = time.Second
+
+// Hub listens for account activity notifications and wakes up the watchers of the account.
+// A wake up only says something happened, watchers read the new entries from the db themselves,
+// so a notification missed while reconnecting is caught up with the next one.
+type Hub struct {
+	mutex       sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+	closed      bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: map[int64]map[chan struct{}]struct{}{},
+	}
+}
+
+// Subscribe returns a channel receiving a value when the account has new activity.
+// The channel is closed when the hub stops, unsubscribe must be called once the watcher is done.
+func (hub *Hub) Subscribe(accountID int64) (wake <-chan struct{}, unsubscribe func()) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if hub.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if hub.subscribers[accountID] == nil {
+		hub.subscribers[accountID] = map[chan struct{}]struct{}{}
+	}
+	hub.subscribers[accountID][ch] = struct{}{}
+
+	return ch, func() {
+		hub.mutex.Lock()
+		defer hub.mutex.Unlock()
+
+		if _, ok := hub.subscribers[accountID][ch]; !ok {
+			return
+		}
+
+		delete(hub.subscribers[accountID], ch)
+		if len(hub.subscribers[accountID]) == 0 {
+			delete(hub.subscribers, accountID)
+		}
+	}
+}
+
+// Publish wakes up the watchers of the account, without waiting for the ones which are still busy
+func (hub *Hub) Publish(accountID int64) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for ch := range hub.subscribers[accountID] {
+		wakeUp(ch)
+	}
+}
+
+func (hub *Hub) publishAll() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, channels := range hub.subscribers {
+		for ch := range channels {
+			wakeUp(ch)
+		}
+	}
+}
+
+func wakeUp(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Close ends every subscription, so streams do not hold up a graceful shutdown
+func (hub *Hub) Close() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if hub.closed {
+		return
+	}
+	hub.closed = true
+
+	for _, channels := range hub.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+	}
+	hub.subscribers = map[int64]map[chan struct{}]struct{}{}
+}
+
+// Listen holds a connection listening on the channel until ctx is cancelled, then closes the hub.
+// The connection is opened again when it is lost.
+func (hub *Hub) Listen(ctx context.Context, pool *pgxpool.Pool) error {
+	defer hub.Close()
+
+	for {
+		err := hub.listen(ctx, pool)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		log.Warn().Err(err).Msg("lost account activity listener connection, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (hub *Hub) listen(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot acquire connection: %w", err)
+	}
+	// LISTEN is bound to the session, the connection must not go back to the pool
+	defer conn.Hijack().Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return fmt.Errorf("cannot listen: %w", err)
+	}
+
+	// notifications may have been missed while the connection was down
+	hub.publishAll()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		accountID, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			log.Warn().Str("payload", notification.Payload).Msg("invalid account activity notification")
+			continue
+		}
+
+		hub.Publish(accountID)
+	}
+}
@@ -0,0 +1,75 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireWoken(t *testing.T, wake <-chan struct{}) {
+	select {
+	case _, ok := <-wake:
+		require.True(t, ok)
+	default:
+		t.Fatal("subscriber was not woken up")
+	}
+}
+
+func requireIdle(t *testing.T, wake <-chan struct{}) {
+	select {
+	case <-wake:
+		t.Fatal("subscriber was woken up")
+	default:
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub()
+
+	wake1, unsubscribe1 := hub.Subscribe(1)
+	defer unsubscribe1()
+	wake2, unsubscribe2 := hub.Subscribe(2)
+	defer unsubscribe2()
+
+	// wake ups of a busy subscriber are merged instead of blocking the publisher
+	hub.Publish(1)
+	hub.Publish(1)
+
+	requireWoken(t, wake1)
+	requireIdle(t, wake1)
+	requireIdle(t, wake2)
+
+	hub.publishAll()
+	requireWoken(t, wake1)
+	requireWoken(t, wake2)
+}
+
+func TestHubUnsubscribe(t *testing.T) {
+	hub := NewHub()
+
+	wake, unsubscribe := hub.Subscribe(1)
+	unsubscribe()
+	unsubscribe()
+
+	hub.Publish(1)
+	requireIdle(t, wake)
+	require.Empty(t, hub.subscribers)
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub()
+
+	wake, unsubscribe := hub.Subscribe(1)
+	hub.Close()
+	unsubscribe()
+
+	_, ok := <-wake
+	require.False(t, ok)
+
+	// subscribing after the hub stopped ends the stream right away
+	wake, unsubscribe = hub.Subscribe(1)
+	defer unsubscribe()
+
+	_, ok = <-wake
+	require.False(t, ok)
+}
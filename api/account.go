@@ -3,82 +3,137 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
 	"github.com/AnkitNayan83/houseBank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// accountResponse sends the balance in minor units and as a decimal string formatted with the currency's exponent
+type accountResponse struct {
+	Number           string    `json:"number"`
+	Owner            string    `json:"owner"`
+	Name             string    `json:"name"`
+	Type             string    `json:"type"`
+	Balance          int64     `json:"balance"`
+	BalanceFormatted string    `json:"balance_formatted"`
+	Currency         string    `json:"currency"`
+	CreatedAt        time.Time `json:"created_at"`
+	// Role is the caller's role as a holder of the account
+	Role string `json:"role,omitempty"`
+}
+
+func newHeldAccountResponse(account db.Account, role string) accountResponse {
+	res := newAccountResponse(account)
+	res.Role = role
+	return res
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		Number:           account.Number,
+		Owner:            account.Owner,
+		Name:             account.Name,
+		Type:             account.Type,
+		Balance:          account.Balance,
+		BalanceFormatted: account.Money().Decimal(),
+		Currency:         account.Currency,
+		CreatedAt:        account.CreatedAt,
+	}
+}
+
+// defaultMaxAccountsPerUser applies when MAX_ACCOUNTS_PER_USER is not set
+const defaultMaxAccountsPerUser = 10
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	// Name defaults to the currency, which keeps the first account of every currency apart
+	Name string `json:"name" binding:"omitempty,min=1,max=50"`
+	Type string `json:"type" binding:"omitempty,oneof=checking savings"`
+}
+
+func (server *Server) maxAccountsPerUser() int64 {
+	if server.config.MaxAccountsPerUser > 0 {
+		return server.config.MaxAccountsPerUser
+	}
+	return defaultMaxAccountsPerUser
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	username := ctx.MustGet(authorizationPayloadKey).(*token.Payload).Username
 
-	arg := db.CreateAccountParams{
-		Owner:    username,
-		Currency: req.Currency,
+	if req.Name == "" {
+		req.Name = req.Currency
+	}
+
+	if req.Type == "" {
+		req.Type = db.AccountTypeChecking
+	}
+
+	arg := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			Owner:    username,
+			Currency: req.Currency,
+			Name:     req.Name,
+			Type:     req.Type,
+		},
+		MaxAccounts: server.maxAccountsPerUser(),
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	account, err := server.store.CreateAccountTx(ctx, arg)
 
 	if err != nil {
+		if errors.Is(err, db.ErrAccountLimitReached) {
+			errorResponse(ctx, http.StatusForbidden, err)
+			return
+		}
+
 		var pgErr *pgconn.PgError
 
 		if errors.As(err, &pgErr) {
 			switch pgErr.Code {
 			case "23503", "23505": // foreign_key_violation, unique_violation
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				errorResponse(ctx, http.StatusForbidden, err)
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, account)
+	ctx.JSON(http.StatusCreated, newAccountResponse(account))
 }
 
-type getAccountByIdRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+// accountRequestUri addresses an account by its public number, the ids of accounts are never exposed
+type accountRequestUri struct {
+	Number string `uri:"number" binding:"required,account_number"`
 }
 
 func (server *Server) getAccountById(ctx *gin.Context) {
-	var req getAccountByIdRequest
+	var req accountRequestUri
 
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-
-	account, err := server.store.GetAccountById(ctx, req.ID)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	held, ok := server.getHeldAccount(ctx, req.Number, db.AccountAccessView)
+	if !ok {
 		return
 	}
 
-	if account.Owner != authPayload.Username {
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("account does not belong to the authenticated user")))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newHeldAccountResponse(held.Account, held.Role.String))
 }
 
 type listAccountRequest struct {
@@ -90,30 +145,32 @@ func (server *Server) getAccounts(ctx *gin.Context) {
 	var req listAccountRequest
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	arg := db.GetAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	// joint accounts are listed for every holder who accepted them
+	arg := db.ListHeldAccountsParams{
+		Username: authPayload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
 	}
 
-	accounts, err := server.store.GetAccounts(ctx, arg)
+	accounts, err := server.store.ListHeldAccounts(ctx, arg)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
-}
+	res := make([]accountResponse, 0, len(accounts))
+	for _, held := range accounts {
+		res = append(res, newHeldAccountResponse(held.Account, held.Role))
+	}
 
-type updateAccountBalanceRequestUri struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+	ctx.JSON(http.StatusOK, res)
 }
 
 type updateAccountBalanceRequestBody struct {
@@ -121,89 +178,177 @@ type updateAccountBalanceRequestBody struct {
 }
 
 func (server *Server) updateAccountBalance(ctx *gin.Context) {
-	var uriReq updateAccountBalanceRequestUri
+	var uriReq accountRequestUri
 	var bodyReq updateAccountBalanceRequestBody
 
 	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := ctx.ShouldBindJSON(&bodyReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	held, ok := server.getHeldAccount(ctx, uriReq.Number, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+	account := held.Account
+
+	// deposits are always made in the currency of the account
+	arg := db.AddAccountBalanceTxParams{
+		ID:     account.ID,
+		Amount: money.New(bodyReq.Amount, account.Currency),
+	}
 
-	account, err := server.store.GetAccountById(ctx, uriReq.ID)
+	updatedAccount, err := server.store.AddAccountBalanceTx(ctx, arg)
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if isMoneyError(err) {
+			errorResponse(ctx, http.StatusBadRequest, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	if account.Owner != authPayload.Username {
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("account does not belong to the authenticated user")))
-		return
+	ctx.JSON(http.StatusOK, newAccountResponse(updatedAccount))
+}
+
+// getHeldAccount loads the account, and aborts the request unless the caller holds it with a role allowing the access
+func (server *Server) getHeldAccount(ctx *gin.Context, number string, access db.AccountAccess) (db.GetAccountForHolderRow, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	held, err := server.store.GetAccountForHolder(ctx, db.GetAccountForHolderParams{
+		Username: authPayload.Username,
+		Number:   number,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return held, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return held, false
 	}
 
-	arg := db.AddAccountBalanceParams{
-		ID:     uriReq.ID,
-		Amount: bodyReq.Amount,
+	if !held.Allows(access) {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("account: %s cannot be accessed by the authenticated user", number))
+		return held, false
 	}
 
-	updatedAccount, err := server.store.AddAccountBalance(ctx, arg)
+	return held, true
+}
+
+// getAccountByNumber loads an account of anyone, e.g. the destination of a transfer, and aborts the request when there is none
+func (server *Server) getAccountByNumber(ctx *gin.Context, number string) (db.Account, bool) {
+	account, err := server.store.GetAccountByNumber(ctx, number)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("account: %s not found", number))
+			return account, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return account, false
 	}
 
-	ctx.JSON(http.StatusOK, updatedAccount)
+	return account, true
 }
 
-type deleteAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+// accountNumbers maps the ids of the accounts referenced by a response to their public numbers,
+// and aborts the request when they cannot be loaded
+func (server *Server) accountNumbers(ctx *gin.Context, ids ...int64) (map[int64]string, bool) {
+	numbers := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return numbers, true
+	}
+
+	rows, err := server.store.ListAccountNumbers(ctx, ids)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	for _, row := range rows {
+		numbers[row.ID] = row.Number
+	}
+
+	return numbers, true
+}
+
+// numbersOf maps the ids of accounts already loaded to their public numbers
+func numbersOf(accounts ...db.Account) map[int64]string {
+	numbers := make(map[int64]string, len(accounts))
+	for _, account := range accounts {
+		numbers[account.ID] = account.Number
+	}
+	return numbers
 }
 
 func (server *Server) deleteAccount(ctx *gin.Context) {
-	var req deleteAccountRequest
+	var req accountRequestUri
 
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	held, ok := server.getHeldAccount(ctx, req.Number, db.AccountAccessManage)
+	if !ok {
+		return
+	}
+	account := held.Account
 
-	// Check if the account exists before attempting to delete
-	account, err := server.store.GetAccountById(ctx, req.ID)
+	err := server.store.DeleteAccountTx(ctx, account.ID)
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	if account.Owner != authPayload.Username {
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("account does not belong to the authenticated user")))
+	ctx.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+}
+
+type defaultAccountResponse struct {
+	Currency      string    `json:"currency"`
+	AccountNumber string    `json:"account_number"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// setDefaultAccount makes the account the one payments to the aliases of its owner are made into, in its currency
+func (server *Server) setDefaultAccount(ctx *gin.Context) {
+	var req accountRequestUri
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	err = server.store.DeleteAccount(ctx, account.ID)
+	held, ok := server.getHeldAccount(ctx, req.Number, db.AccountAccessManage)
+	if !ok {
+		return
+	}
+	account := held.Account
+
+	defaultAccount, err := server.store.SetDefaultAccount(ctx, db.SetDefaultAccountParams{
+		Owner:     account.Owner,
+		Currency:  account.Currency,
+		AccountID: account.ID,
+	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+	ctx.JSON(http.StatusOK, defaultAccountResponse{
+		Currency:      defaultAccount.Currency,
+		AccountNumber: account.Number,
+		UpdatedAt:     defaultAccount.UpdatedAt,
+	})
 }
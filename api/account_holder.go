@@ -0,0 +1,303 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type accountHolderResponse struct {
+	AccountNumber string     `json:"account_number"`
+	Username      string     `json:"username"`
+	Role          string     `json:"role"`
+	InvitedBy     string     `json:"invited_by,omitempty"`
+	AcceptedAt    *time.Time `json:"accepted_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func newAccountHolderResponse(holder db.AccountHolder, numbers map[int64]string) accountHolderResponse {
+	return accountHolderResponse{
+		AccountNumber: numbers[holder.AccountID],
+		Username:      holder.Username,
+		Role:          holder.Role,
+		InvitedBy:     holder.InvitedBy.String,
+		AcceptedAt:    timeOrNil(holder.AcceptedAt),
+		CreatedAt:     holder.CreatedAt,
+	}
+}
+
+func newAccountHolderResponses(holders []db.AccountHolder, numbers map[int64]string) []accountHolderResponse {
+	res := make([]accountHolderResponse, 0, len(holders))
+	for _, holder := range holders {
+		res = append(res, newAccountHolderResponse(holder, numbers))
+	}
+	return res
+}
+
+type inviteAccountHolderRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=joint view_only"`
+}
+
+// inviteAccountHolder lets the primary holder invite another user, who holds the account once they accept
+func (server *Server) inviteAccountHolder(ctx *gin.Context) {
+	var uriReq accountRequestUri
+	var req inviteAccountHolderRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	held, ok := server.getHeldAccount(ctx, uriReq.Number, db.AccountAccessManage)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	holder, err := server.store.InviteAccountHolderTx(ctx, db.InviteAccountHolderTxParams{
+		AccountID: held.Account.ID,
+		Username:  req.Username,
+		Role:      req.Role,
+		InvitedBy: authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrGuardedChild) {
+			errorResponse(ctx, http.StatusForbidden, err)
+			return
+		}
+
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("user %s not found", req.Username))
+				return
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusConflict, fmt.Errorf("%s already holds or is invited to account: %s", req.Username, uriReq.Number))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newAccountHolderResponse(holder, numbersOf(held.Account)))
+}
+
+func (server *Server) listAccountHolders(ctx *gin.Context) {
+	var uriReq accountRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	held, ok := server.getHeldAccount(ctx, uriReq.Number, db.AccountAccessView)
+	if !ok {
+		return
+	}
+
+	holders, err := server.store.ListAccountHolders(ctx, held.Account.ID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountHolderResponses(holders, numbersOf(held.Account)))
+}
+
+// acceptAccountHolder accepts the caller's pending invitation to hold the account
+func (server *Server) acceptAccountHolder(ctx *gin.Context) {
+	var uriReq accountRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	// the caller does not hold the account until the invitation is accepted
+	account, ok := server.getAccountByNumber(ctx, uriReq.Number)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	holder, err := server.store.AcceptAccountHolderTx(ctx, db.AcceptAccountHolderTxParams{
+		AccountID: account.ID,
+		Username:  authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrInvitationNotFound) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, db.ErrGuardedChild) {
+			errorResponse(ctx, http.StatusForbidden, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountHolderResponse(holder, numbersOf(account)))
+}
+
+type removeAccountHolderRequestUri struct {
+	Number   string `uri:"number" binding:"required,account_number"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// removeAccountHolder lets the primary holder remove another holder or withdraw an invitation,
+// and any holder leave the account or decline their invitation
+func (server *Server) removeAccountHolder(ctx *gin.Context) {
+	var uriReq removeAccountHolderRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var account db.Account
+
+	if uriReq.Username != authPayload.Username {
+		held, ok := server.getHeldAccount(ctx, uriReq.Number, db.AccountAccessManage)
+		if !ok {
+			return
+		}
+		account = held.Account
+	} else {
+		var ok bool
+		account, ok = server.getAccountByNumber(ctx, uriReq.Number)
+		if !ok {
+			return
+		}
+	}
+
+	holder, err := server.store.RemoveAccountHolderTx(ctx, db.RemoveAccountHolderTxParams{
+		AccountID: account.ID,
+		Username:  uriReq.Username,
+		RemovedBy: authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("%s does not hold account: %s", uriReq.Username, uriReq.Number))
+			return
+		}
+		if errors.Is(err, db.ErrPrimaryHolder) {
+			errorResponse(ctx, http.StatusForbidden, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountHolderResponse(holder, numbersOf(account)))
+}
+
+// listAccountInvitations lists the accounts the caller has been invited to and not accepted yet
+func (server *Server) listAccountInvitations(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	holders, err := server.store.ListPendingAccountHolders(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, len(holders))
+	for _, holder := range holders {
+		ids = append(ids, holder.AccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountHolderResponses(holders, numbers))
+}
+
+type auditEventResponse struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Subject   string          `json:"subject,omitempty"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type listAccountAuditEventsRequestQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listAccountAuditEvents shows the primary holder who was invited, accepted and removed, newest first
+func (server *Server) listAccountAuditEvents(ctx *gin.Context) {
+	var uriReq accountRequestUri
+	var queryReq listAccountAuditEventsRequestQuery
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	held, ok := server.getHeldAccount(ctx, uriReq.Number, db.AccountAccessManage)
+	if !ok {
+		return
+	}
+
+	events, err := server.store.ListAccountAuditEvents(ctx, db.ListAccountAuditEventsParams{
+		AccountID: pgtype.Int8{Int64: held.Account.ID, Valid: true},
+		Limit:     queryReq.PageSize,
+		Offset:    (queryReq.PageID - 1) * queryReq.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]auditEventResponse, 0, len(events))
+	for _, event := range events {
+		res = append(res, auditEventResponse{
+			ID:        event.ID,
+			Actor:     event.Actor,
+			Action:    event.Action,
+			Subject:   event.Subject.String,
+			Details:   event.Details,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
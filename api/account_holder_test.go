@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountHolderAPI(t *testing.T) {
+	user, _ := randomUser()
+	coHolder, _ := randomUser()
+	account := randomAccount(user.Username)
+
+	// the account as loaded for the co-holder, who holds it with the role once they accepted
+	heldBy := func(role string) db.GetAccountForHolderRow {
+		return db.GetAccountForHolderRow{
+			Account:    account,
+			Role:       pgtype.Text{String: role, Valid: true},
+			AcceptedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		}
+	}
+
+	holder := db.AccountHolder{
+		AccountID: account.ID,
+		Username:  coHolder.Username,
+		Role:      db.AccountHolderJoint,
+		InvitedBy: pgtype.Text{String: user.Username, Valid: true},
+		CreatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Invite",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders", account.Number),
+			body:     gin.H{"username": coHolder.Username, "role": db.AccountHolderJoint},
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					InviteAccountHolderTx(gomock.Any(), gomock.Eq(db.InviteAccountHolderTxParams{
+						AccountID: account.ID,
+						Username:  coHolder.Username,
+						Role:      db.AccountHolderJoint,
+						InvitedBy: user.Username,
+					})).
+					Times(1).
+					Return(holder, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"accepted_at":null`)
+			},
+		},
+		{
+			name:     "InviteAsPrimary",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders", account.Number),
+			body:     gin.H{"username": coHolder.Username, "role": db.AccountHolderPrimary},
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().InviteAccountHolderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InviteByJointHolder",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders", account.Number),
+			body:     gin.H{"username": "someoneelse", "role": db.AccountHolderViewOnly},
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldBy(db.AccountHolderJoint), nil)
+				store.EXPECT().InviteAccountHolderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InviteUnknownUser",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders", account.Number),
+			body:     gin.H{"username": "nobody", "role": db.AccountHolderJoint},
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					InviteAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, &pgconn.PgError{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InviteTwice",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders", account.Number),
+			body:     gin.H{"username": coHolder.Username, "role": db.AccountHolderJoint},
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					InviteAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, &pgconn.PgError{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "InviteGuardedChild",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders", account.Number),
+			body:     gin.H{"username": coHolder.Username, "role": db.AccountHolderJoint},
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					InviteAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, db.ErrGuardedChild)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Accept",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders/accept", account.Number),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				accepted := holder
+				accepted.AcceptedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+				store.EXPECT().
+					AcceptAccountHolderTx(gomock.Any(), gomock.Eq(db.AcceptAccountHolderTxParams{
+						AccountID: account.ID,
+						Username:  coHolder.Username,
+					})).
+					Times(1).
+					Return(accepted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"accepted_at":null`)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"account_number":"%s"`, account.Number))
+			},
+		},
+		{
+			name:     "AcceptWithoutInvitation",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders/accept", account.Number),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					AcceptAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, db.ErrInvitationNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "AcceptAsGuardedChild",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/accounts/%s/holders/accept", account.Number),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					AcceptAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, db.ErrGuardedChild)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Leave",
+			method:   http.MethodDelete,
+			url:      fmt.Sprintf("/accounts/%s/holders/%s", account.Number, coHolder.Username),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+				store.EXPECT().
+					RemoveAccountHolderTx(gomock.Any(), gomock.Eq(db.RemoveAccountHolderTxParams{
+						AccountID: account.ID,
+						Username:  coHolder.Username,
+						RemovedBy: coHolder.Username,
+					})).
+					Times(1).
+					Return(holder, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RemoveNotHolder",
+			method:   http.MethodDelete,
+			url:      fmt.Sprintf("/accounts/%s/holders/%s", account.Number, coHolder.Username),
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					RemoveAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "RemovePrimary",
+			method:   http.MethodDelete,
+			url:      fmt.Sprintf("/accounts/%s/holders/%s", account.Number, user.Username),
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					RemoveAccountHolderTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountHolder{}, db.ErrPrimaryHolder)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "ViewOnlyCannotDeposit",
+			method:   http.MethodPatch,
+			url:      fmt.Sprintf("/accounts/%s", account.Number),
+			body:     gin.H{"amount": 10},
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldBy(db.AccountHolderViewOnly), nil)
+				store.EXPECT().AddAccountBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "JointHolderReadsAccount",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/accounts/%s", account.Number),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: coHolder.Username, Number: account.Number})).
+					Times(1).
+					Return(heldBy(db.AccountHolderJoint), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"role":"joint"`)
+			},
+		},
+		{
+			name:     "PendingHolderCannotReadAccount",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/accounts/%s", account.Number),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				pending := heldBy(db.AccountHolderJoint)
+				pending.AcceptedAt = pgtype.Timestamptz{}
+
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(pending, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "ListInvitations",
+			method:   http.MethodGet,
+			url:      "/account_invitations",
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListPendingAccountHolders(gomock.Any(), gomock.Eq(coHolder.Username)).
+					Times(1).
+					Return([]db.AccountHolder{holder}, nil)
+				store.EXPECT().
+					ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{account.ID})).
+					Times(1).
+					Return([]db.ListAccountNumbersRow{{ID: account.ID, Number: account.Number}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"invited_by":"%s"`, user.Username))
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"account_number":"%s"`, account.Number))
+			},
+		},
+		{
+			name:     "AuditEventsOfJointHolder",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/accounts/%s/audit_events?page_id=1&page_size=5", account.Number),
+			username: coHolder.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldBy(db.AccountHolderJoint), nil)
+				store.EXPECT().ListAccountAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -11,10 +11,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AnkitNayan83/houseBank/accountnumber"
 	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
 	"github.com/AnkitNayan83/houseBank/token"
 	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -26,22 +30,22 @@ func TestGetAccountAPI(t *testing.T) {
 	// Test cases
 	var testCases = []struct {
 		name          string //test name
-		accountID     int64
+		accountNumber string
 		buildStubs    func(store *mockDB.MockStore)
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name:      "OK",
-			accountID: account.ID,
+			name:          "OK",
+			accountNumber: account.Number,
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
+					GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).
 					Times(1).
-					Return(account, nil)
+					Return(heldAccount(account, user.Username), nil)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -49,47 +53,47 @@ func TestGetAccountAPI(t *testing.T) {
 			},
 		},
 		{
-			name:      "NotFound",
-			accountID: account.ID,
+			name:          "NotFound",
+			accountNumber: account.Number,
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
+					GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).
 					Times(1).
-					Return(db.Account{}, sql.ErrNoRows)
+					Return(db.GetAccountForHolderRow{}, sql.ErrNoRows)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
 		{
-			name:      "InternalError",
-			accountID: account.ID,
+			name:          "InternalError",
+			accountNumber: account.Number,
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
+					GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).
 					Times(1).
-					Return(db.Account{}, sql.ErrConnDone)
+					Return(db.GetAccountForHolderRow{}, sql.ErrConnDone)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 		{
-			name:      "InvalidID",
-			accountID: -1,
+			name:          "MistypedNumber",
+			accountNumber: mistypedAccountNumber(account.Number),
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccountById(gomock.Any(), gomock.Any()).
+					GetAccountForHolder(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -108,7 +112,7 @@ func TestGetAccountAPI(t *testing.T) {
 			require.NoError(t, err)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/accounts/%d", tc.accountID)
+			url := fmt.Sprintf("/accounts/%s", tc.accountNumber)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
@@ -150,18 +154,18 @@ func TestGetAccountsAPI(t *testing.T) {
 				PageSize: 5,
 			},
 			buildStubs: func(store *mockDB.MockStore) {
-				arg := db.GetAccountsParams{
-					Owner:  user.Username,
-					Offset: 0,
-					Limit:  5,
+				arg := db.ListHeldAccountsParams{
+					Username: user.Username,
+					Offset:   0,
+					Limit:    5,
 				}
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Eq(arg)).
+					ListHeldAccounts(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
-					Return(accounts[:5], nil)
+					Return(heldAccounts(accounts[:5], user.Username), nil)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -176,18 +180,18 @@ func TestGetAccountsAPI(t *testing.T) {
 				PageSize: 5,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockDB.MockStore) {
-				arg := db.GetAccountsParams{
-					Owner:  user.Username,
-					Offset: 10,
-					Limit:  5,
+				arg := db.ListHeldAccountsParams{
+					Username: user.Username,
+					Offset:   10,
+					Limit:    5,
 				}
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Eq(arg)).
+					ListHeldAccounts(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
-					Return([]db.Account{}, nil)
+					Return([]db.ListHeldAccountsRow{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -201,11 +205,11 @@ func TestGetAccountsAPI(t *testing.T) {
 				PageSize: 5,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Any()).
+					ListHeldAccounts(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -220,11 +224,11 @@ func TestGetAccountsAPI(t *testing.T) {
 				PageSize: 4,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Any()).
+					ListHeldAccounts(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -240,12 +244,12 @@ func TestGetAccountsAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Any()).
+					ListHeldAccounts(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(nil, sql.ErrConnDone)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -259,11 +263,11 @@ func TestGetAccountsAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Any()).
+					ListHeldAccounts(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -277,11 +281,11 @@ func TestGetAccountsAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					GetAccounts(gomock.Any(), gomock.Any()).
+					ListHeldAccounts(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -320,6 +324,8 @@ func TestGetAccountsAPI(t *testing.T) {
 type CreateAccountBody struct {
 	Owner    string
 	Currency string
+	Name     string
+	Type     string
 }
 
 func TestCreateAccountAPI(t *testing.T) {
@@ -340,23 +346,93 @@ func TestCreateAccountAPI(t *testing.T) {
 				Currency: account.Currency,
 			},
 			buildStubs: func(store *mockDB.MockStore) {
-				arg := db.CreateAccountParams{
-					Owner:    account.Owner,
-					Currency: account.Currency,
+				arg := db.CreateAccountTxParams{
+					CreateAccountParams: db.CreateAccountParams{
+						Owner:    account.Owner,
+						Currency: account.Currency,
+						Name:     account.Currency,
+						Type:     db.AccountTypeChecking,
+					},
+					MaxAccounts: defaultMaxAccountsPerUser,
 				}
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(account, nil)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 				requireBodyMatchAccount(t, recorder.Body, account)
 			},
 		},
+		{
+			name: "NamedSavings",
+			body: CreateAccountBody{
+				Currency: account.Currency,
+				Name:     "savings",
+				Type:     db.AccountTypeSavings,
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				arg := db.CreateAccountTxParams{
+					CreateAccountParams: db.CreateAccountParams{
+						Owner:    account.Owner,
+						Currency: account.Currency,
+						Name:     "savings",
+						Type:     db.AccountTypeSavings,
+					},
+					MaxAccounts: defaultMaxAccountsPerUser,
+				}
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(account, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidType",
+			body: CreateAccountBody{
+				Currency: account.Currency,
+				Type:     "pension",
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountLimitReached",
+			body: CreateAccountBody{
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, db.ErrAccountLimitReached)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "InternalError",
 			body: CreateAccountBody{
@@ -365,12 +441,12 @@ func TestCreateAccountAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.Account{}, sql.ErrConnDone)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -384,11 +460,11 @@ func TestCreateAccountAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockDB.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -424,15 +500,117 @@ func TestCreateAccountAPI(t *testing.T) {
 	}
 }
 
+func TestUpdateAccountBalanceAPI(t *testing.T) {
+	user, _ := randomUser()
+	account := randomAccount(user.Username)
+	amount := util.RandomInt(1, 1000)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockDB.MockStore) {
+				updated := account
+				updated.Balance += amount
+
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					AddAccountBalanceTx(gomock.Any(), gomock.Eq(db.AddAccountBalanceTxParams{
+						ID:     account.ID,
+						Amount: money.New(amount, account.Currency),
+					})).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Overflow",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					AddAccountBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, fmt.Errorf("%w: balance of account [%d]", money.ErrOverflow, account.ID))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"amount": amount})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%s", account.Number)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func randomAccount(owner string) db.Account {
+	number, err := accountnumber.New(accountnumber.HeadOfficeBranch)
+	if err != nil {
+		panic(err)
+	}
+
 	return db.Account{
 		ID:       util.RandomInt(1, 1000),
 		Owner:    owner,
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
+		Name:     util.RandomString(6),
+		Type:     db.AccountTypeChecking,
+		Number:   number,
 	}
 }
 
+// heldAccount is the account as loaded for the user, who is its primary holder when they own it
+func heldAccount(account db.Account, username string) db.GetAccountForHolderRow {
+	held := db.GetAccountForHolderRow{Account: account}
+	if account.Owner == username {
+		held.Role = pgtype.Text{String: db.AccountHolderPrimary, Valid: true}
+		held.AcceptedAt = pgtype.Timestamptz{Time: account.CreatedAt, Valid: true}
+	}
+	return held
+}
+
+// mistypedAccountNumber changes one digit of the number, which its check digits catch
+func mistypedAccountNumber(number string) string {
+	digit := number[len(number)-1]
+	return number[:len(number)-1] + string('0'+(digit-'0'+1)%10)
+}
+
+func heldAccounts(accounts []db.Account, username string) []db.ListHeldAccountsRow {
+	rows := make([]db.ListHeldAccountsRow, 0, len(accounts))
+	for _, account := range accounts {
+		rows = append(rows, db.ListHeldAccountsRow{Account: account, Role: heldAccount(account, username).Role.String})
+	}
+	return rows
+}
+
 func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Account) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
@@ -441,11 +619,19 @@ func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Accoun
 
 	err = json.Unmarshal(data, &gotAccount)
 	require.NoError(t, err)
-	require.Equal(t, account.ID, gotAccount.ID)
+	require.Zero(t, gotAccount.ID, "the id of the account must stay private")
+	require.Equal(t, account.Number, gotAccount.Number)
 	require.Equal(t, account.Owner, gotAccount.Owner)
 	require.Equal(t, account.Balance, gotAccount.Balance)
 	require.Equal(t, account.Currency, gotAccount.Currency)
 	require.WithinDuration(t, account.CreatedAt, gotAccount.CreatedAt, time.Second)
+
+	var formatted struct {
+		BalanceFormatted string `json:"balance_formatted"`
+	}
+	err = json.Unmarshal(data, &formatted)
+	require.NoError(t, err)
+	require.Equal(t, util.FormatAmount(account.Balance, account.Currency), formatted.BalanceFormatted)
 }
 
 func requireBodyMatchAccounts(t *testing.T, body *bytes.Buffer, accounts []db.Account) {
@@ -459,7 +645,8 @@ func requireBodyMatchAccounts(t *testing.T, body *bytes.Buffer, accounts []db.Ac
 	require.Equal(t, len(accounts), len(gotAccounts))
 
 	for i := range accounts {
-		require.Equal(t, accounts[i].ID, gotAccounts[i].ID)
+		require.Zero(t, gotAccounts[i].ID)
+		require.Equal(t, accounts[i].Number, gotAccounts[i].Number)
 		require.Equal(t, accounts[i].Owner, gotAccounts[i].Owner)
 		require.Equal(t, accounts[i].Balance, gotAccounts[i].Balance)
 		require.Equal(t, accounts[i].Currency, gotAccounts[i].Currency)
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type apiKeyResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	Scopes         []string   `json:"scopes"`
+	AccountNumbers []string   `json:"account_numbers"`
+	AllowedIPs     []string   `json:"allowed_ips"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func newApiKeyResponse(apiKey db.ApiKey, numbers map[int64]string) apiKeyResponse {
+	res := apiKeyResponse{
+		ID:             apiKey.ID,
+		Name:           apiKey.Name,
+		Prefix:         apiKey.Prefix,
+		Scopes:         apiKey.Scopes,
+		AccountNumbers: make([]string, 0, len(apiKey.AccountIds)),
+		AllowedIPs:     apiKey.AllowedIps,
+		ExpiresAt:      timeOrNil(apiKey.ExpiresAt),
+		LastUsedAt:     timeOrNil(apiKey.LastUsedAt),
+		RevokedAt:      timeOrNil(apiKey.RevokedAt),
+		CreatedAt:      apiKey.CreatedAt,
+	}
+
+	// accounts deleted since the key was created are left out
+	for _, id := range apiKey.AccountIds {
+		if number, ok := numbers[id]; ok {
+			res.AccountNumbers = append(res.AccountNumbers, number)
+		}
+	}
+
+	return res
+}
+
+func timeOrNil(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type createApiKeyRequest struct {
+	Name           string     `json:"name" binding:"required,max=64"`
+	Scopes         []string   `json:"scopes" binding:"required,min=1,dive,required"`
+	AccountNumbers []string   `json:"account_numbers" binding:"omitempty,dive,account_number"`
+	AllowedIPs     []string   `json:"allowed_ips" binding:"omitempty,dive,ip|cidr"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+type createApiKeyResponse struct {
+	ApiKey string         `json:"api_key"`
+	Key    apiKeyResponse `json:"key"`
+}
+
+func (server *Server) createApiKey(ctx *gin.Context) {
+	var req createApiKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !slices.Contains(token.APIKeyScopes, scope) {
+			errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("unknown scope: %s", scope))
+			return
+		}
+	}
+
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		errorResponse(ctx, http.StatusBadRequest, errors.New("expires_at must be in the future"))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	accountIDs := make([]int64, 0, len(req.AccountNumbers))
+	accounts := make([]db.Account, 0, len(req.AccountNumbers))
+
+	for _, number := range req.AccountNumbers {
+		held, ok := server.getHeldAccount(ctx, number, db.AccountAccessView)
+		if !ok {
+			return
+		}
+		accountIDs = append(accountIDs, held.Account.ID)
+		accounts = append(accounts, held.Account)
+	}
+
+	key, prefix, hashedSecret, err := token.GenerateAPIKey()
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	arg := db.CreateApiKeyParams{
+		ID:           uuid.New(),
+		Username:     authPayload.Username,
+		Name:         req.Name,
+		Prefix:       prefix,
+		HashedSecret: hashedSecret,
+		Scopes:       req.Scopes,
+		AccountIds:   accountIDs,
+		AllowedIps:   req.AllowedIPs,
+	}
+
+	if arg.AllowedIps == nil {
+		arg.AllowedIps = []string{}
+	}
+
+	if req.ExpiresAt != nil {
+		arg.ExpiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	apiKey, err := server.store.CreateApiKey(ctx, arg)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createApiKeyResponse{
+		ApiKey: key,
+		Key:    newApiKeyResponse(apiKey, numbersOf(accounts...)),
+	})
+}
+
+type listApiKeysRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listApiKeys(ctx *gin.Context) {
+	var req listApiKeysRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	apiKeys, err := server.store.ListApiKeys(ctx, db.ListApiKeysParams{
+		Username: authPayload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var ids []int64
+	for _, apiKey := range apiKeys {
+		ids = append(ids, apiKey.AccountIds...)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]apiKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		res = append(res, newApiKeyResponse(apiKey, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type revokeApiKeyRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+func (server *Server) revokeApiKey(ctx *gin.Context) {
+	var req revokeApiKeyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	apiKey, err := server.store.RevokeApiKey(ctx, db.RevokeApiKeyParams{
+		ID:       uuid.MustParse(req.ID),
+		Username: authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, apiKey.AccountIds...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newApiKeyResponse(apiKey, numbers))
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomApiKey(t *testing.T, username string, scopes ...string) (string, db.ApiKey) {
+	key, prefix, hashedSecret, err := token.GenerateAPIKey()
+	require.NoError(t, err)
+
+	return key, db.ApiKey{
+		ID:           uuid.New(),
+		Username:     username,
+		Name:         "integration",
+		Prefix:       prefix,
+		HashedSecret: hashedSecret,
+		Scopes:       scopes,
+		AccountIds:   []int64{},
+		AllowedIps:   []string{},
+		CreatedAt:    time.Now(),
+	}
+}
+
+func TestApiKeyAuthorization(t *testing.T) {
+	user, _ := randomUser()
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		scopes        []string
+		buildStubs    func(store *mockDB.MockStore, apiKey db.ApiKey)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "WithScope",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%s", account.Number),
+			scopes: []string{token.ScopeAccountsRead},
+			buildStubs: func(store *mockDB.MockStore, apiKey db.ApiKey) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).Times(1).Return(apiKey, nil)
+				store.EXPECT().UpdateApiKeyLastUsed(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).Times(1).Return(heldAccount(account, user.Username), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:   "MissingScope",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%s", account.Number),
+			scopes: []string{token.ScopeTransfersRead},
+			buildStubs: func(store *mockDB.MockStore, apiKey db.ApiKey) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).Times(1).Return(apiKey, nil)
+				store.EXPECT().UpdateApiKeyLastUsed(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "BearerOnlyRoute",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/accounts/%s", account.Number),
+			scopes: token.APIKeyScopes,
+			buildStubs: func(store *mockDB.MockStore, apiKey db.ApiKey) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "TransferFromOtherAccount",
+			method: http.MethodPost,
+			url:    "/transfers",
+			scopes: []string{token.ScopeTransfersCreate},
+			buildStubs: func(store *mockDB.MockStore, apiKey db.ApiKey) {
+				apiKey.AccountIds = []int64{account.ID + 1}
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).Times(1).Return(apiKey, nil)
+				store.EXPECT().UpdateApiKeyLastUsed(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+
+			key, apiKey := randomApiKey(t, user.Username, tc.scopes...)
+			tc.buildStubs(store, apiKey)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			var body io.Reader
+			if tc.method == http.MethodPost {
+				data, err := json.Marshal(gin.H{
+					"from_account_number": account.Number,
+					"to_account_number":   randomAccount(user.Username).Number,
+					"amount":              10,
+					"currency":            account.Currency,
+				})
+				require.NoError(t, err)
+				body = bytes.NewReader(data)
+			}
+
+			request, err := http.NewRequest(tc.method, tc.url, body)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeApiKey, key))
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateApiKeyAPI(t *testing.T) {
+	user, _ := randomUser()
+	account := randomAccount(user.Username)
+	otherAccount := randomAccount("other")
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":            "payroll",
+				"scopes":          []string{token.ScopeTransfersCreate},
+				"account_numbers": []string{account.Number},
+				"allowed_ips":     []string{"10.0.0.0/24"},
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).Times(1).Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateApiKeyParams) (db.ApiKey, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, []int64{account.ID}, arg.AccountIds)
+						require.NotEmpty(t, arg.HashedSecret)
+						return db.ApiKey{ID: arg.ID, Username: arg.Username, Prefix: arg.Prefix, Scopes: arg.Scopes, AccountIds: arg.AccountIds}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var res createApiKeyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				prefix, _, err := token.ParseAPIKey(res.ApiKey)
+				require.NoError(t, err)
+				require.Equal(t, res.Key.Prefix, prefix)
+				require.Equal(t, []string{account.Number}, res.Key.AccountNumbers)
+			},
+		},
+		{
+			name: "UnknownScope",
+			body: gin.H{
+				"name":   "payroll",
+				"scopes": []string{"accounts:delete"},
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUsersAccount",
+			body: gin.H{
+				"name":            "payroll",
+				"scopes":          []string{token.ScopeTransfersCreate},
+				"account_numbers": []string{otherAccount.Number},
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: otherAccount.Number})).Times(1).Return(heldAccount(otherAccount, user.Username), nil)
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api_keys", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
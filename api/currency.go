@@ -0,0 +1,152 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+type currencyResponse struct {
+	Code      string    `json:"code"`
+	Exponent  int32     `json:"exponent"`
+	Symbol    string    `json:"symbol"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newCurrencyResponse(currency db.Currency) currencyResponse {
+	return currencyResponse{
+		Code:      currency.Code,
+		Exponent:  currency.Exponent,
+		Symbol:    currency.Symbol,
+		Enabled:   currency.Enabled,
+		CreatedAt: currency.CreatedAt,
+	}
+}
+
+// reloadCurrencies makes a banker's change visible to the validators right away,
+// other instances pick it up on their next periodic reload
+func (server *Server) reloadCurrencies(ctx *gin.Context) {
+	if err := db.LoadCurrencyRegistry(ctx, server.store); err != nil {
+		log.Error().Err(err).Msg("cannot reload currency registry")
+	}
+}
+
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListCurrencies(ctx)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]currencyResponse, 0, len(currencies))
+	for _, currency := range currencies {
+		res = append(res, newCurrencyResponse(currency))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type createCurrencyRequest struct {
+	Code     string `json:"code" binding:"required,iso4217"`
+	Exponent *int32 `json:"exponent" binding:"required,min=0,max=4"`
+	Symbol   string `json:"symbol" binding:"required,max=8"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+func (server *Server) createCurrency(ctx *gin.Context) {
+	var req createCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	currency, err := server.store.CreateCurrency(ctx, db.CreateCurrencyParams{
+		Code:     req.Code,
+		Exponent: *req.Exponent,
+		Symbol:   req.Symbol,
+		Enabled:  enabled,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusConflict, err)
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	server.reloadCurrencies(ctx)
+
+	ctx.JSON(http.StatusCreated, newCurrencyResponse(currency))
+}
+
+type updateCurrencyRequestUri struct {
+	Code string `uri:"code" binding:"required,iso4217"`
+}
+
+type updateCurrencyRequestBody struct {
+	Symbol  *string `json:"symbol" binding:"omitempty,min=1,max=8"`
+	Enabled *bool   `json:"enabled"`
+}
+
+func (server *Server) updateCurrency(ctx *gin.Context) {
+	var uriReq updateCurrencyRequestUri
+	var bodyReq updateCurrencyRequestBody
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&bodyReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.UpdateCurrencyParams{
+		Code: uriReq.Code,
+	}
+
+	if bodyReq.Symbol != nil {
+		arg.Symbol = pgtype.Text{String: *bodyReq.Symbol, Valid: true}
+	}
+
+	if bodyReq.Enabled != nil {
+		arg.Enabled = pgtype.Bool{Bool: *bodyReq.Enabled, Valid: true}
+	}
+
+	currency, err := server.store.UpdateCurrency(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	server.reloadCurrencies(ctx)
+
+	ctx.JSON(http.StatusOK, newCurrencyResponse(currency))
+}
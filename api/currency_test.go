@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func seededCurrencies() []db.Currency {
+	return []db.Currency{
+		{Code: util.INR, Exponent: 2, Symbol: "₹", Enabled: true},
+		{Code: util.USD, Exponent: 2, Symbol: "$", Enabled: true},
+	}
+}
+
+// resetCurrencies restores the registry the other tests validate currencies with
+func resetCurrencies(t *testing.T) {
+	t.Cleanup(func() {
+		util.SetCurrencies([]util.Currency{
+			{Code: util.USD, Exponent: 2, Symbol: "$", Enabled: true},
+			{Code: util.INR, Exponent: 2, Symbol: "₹", Enabled: true},
+		})
+	})
+}
+
+func TestCurrencyAPI(t *testing.T) {
+	eur := db.Currency{Code: "EUR", Exponent: 2, Symbol: "€", Enabled: true, CreatedAt: time.Now()}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          any
+		role          string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/currencies",
+			role:   util.DepositorRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(seededCurrencies(), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"code":"USD"`)
+			},
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			url:    "/currencies",
+			body:   gin.H{"code": eur.Code, "exponent": eur.Exponent, "symbol": eur.Symbol},
+			role:   util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateCurrency(gomock.Any(), gomock.Eq(db.CreateCurrencyParams{
+						Code:     eur.Code,
+						Exponent: eur.Exponent,
+						Symbol:   eur.Symbol,
+						Enabled:  true,
+					})).
+					Times(1).
+					Return(eur, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(append(seededCurrencies(), eur), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.True(t, util.IsSupportedCurrency(eur.Code))
+			},
+		},
+		{
+			name:   "CreateZeroExponent",
+			method: http.MethodPost,
+			url:    "/currencies",
+			body:   gin.H{"code": "JPY", "exponent": 0, "symbol": "¥", "enabled": false},
+			role:   util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				jpy := db.Currency{Code: "JPY", Exponent: 0, Symbol: "¥"}
+				store.EXPECT().
+					CreateCurrency(gomock.Any(), gomock.Eq(db.CreateCurrencyParams{Code: "JPY", Symbol: "¥"})).
+					Times(1).
+					Return(jpy, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(append(seededCurrencies(), jpy), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.False(t, util.IsSupportedCurrency("JPY"))
+			},
+		},
+		{
+			name:   "CreateNotISO",
+			method: http.MethodPost,
+			url:    "/currencies",
+			body:   gin.H{"code": "ABC", "exponent": 2, "symbol": "A"},
+			role:   util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateDuplicate",
+			method: http.MethodPost,
+			url:    "/currencies",
+			body:   gin.H{"code": util.USD, "exponent": 2, "symbol": "$"},
+			role:   util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateCurrency(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Currency{}, &pgconn.PgError{Code: "23505"})
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateNotBanker",
+			method: http.MethodPost,
+			url:    "/currencies",
+			body:   gin.H{"code": eur.Code, "exponent": eur.Exponent, "symbol": eur.Symbol},
+			role:   util.DepositorRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Disable",
+			method: http.MethodPatch,
+			url:    fmt.Sprintf("/currencies/%s", util.INR),
+			body:   gin.H{"enabled": false},
+			role:   util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				currencies := seededCurrencies()
+				currencies[0].Enabled = false
+
+				store.EXPECT().
+					UpdateCurrency(gomock.Any(), gomock.Eq(db.UpdateCurrencyParams{
+						Code:    util.INR,
+						Enabled: pgtype.Bool{Bool: false, Valid: true},
+					})).
+					Times(1).
+					Return(currencies[0], nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(currencies, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.False(t, util.IsSupportedCurrency(util.INR))
+			},
+		},
+		{
+			name:   "UpdateNotFound",
+			method: http.MethodPatch,
+			url:    "/currencies/EUR",
+			body:   gin.H{"symbol": "€"},
+			role:   util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateCurrency(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Currency{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			resetCurrencies(t)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "banker", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+)
+
+const requestIDKey = "request_id_key"
+
+// errorResponse aborts the request with the error model. The error is logged with the request id,
+// but only messages written for the client are returned, never the ones of postgres or of internal failures.
+func errorResponse(ctx *gin.Context, status int, err error) {
+	code := httpStatusCode(status)
+	detail := util.ErrorDetail{
+		Code:      util.ErrorCode(code),
+		Message:   clientMessage(code, err),
+		RequestID: ctx.GetString(requestIDKey),
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fieldErr := range validationErrors {
+			detail.Violations = append(detail.Violations, util.FieldViolation{
+				Field:       fieldErr.Field(),
+				Description: violationDescription(fieldErr),
+			})
+		}
+	}
+
+	logger := log.Warn()
+	if status >= http.StatusInternalServerError {
+		logger = log.Error()
+	}
+
+	logger.Err(err).
+		Str("request_id", detail.RequestID).
+		Str("method", ctx.Request.Method).
+		Str("path", ctx.Request.URL.Path).
+		Int("status_code", status).
+		Msg("request failed")
+
+	ctx.AbortWithStatusJSON(status, util.ErrorResponse{Error: detail})
+}
+
+func clientMessage(code codes.Code, err error) string {
+	if util.IsInternalCode(code) {
+		return util.InternalErrorMessage
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return "invalid parameters"
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return "invalid request body"
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return "resource not found"
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503": // foreign_key_violation
+			return "a referenced resource does not exist"
+		case "23505": // unique_violation
+			return "resource already exists"
+		}
+		return "request cannot be completed"
+	}
+
+	return err.Error()
+}
+
+func httpStatusCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if status >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.FailedPrecondition
+}
+
+func violationDescription(fieldErr validator.FieldError) string {
+	if fieldErr.Tag() == "required" {
+		return "is required"
+	}
+
+	if fieldErr.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fieldErr.Tag(), fieldErr.Param())
+	}
+	return fmt.Sprintf("must satisfy %s", fieldErr.Tag())
+}
+
+// requestFieldName names violations after the json, uri or query parameter the client sent, not the go field
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "uri", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// requestIDMiddleware keeps the request id sent by the client or creates one, and returns it in the response header
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := util.RequestID(ctx.GetHeader(util.RequestIDHeader))
+
+		ctx.Set(requestIDKey, requestID)
+		ctx.Request = ctx.Request.WithContext(util.WithRequestID(ctx.Request.Context(), requestID))
+		ctx.Header(util.RequestIDHeader, requestID)
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestErrorResponse(t *testing.T) {
+	user, _ := randomUser()
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		requestID     string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "FieldViolations",
+			method:    http.MethodPost,
+			url:       "/accounts",
+			body:      gin.H{},
+			requestID: "client-request-1",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, "client-request-1", recorder.Header().Get(util.RequestIDHeader))
+
+				res := requireBodyError(t, recorder.Body)
+				require.Equal(t, "invalid_argument", res.Error.Code)
+				require.Equal(t, "client-request-1", res.Error.RequestID)
+				require.Equal(t, []util.FieldViolation{{Field: "currency", Description: "is required"}}, res.Error.Violations)
+			},
+		},
+		{
+			name:      "InternalErrorIsHidden",
+			method:    http.MethodGet,
+			url:       fmt.Sprintf("/accounts/%s", account.Number),
+			requestID: "not a valid id\n",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).
+					Times(1).
+					Return(db.GetAccountForHolderRow{}, &pgconn.PgError{Code: "XX000", Message: "relation accounts is corrupted"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "corrupted")
+
+				res := requireBodyError(t, recorder.Body)
+				require.Equal(t, "internal", res.Error.Code)
+				require.Equal(t, util.InternalErrorMessage, res.Error.Message)
+
+				// unusable client ids are replaced
+				require.NotEmpty(t, res.Error.RequestID)
+				require.NotEqual(t, "not a valid id\n", res.Error.RequestID)
+				require.Equal(t, res.Error.RequestID, recorder.Header().Get(util.RequestIDHeader))
+			},
+		},
+		{
+			name:   "PostgresConflictIsHidden",
+			method: http.MethodPost,
+			url:    "/accounts",
+			body:   gin.H{"currency": account.Currency},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(account, &pgconn.PgError{Code: "23505", ConstraintName: "owner_currency_key"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "owner_currency_key")
+
+				res := requireBodyError(t, recorder.Body)
+				require.Equal(t, "permission_denied", res.Error.Code)
+				require.Equal(t, "resource already exists", res.Error.Message)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			request, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+			if tc.requestID != "" {
+				request.Header.Set(util.RequestIDHeader, tc.requestID)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyError(t *testing.T, body *bytes.Buffer) util.ErrorResponse {
+	var res util.ErrorResponse
+	require.NoError(t, json.Unmarshal(body.Bytes(), &res))
+	return res
+}
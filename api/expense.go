@@ -0,0 +1,346 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type expenseResponse struct {
+	ID              int64     `json:"id"`
+	PaidBy          string    `json:"paid_by"`
+	AccountNumber   string    `json:"account_number"`
+	Amount          int64     `json:"amount"`
+	AmountFormatted string    `json:"amount_formatted"`
+	Currency        string    `json:"currency"`
+	Description     string    `json:"description,omitempty"`
+	Split           string    `json:"split"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func newExpenseResponse(expense db.Expense, numbers map[int64]string) expenseResponse {
+	return expenseResponse{
+		ID:              expense.ID,
+		PaidBy:          expense.PaidBy,
+		AccountNumber:   numbers[expense.AccountID],
+		Amount:          expense.Amount,
+		AmountFormatted: expense.Money().Decimal(),
+		Currency:        expense.Currency,
+		Description:     expense.Description,
+		Split:           expense.Split,
+		CreatedAt:       expense.CreatedAt,
+	}
+}
+
+type expenseShareResponse struct {
+	Username        string     `json:"username"`
+	Amount          int64      `json:"amount"`
+	AmountFormatted string     `json:"amount_formatted"`
+	TransferID      *int64     `json:"transfer_id"`
+	SettledAt       *time.Time `json:"settled_at"`
+}
+
+func newExpenseShareResponse(share db.ExpenseShare, currency string) expenseShareResponse {
+	res := expenseShareResponse{
+		Username:        share.Username,
+		Amount:          share.Amount,
+		AmountFormatted: money.New(share.Amount, currency).Decimal(),
+		SettledAt:       timeOrNil(share.SettledAt),
+	}
+
+	if share.TransferID.Valid {
+		res.TransferID = &share.TransferID.Int64
+	}
+
+	return res
+}
+
+type expenseWithSharesResponse struct {
+	Expense expenseResponse        `json:"expense"`
+	Shares  []expenseShareResponse `json:"shares"`
+}
+
+func newExpenseWithSharesResponse(expense db.Expense, shares []db.ExpenseShare, numbers map[int64]string) expenseWithSharesResponse {
+	res := expenseWithSharesResponse{
+		Expense: newExpenseResponse(expense, numbers),
+		Shares:  make([]expenseShareResponse, 0, len(shares)),
+	}
+	for _, share := range shares {
+		res.Shares = append(res.Shares, newExpenseShareResponse(share, expense.Currency))
+	}
+	return res
+}
+
+type expenseParticipantRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	// Percentage is only read by the percentage split, and Amount by the exact one
+	Percentage int64 `json:"percentage" binding:"min=0,max=100"`
+	Amount     int64 `json:"amount" binding:"min=0"`
+}
+
+type createExpenseRequest struct {
+	// AccountNumber is the caller's account the participants pay their shares back into
+	AccountNumber string                      `json:"account_number" binding:"required,account_number"`
+	Amount        int64                       `json:"amount" binding:"required,gt=0"`
+	Currency      string                      `json:"currency" binding:"required,currency"`
+	Description   string                      `json:"description" binding:"max=200"`
+	Split         string                      `json:"split" binding:"required,oneof=equal percentage exact"`
+	Participants  []expenseParticipantRequest `json:"participants" binding:"required,min=1,max=50,dive"`
+}
+
+// createExpense records an expense the caller paid, split between the participants, who are asked to settle their shares
+func (server *Server) createExpense(ctx *gin.Context) {
+	var req createExpenseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	account, ok := server.getHeldAccount(ctx, req.AccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+
+	if account.Account.Currency != req.Currency {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w: %s vs %s", account.Account.Number, money.ErrCurrencyMismatch, account.Account.Currency, req.Currency))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	participants := make([]db.ExpenseParticipant, 0, len(req.Participants))
+	for _, participant := range req.Participants {
+		participants = append(participants, db.ExpenseParticipant{
+			Username:   participant.Username,
+			Percentage: participant.Percentage,
+			Amount:     participant.Amount,
+		})
+	}
+
+	result, err := server.store.CreateExpenseTx(ctx, db.CreateExpenseTxParams{
+		PaidBy:       authPayload.Username,
+		AccountID:    account.Account.ID,
+		Amount:       money.New(req.Amount, req.Currency),
+		Description:  req.Description,
+		Split:        req.Split,
+		Participants: participants,
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidSplit) {
+			errorResponse(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("a participant of the expense was not found: %w", err))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newExpenseWithSharesResponse(result.Expense, result.Shares, numbersOf(account.Account)))
+}
+
+type listExpensesRequestQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listExpenses lists the expenses the caller paid or has a share of, newest first
+func (server *Server) listExpenses(ctx *gin.Context) {
+	var queryReq listExpensesRequestQuery
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	expenses, err := server.store.ListUserExpenses(ctx, db.ListUserExpensesParams{
+		Username:    authPayload.Username,
+		LimitCount:  queryReq.PageSize,
+		OffsetCount: (queryReq.PageID - 1) * queryReq.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, len(expenses))
+	for _, expense := range expenses {
+		ids = append(ids, expense.AccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]expenseResponse, 0, len(expenses))
+	for _, expense := range expenses {
+		res = append(res, newExpenseResponse(expense, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type expenseRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getExpense responds with the expense and its shares, to its payer and participants only
+func (server *Server) getExpense(ctx *gin.Context) {
+	var uriReq expenseRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	expense, err := server.store.GetExpense(ctx, uriReq.ID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("expense: [%d] not found", uriReq.ID))
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	shares, err := server.store.ListExpenseShares(ctx, expense.ID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	allowed := expense.PaidBy == authPayload.Username
+	for _, share := range shares {
+		allowed = allowed || share.Username == authPayload.Username
+	}
+
+	if !allowed {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("expense: [%d] is not shared with the authenticated user", expense.ID))
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, expense.AccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newExpenseWithSharesResponse(expense, shares, numbers))
+}
+
+type settleExpenseShareRequest struct {
+	FromAccountNumber string `json:"from_account_number" binding:"required,account_number"`
+}
+
+type settleExpenseShareResponse struct {
+	Share    expenseShareResponse  `json:"share"`
+	Transfer transferMoneyResponse `json:"transfer"`
+}
+
+// settleExpenseShare pays the caller's share of the expense back to its payer
+func (server *Server) settleExpenseShare(ctx *gin.Context) {
+	var uriReq expenseRequestUri
+	var req settleExpenseShareRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	from, ok := server.getHeldAccount(ctx, req.FromAccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.SettleExpenseShareTx(ctx, db.SettleExpenseShareTxParams{
+		ExpenseID:     uriReq.ID,
+		Username:      authPayload.Username,
+		FromAccountID: from.Account.ID,
+		Now:           time.Now(),
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("%s has no share of expense: [%d]", authPayload.Username, uriReq.ID))
+		case errors.Is(err, db.ErrAlreadySettled):
+			errorResponse(ctx, http.StatusConflict, err)
+		case errors.Is(err, db.ErrChildControls):
+			errorResponse(ctx, http.StatusForbidden, err)
+		case errors.Is(err, db.ErrInsufficientBalance), isMoneyError(err):
+			errorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			errorResponse(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settleExpenseShareResponse{
+		Share:    newExpenseShareResponse(result.Share, result.Transfer.FromAccount.Currency),
+		Transfer: newTransferMoneyResponse(result.Transfer),
+	})
+}
+
+// outstandingBalanceResponse is what the user owes the caller, net of what the caller owes them.
+// A negative amount is owed by the caller.
+type outstandingBalanceResponse struct {
+	Username        string `json:"username"`
+	Amount          int64  `json:"amount"`
+	AmountFormatted string `json:"amount_formatted"`
+	Currency        string `json:"currency"`
+}
+
+// listOutstandingBalances nets the unsettled shares between the caller and each other user, per currency
+func (server *Server) listOutstandingBalances(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	balances, err := server.store.ListOutstandingBalances(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]outstandingBalanceResponse, 0, len(balances))
+	for _, balance := range balances {
+		res = append(res, outstandingBalanceResponse{
+			Username:        balance.Counterparty,
+			Amount:          balance.Amount,
+			AmountFormatted: money.New(balance.Amount, balance.Currency).Decimal(),
+			Currency:        balance.Currency,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
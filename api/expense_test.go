@@ -0,0 +1,353 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExpenseAPI(t *testing.T) {
+	payer, _ := randomUser()
+	friend, _ := randomUser()
+	stranger, _ := randomUser()
+
+	payerAccount := randomAccount(payer.Username)
+	payerAccount.Currency = util.USD
+
+	friendAccount := randomAccount(friend.Username)
+	friendAccount.ID = payerAccount.ID + 1
+	friendAccount.Currency = util.USD
+
+	expense := db.Expense{
+		ID:          1,
+		PaidBy:      payer.Username,
+		AccountID:   payerAccount.ID,
+		Amount:      3001,
+		Currency:    util.USD,
+		Description: "dinner",
+		Split:       db.ExpenseSplitEqual,
+		CreatedAt:   time.Now(),
+	}
+
+	shares := []db.ExpenseShare{
+		{ExpenseID: expense.ID, Username: payer.Username, Amount: 1501, SettledAt: pgtype.Timestamptz{Time: expense.CreatedAt, Valid: true}},
+		{ExpenseID: expense.ID, Username: friend.Username, Amount: 1500},
+	}
+
+	settledShare := shares[1]
+	settledShare.TransferID = pgtype.Int8{Int64: 1, Valid: true}
+	settledShare.SettledAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	transfer := db.TransfeMoneyTxResult{
+		Transfer:    &db.Transfer{ID: 1, FromAccountID: friendAccount.ID, ToAccountID: payerAccount.ID, Amount: 1500},
+		FromAccount: &friendAccount,
+		ToAccount:   &payerAccount,
+		FromEntry:   &db.Entry{ID: 1, AccountID: friendAccount.ID, Amount: -1500},
+		ToEntry:     &db.Entry{ID: 2, AccountID: payerAccount.ID, Amount: 1500},
+	}
+
+	holdsAccount := func(store *mockDB.MockStore, account db.Account, username string) {
+		store.EXPECT().
+			GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: username, Number: account.Number})).
+			Times(1).
+			Return(heldAccount(account, username), nil)
+	}
+
+	listsPayerNumber := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{payerAccount.ID})).
+			Times(1).
+			Return([]db.ListAccountNumbersRow{{ID: payerAccount.ID, Number: payerAccount.Number}}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			url:    "/expenses",
+			body: gin.H{
+				"account_number": payerAccount.Number,
+				"amount":         expense.Amount,
+				"currency":       util.USD,
+				"description":    expense.Description,
+				"split":          db.ExpenseSplitEqual,
+				"participants":   []gin.H{{"username": payer.Username}, {"username": friend.Username}},
+			},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					CreateExpenseTx(gomock.Any(), gomock.Eq(db.CreateExpenseTxParams{
+						PaidBy:       payer.Username,
+						AccountID:    payerAccount.ID,
+						Amount:       money.New(expense.Amount, util.USD),
+						Description:  expense.Description,
+						Split:        db.ExpenseSplitEqual,
+						Participants: []db.ExpenseParticipant{{Username: payer.Username}, {Username: friend.Username}},
+					})).
+					Times(1).
+					Return(db.CreateExpenseTxResult{Expense: expense, Shares: shares}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"15.00"`)
+			},
+		},
+		{
+			name:   "CreateCurrencyMismatch",
+			method: http.MethodPost,
+			url:    "/expenses",
+			body: gin.H{
+				"account_number": payerAccount.Number,
+				"amount":         expense.Amount,
+				"currency":       util.INR,
+				"split":          db.ExpenseSplitEqual,
+				"participants":   []gin.H{{"username": friend.Username}},
+			},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().CreateExpenseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateInvalidSplit",
+			method: http.MethodPost,
+			url:    "/expenses",
+			body: gin.H{
+				"account_number": payerAccount.Number,
+				"amount":         expense.Amount,
+				"currency":       util.USD,
+				"split":          db.ExpenseSplitPercentage,
+				"participants":   []gin.H{{"username": friend.Username, "percentage": 60}},
+			},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					CreateExpenseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateExpenseTxResult{}, db.ErrInvalidSplit)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateWithoutParticipants",
+			method: http.MethodPost,
+			url:    "/expenses",
+			body: gin.H{
+				"account_number": payerAccount.Number,
+				"amount":         expense.Amount,
+				"currency":       util.USD,
+				"split":          db.ExpenseSplitEqual,
+			},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateExpenseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Get",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/expenses/%d", expense.ID),
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetExpense(gomock.Any(), gomock.Eq(expense.ID)).Times(1).Return(expense, nil)
+				store.EXPECT().ListExpenseShares(gomock.Any(), gomock.Eq(expense.ID)).Times(1).Return(shares, nil)
+				listsPayerNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"account_number":%q`, payerAccount.Number))
+			},
+		},
+		{
+			name:     "GetNotShared",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/expenses/%d", expense.ID),
+			username: stranger.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetExpense(gomock.Any(), gomock.Eq(expense.ID)).Times(1).Return(expense, nil)
+				store.EXPECT().ListExpenseShares(gomock.Any(), gomock.Eq(expense.ID)).Times(1).Return(shares, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "List",
+			method:   http.MethodGet,
+			url:      "/expenses?page_id=2&page_size=5",
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListUserExpenses(gomock.Any(), gomock.Eq(db.ListUserExpensesParams{Username: friend.Username, LimitCount: 5, OffsetCount: 5})).
+					Times(1).
+					Return([]db.Expense{expense}, nil)
+				listsPayerNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Settle",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/expenses/%d/settle", expense.ID),
+			body:     gin.H{"from_account_number": friendAccount.Number},
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, friendAccount, friend.Username)
+				store.EXPECT().
+					SettleExpenseShareTx(gomock.Any(), gomock.Cond(func(arg db.SettleExpenseShareTxParams) bool {
+						return arg.ExpenseID == expense.ID && arg.Username == friend.Username && arg.FromAccountID == friendAccount.ID &&
+							time.Since(arg.Now) < time.Minute
+					})).
+					Times(1).
+					Return(db.SettleExpenseShareTxResult{Share: settledShare, Transfer: transfer}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"transfer_id":1`)
+			},
+		},
+		{
+			name:     "SettleWithoutShare",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/expenses/%d/settle", expense.ID),
+			body:     gin.H{"from_account_number": friendAccount.Number},
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, friendAccount, friend.Username)
+				store.EXPECT().
+					SettleExpenseShareTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SettleExpenseShareTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "SettleByChildOverControls",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/expenses/%d/settle", expense.ID),
+			body:     gin.H{"from_account_number": friendAccount.Number},
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, friendAccount, friend.Username)
+				store.EXPECT().
+					SettleExpenseShareTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SettleExpenseShareTxResult{}, fmt.Errorf("%w: over the per-transaction limit of 100", db.ErrChildControls))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "SettleTwice",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/expenses/%d/settle", expense.ID),
+			body:     gin.H{"from_account_number": friendAccount.Number},
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, friendAccount, friend.Username)
+				store.EXPECT().
+					SettleExpenseShareTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SettleExpenseShareTxResult{}, db.ErrAlreadySettled)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "SettleFromOthersAccount",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/expenses/%d/settle", expense.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, friend.Username)
+				store.EXPECT().SettleExpenseShareTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "OutstandingBalances",
+			method:   http.MethodGet,
+			url:      "/ious",
+			username: friend.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListOutstandingBalances(gomock.Any(), gomock.Eq(friend.Username)).
+					Times(1).
+					Return([]db.ListOutstandingBalancesRow{{Counterparty: payer.Username, Currency: util.USD, Amount: -1500}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"-15.00"`)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, payer.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
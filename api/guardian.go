@@ -0,0 +1,675 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type childRequestUri struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// requireGuardian responds with 403 unless the caller is a guardian of the child
+func (server *Server) requireGuardian(ctx *gin.Context, child string) bool {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	_, err := server.store.GetGuardianship(ctx, db.GetGuardianshipParams{
+		Guardian: authPayload.Username,
+		Child:    child,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusForbidden, fmt.Errorf("%s is not a guardian of %s", authPayload.Username, child))
+			return false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+
+	return true
+}
+
+// createChild creates a user the caller is the guardian of. The child logs in like any user,
+// but their transfers to others are held to the controls their guardians set.
+func (server *Server) createChild(ctx *gin.Context) {
+	var req createUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	child, err := server.store.CreateChildTx(ctx, db.CreateChildTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.Username,
+			FullName:       req.FullName,
+			Email:          req.Email,
+			HashedPassword: hashedPassword,
+		},
+		Guardian: authPayload.Username,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusForbidden, err)
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newUserResponse(child))
+}
+
+type guardianshipResponse struct {
+	Guardian  string    `json:"guardian"`
+	Child     string    `json:"child"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (server *Server) listChildren(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	guardianships, err := server.store.ListChildren(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]guardianshipResponse, 0, len(guardianships))
+	for _, guardianship := range guardianships {
+		res = append(res, guardianshipResponse{
+			Guardian:  guardianship.Guardian,
+			Child:     guardianship.Child,
+			CreatedAt: guardianship.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type childControlsResponse struct {
+	Child                        string    `json:"child"`
+	Currency                     string    `json:"currency"`
+	PerTransactionLimit          int64     `json:"per_transaction_limit"`
+	PerTransactionLimitFormatted string    `json:"per_transaction_limit_formatted"`
+	DailyLimit                   int64     `json:"daily_limit"`
+	DailyLimitFormatted          string    `json:"daily_limit_formatted"`
+	UpdatedBy                    string    `json:"updated_by"`
+	UpdatedAt                    time.Time `json:"updated_at"`
+}
+
+func newChildControlsResponse(controls db.ChildControl) childControlsResponse {
+	return childControlsResponse{
+		Child:                        controls.Child,
+		Currency:                     controls.Currency,
+		PerTransactionLimit:          controls.PerTransactionLimit,
+		PerTransactionLimitFormatted: money.New(controls.PerTransactionLimit, controls.Currency).Decimal(),
+		DailyLimit:                   controls.DailyLimit,
+		DailyLimitFormatted:          money.New(controls.DailyLimit, controls.Currency).Decimal(),
+		UpdatedBy:                    controls.UpdatedBy,
+		UpdatedAt:                    controls.UpdatedAt,
+	}
+}
+
+type setChildControlsRequest struct {
+	Currency            string `json:"currency" binding:"required,currency"`
+	PerTransactionLimit int64  `json:"per_transaction_limit" binding:"min=0"`
+	DailyLimit          int64  `json:"daily_limit" binding:"min=0,gtefield=PerTransactionLimit"`
+}
+
+// setChildControls sets the limits the child's transfers to others are held to, a limit of 0 blocks them all
+func (server *Server) setChildControls(ctx *gin.Context) {
+	var uriReq childRequestUri
+	var req setChildControlsRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	controls, err := server.store.UpsertChildControls(ctx, db.UpsertChildControlsParams{
+		Child:               uriReq.Username,
+		Currency:            req.Currency,
+		PerTransactionLimit: req.PerTransactionLimit,
+		DailyLimit:          req.DailyLimit,
+		UpdatedBy:           authPayload.Username,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newChildControlsResponse(controls))
+}
+
+func (server *Server) getChildControls(ctx *gin.Context) {
+	var uriReq childRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	controls, err := server.store.GetChildControls(ctx, uriReq.Username)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("no spending limits are set for %s", uriReq.Username))
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newChildControlsResponse(controls))
+}
+
+type childPayeeResponse struct {
+	AccountNumber string    `json:"account_number"`
+	AddedBy       string    `json:"added_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newChildPayeeResponse(payee db.ChildPayee, numbers map[int64]string) childPayeeResponse {
+	return childPayeeResponse{
+		AccountNumber: numbers[payee.AccountID],
+		AddedBy:       payee.AddedBy,
+		CreatedAt:     payee.CreatedAt,
+	}
+}
+
+type addChildPayeeRequest struct {
+	AccountNumber string `json:"account_number" binding:"required,account_number"`
+}
+
+// addChildPayee allows the child to send money to the account without asking a guardian, within their limits
+func (server *Server) addChildPayee(ctx *gin.Context) {
+	var uriReq childRequestUri
+	var req addChildPayeeRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	account, ok := server.getAccountByNumber(ctx, req.AccountNumber)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.store.CreateChildPayee(ctx, db.CreateChildPayeeParams{
+		Child:     uriReq.Username,
+		AccountID: account.ID,
+		AddedBy:   authPayload.Username,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("account: %s not found", req.AccountNumber))
+				return
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusConflict, fmt.Errorf("account: %s is already a payee of %s", req.AccountNumber, uriReq.Username))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newChildPayeeResponse(payee, numbersOf(account)))
+}
+
+func (server *Server) listChildPayees(ctx *gin.Context) {
+	var uriReq childRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	payees, err := server.store.ListChildPayees(ctx, uriReq.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, len(payees))
+	for _, payee := range payees {
+		ids = append(ids, payee.AccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]childPayeeResponse, 0, len(payees))
+	for _, payee := range payees {
+		res = append(res, newChildPayeeResponse(payee, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type removeChildPayeeRequestUri struct {
+	Username      string `uri:"username" binding:"required,alphanum"`
+	AccountNumber string `uri:"account_number" binding:"required,account_number"`
+}
+
+func (server *Server) removeChildPayee(ctx *gin.Context) {
+	var uriReq removeChildPayeeRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	account, ok := server.getAccountByNumber(ctx, uriReq.AccountNumber)
+	if !ok {
+		return
+	}
+
+	payee, err := server.store.DeleteChildPayee(ctx, db.DeleteChildPayeeParams{
+		Child:     uriReq.Username,
+		AccountID: account.ID,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("account: %s is not a payee of %s", uriReq.AccountNumber, uriReq.Username))
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newChildPayeeResponse(payee, numbersOf(account)))
+}
+
+type allowanceResponse struct {
+	ID                int64     `json:"id"`
+	Guardian          string    `json:"guardian"`
+	Child             string    `json:"child"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	AmountFormatted   string    `json:"amount_formatted"`
+	Currency          string    `json:"currency"`
+	Period            string    `json:"period"`
+	NextPaymentAt     time.Time `json:"next_payment_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func newAllowanceResponse(allowance db.Allowance, numbers map[int64]string) allowanceResponse {
+	return allowanceResponse{
+		ID:                allowance.ID,
+		Guardian:          allowance.Guardian,
+		Child:             allowance.Child,
+		FromAccountNumber: numbers[allowance.FromAccountID],
+		ToAccountNumber:   numbers[allowance.ToAccountID],
+		Amount:            allowance.Amount,
+		AmountFormatted:   allowance.Money().Decimal(),
+		Currency:          allowance.Currency,
+		Period:            allowance.Period,
+		NextPaymentAt:     allowance.NextPaymentAt,
+		CreatedAt:         allowance.CreatedAt,
+	}
+}
+
+type createAllowanceRequest struct {
+	FromAccountNumber string `json:"from_account_number" binding:"required,account_number"`
+	ToAccountNumber   string `json:"to_account_number" binding:"required,account_number"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Currency          string `json:"currency" binding:"required,currency"`
+	Period            string `json:"period" binding:"required,oneof=weekly monthly"`
+	// FirstPaymentAt defaults to now, so the first allowance is paid right away
+	FirstPaymentAt *time.Time `json:"first_payment_at"`
+}
+
+// createAllowance sets up pocket money paid from an account of the guardian to an account of the child every period
+func (server *Server) createAllowance(ctx *gin.Context) {
+	var uriReq childRequestUri
+	var req createAllowanceRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	from, ok := server.getHeldAccount(ctx, req.FromAccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+
+	if from.Account.Currency != req.Currency {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w: %s vs %s", from.Account.Number, money.ErrCurrencyMismatch, from.Account.Currency, req.Currency))
+		return
+	}
+
+	to, ok := server.getAccountByNumber(ctx, req.ToAccountNumber)
+	if !ok {
+		return
+	}
+
+	if to.Owner != uriReq.Username {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("account: %s is not an account of %s", to.Number, uriReq.Username))
+		return
+	}
+
+	if to.Currency != req.Currency {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w: %s vs %s", to.Number, money.ErrCurrencyMismatch, to.Currency, req.Currency))
+		return
+	}
+
+	nextPaymentAt := time.Now()
+	if req.FirstPaymentAt != nil {
+		nextPaymentAt = *req.FirstPaymentAt
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	allowance, err := server.store.CreateAllowance(ctx, db.CreateAllowanceParams{
+		Guardian:      authPayload.Username,
+		Child:         uriReq.Username,
+		FromAccountID: from.Account.ID,
+		ToAccountID:   to.ID,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		Period:        req.Period,
+		NextPaymentAt: nextPaymentAt,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newAllowanceResponse(allowance, numbersOf(from.Account, to)))
+}
+
+func (server *Server) listChildAllowances(ctx *gin.Context) {
+	var uriReq childRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	allowances, err := server.store.ListChildAllowances(ctx, uriReq.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, 2*len(allowances))
+	for _, allowance := range allowances {
+		ids = append(ids, allowance.FromAccountID, allowance.ToAccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]allowanceResponse, 0, len(allowances))
+	for _, allowance := range allowances {
+		res = append(res, newAllowanceResponse(allowance, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type deleteAllowanceRequestUri struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+	ID       int64  `uri:"id" binding:"required,min=1"`
+}
+
+// deleteAllowance stops an allowance, any guardian of the child may stop it
+func (server *Server) deleteAllowance(ctx *gin.Context) {
+	var uriReq deleteAllowanceRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.requireGuardian(ctx, uriReq.Username) {
+		return
+	}
+
+	allowance, err := server.store.DeleteAllowance(ctx, db.DeleteAllowanceParams{
+		ID:    uriReq.ID,
+		Child: uriReq.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("allowance: [%d] of %s not found", uriReq.ID, uriReq.Username))
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, allowance.FromAccountID, allowance.ToAccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAllowanceResponse(allowance, numbers))
+}
+
+type childTransferApprovalResponse struct {
+	ID                int64      `json:"id"`
+	Child             string     `json:"child"`
+	FromAccountNumber string     `json:"from_account_number"`
+	ToAccountNumber   string     `json:"to_account_number"`
+	Amount            int64      `json:"amount"`
+	AmountFormatted   string     `json:"amount_formatted"`
+	Currency          string     `json:"currency"`
+	Reason            string     `json:"reason"`
+	Status            string     `json:"status"`
+	TransferID        *int64     `json:"transfer_id"`
+	DecidedBy         string     `json:"decided_by,omitempty"`
+	DecidedAt         *time.Time `json:"decided_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func newChildTransferApprovalResponse(approval db.ChildTransferApproval, numbers map[int64]string) childTransferApprovalResponse {
+	res := childTransferApprovalResponse{
+		ID:                approval.ID,
+		Child:             approval.Child,
+		FromAccountNumber: numbers[approval.FromAccountID],
+		ToAccountNumber:   numbers[approval.ToAccountID],
+		Amount:            approval.Amount,
+		AmountFormatted:   approval.Money().Decimal(),
+		Currency:          approval.Currency,
+		Reason:            approval.Reason,
+		Status:            approval.Status,
+		DecidedBy:         approval.DecidedBy.String,
+		DecidedAt:         timeOrNil(approval.DecidedAt),
+		CreatedAt:         approval.CreatedAt,
+	}
+
+	if approval.TransferID.Valid {
+		res.TransferID = &approval.TransferID.Int64
+	}
+
+	return res
+}
+
+// listChildTransferApprovals lists the transfers of the caller's children waiting for a guardian
+func (server *Server) listChildTransferApprovals(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	approvals, err := server.store.ListPendingChildTransferApprovals(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, 2*len(approvals))
+	for _, approval := range approvals {
+		ids = append(ids, approval.FromAccountID, approval.ToAccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]childTransferApprovalResponse, 0, len(approvals))
+	for _, approval := range approvals {
+		res = append(res, newChildTransferApprovalResponse(approval, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type decideChildTransferRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) approveChildTransfer(ctx *gin.Context) {
+	server.decideChildTransfer(ctx, true)
+}
+
+func (server *Server) declineChildTransfer(ctx *gin.Context) {
+	server.decideChildTransfer(ctx, false)
+}
+
+type decideChildTransferResponse struct {
+	Approval childTransferApprovalResponse `json:"approval"`
+	Transfer *transferMoneyResponse        `json:"transfer,omitempty"`
+}
+
+// decideChildTransfer lets a guardian of the child make or refuse a transfer waiting for them
+func (server *Server) decideChildTransfer(ctx *gin.Context, approve bool) {
+	var uriReq decideChildTransferRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.DecideChildTransferTx(ctx, db.DecideChildTransferTxParams{
+		ID:       uriReq.ID,
+		Guardian: authPayload.Username,
+		Approve:  approve,
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("child transfer: [%d] not found", uriReq.ID))
+		case errors.Is(err, db.ErrNotGuardian), errors.Is(err, db.ErrChildNoAccess):
+			errorResponse(ctx, http.StatusForbidden, err)
+		case errors.Is(err, db.ErrAlreadyDecided), errors.Is(err, db.ErrMoneyRequestExpired):
+			errorResponse(ctx, http.StatusConflict, err)
+		case errors.Is(err, db.ErrInsufficientBalance), isMoneyError(err):
+			errorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			errorResponse(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, result.Approval.FromAccountID, result.Approval.ToAccountID)
+	if !ok {
+		return
+	}
+
+	res := decideChildTransferResponse{
+		Approval: newChildTransferApprovalResponse(result.Approval, numbers),
+	}
+
+	if result.Transfer != nil {
+		transfer := newTransferMoneyResponse(*result.Transfer)
+		res.Transfer = &transfer
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,390 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGuardianAPI(t *testing.T) {
+	guardian, _ := randomUser()
+	child, _ := randomUser()
+	stranger, _ := randomUser()
+
+	guardianAccount := randomAccount(guardian.Username)
+	guardianAccount.Currency = util.USD
+
+	childAccount := randomAccount(child.Username)
+	childAccount.ID = guardianAccount.ID + 1
+	childAccount.Currency = util.USD
+
+	othersAccount := randomAccount(stranger.Username)
+	othersAccount.ID = guardianAccount.ID + 5
+	othersAccount.Currency = util.USD
+
+	guardianship := db.Guardianship{Guardian: guardian.Username, Child: child.Username, CreatedAt: time.Now()}
+
+	isGuardian := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			GetGuardianship(gomock.Any(), gomock.Eq(db.GetGuardianshipParams{Guardian: guardian.Username, Child: child.Username})).
+			Times(1).
+			Return(guardianship, nil)
+	}
+
+	approval := db.ChildTransferApproval{
+		ID:            1,
+		Child:         child.Username,
+		FromAccountID: childAccount.ID,
+		ToAccountID:   childAccount.ID + 1,
+		Amount:        500,
+		Currency:      util.USD,
+		Reason:        "payee is not on the allowlist",
+		Status:        db.ChildTransferPending,
+		CreatedAt:     time.Now(),
+	}
+
+	allowance := db.Allowance{
+		ID:            1,
+		Guardian:      guardian.Username,
+		Child:         child.Username,
+		FromAccountID: guardianAccount.ID,
+		ToAccountID:   childAccount.ID,
+		Amount:        1000,
+		Currency:      util.USD,
+		Period:        db.AllowanceWeekly,
+		NextPaymentAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "CreateChild",
+			method:   http.MethodPost,
+			url:      "/children",
+			body:     gin.H{"username": child.Username, "full_name": child.FullName, "email": child.Email, "password": "secret"},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateChildTx(gomock.Any(), gomock.Cond(func(arg db.CreateChildTxParams) bool {
+						return arg.Guardian == guardian.Username && arg.Username == child.Username && util.CheckPasswordHash("secret", arg.HashedPassword) == nil
+					})).
+					Times(1).
+					Return(child, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"username":"%s"`, child.Username))
+			},
+		},
+		{
+			name:     "SetControls",
+			method:   http.MethodPut,
+			url:      fmt.Sprintf("/children/%s/controls", child.Username),
+			body:     gin.H{"currency": util.USD, "per_transaction_limit": 500, "daily_limit": 2000},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isGuardian(store)
+				store.EXPECT().
+					UpsertChildControls(gomock.Any(), gomock.Eq(db.UpsertChildControlsParams{
+						Child:               child.Username,
+						Currency:            util.USD,
+						PerTransactionLimit: 500,
+						DailyLimit:          2000,
+						UpdatedBy:           guardian.Username,
+					})).
+					Times(1).
+					Return(db.ChildControl{Child: child.Username, Currency: util.USD, PerTransactionLimit: 500, DailyLimit: 2000, UpdatedBy: guardian.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"daily_limit_formatted":"20.00"`)
+			},
+		},
+		{
+			name:     "SetControlsDailyBelowPerTransaction",
+			method:   http.MethodPut,
+			url:      fmt.Sprintf("/children/%s/controls", child.Username),
+			body:     gin.H{"currency": util.USD, "per_transaction_limit": 500, "daily_limit": 100},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().UpsertChildControls(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "SetControlsNotGuardian",
+			method:   http.MethodPut,
+			url:      fmt.Sprintf("/children/%s/controls", child.Username),
+			body:     gin.H{"currency": util.USD, "per_transaction_limit": 500, "daily_limit": 2000},
+			username: stranger.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetGuardianship(gomock.Any(), gomock.Any()).Times(1).Return(db.Guardianship{}, sql.ErrNoRows)
+				store.EXPECT().UpsertChildControls(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "GetControlsNotSet",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/children/%s/controls", child.Username),
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isGuardian(store)
+				store.EXPECT().GetChildControls(gomock.Any(), gomock.Eq(child.Username)).Times(1).Return(db.ChildControl{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "AddPayee",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/children/%s/payees", child.Username),
+			body:     gin.H{"account_number": guardianAccount.Number},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isGuardian(store)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(guardianAccount.Number)).Times(1).Return(guardianAccount, nil)
+				store.EXPECT().
+					CreateChildPayee(gomock.Any(), gomock.Eq(db.CreateChildPayeeParams{
+						Child:     child.Username,
+						AccountID: guardianAccount.ID,
+						AddedBy:   guardian.Username,
+					})).
+					Times(1).
+					Return(db.ChildPayee{Child: child.Username, AccountID: guardianAccount.ID, AddedBy: guardian.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "AddPayeeTwice",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/children/%s/payees", child.Username),
+			body:     gin.H{"account_number": guardianAccount.Number},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isGuardian(store)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(guardianAccount.Number)).Times(1).Return(guardianAccount, nil)
+				store.EXPECT().CreateChildPayee(gomock.Any(), gomock.Any()).Times(1).Return(db.ChildPayee{}, &pgconn.PgError{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "CreateAllowance",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/children/%s/allowances", child.Username),
+			body:     gin.H{"from_account_number": guardianAccount.Number, "to_account_number": childAccount.Number, "amount": 1000, "currency": util.USD, "period": db.AllowanceWeekly},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isGuardian(store)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(guardianAccount, guardian.Username), nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(childAccount.Number)).Times(1).Return(childAccount, nil)
+				store.EXPECT().
+					CreateAllowance(gomock.Any(), gomock.Cond(func(arg db.CreateAllowanceParams) bool {
+						return arg.Guardian == guardian.Username && arg.Child == child.Username &&
+							arg.FromAccountID == guardianAccount.ID && arg.ToAccountID == childAccount.ID &&
+							arg.Amount == 1000 && arg.Period == db.AllowanceWeekly && !arg.NextPaymentAt.IsZero()
+					})).
+					Times(1).
+					Return(allowance, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"10.00"`)
+			},
+		},
+		{
+			name:     "CreateAllowanceToOthersAccount",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/children/%s/allowances", child.Username),
+			body:     gin.H{"from_account_number": guardianAccount.Number, "to_account_number": othersAccount.Number, "amount": 1000, "currency": util.USD, "period": db.AllowanceMonthly},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isGuardian(store)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(guardianAccount, guardian.Username), nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(othersAccount.Number)).Times(1).Return(othersAccount, nil)
+				store.EXPECT().CreateAllowance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "CreateAllowanceBadPeriod",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/children/%s/allowances", child.Username),
+			body:     gin.H{"from_account_number": guardianAccount.Number, "to_account_number": childAccount.Number, "amount": 1000, "currency": util.USD, "period": "daily"},
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateAllowance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ListPendingTransfers",
+			method:   http.MethodGet,
+			url:      "/child_transfers",
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListPendingChildTransferApprovals(gomock.Any(), gomock.Eq(guardian.Username)).
+					Times(1).
+					Return([]db.ChildTransferApproval{approval}, nil)
+				store.EXPECT().ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{approval.FromAccountID, approval.ToAccountID})).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"transfer_id":null`)
+			},
+		},
+		{
+			name:     "ApproveTransfer",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/child_transfers/%d/approve", approval.ID),
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				approved := approval
+				approved.Status = db.ChildTransferApproved
+				approved.TransferID = pgtype.Int8{Int64: 7, Valid: true}
+				approved.DecidedBy = pgtype.Text{String: guardian.Username, Valid: true}
+
+				fromAccount := childAccount
+				toAccount := randomAccount(stranger.Username)
+				toAccount.ID = approval.ToAccountID
+				toAccount.Currency = util.USD
+
+				store.EXPECT().
+					DecideChildTransferTx(gomock.Any(), gomock.Eq(db.DecideChildTransferTxParams{ID: approval.ID, Guardian: guardian.Username, Approve: true})).
+					Times(1).
+					Return(db.DecideChildTransferTxResult{
+						Approval: approved,
+						Transfer: &db.TransfeMoneyTxResult{
+							Transfer:    &db.Transfer{ID: 7, FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: approval.Amount},
+							FromAccount: &fromAccount,
+							ToAccount:   &toAccount,
+							FromEntry:   &db.Entry{ID: 1, AccountID: fromAccount.ID, Amount: -approval.Amount},
+							ToEntry:     &db.Entry{ID: 2, AccountID: toAccount.ID, Amount: approval.Amount},
+						},
+					}, nil)
+				store.EXPECT().
+					ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{approval.FromAccountID, approval.ToAccountID})).
+					Times(1).
+					Return([]db.ListAccountNumbersRow{{ID: fromAccount.ID, Number: fromAccount.Number}, {ID: toAccount.ID, Number: toAccount.Number}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"transfer_id":7`)
+			},
+		},
+		{
+			name:     "ApproveTransferNotGuardian",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/child_transfers/%d/approve", approval.ID),
+			username: stranger.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().DecideChildTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DecideChildTransferTxResult{}, db.ErrNotGuardian)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveTransferChildNoAccess",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/child_transfers/%d/approve", approval.ID),
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().DecideChildTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DecideChildTransferTxResult{}, db.ErrChildNoAccess)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveTransferInsufficientBalance",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/child_transfers/%d/approve", approval.ID),
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().DecideChildTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DecideChildTransferTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "DeclineDecidedTransfer",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/child_transfers/%d/decline", approval.ID),
+			username: guardian.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					DecideChildTransferTx(gomock.Any(), gomock.Eq(db.DecideChildTransferTxParams{ID: approval.ID, Guardian: guardian.Username, Approve: false})).
+					Times(1).
+					Return(db.DecideChildTransferTxResult{}, db.ErrAlreadyDecided)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, guardian.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,735 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// defaultContributionsPeriod applies when the contributions of a household are asked for without a start
+const defaultContributionsPeriod = 30 * 24 * time.Hour
+
+type householdResponse struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	CreatedBy         string    `json:"created_by"`
+	AccountNumber     string    `json:"account_number"`
+	SpendRule         string    `json:"spend_rule"`
+	ApprovalThreshold int64     `json:"approval_threshold"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func newHouseholdResponse(household db.Household, numbers map[int64]string) householdResponse {
+	return householdResponse{
+		ID:                household.ID,
+		Name:              household.Name,
+		CreatedBy:         household.CreatedBy,
+		AccountNumber:     numbers[household.AccountID],
+		SpendRule:         household.SpendRule,
+		ApprovalThreshold: household.ApprovalThreshold,
+		CreatedAt:         household.CreatedAt,
+	}
+}
+
+type householdMemberResponse struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newHouseholdMemberResponse(member db.HouseholdMember) householdMemberResponse {
+	return householdMemberResponse{
+		Username:  member.Username,
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+type householdRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getMemberHousehold loads the household, and aborts the request unless the caller is one of its members
+func (server *Server) getMemberHousehold(ctx *gin.Context, id int64) (db.Household, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	household, err := server.store.GetHousehold(ctx, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("household: [%d] not found", id))
+			return household, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return household, false
+	}
+
+	_, err = server.store.GetHouseholdMember(ctx, db.GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusForbidden, fmt.Errorf("%w: %s", db.ErrNotHouseholdMember, authPayload.Username))
+			return household, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return household, false
+	}
+
+	return household, true
+}
+
+// getCreatedHousehold loads the household, and aborts the request unless the caller created it
+func (server *Server) getCreatedHousehold(ctx *gin.Context, id int64) (db.Household, bool) {
+	household, ok := server.getMemberHousehold(ctx, id)
+	if !ok {
+		return household, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if household.CreatedBy != authPayload.Username {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("household: [%d] can only be managed by %s", household.ID, household.CreatedBy))
+		return household, false
+	}
+
+	return household, true
+}
+
+type createHouseholdRequest struct {
+	Name     string `json:"name" binding:"required,min=1,max=50"`
+	Currency string `json:"currency" binding:"required,currency"`
+	// SpendRule defaults to any_member
+	SpendRule         string `json:"spend_rule" binding:"omitempty,oneof=any_member majority"`
+	ApprovalThreshold int64  `json:"approval_threshold" binding:"min=0"`
+}
+
+type createHouseholdResponse struct {
+	Household householdResponse `json:"household"`
+	Pot       accountResponse   `json:"pot"`
+}
+
+// createHousehold creates a household with the caller as its first member, along with the pot its members pay into
+func (server *Server) createHousehold(ctx *gin.Context) {
+	var req createHouseholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.SpendRule == "" {
+		req.SpendRule = db.HouseholdSpendAnyMember
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.CreateHouseholdTx(ctx, db.CreateHouseholdTxParams{
+		Name:              req.Name,
+		Currency:          req.Currency,
+		CreatedBy:         authPayload.Username,
+		SpendRule:         req.SpendRule,
+		ApprovalThreshold: req.ApprovalThreshold,
+		MaxAccounts:       server.maxAccountsPerUser(),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrAccountLimitReached) {
+			errorResponse(ctx, http.StatusForbidden, err)
+			return
+		}
+
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503", "23505": // foreign_key_violation, unique_violation
+				errorResponse(ctx, http.StatusForbidden, err)
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createHouseholdResponse{
+		Household: newHouseholdResponse(result.Household, numbersOf(result.Account)),
+		Pot:       newAccountResponse(result.Account),
+	})
+}
+
+// listHouseholds lists the households the caller is a member of
+func (server *Server) listHouseholds(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	households, err := server.store.ListMemberHouseholds(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, len(households))
+	for _, household := range households {
+		ids = append(ids, household.AccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]householdResponse, 0, len(households))
+	for _, household := range households {
+		res = append(res, newHouseholdResponse(household, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type getHouseholdResponse struct {
+	Household householdResponse         `json:"household"`
+	Pot       accountResponse           `json:"pot"`
+	Members   []householdMemberResponse `json:"members"`
+}
+
+func (server *Server) getHousehold(ctx *gin.Context) {
+	var uriReq householdRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	household, ok := server.getMemberHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	pot, err := server.store.GetAccountById(ctx, household.AccountID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	members, err := server.store.ListHouseholdMembers(ctx, household.ID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := getHouseholdResponse{
+		Household: newHouseholdResponse(household, numbersOf(pot)),
+		Pot:       newAccountResponse(pot),
+		Members:   make([]householdMemberResponse, 0, len(members)),
+	}
+	for _, member := range members {
+		res.Members = append(res.Members, newHouseholdMemberResponse(member))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type updateHouseholdSpendRuleRequest struct {
+	SpendRule         string `json:"spend_rule" binding:"required,oneof=any_member majority"`
+	ApprovalThreshold int64  `json:"approval_threshold" binding:"min=0"`
+}
+
+// updateHouseholdSpendRule changes how money may be spent from the pot, spends already waiting for votes keep waiting
+func (server *Server) updateHouseholdSpendRule(ctx *gin.Context) {
+	var uriReq householdRequestUri
+	var req updateHouseholdSpendRuleRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := server.getCreatedHousehold(ctx, uriReq.ID); !ok {
+		return
+	}
+
+	household, err := server.store.UpdateHouseholdSpendRule(ctx, db.UpdateHouseholdSpendRuleParams{
+		ID:                uriReq.ID,
+		SpendRule:         req.SpendRule,
+		ApprovalThreshold: req.ApprovalThreshold,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, household.AccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHouseholdResponse(household, numbers))
+}
+
+type addHouseholdMemberRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+}
+
+// addHouseholdMember adds a user to the household, who may then see the pot and spend from it by its spend rule
+func (server *Server) addHouseholdMember(ctx *gin.Context) {
+	var uriReq householdRequestUri
+	var req addHouseholdMemberRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	household, ok := server.getCreatedHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	member, err := server.store.AddHouseholdMemberTx(ctx, db.AddHouseholdMemberTxParams{
+		Household: household,
+		Username:  req.Username,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("user: %s not found", req.Username))
+				return
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusConflict, fmt.Errorf("%s is already a member of household: [%d]", req.Username, household.ID))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newHouseholdMemberResponse(member))
+}
+
+type removeHouseholdMemberRequestUri struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// removeHouseholdMember lets the creator remove a member, or a member leave the household
+func (server *Server) removeHouseholdMember(ctx *gin.Context) {
+	var uriReq removeHouseholdMemberRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	household, ok := server.getMemberHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if authPayload.Username != household.CreatedBy && authPayload.Username != uriReq.Username {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("only %s or %s can remove %s from household: [%d]", household.CreatedBy, uriReq.Username, uriReq.Username, household.ID))
+		return
+	}
+
+	member, err := server.store.RemoveHouseholdMemberTx(ctx, db.RemoveHouseholdMemberTxParams{
+		Household: household,
+		Username:  uriReq.Username,
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("%s is not a member of household: [%d]", uriReq.Username, household.ID))
+		case errors.Is(err, db.ErrHouseholdCreator):
+			errorResponse(ctx, http.StatusForbidden, err)
+		default:
+			errorResponse(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHouseholdMemberResponse(member))
+}
+
+type listHouseholdContributionsRequestQuery struct {
+	// Since defaults to 30 days before Until, which defaults to now
+	Since *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until *time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type householdContributionResponse struct {
+	Username        string `json:"username"`
+	Transfers       int64  `json:"transfers"`
+	Amount          int64  `json:"amount"`
+	AmountFormatted string `json:"amount_formatted"`
+}
+
+type listHouseholdContributionsResponse struct {
+	Since            time.Time                       `json:"since"`
+	Until            time.Time                       `json:"until"`
+	Currency         string                          `json:"currency"`
+	PaidIn           int64                           `json:"paid_in"`
+	PaidInFormatted  string                          `json:"paid_in_formatted"`
+	PaidOut          int64                           `json:"paid_out"`
+	PaidOutFormatted string                          `json:"paid_out_formatted"`
+	Contributions    []householdContributionResponse `json:"contributions"`
+}
+
+// listHouseholdContributions sums up the money which came into and went out of the pot over the period,
+// and who paid in how much of it
+func (server *Server) listHouseholdContributions(ctx *gin.Context) {
+	var uriReq householdRequestUri
+	var queryReq listHouseholdContributionsRequestQuery
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	until := time.Now()
+	if queryReq.Until != nil {
+		until = *queryReq.Until
+	}
+
+	since := until.Add(-defaultContributionsPeriod)
+	if queryReq.Since != nil {
+		since = *queryReq.Since
+	}
+
+	if !since.Before(until) {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("since must be before until"))
+		return
+	}
+
+	household, ok := server.getMemberHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	pot, err := server.store.GetAccountById(ctx, household.AccountID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	totals, err := server.store.SumAccountEntries(ctx, db.SumAccountEntriesParams{
+		AccountID: pot.ID,
+		Since:     since,
+		Until:     until,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	contributions, err := server.store.ListHouseholdContributions(ctx, db.ListHouseholdContributionsParams{
+		AccountID: pot.ID,
+		Since:     since,
+		Until:     until,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := listHouseholdContributionsResponse{
+		Since:            since,
+		Until:            until,
+		Currency:         pot.Currency,
+		PaidIn:           totals.PaidIn,
+		PaidInFormatted:  money.New(totals.PaidIn, pot.Currency).Decimal(),
+		PaidOut:          totals.PaidOut,
+		PaidOutFormatted: money.New(totals.PaidOut, pot.Currency).Decimal(),
+		Contributions:    make([]householdContributionResponse, 0, len(contributions)),
+	}
+	for _, contribution := range contributions {
+		res.Contributions = append(res.Contributions, householdContributionResponse{
+			Username:        contribution.Username,
+			Transfers:       contribution.Transfers,
+			Amount:          contribution.Amount,
+			AmountFormatted: money.New(contribution.Amount, pot.Currency).Decimal(),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type householdSpendResponse struct {
+	ID              int64      `json:"id"`
+	HouseholdID     int64      `json:"household_id"`
+	RequestedBy     string     `json:"requested_by"`
+	ToAccountNumber string     `json:"to_account_number"`
+	Amount          int64      `json:"amount"`
+	Note            string     `json:"note,omitempty"`
+	Status          string     `json:"status"`
+	TransferID      *int64     `json:"transfer_id"`
+	DecidedAt       *time.Time `json:"decided_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func newHouseholdSpendResponse(spend db.HouseholdSpend, numbers map[int64]string) householdSpendResponse {
+	res := householdSpendResponse{
+		ID:              spend.ID,
+		HouseholdID:     spend.HouseholdID,
+		RequestedBy:     spend.RequestedBy,
+		ToAccountNumber: numbers[spend.ToAccountID],
+		Amount:          spend.Amount,
+		Note:            spend.Note,
+		Status:          spend.Status,
+		DecidedAt:       timeOrNil(spend.DecidedAt),
+		CreatedAt:       spend.CreatedAt,
+	}
+
+	if spend.TransferID.Valid {
+		res.TransferID = &spend.TransferID.Int64
+	}
+
+	return res
+}
+
+type householdSpendTxResponse struct {
+	Spend    householdSpendResponse `json:"spend"`
+	Transfer *transferMoneyResponse `json:"transfer,omitempty"`
+}
+
+func newHouseholdSpendTxResponse(result db.HouseholdSpendTxResult, numbers map[int64]string) householdSpendTxResponse {
+	res := householdSpendTxResponse{
+		Spend: newHouseholdSpendResponse(result.Spend, numbers),
+	}
+
+	if result.Transfer != nil {
+		transfer := newTransferMoneyResponse(*result.Transfer)
+		res.Transfer = &transfer
+	}
+
+	return res
+}
+
+// householdSpendErrorResponse responds to the errors of spending from a pot and voting on a spend
+func householdSpendErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		errorResponse(ctx, http.StatusNotFound, err)
+	case errors.Is(err, db.ErrNotHouseholdMember), errors.Is(err, db.ErrChildControls):
+		errorResponse(ctx, http.StatusForbidden, err)
+	case errors.Is(err, db.ErrAlreadyDecided):
+		errorResponse(ctx, http.StatusConflict, err)
+	case errors.Is(err, db.ErrInsufficientBalance), isMoneyError(err):
+		errorResponse(ctx, http.StatusBadRequest, err)
+	default:
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, err)
+				return
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusConflict, err)
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+type createHouseholdSpendRequest struct {
+	ToAccountNumber string `json:"to_account_number" binding:"required,account_number"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Currency        string `json:"currency" binding:"required,currency"`
+	Note            string `json:"note" binding:"max=200"`
+}
+
+// createHouseholdSpend spends from the pot right away when the spend rule allows it, and responds with 201.
+// Otherwise the spend waits for the votes of the other members, and it responds with 202.
+func (server *Server) createHouseholdSpend(ctx *gin.Context) {
+	var uriReq householdRequestUri
+	var req createHouseholdSpendRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	household, ok := server.getMemberHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	to, ok := server.getAccountByNumber(ctx, req.ToAccountNumber)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.SpendFromHouseholdTx(ctx, db.SpendFromHouseholdTxParams{
+		Household:   household,
+		RequestedBy: authPayload.Username,
+		ToAccountID: to.ID,
+		Amount:      money.New(req.Amount, req.Currency),
+		Note:        req.Note,
+		Now:         time.Now(),
+	})
+
+	if err != nil {
+		householdSpendErrorResponse(ctx, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Spend.Status == db.HouseholdSpendPending {
+		status = http.StatusAccepted
+	}
+
+	ctx.JSON(status, newHouseholdSpendTxResponse(result, numbersOf(to)))
+}
+
+type listHouseholdSpendsRequestQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listHouseholdSpends lists the spends from the pot, newest first
+func (server *Server) listHouseholdSpends(ctx *gin.Context) {
+	var uriReq householdRequestUri
+	var queryReq listHouseholdSpendsRequestQuery
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	household, ok := server.getMemberHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	spends, err := server.store.ListHouseholdSpends(ctx, db.ListHouseholdSpendsParams{
+		HouseholdID: household.ID,
+		Limit:       queryReq.PageSize,
+		Offset:      (queryReq.PageID - 1) * queryReq.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]int64, 0, len(spends))
+	for _, spend := range spends {
+		ids = append(ids, spend.ToAccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]householdSpendResponse, 0, len(spends))
+	for _, spend := range spends {
+		res = append(res, newHouseholdSpendResponse(spend, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type voteHouseholdSpendRequestUri struct {
+	ID      int64 `uri:"id" binding:"required,min=1"`
+	SpendID int64 `uri:"spend_id" binding:"required,min=1"`
+}
+
+type voteHouseholdSpendRequest struct {
+	Approve *bool `json:"approve" binding:"required"`
+}
+
+// voteHouseholdSpend records the caller's vote on a spend waiting for the members, which may decide it
+func (server *Server) voteHouseholdSpend(ctx *gin.Context) {
+	var uriReq voteHouseholdSpendRequestUri
+	var req voteHouseholdSpendRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	household, ok := server.getMemberHousehold(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.VoteHouseholdSpendTx(ctx, db.VoteHouseholdSpendTxParams{
+		Household: household,
+		SpendID:   uriReq.SpendID,
+		Username:  authPayload.Username,
+		Approve:   *req.Approve,
+		Now:       time.Now(),
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("spend: [%d] of household: [%d] not found", uriReq.SpendID, household.ID))
+			return
+		}
+		householdSpendErrorResponse(ctx, err)
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, result.Spend.ToAccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHouseholdSpendTxResponse(result, numbers))
+}
@@ -0,0 +1,483 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHouseholdAPI(t *testing.T) {
+	creator, _ := randomUser()
+	member, _ := randomUser()
+	stranger, _ := randomUser()
+
+	pot := randomAccount(creator.Username)
+	pot.Type = db.AccountTypeHousehold
+	pot.Currency = util.USD
+
+	shop := randomAccount(stranger.Username)
+	shop.ID = pot.ID + 1
+	shop.Currency = util.USD
+
+	household := db.Household{
+		ID:                1,
+		Name:              pot.Name,
+		CreatedBy:         creator.Username,
+		AccountID:         pot.ID,
+		SpendRule:         db.HouseholdSpendMajority,
+		ApprovalThreshold: 1000,
+		CreatedAt:         time.Now(),
+	}
+
+	spend := db.HouseholdSpend{
+		ID:          1,
+		HouseholdID: household.ID,
+		RequestedBy: member.Username,
+		ToAccountID: shop.ID,
+		Amount:      5000,
+		Status:      db.HouseholdSpendPending,
+		CreatedAt:   time.Now(),
+	}
+
+	approvedSpend := spend
+	approvedSpend.Status = db.HouseholdSpendApproved
+	approvedSpend.TransferID = pgtype.Int8{Int64: 1, Valid: true}
+
+	transfer := db.TransfeMoneyTxResult{
+		Transfer:    &db.Transfer{ID: 1, FromAccountID: pot.ID, ToAccountID: shop.ID, Amount: spend.Amount},
+		FromAccount: &pot,
+		ToAccount:   &shop,
+		FromEntry:   &db.Entry{ID: 1, AccountID: pot.ID, Amount: -spend.Amount},
+		ToEntry:     &db.Entry{ID: 2, AccountID: shop.ID, Amount: spend.Amount},
+	}
+
+	getHousehold := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			GetHousehold(gomock.Any(), gomock.Eq(household.ID)).
+			Times(1).
+			Return(household, nil)
+	}
+
+	isMember := func(store *mockDB.MockStore, username string) {
+		getHousehold(store)
+		store.EXPECT().
+			GetHouseholdMember(gomock.Any(), gomock.Eq(db.GetHouseholdMemberParams{HouseholdID: household.ID, Username: username})).
+			Times(1).
+			Return(db.HouseholdMember{HouseholdID: household.ID, Username: username}, nil)
+	}
+
+	getShop := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			GetAccountByNumber(gomock.Any(), gomock.Eq(shop.Number)).
+			Times(1).
+			Return(shop, nil)
+	}
+
+	listsShopNumber := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{shop.ID})).
+			Times(1).
+			Return([]db.ListAccountNumbersRow{{ID: shop.ID, Number: shop.Number}}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Create",
+			method:   http.MethodPost,
+			url:      "/households",
+			body:     gin.H{"name": household.Name, "currency": util.USD, "spend_rule": db.HouseholdSpendMajority, "approval_threshold": 1000},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateHouseholdTx(gomock.Any(), gomock.Eq(db.CreateHouseholdTxParams{
+						Name:              household.Name,
+						Currency:          util.USD,
+						CreatedBy:         creator.Username,
+						SpendRule:         db.HouseholdSpendMajority,
+						ApprovalThreshold: 1000,
+						MaxAccounts:       defaultMaxAccountsPerUser,
+					})).
+					Times(1).
+					Return(db.CreateHouseholdTxResult{Household: household, Account: pot}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"type":"household"`)
+			},
+		},
+		{
+			name:     "CreateInvalidSpendRule",
+			method:   http.MethodPost,
+			url:      "/households",
+			body:     gin.H{"name": household.Name, "currency": util.USD, "spend_rule": "anyone"},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateHouseholdTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Get",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/households/%d", household.ID),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(pot.ID)).Times(1).Return(pot, nil)
+				store.EXPECT().
+					ListHouseholdMembers(gomock.Any(), gomock.Eq(household.ID)).
+					Times(1).
+					Return([]db.HouseholdMember{{HouseholdID: household.ID, Username: creator.Username}, {HouseholdID: household.ID, Username: member.Username}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"username":"%s"`, member.Username))
+			},
+		},
+		{
+			name:     "GetNotMember",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/households/%d", household.ID),
+			username: stranger.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				getHousehold(store)
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdMember{}, sql.ErrNoRows)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "GetNotFound",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/households/%d", household.ID),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetHousehold(gomock.Any(), gomock.Any()).Times(1).Return(db.Household{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "UpdateSpendRuleNotCreator",
+			method:   http.MethodPut,
+			url:      fmt.Sprintf("/households/%d/spend_rule", household.ID),
+			body:     gin.H{"spend_rule": db.HouseholdSpendAnyMember},
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				store.EXPECT().UpdateHouseholdSpendRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "AddMember",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/members", household.ID),
+			body:     gin.H{"username": member.Username},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, creator.Username)
+				store.EXPECT().
+					AddHouseholdMemberTx(gomock.Any(), gomock.Eq(db.AddHouseholdMemberTxParams{Household: household, Username: member.Username})).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: member.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "AddMemberTwice",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/members", household.ID),
+			body:     gin.H{"username": member.Username},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, creator.Username)
+				store.EXPECT().
+					AddHouseholdMemberTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdMember{}, &pgconn.PgError{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "LeaveHousehold",
+			method:   http.MethodDelete,
+			url:      fmt.Sprintf("/households/%d/members/%s", household.ID, member.Username),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				store.EXPECT().
+					RemoveHouseholdMemberTx(gomock.Any(), gomock.Eq(db.RemoveHouseholdMemberTxParams{Household: household, Username: member.Username})).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: member.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RemoveOtherMember",
+			method:   http.MethodDelete,
+			url:      fmt.Sprintf("/households/%d/members/%s", household.ID, creator.Username),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				store.EXPECT().RemoveHouseholdMemberTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Contributions",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/households/%d/contributions", household.ID),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(pot.ID)).Times(1).Return(pot, nil)
+				store.EXPECT().
+					SumAccountEntries(gomock.Any(), gomock.Cond(func(arg db.SumAccountEntriesParams) bool {
+						return arg.AccountID == pot.ID && arg.Until.Sub(arg.Since) == defaultContributionsPeriod
+					})).
+					Times(1).
+					Return(db.SumAccountEntriesRow{PaidIn: 15000, PaidOut: 5000}, nil)
+				store.EXPECT().
+					ListHouseholdContributions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListHouseholdContributionsRow{
+						{Username: creator.Username, Transfers: 2, Amount: 10000},
+						{Username: member.Username, Transfers: 1, Amount: 5000},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"paid_in_formatted":"150.00"`)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"100.00"`)
+			},
+		},
+		{
+			name:     "ContributionsSinceAfterUntil",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/households/%d/contributions?since=2026-02-01T00:00:00Z&until=2026-01-01T00:00:00Z", household.ID),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetHousehold(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "SpendWaitingForVotes",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends", household.ID),
+			body:     gin.H{"to_account_number": shop.Number, "amount": spend.Amount, "currency": util.USD},
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				getShop(store)
+				store.EXPECT().
+					SpendFromHouseholdTx(gomock.Any(), gomock.Cond(func(arg db.SpendFromHouseholdTxParams) bool {
+						return arg.Household == household && arg.RequestedBy == member.Username && arg.ToAccountID == shop.ID &&
+							arg.Amount == money.New(spend.Amount, util.USD) && time.Since(arg.Now) < time.Minute
+					})).
+					Times(1).
+					Return(db.HouseholdSpendTxResult{Spend: spend}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"transfer":`)
+			},
+		},
+		{
+			name:     "SpendRightAway",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends", household.ID),
+			body:     gin.H{"to_account_number": shop.Number, "amount": spend.Amount, "currency": util.USD},
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				getShop(store)
+				store.EXPECT().
+					SpendFromHouseholdTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdSpendTxResult{Spend: approvedSpend, Transfer: &transfer}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"transfer_id":1`)
+			},
+		},
+		{
+			name:     "SpendInsufficientBalance",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends", household.ID),
+			body:     gin.H{"to_account_number": shop.Number, "amount": spend.Amount, "currency": util.USD},
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				getShop(store)
+				store.EXPECT().
+					SpendFromHouseholdTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdSpendTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "SpendByChildOverControls",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends", household.ID),
+			body:     gin.H{"to_account_number": shop.Number, "amount": spend.Amount, "currency": util.USD},
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				getShop(store)
+				store.EXPECT().
+					SpendFromHouseholdTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdSpendTxResult{}, fmt.Errorf("%w: payee is not on the allowlist", db.ErrChildControls))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "ListSpends",
+			method:   http.MethodGet,
+			url:      fmt.Sprintf("/households/%d/spends?page_id=1&page_size=5", household.ID),
+			username: member.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, member.Username)
+				store.EXPECT().
+					ListHouseholdSpends(gomock.Any(), gomock.Eq(db.ListHouseholdSpendsParams{HouseholdID: household.ID, Limit: 5, Offset: 0})).
+					Times(1).
+					Return([]db.HouseholdSpend{spend}, nil)
+				listsShopNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"to_account_number":%q`, shop.Number))
+			},
+		},
+		{
+			name:     "Vote",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends/%d/votes", household.ID, spend.ID),
+			body:     gin.H{"approve": true},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, creator.Username)
+				store.EXPECT().
+					VoteHouseholdSpendTx(gomock.Any(), gomock.Cond(func(arg db.VoteHouseholdSpendTxParams) bool {
+						return arg.Household == household && arg.SpendID == spend.ID && arg.Username == creator.Username &&
+							arg.Approve && time.Since(arg.Now) < time.Minute
+					})).
+					Times(1).
+					Return(db.HouseholdSpendTxResult{Spend: approvedSpend, Transfer: &transfer}, nil)
+				listsShopNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"status":"approved"`)
+			},
+		},
+		{
+			name:     "VoteWithoutDecision",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends/%d/votes", household.ID, spend.ID),
+			body:     gin.H{},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().VoteHouseholdSpendTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "VoteAlreadyDecided",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/households/%d/spends/%d/votes", household.ID, spend.ID),
+			body:     gin.H{"approve": false},
+			username: creator.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				isMember(store, creator.Username)
+				store.EXPECT().
+					VoteHouseholdSpendTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdSpendTxResult{}, db.ErrAlreadyDecided)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, creator.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
@@ -16,7 +17,7 @@ func newTestServer(t *testing.T, store db.Store) (*Server, error) {
 		ACCESS_TOKEN_DURATION: 15,
 	}
 
-	server, err := NewServer(store, config)
+	server, err := NewServer(store, config, token.NewMemoryRevocationList(), nil, nil)
 	require.NoError(t, err)
 
 	return server, nil
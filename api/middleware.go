@@ -1,44 +1,99 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/ratelimit"
 	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	authorizationHeaderKey  = "Authorization"
 	authorizationTypeBearer = "bearer"
+	authorizationTypeApiKey = "apikey"
 	authorizationPayloadKey = "auth_payload_key"
+	apiKeyPayloadKey        = "api_key_payload_key"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// authMiddleware authenticates bearer tokens, and api keys or third-party app tokens carrying one of the given scopes.
+// Routes that do not list any scope can only be used with a user's own bearer token.
+func authMiddleware(tokenMaker token.Maker, revocations token.RevocationList, apiKeys token.APIKeyVerifier, scopes ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("authorization header is not provided")))
+			errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("authorization header is not provided"))
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 
 		if len(fields) < 2 {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("invalid authorization header format")))
+			errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("invalid authorization header format"))
 			return
 		}
 
-		if strings.ToLower(fields[0]) != authorizationTypeBearer {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("unsupported authorization type")))
+		var payload *token.Payload
+
+		switch strings.ToLower(fields[0]) {
+		case authorizationTypeBearer:
+			accessToken := fields[1]
+			tokenPayload, err := tokenMaker.VerifyToken(accessToken)
+			if err != nil {
+				errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("invalid access token"))
+				return
+			}
+
+			// tokens issued to third-party apps are limited to the scopes the user consented to
+			if tokenPayload.IsDelegated() && !tokenPayload.HasScope(scopes...) {
+				errorResponse(ctx, http.StatusForbidden, fmt.Errorf("access token is missing scope for this route"))
+				return
+			}
+			payload = tokenPayload
+		case authorizationTypeApiKey:
+			if len(scopes) == 0 {
+				errorResponse(ctx, http.StatusForbidden, fmt.Errorf("api keys cannot be used for this route"))
+				return
+			}
+
+			apiKeyPayload, err := apiKeys.VerifyAPIKey(ctx, fields[1], ctx.ClientIP())
+			if err != nil {
+				if errors.Is(err, token.ErrAPIKeyIPBlocked) {
+					errorResponse(ctx, http.StatusForbidden, err)
+					return
+				}
+				errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("invalid api key"))
+				return
+			}
+
+			if !apiKeyPayload.HasScope(scopes...) {
+				errorResponse(ctx, http.StatusForbidden, fmt.Errorf("api key is missing scope: %s", strings.Join(scopes, " or ")))
+				return
+			}
+
+			ctx.Set(apiKeyPayloadKey, apiKeyPayload)
+			payload = &apiKeyPayload.Payload
+		default:
+			errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("unsupported authorization type"))
 			return
 		}
 
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
+		revoked, err := revocations.IsRevoked(ctx, payload)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("invalid access token")))
+			errorResponse(ctx, http.StatusInternalServerError, fmt.Errorf("cannot check access token: %w", err))
+			return
+		}
+
+		if revoked {
+			errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("access token has been revoked"))
 			return
 		}
 
@@ -46,3 +101,99 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// apiKeyCanUseAccount reports whether the request is allowed to move money out of the account.
+// Requests authenticated with a bearer token can use every account of the user.
+func apiKeyCanUseAccount(ctx *gin.Context, accountID int64) bool {
+	value, ok := ctx.Get(apiKeyPayloadKey)
+	if !ok {
+		return true
+	}
+
+	return value.(*token.APIKeyPayload).CanUseAccount(accountID)
+}
+
+// bankerMiddleware only lets bankers through, it must run after authMiddleware
+func bankerMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		if authPayload.Role != util.BankerRole {
+			errorResponse(ctx, http.StatusForbidden, fmt.Errorf("only bankers can use this route"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// metricsMiddleware counts the requests and their latency by route template, unknown routes share one label
+func metricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		startTime := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.ObserveHTTPRequest("gin", ctx.Request.Method, route, ctx.Writer.Status(), time.Since(startTime))
+	}
+}
+
+// tracingMiddleware names the span started by otelhttp after the matched route
+func tracingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if route := ctx.FullPath(); route != "" {
+			span := trace.SpanFromContext(ctx.Request.Context())
+			span.SetName(ctx.Request.Method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		}
+	}
+}
+
+// rateLimitMiddleware rejects the requests over the policies of their route. Users are identified by their bearer token,
+// requests without a valid one are counted by ip.
+func rateLimitMiddleware(limiter *ratelimit.Limiter, tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		client := ratelimit.Client{
+			IP:       ctx.ClientIP(),
+			Username: bearerUsername(tokenMaker, ctx.GetHeader(authorizationHeaderKey)),
+		}
+
+		result, ok := limiter.Allow(ctx, ctx.Request.Method+" "+ctx.FullPath(), client)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		for key, value := range result.Headers() {
+			ctx.Header(key, value)
+		}
+
+		if !result.Allowed {
+			errorResponse(ctx, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded, retry in %s", result.RetryAfter.Round(time.Second)))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// bearerUsername returns the user of a valid bearer token in the authorization header, or an empty string
+func bearerUsername(tokenMaker token.Maker, authorizationHeader string) string {
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationTypeBearer {
+		return ""
+	}
+
+	payload, err := tokenMaker.VerifyToken(fields[1])
+	if err != nil {
+		return ""
+	}
+
+	return payload.Username
+}
@@ -7,13 +7,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/ratelimit"
 	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
 
-func addAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, authorizationType string, username string, duration time.Duration) {
-	accessToken, _, err := tokenMaker.CreateToken(username, duration)
+func addAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, authorizationType string, username string, role string, duration time.Duration) {
+	accessToken, _, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, accessToken)
@@ -29,7 +32,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -48,7 +51,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "invalid", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "invalid", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -57,7 +60,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -66,7 +69,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -83,7 +86,7 @@ func TestAuthMiddleware(t *testing.T) {
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.revocations, server.apiKeys),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
 				},
@@ -99,3 +102,144 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddlewareDelegatedToken(t *testing.T) {
+	addDelegatedAuthorization := func(t *testing.T, request *http.Request, tokenMaker token.Maker, scopes ...string) {
+		accessToken, _, err := tokenMaker.CreateScopedToken("user", util.DepositorRole, "client", scopes, time.Minute)
+		require.NoError(t, err)
+
+		request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+	}
+
+	testCases := []struct {
+		name          string
+		routeScopes   []string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			routeScopes: []string{token.ScopeAccountsRead},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addDelegatedAuthorization(t, request, tokenMaker, token.ScopeOpenID, token.ScopeAccountsRead)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "MissingScope",
+			routeScopes: []string{token.ScopeTransfersCreate},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addDelegatedAuthorization(t, request, tokenMaker, token.ScopeAccountsRead)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "RouteWithoutScopes",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addDelegatedAuthorization(t, request, tokenMaker, token.OAuthScopes...)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "UserTokenIgnoresScopes",
+			routeScopes: []string{token.ScopeTransfersCreate},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := newTestServer(t, nil)
+			require.NoError(t, err)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.revocations, server.apiKeys, tc.routeScopes...),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+				},
+			)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	server, err := newTestServer(t, nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts/HB5800010123456789", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, metrics.Path, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `housebank_http_requests_total{method="GET",route="/accounts/:number",server="gin",status="401"} 1`)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	policies, err := ratelimit.ParsePolicies("POST /users/login=ip:1/m")
+	require.NoError(t, err)
+
+	config := util.Config{
+		TOKEN_SYMMETRIC_KEY:   util.RandomString(32),
+		ACCESS_TOKEN_DURATION: time.Minute,
+	}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policies)
+	server, err := NewServer(nil, config, token.NewMemoryRevocationList(), nil, limiter)
+	require.NoError(t, err)
+
+	// the body is invalid, the limit is checked before the handler runs
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get(ratelimit.HeaderLimit))
+	require.Equal(t, "0", recorder.Header().Get(ratelimit.HeaderRemaining))
+
+	recorder = httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get(ratelimit.HeaderRetryAfter))
+
+	res := requireBodyError(t, recorder.Body)
+	require.Equal(t, "resource_exhausted", res.Error.Code)
+
+	// routes without a policy are not limited
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/users", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Empty(t, recorder.Header().Get(ratelimit.HeaderLimit))
+}
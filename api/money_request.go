@@ -0,0 +1,327 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// defaultMoneyRequestExpiry applies when a request is made without an expiry
+	defaultMoneyRequestExpiry = 7 * 24 * time.Hour
+	// maxMoneyRequestExpiry keeps requests from waiting on their payer forever
+	maxMoneyRequestExpiry = 90 * 24 * time.Hour
+)
+
+type moneyRequestResponse struct {
+	ID              int64      `json:"id"`
+	Requester       string     `json:"requester"`
+	Payer           string     `json:"payer"`
+	ToAccountNumber string     `json:"to_account_number"`
+	Amount          int64      `json:"amount"`
+	AmountFormatted string     `json:"amount_formatted"`
+	Currency        string     `json:"currency"`
+	Memo            string     `json:"memo,omitempty"`
+	Status          string     `json:"status"`
+	TransferID      *int64     `json:"transfer_id"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	DecidedAt       *time.Time `json:"decided_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func newMoneyRequestResponse(request db.MoneyRequest, numbers map[int64]string) moneyRequestResponse {
+	res := moneyRequestResponse{
+		ID:              request.ID,
+		Requester:       request.Requester,
+		Payer:           request.Payer,
+		ToAccountNumber: numbers[request.ToAccountID],
+		Amount:          request.Amount,
+		AmountFormatted: request.Money().Decimal(),
+		Currency:        request.Currency,
+		Memo:            request.Memo,
+		Status:          request.Status,
+		ExpiresAt:       request.ExpiresAt,
+		DecidedAt:       timeOrNil(request.DecidedAt),
+		CreatedAt:       request.CreatedAt,
+	}
+
+	if request.TransferID.Valid {
+		res.TransferID = &request.TransferID.Int64
+	}
+
+	return res
+}
+
+// newMoneyRequestResponses looks up the numbers of the accounts the requests are paid into
+func (server *Server) newMoneyRequestResponses(ctx *gin.Context, requests []db.MoneyRequest) ([]moneyRequestResponse, bool) {
+	ids := make([]int64, 0, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ToAccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return nil, false
+	}
+
+	res := make([]moneyRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		res = append(res, newMoneyRequestResponse(request, numbers))
+	}
+
+	return res, true
+}
+
+type createMoneyRequestRequest struct {
+	Payer string `json:"payer" binding:"required,alphanum"`
+	// ToAccountNumber is the caller's account the money is paid into
+	ToAccountNumber string `json:"to_account_number" binding:"required,account_number"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Currency        string `json:"currency" binding:"required,currency"`
+	Memo            string `json:"memo" binding:"max=200"`
+	// ExpiresAt defaults to a week from now
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// createMoneyRequest asks another user for money, who may pay it from an account of their choice until it expires
+func (server *Server) createMoneyRequest(ctx *gin.Context) {
+	var req createMoneyRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if req.Payer == authPayload.Username {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("money cannot be requested from oneself"))
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(defaultMoneyRequestExpiry)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+
+	if !expiresAt.After(now) || expiresAt.After(now.Add(maxMoneyRequestExpiry)) {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("expires_at must be in the next %d days", int(maxMoneyRequestExpiry.Hours()/24)))
+		return
+	}
+
+	account, ok := server.getHeldAccount(ctx, req.ToAccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+
+	if account.Account.Currency != req.Currency {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w: %s vs %s", account.Account.Number, money.ErrCurrencyMismatch, account.Account.Currency, req.Currency))
+		return
+	}
+
+	request, err := server.store.CreateMoneyRequestTx(ctx, db.CreateMoneyRequestParams{
+		Requester:   authPayload.Username,
+		Payer:       req.Payer,
+		ToAccountID: account.Account.ID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Memo:        req.Memo,
+		ExpiresAt:   expiresAt,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("user: %s not found", req.Payer))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newMoneyRequestResponse(request, numbersOf(account.Account)))
+}
+
+type listMoneyRequestsRequestQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listMoneyRequests lists the requests the caller made, newest first
+func (server *Server) listMoneyRequests(ctx *gin.Context) {
+	var queryReq listMoneyRequestsRequestQuery
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	requests, err := server.store.ListRequesterMoneyRequests(ctx, db.ListRequesterMoneyRequestsParams{
+		Requester: authPayload.Username,
+		Limit:     queryReq.PageSize,
+		Offset:    (queryReq.PageID - 1) * queryReq.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res, ok := server.newMoneyRequestResponses(ctx, requests)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// listIncomingMoneyRequests lists the requests the caller may still pay or decline
+func (server *Server) listIncomingMoneyRequests(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	requests, err := server.store.ListPendingMoneyRequests(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res, ok := server.newMoneyRequestResponses(ctx, requests)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type moneyRequestRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type approveMoneyRequestRequest struct {
+	FromAccountNumber string `json:"from_account_number" binding:"required,account_number"`
+}
+
+type decideMoneyRequestResponse struct {
+	Request  moneyRequestResponse   `json:"request"`
+	Transfer *transferMoneyResponse `json:"transfer,omitempty"`
+	// Approval is the payment of a payer under guardianship waiting for a guardian
+	Approval *childTransferApprovalResponse `json:"approval,omitempty"`
+}
+
+// approveMoneyRequest pays the request from the caller's account of their choice
+func (server *Server) approveMoneyRequest(ctx *gin.Context) {
+	var uriReq moneyRequestRequestUri
+	var req approveMoneyRequestRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	from, ok := server.getHeldAccount(ctx, req.FromAccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+
+	server.decideMoneyRequest(ctx, db.DecideMoneyRequestTxParams{
+		ID:            uriReq.ID,
+		FromAccountID: from.Account.ID,
+		Approve:       true,
+	})
+}
+
+func (server *Server) declineMoneyRequest(ctx *gin.Context) {
+	var uriReq moneyRequestRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	server.decideMoneyRequest(ctx, db.DecideMoneyRequestTxParams{ID: uriReq.ID})
+}
+
+// decideMoneyRequest pays or declines a request made to the caller
+func (server *Server) decideMoneyRequest(ctx *gin.Context, arg db.DecideMoneyRequestTxParams) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg.Payer = authPayload.Username
+	arg.Now = time.Now()
+
+	result, err := server.store.DecideMoneyRequestTx(ctx, arg)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("money request: [%d] not found", arg.ID))
+		case errors.Is(err, db.ErrNotMoneyRequestPayer):
+			errorResponse(ctx, http.StatusForbidden, err)
+		case errors.Is(err, db.ErrAlreadyDecided), errors.Is(err, db.ErrMoneyRequestExpired):
+			errorResponse(ctx, http.StatusConflict, err)
+		case errors.Is(err, db.ErrInsufficientBalance), isMoneyError(err):
+			errorResponse(ctx, http.StatusBadRequest, err)
+		case errors.As(err, &pgErr) && pgErr.Code == "23505": // unique_violation, a pending approval of the payment
+			errorResponse(ctx, http.StatusConflict, fmt.Errorf("money request: [%d] is waiting for a guardian", arg.ID))
+		default:
+			errorResponse(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// the request was declined, it cannot be paid within the controls of the payer's guardians
+	if result.Blocked != "" {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("payment blocked: %s", result.Blocked))
+		return
+	}
+
+	ids := []int64{result.Request.ToAccountID}
+	if result.Approval != nil {
+		ids = append(ids, result.Approval.FromAccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := decideMoneyRequestResponse{
+		Request: newMoneyRequestResponse(result.Request, numbers),
+	}
+
+	if result.Transfer != nil {
+		transfer := newTransferMoneyResponse(*result.Transfer)
+		res.Transfer = &transfer
+	}
+
+	status := http.StatusOK
+	if result.Approval != nil {
+		// nothing moved yet, the request stays pending until a guardian pays or declines it
+		approval := newChildTransferApprovalResponse(*result.Approval, numbers)
+		res.Approval = &approval
+		status = http.StatusAccepted
+	}
+
+	ctx.JSON(status, res)
+}
@@ -0,0 +1,410 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMoneyRequestAPI(t *testing.T) {
+	requester, _ := randomUser()
+	payer, _ := randomUser()
+
+	requesterAccount := randomAccount(requester.Username)
+	requesterAccount.Currency = util.USD
+
+	payerAccount := randomAccount(payer.Username)
+	payerAccount.ID = requesterAccount.ID + 1
+	payerAccount.Currency = util.USD
+
+	request := db.MoneyRequest{
+		ID:          1,
+		Requester:   requester.Username,
+		Payer:       payer.Username,
+		ToAccountID: requesterAccount.ID,
+		Amount:      2500,
+		Currency:    util.USD,
+		Memo:        "concert tickets",
+		Status:      db.MoneyRequestPending,
+		ExpiresAt:   time.Now().Add(defaultMoneyRequestExpiry),
+		CreatedAt:   time.Now(),
+	}
+
+	paidRequest := request
+	paidRequest.Status = db.MoneyRequestPaid
+	paidRequest.TransferID = pgtype.Int8{Int64: 1, Valid: true}
+	paidRequest.DecidedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	declinedRequest := request
+	declinedRequest.Status = db.MoneyRequestDeclined
+	declinedRequest.DecidedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	transfer := db.TransfeMoneyTxResult{
+		Transfer:    &db.Transfer{ID: 1, FromAccountID: payerAccount.ID, ToAccountID: requesterAccount.ID, Amount: request.Amount},
+		FromAccount: &payerAccount,
+		ToAccount:   &requesterAccount,
+		FromEntry:   &db.Entry{ID: 1, AccountID: payerAccount.ID, Amount: -request.Amount},
+		ToEntry:     &db.Entry{ID: 2, AccountID: requesterAccount.ID, Amount: request.Amount},
+	}
+
+	holdsAccount := func(store *mockDB.MockStore, account db.Account, username string) {
+		store.EXPECT().
+			GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: username, Number: account.Number})).
+			Times(1).
+			Return(heldAccount(account, username), nil)
+	}
+
+	listsRequesterNumber := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{requesterAccount.ID})).
+			Times(1).
+			Return([]db.ListAccountNumbersRow{{ID: requesterAccount.ID, Number: requesterAccount.Number}}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Create",
+			method:   http.MethodPost,
+			url:      "/money_requests",
+			body:     gin.H{"payer": payer.Username, "to_account_number": requesterAccount.Number, "amount": request.Amount, "currency": util.USD, "memo": request.Memo},
+			username: requester.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, requesterAccount, requester.Username)
+				store.EXPECT().
+					CreateMoneyRequestTx(gomock.Any(), gomock.Cond(func(arg db.CreateMoneyRequestParams) bool {
+						return arg.Requester == requester.Username && arg.Payer == payer.Username && arg.ToAccountID == requesterAccount.ID &&
+							arg.Amount == request.Amount && arg.Memo == request.Memo &&
+							time.Until(arg.ExpiresAt) > defaultMoneyRequestExpiry-time.Minute
+					})).
+					Times(1).
+					Return(request, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"25.00"`)
+			},
+		},
+		{
+			name:     "CreateFromOneself",
+			method:   http.MethodPost,
+			url:      "/money_requests",
+			body:     gin.H{"payer": requester.Username, "to_account_number": requesterAccount.Number, "amount": request.Amount, "currency": util.USD},
+			username: requester.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateMoneyRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateExpired",
+			method: http.MethodPost,
+			url:    "/money_requests",
+			body: gin.H{
+				"payer":             payer.Username,
+				"to_account_number": requesterAccount.Number,
+				"amount":            request.Amount,
+				"currency":          util.USD,
+				"expires_at":        time.Now().Add(-time.Hour),
+			},
+			username: requester.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateMoneyRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "CreatePayerNotFound",
+			method:   http.MethodPost,
+			url:      "/money_requests",
+			body:     gin.H{"payer": payer.Username, "to_account_number": requesterAccount.Number, "amount": request.Amount, "currency": util.USD},
+			username: requester.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, requesterAccount, requester.Username)
+				store.EXPECT().
+					CreateMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MoneyRequest{}, &pgconn.PgError{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "ListOutgoing",
+			method:   http.MethodGet,
+			url:      "/money_requests?page_id=1&page_size=5",
+			username: requester.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListRequesterMoneyRequests(gomock.Any(), gomock.Eq(db.ListRequesterMoneyRequestsParams{Requester: requester.Username, Limit: 5, Offset: 0})).
+					Times(1).
+					Return([]db.MoneyRequest{request}, nil)
+				listsRequesterNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "ListIncoming",
+			method:   http.MethodGet,
+			url:      "/money_requests/incoming",
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListPendingMoneyRequests(gomock.Any(), gomock.Eq(payer.Username)).
+					Times(1).
+					Return([]db.MoneyRequest{request}, nil)
+				listsRequesterNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"requester":"%s"`, requester.Username))
+			},
+		},
+		{
+			name:     "Approve",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Cond(func(arg db.DecideMoneyRequestTxParams) bool {
+						return arg.ID == request.ID && arg.Payer == payer.Username && arg.FromAccountID == payerAccount.ID && arg.Approve
+					})).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{Request: paidRequest, Transfer: &transfer}, nil)
+				listsRequesterNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"status":"paid"`)
+			},
+		},
+		{
+			name:     "ApproveByChildNeedsApproval",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				approval := db.ChildTransferApproval{
+					ID:             1,
+					Child:          payer.Username,
+					FromAccountID:  payerAccount.ID,
+					ToAccountID:    requesterAccount.ID,
+					Amount:         request.Amount,
+					Currency:       util.USD,
+					Reason:         "payee is not on the allowlist",
+					Status:         db.ChildTransferPending,
+					MoneyRequestID: pgtype.Int8{Int64: request.ID, Valid: true},
+				}
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{Request: request, Approval: &approval}, nil)
+				store.EXPECT().
+					ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{requesterAccount.ID, payerAccount.ID})).
+					Times(1).
+					Return([]db.ListAccountNumbersRow{
+						{ID: requesterAccount.ID, Number: requesterAccount.Number},
+						{ID: payerAccount.ID, Number: payerAccount.Number},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"reason":"payee is not on the allowlist"`)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"from_account_number":"%s"`, payerAccount.Number))
+				require.NotContains(t, recorder.Body.String(), `"transfer":`)
+			},
+		},
+		{
+			name:     "ApproveByChildBlocked",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{Request: declinedRequest, Blocked: "over the per-transaction limit of 1000"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "per-transaction limit")
+			},
+		},
+		{
+			name:     "ApproveWaitingForGuardian",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{}, &pgconn.PgError{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveFromOthersAccount",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": requesterAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, requesterAccount, payer.Username)
+				store.EXPECT().DecideMoneyRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveInsufficientBalance",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveExpired",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/approve", request.ID),
+			body:     gin.H{"from_account_number": payerAccount.Number},
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				holdsAccount(store, payerAccount, payer.Username)
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{}, db.ErrMoneyRequestExpired)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "Decline",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/decline", request.ID),
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Cond(func(arg db.DecideMoneyRequestTxParams) bool {
+						return arg.ID == request.ID && arg.Payer == payer.Username && !arg.Approve
+					})).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{Request: declinedRequest}, nil)
+				listsRequesterNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"transfer":`)
+			},
+		},
+		{
+			name:     "DeclineNotPayer",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/decline", request.ID),
+			username: requester.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{}, db.ErrNotMoneyRequestPayer)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "DeclineNotFound",
+			method:   http.MethodPost,
+			url:      fmt.Sprintf("/money_requests/%d/decline", request.ID),
+			username: payer.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					DecideMoneyRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideMoneyRequestTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, requester.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type notificationResponse struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newNotificationResponse(notification db.Notification) notificationResponse {
+	return notificationResponse{
+		ID:        notification.ID,
+		Kind:      notification.Kind,
+		Payload:   notification.Payload,
+		ReadAt:    timeOrNil(notification.ReadAt),
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+type listNotificationsRequestQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listNotifications lists the caller's notifications, newest first
+func (server *Server) listNotifications(ctx *gin.Context) {
+	var queryReq listNotificationsRequestQuery
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	notifications, err := server.store.ListNotifications(ctx, db.ListNotificationsParams{
+		Username: authPayload.Username,
+		Limit:    queryReq.PageSize,
+		Offset:   (queryReq.PageID - 1) * queryReq.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]notificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		res = append(res, newNotificationResponse(notification))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type markNotificationReadRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) markNotificationRead(ctx *gin.Context) {
+	var uriReq markNotificationReadRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	notification, err := server.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:       uriReq.ID,
+		Username: authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("notification: [%d] not found", uriReq.ID))
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newNotificationResponse(notification))
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotificationAPI(t *testing.T) {
+	user, _ := randomUser()
+
+	notification := db.Notification{
+		ID:        1,
+		Username:  user.Username,
+		Kind:      db.NotificationChildTransferBlocked,
+		Payload:   []byte(`{"child":"kid","amount":500}`),
+		CreatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/notifications?page_id=2&page_size=5",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					ListNotifications(gomock.Any(), gomock.Eq(db.ListNotificationsParams{Username: user.Username, Limit: 5, Offset: 5})).
+					Times(1).
+					Return([]db.Notification{notification}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"payload":{"child":"kid","amount":500}`)
+				require.Contains(t, recorder.Body.String(), `"read_at":null`)
+			},
+		},
+		{
+			name:   "MarkRead",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/notifications/%d/read", notification.ID),
+			buildStubs: func(store *mockDB.MockStore) {
+				read := notification
+				read.ReadAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+				store.EXPECT().
+					MarkNotificationRead(gomock.Any(), gomock.Eq(db.MarkNotificationReadParams{ID: notification.ID, Username: user.Username})).
+					Times(1).
+					Return(read, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"read_at":null`)
+			},
+		},
+		{
+			name:   "MarkReadOfOtherUser",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/notifications/%d/read", notification.ID+1),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(1).Return(db.Notification{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// aliasDomain turns a username into an alias, e.g. alice@housebank
+const aliasDomain = "@housebank"
+
+var errInvalidAlias = errors.New("alias must be a username followed by " + aliasDomain + ", or an email")
+
+// parseAlias returns the username or the email the alias stands for
+func parseAlias(alias string) (username string, email string, err error) {
+	alias = strings.TrimSpace(alias)
+
+	if len(alias) > len(aliasDomain) && strings.EqualFold(alias[len(alias)-len(aliasDomain):], aliasDomain) {
+		username = alias[:len(alias)-len(aliasDomain)]
+		for _, r := range username {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+				return "", "", fmt.Errorf("%w: %s", errInvalidAlias, alias)
+			}
+		}
+		return username, "", nil
+	}
+
+	address, err := mail.ParseAddress(alias)
+	if err != nil || address.Address != alias {
+		return "", "", fmt.Errorf("%w: %s", errInvalidAlias, alias)
+	}
+
+	return "", address.Address, nil
+}
+
+// resolveAlias finds the user behind the alias and the account payments to it are made into in the currency.
+// It aborts the request when there is none, without telling whether the alias or the account is missing.
+func (server *Server) resolveAlias(ctx *gin.Context, alias string, currency string) (db.User, db.Account, bool) {
+	var user db.User
+	var account db.Account
+
+	username, email, err := parseAlias(alias)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return user, account, false
+	}
+
+	notFound := fmt.Errorf("alias: %s has no account in %s", alias, currency)
+
+	if email != "" {
+		user, err = server.store.GetUserByEmail(ctx, email)
+		// an unverified email could belong to anyone
+		if err == nil && !user.EmailVerifiedAt.Valid {
+			err = sql.ErrNoRows
+		}
+	} else {
+		user, err = server.store.GetUserByUsername(ctx, username)
+	}
+
+	if err == nil {
+		account, err = server.store.GetDefaultAccount(ctx, db.GetDefaultAccountParams{
+			Owner:    user.Username,
+			Currency: currency,
+		})
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, notFound)
+			return user, account, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return user, account, false
+	}
+
+	return user, account, true
+}
+
+type resolveAliasRequestQuery struct {
+	Alias    string `form:"alias" binding:"required,max=254"`
+	Currency string `form:"currency" binding:"required,currency"`
+}
+
+// aliasResponse tells the caller who they are about to pay, without the number of their account
+type aliasResponse struct {
+	Alias      string `json:"alias"`
+	HolderName string `json:"holder_name"`
+	Currency   string `json:"currency"`
+}
+
+// resolvePaymentAlias responds with the name of the holder of the account behind the alias, to be confirmed before paying it
+func (server *Server) resolvePaymentAlias(ctx *gin.Context) {
+	var queryReq resolveAliasRequestQuery
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	user, account, ok := server.resolveAlias(ctx, queryReq.Alias, queryReq.Currency)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, aliasResponse{
+		Alias:      queryReq.Alias,
+		HolderName: user.FullName,
+		Currency:   account.Currency,
+	})
+}
+
+// payeeResponse leaves out the account number of a payee added by alias, which the alias is meant to keep private
+type payeeResponse struct {
+	ID            int64     `json:"id"`
+	Nickname      string    `json:"nickname"`
+	AccountNumber string    `json:"account_number,omitempty"`
+	VerifiedName  string    `json:"verified_name"`
+	Alias         string    `json:"alias,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newPayeeResponse(payee db.Payee, numbers map[int64]string) payeeResponse {
+	res := payeeResponse{
+		ID:           payee.ID,
+		Nickname:     payee.Nickname,
+		VerifiedName: payee.VerifiedName,
+		Alias:        payee.Alias,
+		CreatedAt:    payee.CreatedAt,
+	}
+
+	if payee.Alias == "" {
+		res.AccountNumber = numbers[payee.AccountID]
+	}
+
+	return res
+}
+
+// payeeAccountIDs lists the accounts of the payees whose number is shown, those added by account number
+func payeeAccountIDs(payees ...db.Payee) []int64 {
+	ids := make([]int64, 0, len(payees))
+	for _, payee := range payees {
+		if payee.Alias == "" {
+			ids = append(ids, payee.AccountID)
+		}
+	}
+	return ids
+}
+
+type createPayeeRequest struct {
+	Nickname string `json:"nickname" binding:"required,min=1,max=50"`
+	// the payee is either an account number, or an alias resolved to an account in the currency
+	AccountNumber string `json:"account_number" binding:"omitempty,account_number"`
+	Alias         string `json:"alias" binding:"omitempty,max=254"`
+	Currency      string `json:"currency" binding:"omitempty,currency"`
+}
+
+// createPayee adds an account to the caller's payee book, along with the name of its holder
+func (server *Server) createPayee(ctx *gin.Context) {
+	var req createPayeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if (req.AccountNumber == "") == (req.Alias == "") {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("either account_number or alias is required"))
+		return
+	}
+
+	var holder db.User
+	var account db.Account
+
+	if req.Alias != "" {
+		if req.Currency == "" {
+			errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("currency is required to resolve an alias"))
+			return
+		}
+
+		var ok bool
+		holder, account, ok = server.resolveAlias(ctx, req.Alias, req.Currency)
+		if !ok {
+			return
+		}
+	} else {
+		var err error
+		account, err = server.store.GetAccountByNumber(ctx, req.AccountNumber)
+		if err == nil {
+			holder, err = server.store.GetUserByUsername(ctx, account.Owner)
+		}
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("account: %s not found", req.AccountNumber))
+				return
+			}
+			errorResponse(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.store.CreatePayee(ctx, db.CreatePayeeParams{
+		Owner:        authPayload.Username,
+		Nickname:     req.Nickname,
+		AccountID:    account.ID,
+		VerifiedName: holder.FullName,
+		Alias:        req.Alias,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505": // unique_violation
+				errorResponse(ctx, http.StatusConflict, fmt.Errorf("payee: %s already in the payee book", req.Nickname))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newPayeeResponse(payee, numbersOf(account)))
+}
+
+// listPayees lists the caller's payee book by nickname
+func (server *Server) listPayees(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payees, err := server.store.ListPayees(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, payeeAccountIDs(payees...)...)
+	if !ok {
+		return
+	}
+
+	res := make([]payeeResponse, 0, len(payees))
+	for _, payee := range payees {
+		res = append(res, newPayeeResponse(payee, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type payeeRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) deletePayee(ctx *gin.Context) {
+	var uriReq payeeRequestUri
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.store.DeletePayee(ctx, db.DeletePayeeParams{
+		ID:    uriReq.ID,
+		Owner: authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("payee: [%d] not found", uriReq.ID))
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	numbers, ok := server.accountNumbers(ctx, payeeAccountIDs(payee)...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPayeeResponse(payee, numbers))
+}
@@ -0,0 +1,363 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseAlias(t *testing.T) {
+	testCases := []struct {
+		alias    string
+		username string
+		email    string
+		valid    bool
+	}{
+		{alias: "alice@housebank", username: "alice", valid: true},
+		{alias: "Alice42@HouseBank", username: "Alice42", valid: true},
+		{alias: "alice@example.com", email: "alice@example.com", valid: true},
+		{alias: "@housebank"},
+		{alias: "al.ice@housebank"},
+		{alias: "alice"},
+		{alias: "Alice <alice@example.com>"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.alias, func(t *testing.T) {
+			username, email, err := parseAlias(tc.alias)
+			if !tc.valid {
+				require.ErrorIs(t, err, errInvalidAlias)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.username, username)
+			require.Equal(t, tc.email, email)
+		})
+	}
+}
+
+func TestPayeeAPI(t *testing.T) {
+	user, _ := randomUser()
+	recipient, _ := randomUser()
+	recipient.EmailVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	unverified, _ := randomUser()
+
+	account := randomAccount(user.Username)
+
+	recipientAccount := randomAccount(recipient.Username)
+	recipientAccount.ID = account.ID + 1
+	recipientAccount.Currency = util.USD
+
+	payee := db.Payee{
+		ID:           1,
+		Owner:        user.Username,
+		Nickname:     "landlord",
+		AccountID:    recipientAccount.ID,
+		VerifiedName: recipient.FullName,
+		Alias:        recipient.Username + aliasDomain,
+		CreatedAt:    time.Now(),
+	}
+
+	numberedPayee := payee
+	numberedPayee.ID = 2
+	numberedPayee.Nickname = "plumber"
+	numberedPayee.Alias = ""
+
+	listsRecipientNumber := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{recipientAccount.ID})).
+			Times(1).
+			Return([]db.ListAccountNumbersRow{{ID: recipientAccount.ID, Number: recipientAccount.Number}}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "ResolveUsername",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/payees/resolve?alias=%s@housebank&currency=%s", recipient.Username, util.USD),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().
+					GetDefaultAccount(gomock.Any(), gomock.Eq(db.GetDefaultAccountParams{Owner: recipient.Username, Currency: util.USD})).
+					Times(1).
+					Return(recipientAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"holder_name":"%s"`, recipient.FullName))
+				require.NotContains(t, recorder.Body.String(), "account_id")
+			},
+		},
+		{
+			name:   "ResolveEmail",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/payees/resolve?alias=%s&currency=%s", recipient.Email, util.USD),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(recipient.Email)).Times(1).Return(recipient, nil)
+				store.EXPECT().GetDefaultAccount(gomock.Any(), gomock.Any()).Times(1).Return(recipientAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ResolveUnverifiedEmail",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/payees/resolve?alias=%s&currency=%s", unverified.Email, util.USD),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(unverified.Email)).Times(1).Return(unverified, nil)
+				store.EXPECT().GetDefaultAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "ResolveNoAccountInCurrency",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/payees/resolve?alias=%s@housebank&currency=%s", recipient.Username, util.INR),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().GetDefaultAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "ResolveInvalidAlias",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/payees/resolve?alias=%s&currency=%s", recipient.Username, util.USD),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateByAlias",
+			method: http.MethodPost,
+			url:    "/payees",
+			body:   gin.H{"nickname": payee.Nickname, "alias": payee.Alias, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().GetDefaultAccount(gomock.Any(), gomock.Any()).Times(1).Return(recipientAccount, nil)
+				store.EXPECT().
+					CreatePayee(gomock.Any(), gomock.Eq(db.CreatePayeeParams{
+						Owner:        user.Username,
+						Nickname:     payee.Nickname,
+						AccountID:    recipientAccount.ID,
+						VerifiedName: recipient.FullName,
+						Alias:        payee.Alias,
+					})).
+					Times(1).
+					Return(payee, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"verified_name":"%s"`, recipient.FullName))
+				require.NotContains(t, recorder.Body.String(), recipientAccount.Number)
+			},
+		},
+		{
+			name:   "CreateByAccountNumber",
+			method: http.MethodPost,
+			url:    "/payees",
+			body:   gin.H{"nickname": payee.Nickname, "account_number": recipientAccount.Number},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(recipientAccount.Number)).Times(1).Return(recipientAccount, nil)
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().
+					CreatePayee(gomock.Any(), gomock.Eq(db.CreatePayeeParams{
+						Owner:        user.Username,
+						Nickname:     payee.Nickname,
+						AccountID:    recipientAccount.ID,
+						VerifiedName: recipient.FullName,
+					})).
+					Times(1).
+					Return(numberedPayee, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"account_number":%q`, recipientAccount.Number))
+			},
+		},
+		{
+			name:   "CreateAccountNotFound",
+			method: http.MethodPost,
+			url:    "/payees",
+			body:   gin.H{"nickname": payee.Nickname, "account_number": recipientAccount.Number},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(recipientAccount.Number)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateBothDestinations",
+			method: http.MethodPost,
+			url:    "/payees",
+			body:   gin.H{"nickname": payee.Nickname, "account_number": recipientAccount.Number, "alias": payee.Alias, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateAliasWithoutCurrency",
+			method: http.MethodPost,
+			url:    "/payees",
+			body:   gin.H{"nickname": payee.Nickname, "alias": payee.Alias},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateNicknameTaken",
+			method: http.MethodPost,
+			url:    "/payees",
+			body:   gin.H{"nickname": payee.Nickname, "account_number": recipientAccount.Number},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(1).Return(recipientAccount, nil)
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Any()).Times(1).Return(recipient, nil)
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(1).Return(db.Payee{}, &pgconn.PgError{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/payees",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().ListPayees(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.Payee{payee, numberedPayee}, nil)
+				listsRecipientNumber(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"nickname":"landlord"`)
+				// only the payee added by account number shows it
+				require.Equal(t, 1, strings.Count(recorder.Body.String(), recipientAccount.Number))
+			},
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/payees/%d", payee.ID),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					DeletePayee(gomock.Any(), gomock.Eq(db.DeletePayeeParams{ID: payee.ID, Owner: user.Username})).
+					Times(1).
+					Return(payee, nil)
+				store.EXPECT().ListAccountNumbers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"account_number"`)
+			},
+		},
+		{
+			name:   "DeleteNotFound",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/payees/%d", payee.ID),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().DeletePayee(gomock.Any(), gomock.Any()).Times(1).Return(db.Payee{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "SetDefaultAccount",
+			method: http.MethodPut,
+			url:    fmt.Sprintf("/accounts/%s/default", account.Number),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: account.Number})).
+					Times(1).
+					Return(heldAccount(account, user.Username), nil)
+				store.EXPECT().
+					SetDefaultAccount(gomock.Any(), gomock.Eq(db.SetDefaultAccountParams{Owner: user.Username, Currency: account.Currency, AccountID: account.ID})).
+					Times(1).
+					Return(db.DefaultAccount{Owner: user.Username, Currency: account.Currency, AccountID: account.ID, UpdatedAt: time.Now()}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "SetDefaultAccountNotHeld",
+			method: http.MethodPut,
+			url:    fmt.Sprintf("/accounts/%s/default", recipientAccount.Number),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					GetAccountForHolder(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetAccountForHolderRow{Account: recipientAccount}, nil)
+				store.EXPECT().SetDefaultAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,36 +2,51 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/ratelimit"
 	"github.com/AnkitNayan83/houseBank/token"
 	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/AnkitNayan83/houseBank/workers"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
 type Server struct {
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
-	config     util.Config
+	store       db.Store
+	tokenMaker  token.Maker
+	router      *gin.Engine
+	config      util.Config
+	revocations token.RevocationList
+	apiKeys     token.APIKeyVerifier
+	tasks       workers.TaskInspector
+	limiter     *ratelimit.Limiter
 }
 
-func NewServer(store db.Store, config util.Config) (*Server, error) {
+// NewServer creates the gin server, requests are not rate limited when limiter is nil
+func NewServer(store db.Store, config util.Config, revocations token.RevocationList, tasks workers.TaskInspector, limiter *ratelimit.Limiter) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TOKEN_SYMMETRIC_KEY)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %v", err)
 	}
 	server := &Server{
-		store:      store,
-		tokenMaker: tokenMaker,
-		config:     config,
+		store:       store,
+		tokenMaker:  tokenMaker,
+		config:      config,
+		revocations: revocations,
+		apiKeys:     token.NewStoreAPIKeyVerifier(store),
+		tasks:       tasks,
+		limiter:     limiter,
 	}
 
 	// use custom validator
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("account_number", validAccountNumber)
+		v.RegisterTagNameFunc(requestFieldName)
 	}
 
 	server.setupServerRoutes()
@@ -39,16 +54,19 @@ func NewServer(store db.Store, config util.Config) (*Server, error) {
 	return server, nil
 }
 
-func (server *Server) Start(address string) error {
-	return server.router.Run(address)
-}
-
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// Handler exposes the routes so they can be served by an http.Server which supports graceful shutdown
+func (server *Server) Handler() http.Handler {
+	return server.router
 }
 
 func (server *Server) setupServerRoutes() {
 	router := gin.Default()
+	router.Use(requestIDMiddleware(), metricsMiddleware(), tracingMiddleware())
+	if server.limiter != nil {
+		router.Use(rateLimitMiddleware(server.limiter, server.tokenMaker))
+	}
+
+	router.GET(metrics.Path, gin.WrapH(metrics.Handler()))
 
 	// users routes
 	router.POST("/users", server.createUser)
@@ -57,17 +75,115 @@ func (server *Server) setupServerRoutes() {
 	// token routes
 	router.POST("/tokens/renew_access", server.renewToken)
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations, server.apiKeys))
+
+	// routes which can also be used by machine clients with an api key holding the scope
+	readAccountsRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations, server.apiKeys, token.ScopeAccountsRead))
+	readTransfersRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations, server.apiKeys, token.ScopeTransfersRead))
+	createTransfersRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations, server.apiKeys, token.ScopeTransfersCreate))
+
+	// token revocation routes
+	authRoutes.POST("/tokens/revoke", server.revokeToken)
+	authRoutes.POST("/users/:username/tokens/revoke", server.revokeUserTokens)
+
+	// api keys routes
+	authRoutes.POST("/api_keys", server.createApiKey)
+	authRoutes.GET("/api_keys", server.listApiKeys)
+	authRoutes.DELETE("/api_keys/:id", server.revokeApiKey)
 
 	// accounts routes
 	authRoutes.POST("/accounts", server.createAccount)
-	authRoutes.GET("/accounts/:id", server.getAccountById)
-	authRoutes.GET("/accounts", server.getAccounts)
-	authRoutes.PATCH("/accounts/:id", server.updateAccountBalance)
-	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
+	readAccountsRoutes.GET("/accounts/:number", server.getAccountById)
+	readAccountsRoutes.GET("/accounts", server.getAccounts)
+	authRoutes.PATCH("/accounts/:number", server.updateAccountBalance)
+	authRoutes.DELETE("/accounts/:number", server.deleteAccount)
+	authRoutes.POST("/accounts/:number/holders", server.inviteAccountHolder)
+	readAccountsRoutes.GET("/accounts/:number/holders", server.listAccountHolders)
+	authRoutes.POST("/accounts/:number/holders/accept", server.acceptAccountHolder)
+	authRoutes.DELETE("/accounts/:number/holders/:username", server.removeAccountHolder)
+	authRoutes.GET("/accounts/:number/audit_events", server.listAccountAuditEvents)
+	authRoutes.PUT("/accounts/:number/default", server.setDefaultAccount)
+	authRoutes.GET("/account_invitations", server.listAccountInvitations)
+
+	// currencies routes
+	authRoutes.GET("/currencies", server.listCurrencies)
 
 	// transactions routes
-	authRoutes.POST("/transfers", server.TransferMoney)
+	createTransfersRoutes.POST("/transfers", server.TransferMoney)
+	createTransfersRoutes.POST("/transfers/own", server.transferBetweenOwnAccounts)
+	readTransfersRoutes.GET("/accounts/:number/transfers", server.listAccountTransfers)
+
+	// payees routes, for the payee book and resolving the aliases of other users before paying them
+	authRoutes.POST("/payees", server.createPayee)
+	authRoutes.GET("/payees", server.listPayees)
+	authRoutes.GET("/payees/resolve", server.resolvePaymentAlias)
+	authRoutes.DELETE("/payees/:id", server.deletePayee)
+
+	// children routes, for guardians to set the limits, payees and allowances of their children
+	authRoutes.POST("/children", server.createChild)
+	authRoutes.GET("/children", server.listChildren)
+	authRoutes.PUT("/children/:username/controls", server.setChildControls)
+	authRoutes.GET("/children/:username/controls", server.getChildControls)
+	authRoutes.POST("/children/:username/payees", server.addChildPayee)
+	authRoutes.GET("/children/:username/payees", server.listChildPayees)
+	authRoutes.DELETE("/children/:username/payees/:account_number", server.removeChildPayee)
+	authRoutes.POST("/children/:username/allowances", server.createAllowance)
+	authRoutes.GET("/children/:username/allowances", server.listChildAllowances)
+	authRoutes.DELETE("/children/:username/allowances/:id", server.deleteAllowance)
+	authRoutes.GET("/child_transfers", server.listChildTransferApprovals)
+	authRoutes.POST("/child_transfers/:id/approve", server.approveChildTransfer)
+	authRoutes.POST("/child_transfers/:id/decline", server.declineChildTransfer)
+
+	// households routes, for groups of users sharing a pot
+	authRoutes.POST("/households", server.createHousehold)
+	authRoutes.GET("/households", server.listHouseholds)
+	authRoutes.GET("/households/:id", server.getHousehold)
+	authRoutes.PUT("/households/:id/spend_rule", server.updateHouseholdSpendRule)
+	authRoutes.POST("/households/:id/members", server.addHouseholdMember)
+	authRoutes.DELETE("/households/:id/members/:username", server.removeHouseholdMember)
+	authRoutes.GET("/households/:id/contributions", server.listHouseholdContributions)
+	authRoutes.POST("/households/:id/spends", server.createHouseholdSpend)
+	authRoutes.GET("/households/:id/spends", server.listHouseholdSpends)
+	authRoutes.POST("/households/:id/spends/:spend_id/votes", server.voteHouseholdSpend)
+
+	// expenses routes, for splitting bills and settling up the shares
+	authRoutes.POST("/expenses", server.createExpense)
+	authRoutes.GET("/expenses", server.listExpenses)
+	authRoutes.GET("/expenses/:id", server.getExpense)
+	authRoutes.POST("/expenses/:id/settle", server.settleExpenseShare)
+	authRoutes.GET("/ious", server.listOutstandingBalances)
+
+	// money requests routes, for asking other users for money
+	authRoutes.POST("/money_requests", server.createMoneyRequest)
+	authRoutes.GET("/money_requests", server.listMoneyRequests)
+	authRoutes.GET("/money_requests/incoming", server.listIncomingMoneyRequests)
+	authRoutes.POST("/money_requests/:id/approve", server.approveMoneyRequest)
+	authRoutes.POST("/money_requests/:id/decline", server.declineMoneyRequest)
+
+	// notifications routes
+	authRoutes.GET("/notifications", server.listNotifications)
+	authRoutes.POST("/notifications/:id/read", server.markNotificationRead)
+
+	// webhooks
+	authRoutes.POST("/webhooks", server.createWebhookEndpoint)
+	authRoutes.GET("/webhooks", server.listWebhookEndpoints)
+	authRoutes.DELETE("/webhooks/:id", server.deleteWebhookEndpoint)
+	authRoutes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	authRoutes.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", server.redeliverWebhook)
+	authRoutes.POST("/webhooks/:id/ping", server.pingWebhookEndpoint)
+
+	bankerRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations, server.apiKeys), bankerMiddleware())
+
+	// background task routes, to inspect and replay failed tasks
+	bankerRoutes.GET("/tasks", server.listFailedTasks)
+	bankerRoutes.GET("/tasks/stats", server.getTaskStats)
+	bankerRoutes.GET("/tasks/:queue/:id", server.getTask)
+	bankerRoutes.POST("/tasks/:queue/:id/replay", server.replayTask)
+	bankerRoutes.DELETE("/tasks/:queue/:id", server.deleteTask)
+
+	// currency registry routes
+	bankerRoutes.POST("/currencies", server.createCurrency)
+	bankerRoutes.PATCH("/currencies/:code", server.updateCurrency)
 
 	server.router = router
 }
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AnkitNayan83/houseBank/workers"
+	"github.com/gin-gonic/gin"
+)
+
+type listFailedTasksRequest struct {
+	Queue    string `form:"queue" binding:"required"`
+	State    string `form:"state" binding:"required,oneof=retry archived"`
+	Type     string `form:"type"`
+	PageID   int    `form:"page_id" binding:"required,min=1"`
+	PageSize int    `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listFailedTasks lists tasks waiting for a retry, or archived after running out of retries
+func (server *Server) listFailedTasks(ctx *gin.Context) {
+	var req listFailedTasksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := server.tasks.ListFailedTasks(req.Queue, req.State, req.Type, req.PageID, req.PageSize)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+type taskRequest struct {
+	Queue string `uri:"queue" binding:"required"`
+	ID    string `uri:"id" binding:"required"`
+}
+
+func (server *Server) getTask(ctx *gin.Context) {
+	var req taskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	task, err := server.tasks.GetTask(req.Queue, req.ID)
+
+	if err != nil {
+		if errors.Is(err, workers.ErrTaskNotFound) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, task)
+}
+
+// replayTask moves a failed task back to pending so the workers pick it up right away
+func (server *Server) replayTask(ctx *gin.Context) {
+	var req taskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	err := server.tasks.RunTask(req.Queue, req.ID)
+
+	if err != nil {
+		if errors.Is(err, workers.ErrTaskNotFound) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task queued for replay"})
+}
+
+func (server *Server) deleteTask(ctx *gin.Context) {
+	var req taskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	err := server.tasks.DeleteTask(req.Queue, req.ID)
+
+	if err != nil {
+		if errors.Is(err, workers.ErrTaskNotFound) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
+}
+
+func (server *Server) getTaskStats(ctx *gin.Context) {
+	stats, err := server.tasks.QueueStats()
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
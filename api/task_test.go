@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/AnkitNayan83/houseBank/workers"
+	mockWorkers "github.com/AnkitNayan83/houseBank/workers/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomFailedTask() workers.TaskInfo {
+	return workers.TaskInfo{
+		ID:        util.RandomString(12),
+		Queue:     workers.QueueueCritical,
+		Type:      workers.TaskSendVerifyEmail,
+		State:     workers.TaskStateArchived,
+		Payload:   []byte(`{"username":"` + util.RandomOwner() + `"}`),
+		MaxRetry:  10,
+		Retried:   10,
+		LastError: "failed to get user",
+	}
+}
+
+func TestTaskAPI(t *testing.T) {
+	task := randomFailedTask()
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		role          string
+		buildStubs    func(tasks *mockWorkers.MockTaskInspector)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "ListArchived",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/tasks?queue=%s&state=archived&type=%s&page_id=1&page_size=5", task.Queue, task.Type),
+			role:   util.BankerRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().
+					ListFailedTasks(gomock.Eq(task.Queue), gomock.Eq(workers.TaskStateArchived), gomock.Eq(task.Type), gomock.Eq(1), gomock.Eq(5)).
+					Times(1).
+					Return([]workers.TaskInfo{task}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), task.ID)
+			},
+		},
+		{
+			name:   "InvalidState",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/tasks?queue=%s&state=pending&page_id=1&page_size=5", task.Queue),
+			role:   util.BankerRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().ListFailedTasks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotBanker",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/tasks?queue=%s&state=archived&page_id=1&page_size=5", task.Queue),
+			role:   util.DepositorRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().ListFailedTasks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Replay",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/tasks/%s/%s/replay", task.Queue, task.ID),
+			role:   util.BankerRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().RunTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ReplayNotFound",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/tasks/%s/%s/replay", task.Queue, task.ID),
+			role:   util.BankerRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().RunTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).Times(1).Return(workers.ErrTaskNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/tasks/%s/%s", task.Queue, task.ID),
+			role:   util.BankerRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().DeleteTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Stats",
+			method: http.MethodGet,
+			url:    "/tasks/stats",
+			role:   util.BankerRole,
+			buildStubs: func(tasks *mockWorkers.MockTaskInspector) {
+				tasks.EXPECT().QueueStats().Times(1).Return([]workers.QueueStats{{Queue: task.Queue, Archived: 1, FailedTotal: 11}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"failed_total":11`)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tasks := mockWorkers.NewMockTaskInspector(ctrl)
+			tc.buildStubs(tasks)
+
+			server, err := newTestServer(t, nil)
+			require.NoError(t, err)
+			server.tasks = tasks
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "banker", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type renewTokenRequest struct {
@@ -22,14 +26,14 @@ type renewTokenResponse struct {
 func (server *Server) renewToken(ctx *gin.Context) {
 	var req renewTokenRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	refreshTokenPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
 
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		errorResponse(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -37,32 +41,44 @@ func (server *Server) renewToken(ctx *gin.Context) {
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("invalid username or password")))
+			errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("invalid username or password"))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	revoked, err := server.revocations.IsRevoked(ctx, refreshTokenPayload)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if revoked {
+		errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("refresh token has been revoked"))
 		return
 	}
 
 	if session.Username != refreshTokenPayload.Username {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("incorrect session user")))
+		errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("incorrect session user"))
 		return
 	}
 
 	if session.RefreshToken != req.RefreshToken {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("mismatched session")))
+		errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("mismatched session"))
 		return
 	}
 
 	if session.IsBlocked {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("blocked session")))
+		errorResponse(ctx, http.StatusUnauthorized, fmt.Errorf("blocked session"))
 		return
 	}
 
-	accessToken, accessTokenPayload, err := server.tokenMaker.CreateToken(session.Username, server.config.ACCESS_TOKEN_DURATION)
+	accessToken, accessTokenPayload, err := server.tokenMaker.CreateToken(session.Username, refreshTokenPayload.Role, server.config.ACCESS_TOKEN_DURATION)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -74,3 +90,124 @@ func (server *Server) renewToken(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 
 }
+
+type revokeTokenRequest struct {
+	Token    string `json:"token"`
+	TokenID  string `json:"token_id" binding:"omitempty,uuid"`
+	Username string `json:"username" binding:"required_with=TokenID"`
+}
+
+// revokeToken revokes a token the user holds, the current access token when no token is given,
+// or for bankers any token by its id
+func (server *Server) revokeToken(ctx *gin.Context) {
+	var req revokeTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var payload *token.Payload
+
+	switch {
+	case req.Token != "":
+		tokenPayload, err := server.tokenMaker.VerifyToken(req.Token)
+		if err != nil {
+			errorResponse(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		if tokenPayload.Username != authPayload.Username && authPayload.Role != util.BankerRole {
+			errorResponse(ctx, http.StatusForbidden, errors.New("token does not belong to the authenticated user"))
+			return
+		}
+
+		payload = tokenPayload
+	case req.TokenID != "":
+		if authPayload.Role != util.BankerRole {
+			errorResponse(ctx, http.StatusForbidden, errors.New("only bankers can revoke a token by id"))
+			return
+		}
+
+		// we do not know when the token expires, so keep it until the longest lived token would have expired
+		payload = &token.Payload{
+			ID:        uuid.MustParse(req.TokenID),
+			Username:  req.Username,
+			ExpiresAt: time.Now().Add(server.config.REFRESH_TOKEN_DURATION),
+		}
+	default:
+		payload = authPayload
+	}
+
+	err := server.revocations.Revoke(ctx, payload, authPayload.Username)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("user %s not found", payload.Username))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// refresh tokens share their id with the session, block it so it cannot be renewed either
+	err = server.store.UpdateSession(ctx, payload.ID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Token revoked successfully"})
+}
+
+type revokeUserTokensRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// revokeUserTokens revokes every token issued to a user so far and blocks all of their sessions
+func (server *Server) revokeUserTokens(ctx *gin.Context) {
+	var req revokeUserTokensRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if req.Username != authPayload.Username && authPayload.Role != util.BankerRole {
+		errorResponse(ctx, http.StatusForbidden, errors.New("user does not have permission to revoke other user's tokens"))
+		return
+	}
+
+	err := server.revocations.RevokeUser(ctx, req.Username, authPayload.Username)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation
+				errorResponse(ctx, http.StatusNotFound, fmt.Errorf("user %s not found", req.Username))
+				return
+			}
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = server.store.BlockUserSessions(ctx, req.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User tokens revoked successfully"})
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRevokeTokenAPI(t *testing.T) {
+	user, _ := randomUser()
+	otherUser, _ := randomUser()
+
+	testCases := []struct {
+		name          string
+		role          string
+		body          func(t *testing.T, tokenMaker token.Maker) gin.H
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "CurrentToken",
+			role: util.DepositorRole,
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{}
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OwnToken",
+			role: util.DepositorRole,
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				refreshToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+				require.NoError(t, err)
+				return gin.H{"token": refreshToken}
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUsersToken",
+			role: util.DepositorRole,
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				otherToken, _, err := tokenMaker.CreateToken(otherUser.Username, otherUser.Role, time.Minute)
+				require.NoError(t, err)
+				return gin.H{"token": otherToken}
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "TokenIDByDepositor",
+			role: util.DepositorRole,
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{"token_id": uuid.NewString(), "username": otherUser.Username}
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "TokenIDByBanker",
+			role: util.BankerRole,
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{"token_id": uuid.NewString(), "username": otherUser.Username}
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "TokenIDWithoutUsername",
+			role: util.BankerRole,
+			body: func(t *testing.T, tokenMaker token.Maker) gin.H {
+				return gin.H{"token_id": uuid.NewString()}
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					UpdateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body(t, server.tokenMaker))
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens/revoke", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokedTokenIsRejected(t *testing.T) {
+	user, _ := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().
+		UpdateSession(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	server, err := newTestServer(t, store)
+	require.NoError(t, err)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken)
+
+	// revoke the token with itself, then try to use it again
+	for _, expectedCode := range []int{http.StatusOK, http.StatusUnauthorized} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/tokens/revoke", bytes.NewReader([]byte("{}")))
+		require.NoError(t, err)
+		request.Header.Set(authorizationHeaderKey, authorizationHeader)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, expectedCode, recorder.Code)
+	}
+}
+
+func TestRevokeUserTokensAPI(t *testing.T) {
+	user, _ := randomUser()
+	otherUser, _ := randomUser()
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Self",
+			username: user.Username,
+			role:     util.DepositorRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherUser",
+			username: otherUser.Username,
+			role:     util.DepositorRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Banker",
+			username: otherUser.Username,
+			role:     util.BankerRole,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(otherUser.Username)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/users/%s/tokens/revoke", tc.username)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -5,82 +5,344 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
 	"github.com/AnkitNayan83/houseBank/token"
 	"github.com/gin-gonic/gin"
 )
 
+type transferResponse struct {
+	ID                int64     `json:"id"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	AmountFormatted   string    `json:"amount_formatted"`
+	Currency          string    `json:"currency"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// newTransferResponse formats the amount with the currency of the accounts, transfers never mix currencies
+func newTransferResponse(transfer db.Transfer, currency string, numbers map[int64]string) transferResponse {
+	return transferResponse{
+		ID:                transfer.ID,
+		FromAccountNumber: numbers[transfer.FromAccountID],
+		ToAccountNumber:   numbers[transfer.ToAccountID],
+		Amount:            transfer.Amount,
+		AmountFormatted:   money.New(transfer.Amount, currency).Decimal(),
+		Currency:          currency,
+		CreatedAt:         transfer.CreatedAt,
+	}
+}
+
+type entryResponse struct {
+	ID              int64     `json:"id"`
+	AccountNumber   string    `json:"account_number"`
+	Amount          int64     `json:"amount"`
+	AmountFormatted string    `json:"amount_formatted"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func newEntryResponse(entry db.Entry, currency string, numbers map[int64]string) entryResponse {
+	return entryResponse{
+		ID:              entry.ID,
+		AccountNumber:   numbers[entry.AccountID],
+		Amount:          entry.Amount,
+		AmountFormatted: money.New(entry.Amount, currency).Decimal(),
+		CreatedAt:       entry.CreatedAt,
+	}
+}
+
+// isMoneyError reports amounts which cannot be added to a balance, because of its currency or size
+func isMoneyError(err error) bool {
+	return errors.Is(err, money.ErrCurrencyMismatch) || errors.Is(err, money.ErrOverflow)
+}
+
+type transferMoneyResponse struct {
+	Transfer    transferResponse `json:"transfer"`
+	FromAccount accountResponse  `json:"from_account"`
+	ToAccount   accountResponse  `json:"to_account"`
+	FromEntry   entryResponse    `json:"from_entry"`
+	ToEntry     entryResponse    `json:"to_entry"`
+}
+
+func newTransferMoneyResponse(result db.TransfeMoneyTxResult) transferMoneyResponse {
+	currency := result.FromAccount.Currency
+	numbers := numbersOf(*result.FromAccount, *result.ToAccount)
+
+	return transferMoneyResponse{
+		Transfer:    newTransferResponse(*result.Transfer, currency, numbers),
+		FromAccount: newAccountResponse(*result.FromAccount),
+		ToAccount:   newAccountResponse(*result.ToAccount),
+		FromEntry:   newEntryResponse(*result.FromEntry, currency, numbers),
+		ToEntry:     newEntryResponse(*result.ToEntry, currency, numbers),
+	}
+}
+
 type transferMoneyRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	FromAccountNumber string `json:"from_account_number" binding:"required,account_number"`
+	// the money goes to exactly one of an account number, a payee of the caller's payee book, or an alias
+	ToAccountNumber string `json:"to_account_number" binding:"omitempty,account_number"`
+	ToPayeeID       int64  `json:"to_payee_id" binding:"omitempty,min=1"`
+	ToAlias         string `json:"to_alias" binding:"omitempty,max=254"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Currency        string `json:"currency" binding:"required,currency"`
+}
+
+// getTransferDestination loads the account the transfer goes to, and aborts the request when there is none
+func (server *Server) getTransferDestination(ctx *gin.Context, req transferMoneyRequest) (db.Account, bool) {
+	destinations := 0
+	for _, set := range []bool{req.ToAccountNumber != "", req.ToPayeeID != 0, req.ToAlias != ""} {
+		if set {
+			destinations++
+		}
+	}
+
+	if destinations != 1 {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("exactly one of to_account_number, to_payee_id or to_alias is required"))
+		return db.Account{}, false
+	}
+
+	if req.ToAlias != "" {
+		_, account, ok := server.resolveAlias(ctx, req.ToAlias, req.Currency)
+		return account, ok
+	}
+
+	if req.ToAccountNumber != "" {
+		return server.getAccountByNumber(ctx, req.ToAccountNumber)
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.store.GetPayee(ctx, db.GetPayeeParams{
+		ID:    req.ToPayeeID,
+		Owner: authPayload.Username,
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, fmt.Errorf("payee: [%d] not found", req.ToPayeeID))
+			return db.Account{}, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return db.Account{}, false
+	}
+
+	account, err := server.store.GetAccountById(ctx, payee.AccountID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return account, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return account, false
+	}
+
+	return account, true
 }
 
 func (server *Server) TransferMoney(ctx *gin.Context) {
 	var req transferMoneyRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	amount := money.New(req.Amount, req.Currency)
+
+	from, ok := server.getHeldAccount(ctx, req.FromAccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+	account1 := from.Account
+
+	if !apiKeyCanUseAccount(ctx, account1.ID) {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("api key is not allowed to transfer from account: %s", account1.Number))
+		return
+	}
+
+	// the balance is checked again when the transfer is committed, this only saves a transaction bound to fail
+	remaining, err := account1.Money().Sub(amount)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w", account1.Number, err))
+		return
+	}
+
+	if remaining.IsNegative() {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s insufficient balance: %s < %s", account1.Number, account1.Money(), amount))
+		return
+	}
+
+	account2, ok := server.getTransferDestination(ctx, req)
+	if !ok {
+		return
+	}
+
+	if _, err := account2.Money().Add(amount); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w", account2.Number, err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	account1, err := server.store.GetAccountById(ctx, req.FromAccountID)
+	// users without a guardian are not held to any controls, their transfer is made right away
+	arg := db.ChildTransferTxParams{
+		TransferMoneyTxParams: db.TransferMoneyTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		},
+		Child: authPayload.Username,
+		Now:   time.Now(),
+	}
+
+	server.childTransfer(ctx, arg, account1, account2)
+}
+
+// childTransfer makes the transfer within the controls the guardians of the caller set, and responds with its outcome
+func (server *Server) childTransfer(ctx *gin.Context, arg db.ChildTransferTxParams, from db.Account, to db.Account) {
+	result, err := server.store.ChildTransferTx(ctx, arg)
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if isMoneyError(err) {
+			errorResponse(ctx, http.StatusBadRequest, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	if account1.Owner != authPayload.Username {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("account: [%d] does not belong to the authenticated user", account1.ID)))
+	switch {
+	case result.Blocked != "":
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("transfer blocked: %s", result.Blocked))
+	case result.Approval != nil:
+		// nothing moved yet, the transfer is made once a guardian approves it
+		ctx.JSON(http.StatusAccepted, newChildTransferApprovalResponse(*result.Approval, numbersOf(from, to)))
+	default:
+		ctx.JSON(http.StatusCreated, newTransferMoneyResponse(*result.Transfer))
+	}
+}
+
+type ownTransferRequest struct {
+	FromAccountNumber string `json:"from_account_number" binding:"required,account_number"`
+	ToAccountNumber   string `json:"to_account_number" binding:"required,account_number,nefield=FromAccountNumber"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Currency          string `json:"currency" binding:"required,currency"`
+}
+
+// transferBetweenOwnAccounts moves money between two accounts the caller may transact on, e.g. from checking to a savings pot.
+// This route is not held to the rate limit of POST /transfers, but a child is still held to the controls of their guardians.
+func (server *Server) transferBetweenOwnAccounts(ctx *gin.Context) {
+	var req ownTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if account1.Currency != req.Currency {
-		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("account: [%d] currency mismatch: %s vs %s", account1.ID, account1.Currency, req.Currency)))
+	amount := money.New(req.Amount, req.Currency)
+
+	from, ok := server.getHeldAccount(ctx, req.FromAccountNumber, db.AccountAccessTransact)
+	if !ok {
 		return
 	}
+	fromAccount := from.Account
 
-	if account1.Balance < req.Amount {
-		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("account: [%d] insufficient balance: %d < %d", account1.ID, account1.Balance, req.Amount)))
+	if !apiKeyCanUseAccount(ctx, fromAccount.ID) {
+		errorResponse(ctx, http.StatusForbidden, fmt.Errorf("api key is not allowed to transfer from account: %s", fromAccount.Number))
 		return
 	}
 
-	account2, err := server.store.GetAccountById(ctx, req.ToAccountID)
+	to, ok := server.getHeldAccount(ctx, req.ToAccountNumber, db.AccountAccessTransact)
+	if !ok {
+		return
+	}
+	toAccount := to.Account
 
+	remaining, err := fromAccount.Money().Sub(amount)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w", fromAccount.Number, err))
 		return
 	}
 
-	if account1.Currency != account2.Currency {
-		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("account: [%d] currency mismatch: %s vs %s", account2.ID, account2.Currency, req.Currency)))
+	if remaining.IsNegative() {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s insufficient balance: %s < %s", fromAccount.Number, fromAccount.Money(), amount))
 		return
 	}
 
-	arg := db.TransferMoneyTxParams{
-		FromAccountID: account1.ID,
-		ToAccountID:   account2.ID,
-		Amount:        req.Amount,
+	if _, err := toAccount.Money().Add(amount); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("account: %s %w", toAccount.Number, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	// a child holding both accounts with someone else could otherwise move money out of their controls
+	arg := db.ChildTransferTxParams{
+		TransferMoneyTxParams: db.TransferMoneyTxParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        amount,
+		},
+		Child: authPayload.Username,
+		Now:   time.Now(),
+	}
+
+	server.childTransfer(ctx, arg, fromAccount, toAccount)
+}
+
+type listAccountTransfersRequestQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listAccountTransfers(ctx *gin.Context) {
+	var uriReq accountRequestUri
+	var queryReq listAccountTransfersRequestQuery
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
 	}
 
-	TransferMoney, err := server.store.TransferMoneyTx(ctx, arg)
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	held, ok := server.getHeldAccount(ctx, uriReq.Number, db.AccountAccessView)
+	if !ok {
+		return
+	}
+	account := held.Account
+
+	transfers, err := server.store.ListAccountTransfers(ctx, db.ListAccountTransfersParams{
+		AccountID:  account.ID,
+		PageLimit:  queryReq.PageSize,
+		PageOffset: (queryReq.PageID - 1) * queryReq.PageSize,
+	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, TransferMoney)
+	// the other side of every transfer is looked up in one query
+	ids := make([]int64, 0, 2*len(transfers))
+	for _, transfer := range transfers {
+		ids = append(ids, transfer.FromAccountID, transfer.ToAccountID)
+	}
+
+	numbers, ok := server.accountNumbers(ctx, ids...)
+	if !ok {
+		return
+	}
+
+	res := make([]transferResponse, 0, len(transfers))
+	for _, transfer := range transfers {
+		res = append(res, newTransferResponse(transfer, account.Currency, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, res)
 }
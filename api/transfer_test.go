@@ -0,0 +1,464 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransferBetweenOwnAccountsAPI(t *testing.T) {
+	user, _ := randomUser()
+	other, _ := randomUser()
+
+	checking := randomAccount(user.Username)
+	checking.Currency = util.USD
+	checking.Balance = 1000
+
+	savings := randomAccount(user.Username)
+	savings.ID = checking.ID + 1
+	savings.Currency = util.USD
+	savings.Type = db.AccountTypeSavings
+
+	rupees := randomAccount(user.Username)
+	rupees.ID = checking.ID + 2
+	rupees.Currency = util.INR
+
+	othersAccount := randomAccount(other.Username)
+	othersAccount.ID = checking.ID + 3
+	othersAccount.Currency = util.USD
+
+	amount := int64(250)
+
+	// a user with guardians is held to their controls even between their own accounts
+	matchArg := gomock.Cond(func(x db.ChildTransferTxParams) bool {
+		return x.TransferMoneyTxParams == db.TransferMoneyTxParams{
+			FromAccountID: checking.ID,
+			ToAccountID:   savings.ID,
+			Amount:        money.New(amount, util.USD),
+		} && x.Child == user.Username && !x.Now.IsZero()
+	})
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": savings.Number, "amount": amount, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: checking.Number})).Times(1).Return(heldAccount(checking, user.Username), nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: savings.Number})).Times(1).Return(heldAccount(savings, user.Username), nil)
+
+				fromAccount, toAccount := checking, savings
+				fromAccount.Balance -= amount
+				toAccount.Balance += amount
+
+				store.EXPECT().
+					ChildTransferTx(gomock.Any(), matchArg).
+					Times(1).
+					Return(db.ChildTransferTxResult{Transfer: &db.TransfeMoneyTxResult{
+						Transfer:    &db.Transfer{ID: 1, FromAccountID: checking.ID, ToAccountID: savings.ID, Amount: amount},
+						FromAccount: &fromAccount,
+						ToAccount:   &toAccount,
+						FromEntry:   &db.Entry{ID: 1, AccountID: checking.ID, Amount: -amount},
+						ToEntry:     &db.Entry{ID: 2, AccountID: savings.ID, Amount: amount},
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"2.50"`)
+			},
+		},
+		{
+			name: "ChildOverLimit",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": savings.Number, "amount": amount, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: checking.Number})).Times(1).Return(heldAccount(checking, user.Username), nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: savings.Number})).Times(1).Return(heldAccount(savings, user.Username), nil)
+				store.EXPECT().
+					ChildTransferTx(gomock.Any(), matchArg).
+					Times(1).
+					Return(db.ChildTransferTxResult{Blocked: "over the per-transaction limit of 100"}, nil)
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "per-transaction limit")
+			},
+		},
+		{
+			name: "ChildNeedsApproval",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": savings.Number, "amount": amount, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: checking.Number})).Times(1).Return(heldAccount(checking, user.Username), nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: savings.Number})).Times(1).Return(heldAccount(savings, user.Username), nil)
+				store.EXPECT().
+					ChildTransferTx(gomock.Any(), matchArg).
+					Times(1).
+					Return(db.ChildTransferTxResult{Approval: &db.ChildTransferApproval{
+						ID:            1,
+						Child:         user.Username,
+						FromAccountID: checking.ID,
+						ToAccountID:   savings.ID,
+						Amount:        amount,
+						Currency:      util.USD,
+						Reason:        "payee is not on the allowlist",
+						Status:        db.ChildTransferPending,
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"status":"pending"`)
+				require.Contains(t, recorder.Body.String(), savings.Number)
+			},
+		},
+		{
+			name: "NotOwnAccount",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": othersAccount.Number, "amount": amount, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: checking.Number})).Times(1).Return(heldAccount(checking, user.Username), nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: othersAccount.Number})).Times(1).Return(heldAccount(othersAccount, user.Username), nil)
+				store.EXPECT().TransferMoneyTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "SameAccount",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": checking.Number, "amount": amount, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": rupees.Number, "amount": amount, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: checking.Number})).Times(1).Return(heldAccount(checking, user.Username), nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: rupees.Number})).Times(1).Return(heldAccount(rupees, user.Username), nil)
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientBalance",
+			body: gin.H{"from_account_number": checking.Number, "to_account_number": savings.Number, "amount": checking.Balance + 1, "currency": util.USD},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: checking.Number})).Times(1).Return(heldAccount(checking, user.Username), nil)
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Eq(db.GetAccountForHolderParams{Username: user.Username, Number: savings.Number})).Times(1).Return(heldAccount(savings, user.Username), nil)
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/own", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTransferMoneyAPI(t *testing.T) {
+	child, _ := randomUser()
+	other, _ := randomUser()
+
+	from := randomAccount(child.Username)
+	from.Currency = util.USD
+	from.Balance = 1000
+
+	to := randomAccount(other.Username)
+	to.ID = from.ID + 1
+	to.Currency = util.USD
+
+	amount := int64(250)
+	arg := db.ChildTransferTxParams{
+		TransferMoneyTxParams: db.TransferMoneyTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        money.New(amount, util.USD),
+		},
+		Child: child.Username,
+	}
+
+	// the time of the transfer is set by the handler
+	matchArg := gomock.Cond(func(x db.ChildTransferTxParams) bool {
+		return x.TransferMoneyTxParams == arg.TransferMoneyTxParams && x.Child == arg.Child && !x.Now.IsZero()
+	})
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockDB.MockStore) {
+				fromAccount, toAccount := from, to
+				fromAccount.Balance -= amount
+				toAccount.Balance += amount
+
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(from, child.Username), nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(to.Number)).Times(1).Return(to, nil)
+				store.EXPECT().
+					ChildTransferTx(gomock.Any(), matchArg).
+					Times(1).
+					Return(db.ChildTransferTxResult{Transfer: &db.TransfeMoneyTxResult{
+						Transfer:    &db.Transfer{ID: 1, FromAccountID: from.ID, ToAccountID: to.ID, Amount: amount},
+						FromAccount: &fromAccount,
+						ToAccount:   &toAccount,
+						FromEntry:   &db.Entry{ID: 1, AccountID: from.ID, Amount: -amount},
+						ToEntry:     &db.Entry{ID: 2, AccountID: to.ID, Amount: amount},
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"amount_formatted":"2.50"`)
+			},
+		},
+		{
+			name: "NeedsApproval",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(from, child.Username), nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(to.Number)).Times(1).Return(to, nil)
+				store.EXPECT().
+					ChildTransferTx(gomock.Any(), matchArg).
+					Times(1).
+					Return(db.ChildTransferTxResult{Approval: &db.ChildTransferApproval{
+						ID:            1,
+						Child:         child.Username,
+						FromAccountID: from.ID,
+						ToAccountID:   to.ID,
+						Amount:        amount,
+						Currency:      util.USD,
+						Reason:        "payee is not on the allowlist",
+						Status:        db.ChildTransferPending,
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"status":"pending"`)
+			},
+		},
+		{
+			name: "Blocked",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).Times(1).Return(heldAccount(from, child.Username), nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(to.Number)).Times(1).Return(to, nil)
+				store.EXPECT().
+					ChildTransferTx(gomock.Any(), matchArg).
+					Times(1).
+					Return(db.ChildTransferTxResult{Blocked: "over the per-transaction limit of 100"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "per-transaction limit")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_number": from.Number,
+				"to_account_number":   to.Number,
+				"amount":              amount,
+				"currency":            util.USD,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, child.Username, child.Role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTransferMoneyDestinationAPI(t *testing.T) {
+	user, _ := randomUser()
+	recipient, _ := randomUser()
+
+	from := randomAccount(user.Username)
+	from.Currency = util.USD
+	from.Balance = 1000
+
+	to := randomAccount(recipient.Username)
+	to.ID = from.ID + 1
+	to.Currency = util.USD
+
+	amount := int64(250)
+
+	transferred := func(store *mockDB.MockStore) {
+		store.EXPECT().
+			ChildTransferTx(gomock.Any(), gomock.Cond(func(x db.ChildTransferTxParams) bool {
+				return x.FromAccountID == from.ID && x.ToAccountID == to.ID
+			})).
+			Times(1).
+			Return(db.ChildTransferTxResult{Transfer: &db.TransfeMoneyTxResult{
+				Transfer:    &db.Transfer{ID: 1, FromAccountID: from.ID, ToAccountID: to.ID, Amount: amount},
+				FromAccount: &from,
+				ToAccount:   &to,
+				FromEntry:   &db.Entry{ID: 1, AccountID: from.ID, Amount: -amount},
+				ToEntry:     &db.Entry{ID: 2, AccountID: to.ID, Amount: amount},
+			}}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		destination   gin.H
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "Alias",
+			destination: gin.H{"to_alias": recipient.Username + aliasDomain},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().
+					GetDefaultAccount(gomock.Any(), gomock.Eq(db.GetDefaultAccountParams{Owner: recipient.Username, Currency: util.USD})).
+					Times(1).
+					Return(to, nil)
+				transferred(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:        "Payee",
+			destination: gin.H{"to_payee_id": 7},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					GetPayee(gomock.Any(), gomock.Eq(db.GetPayeeParams{ID: 7, Owner: user.Username})).
+					Times(1).
+					Return(db.Payee{ID: 7, Owner: user.Username, AccountID: to.ID}, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+				transferred(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:        "PayeeNotFound",
+			destination: gin.H{"to_payee_id": 7},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Any()).Times(1).Return(db.Payee{}, sql.ErrNoRows)
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "NoDestination",
+			destination: gin.H{},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "SeveralDestinations",
+			destination: gin.H{"to_account_number": to.Number, "to_alias": recipient.Username + aliasDomain},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().ChildTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			store.EXPECT().GetAccountForHolder(gomock.Any(), gomock.Any()).AnyTimes().Return(heldAccount(from, user.Username), nil)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			body := gin.H{
+				"from_account_number": from.Number,
+				"amount":              amount,
+				"currency":            util.USD,
+			}
+			for key, value := range tc.destination {
+				body[key] = value
+			}
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -272,6 +272,7 @@ func randomUser() (db.User, string) {
 		FullName:       util.RandomOwner(),
 		Email:          util.RandomEmail(),
 		HashedPassword: hashedPassword,
+		Role:           util.DepositorRole,
 	}, password
 }
 
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/AnkitNayan83/houseBank/accountnumber"
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/go-playground/validator/v10"
 )
@@ -11,3 +12,10 @@ var validCurrency validator.Func = func(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+var validAccountNumber validator.Func = func(fl validator.FieldLevel) bool {
+	if number, ok := fl.Field().Interface().(string); ok {
+		return accountnumber.Validate(number) == nil
+	}
+	return false
+}
@@ -0,0 +1,355 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/workers"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	webhookSecretTag   = "whsec"
+	webhookSecretBytes = 32
+)
+
+type webhookEndpointResponse struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newWebhookEndpointResponse(endpoint db.WebhookEndpoint) webhookEndpointResponse {
+	return webhookEndpointResponse{
+		ID:         endpoint.ID,
+		URL:        endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		CreatedAt:  endpoint.CreatedAt,
+	}
+}
+
+type webhookDeliveryResponse struct {
+	ID             int64      `json:"id"`
+	EndpointID     int64      `json:"endpoint_id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type,omitempty"`
+	Status         string     `json:"status"`
+	Attempts       int32      `json:"attempts"`
+	ResponseStatus *int32     `json:"response_status"`
+	LastError      *string    `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+func newWebhookDeliveryResponse(delivery db.WebhookDelivery) webhookDeliveryResponse {
+	res := webhookDeliveryResponse{
+		ID:          delivery.ID,
+		EndpointID:  delivery.EndpointID,
+		EventID:     delivery.EventID,
+		Status:      delivery.Status,
+		Attempts:    delivery.Attempts,
+		CreatedAt:   delivery.CreatedAt,
+		DeliveredAt: timeOrNil(delivery.DeliveredAt),
+	}
+
+	if delivery.ResponseStatus.Valid {
+		res.ResponseStatus = &delivery.ResponseStatus.Int32
+	}
+
+	if delivery.LastError.Valid {
+		res.LastError = &delivery.LastError.String
+	}
+
+	return res
+}
+
+// generateWebhookSecret creates the key deliveries to an endpoint are signed with
+func generateWebhookSecret() (string, error) {
+	secretBytes := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("cannot generate webhook secret: %w", err)
+	}
+
+	return webhookSecretTag + "_" + base64.RawURLEncoding.EncodeToString(secretBytes), nil
+}
+
+type createWebhookEndpointRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,required"`
+}
+
+type createWebhookEndpointResponse struct {
+	Secret   string                  `json:"secret"`
+	Endpoint webhookEndpointResponse `json:"endpoint"`
+}
+
+func (server *Server) createWebhookEndpoint(ctx *gin.Context) {
+	var req createWebhookEndpointRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	endpointURL, err := url.Parse(req.URL)
+	if err != nil || (endpointURL.Scheme != "https" && endpointURL.Scheme != "http") {
+		errorResponse(ctx, http.StatusBadRequest, errors.New("url must be an http or https url"))
+		return
+	}
+
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(db.WebhookEventTypes, eventType) {
+			errorResponse(ctx, http.StatusBadRequest, fmt.Errorf("unknown event type: %s", eventType))
+			return
+		}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		Owner:      authPayload.Username,
+		Url:        req.URL,
+		Secret:     secret,
+		EventTypes: slices.Compact(slices.Sorted(slices.Values(req.EventTypes))),
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the secret is only returned once, deliveries can only be verified by whoever created the endpoint
+	ctx.JSON(http.StatusCreated, createWebhookEndpointResponse{
+		Secret:   secret,
+		Endpoint: newWebhookEndpointResponse(endpoint),
+	})
+}
+
+func (server *Server) listWebhookEndpoints(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, authPayload.Username)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]webhookEndpointResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		res = append(res, newWebhookEndpointResponse(endpoint))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+type webhookEndpointRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getOwnedWebhookEndpoint loads the endpoint of the uri, and aborts the request unless it belongs to the caller
+func (server *Server) getOwnedWebhookEndpoint(ctx *gin.Context, id int64) (db.WebhookEndpoint, bool) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return endpoint, false
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return endpoint, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if endpoint.Owner != authPayload.Username {
+		errorResponse(ctx, http.StatusForbidden, errors.New("webhook endpoint does not belong to the authenticated user"))
+		return endpoint, false
+	}
+
+	return endpoint, true
+}
+
+func (server *Server) deleteWebhookEndpoint(ctx *gin.Context) {
+	var req webhookEndpointRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhookEndpoint(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.store.DeleteWebhookEndpoint(ctx, endpoint.ID)
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint deleted successfully"})
+}
+
+type listWebhookDeliveriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uriReq webhookEndpointRequest
+	var queryReq listWebhookDeliveriesRequest
+
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhookEndpoint(ctx, uriReq.ID)
+	if !ok {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      queryReq.PageSize,
+		Offset:     (queryReq.PageID - 1) * queryReq.PageSize,
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]webhookDeliveryResponse, 0, len(deliveries))
+	for _, row := range deliveries {
+		delivery := newWebhookDeliveryResponse(db.WebhookDelivery{
+			ID:             row.ID,
+			EndpointID:     row.EndpointID,
+			EventID:        row.EventID,
+			Status:         row.Status,
+			Attempts:       row.Attempts,
+			ResponseStatus: row.ResponseStatus,
+			LastError:      row.LastError,
+			CreatedAt:      row.CreatedAt,
+			DeliveredAt:    row.DeliveredAt,
+		})
+		delivery.EventType = row.EventType
+
+		res = append(res, delivery)
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// deliverWebhookOutbox queues the new delivery once its transaction commits
+func deliverWebhookOutbox(ctx *gin.Context) func(delivery db.WebhookDelivery) ([]db.CreateOutboxMessageParams, error) {
+	return func(delivery db.WebhookDelivery) ([]db.CreateOutboxMessageParams, error) {
+		message, err := workers.DeliverWebhook.OutboxMessage(ctx, &workers.PayloadDeliverWebhook{
+			DeliveryID: delivery.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return []db.CreateOutboxMessageParams{message}, nil
+	}
+}
+
+type redeliverWebhookRequest struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+func (server *Server) redeliverWebhook(ctx *gin.Context) {
+	var req redeliverWebhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhookEndpoint(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, req.DeliveryID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if delivery.EndpointID != endpoint.ID {
+		errorResponse(ctx, http.StatusNotFound, fmt.Errorf("delivery: [%d] is not a delivery of webhook endpoint: [%d]", delivery.ID, endpoint.ID))
+		return
+	}
+
+	result, err := server.store.CreateWebhookDeliveryTx(ctx, db.CreateWebhookDeliveryTxParams{
+		CreateWebhookDeliveryParams: db.CreateWebhookDeliveryParams{
+			EndpointID: endpoint.ID,
+			EventID:    delivery.EventID,
+		},
+		AfterCreateDelivery: deliverWebhookOutbox(ctx),
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newWebhookDeliveryResponse(result.Delivery))
+}
+
+func (server *Server) pingWebhookEndpoint(ctx *gin.Context) {
+	var req webhookEndpointRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhookEndpoint(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.PingWebhookEndpointTx(ctx, db.PingWebhookEndpointTxParams{
+		Endpoint:            endpoint,
+		AfterCreateDelivery: deliverWebhookOutbox(ctx),
+	})
+
+	if err != nil {
+		errorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := newWebhookDeliveryResponse(result.Delivery)
+	res.EventType = result.Event.EventType
+
+	ctx.JSON(http.StatusAccepted, res)
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/AnkitNayan83/houseBank/workers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomWebhookEndpoint(owner string) db.WebhookEndpoint {
+	return db.WebhookEndpoint{
+		ID:         util.RandomInt(1, 1000),
+		Owner:      owner,
+		Url:        "https://example.com/" + util.RandomString(6),
+		Secret:     webhookSecretTag + "_" + util.RandomString(32),
+		EventTypes: []string{db.WebhookEventTransferReceived},
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestCreateWebhookEndpointAPI(t *testing.T) {
+	user, _ := randomUser()
+	endpoint := randomWebhookEndpoint(user.Username)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"url":         endpoint.Url,
+				"event_types": []string{db.WebhookEventTransferReceived, db.WebhookEventTransferReceived},
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Cond(func(arg db.CreateWebhookEndpointParams) bool {
+						return arg.Owner == user.Username &&
+							arg.Url == endpoint.Url &&
+							strings.HasPrefix(arg.Secret, webhookSecretTag+"_") &&
+							len(arg.EventTypes) == 1
+					})).
+					Times(1).
+					Return(endpoint, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var res createWebhookEndpointResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.True(t, strings.HasPrefix(res.Secret, webhookSecretTag+"_"))
+				require.Equal(t, endpoint.ID, res.Endpoint.ID)
+				require.NotContains(t, recorder.Body.String(), endpoint.Secret)
+			},
+		},
+		{
+			name: "UnknownEventType",
+			body: gin.H{
+				"url":         endpoint.Url,
+				"event_types": []string{db.WebhookEventPing},
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidURL",
+			body: gin.H{
+				"url":         "ftp://example.com/hook",
+				"event_types": []string{db.WebhookEventTransferReceived},
+			},
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPingWebhookEndpointAPI(t *testing.T) {
+	user, _ := randomUser()
+	endpoint := randomWebhookEndpoint(user.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockDB.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().
+					PingWebhookEndpointTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.PingWebhookEndpointTxParams) (db.PingWebhookEndpointTxResult, error) {
+						require.Equal(t, endpoint, arg.Endpoint)
+
+						delivery := db.WebhookDelivery{ID: util.RandomInt(1, 1000), EndpointID: endpoint.ID, Status: db.WebhookDeliveryPending}
+
+						// the delivery task is written to the outbox along with the delivery
+						messages, err := arg.AfterCreateDelivery(delivery)
+						require.NoError(t, err)
+						require.Len(t, messages, 1)
+						require.Equal(t, workers.TaskDeliverWebhook, messages[0].TaskType)
+
+						return db.PingWebhookEndpointTxResult{
+							Event:    db.WebhookEvent{EventType: db.WebhookEventPing},
+							Delivery: delivery,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var res webhookDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, db.WebhookEventPing, res.EventType)
+				require.Equal(t, db.WebhookDeliveryPending, res.Status)
+			},
+		},
+		{
+			name:     "OtherOwner",
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().PingWebhookEndpointTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(db.WebhookEndpoint{}, sql.ErrNoRows)
+				store.EXPECT().PingWebhookEndpointTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, err := newTestServer(t, store)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/webhooks/%d/ping", endpoint.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+CREATE TABLE "revoked_tokens" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "revoked_by" varchar NOT NULL,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "user_token_revocations" (
+  "username" varchar PRIMARY KEY,
+  "revoked_by" varchar NOT NULL,
+  "revoked_before" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "revoked_tokens" ("username");
+CREATE INDEX ON "revoked_tokens" ("expired_at");
+
+ALTER TABLE "revoked_tokens" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+ALTER TABLE "user_token_revocations" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "user_token_revocations"."revoked_before" IS 'every token issued at or before this time is revoked';
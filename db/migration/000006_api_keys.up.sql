@@ -0,0 +1,23 @@
+CREATE TABLE "api_keys" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "prefix" varchar UNIQUE NOT NULL,
+  "hashed_secret" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "account_ids" bigint[] NOT NULL DEFAULT '{}',
+  "allowed_ips" varchar[] NOT NULL DEFAULT '{}',
+  "expires_at" timestamptz,
+  "last_used_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "api_keys" ("username");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "api_keys"."prefix" IS 'public part of the key used to look it up';
+COMMENT ON COLUMN "api_keys"."hashed_secret" IS 'sha256 of the secret part of the key';
+COMMENT ON COLUMN "api_keys"."account_ids" IS 'accounts the key can transfer from, empty means all of the owner''s accounts';
+COMMENT ON COLUMN "api_keys"."allowed_ips" IS 'ips or cidr ranges the key can be used from, empty means any';
@@ -0,0 +1,48 @@
+CREATE TABLE "oauth_clients" (
+  "id" uuid PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "hashed_secret" varchar NOT NULL DEFAULT '',
+  "redirect_uris" varchar[] NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_authorization_codes" (
+  "code_hash" varchar PRIMARY KEY,
+  "client_id" uuid NOT NULL,
+  "username" varchar NOT NULL,
+  "redirect_uri" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "code_challenge" varchar NOT NULL,
+  "nonce" varchar NOT NULL DEFAULT '',
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_consents" (
+  "username" varchar NOT NULL,
+  "client_id" uuid NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "client_id")
+);
+
+CREATE INDEX ON "oauth_clients" ("owner");
+
+ALTER TABLE "oauth_clients" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "oauth_authorization_codes" ADD FOREIGN KEY ("client_id") REFERENCES "oauth_clients" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "oauth_authorization_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "oauth_consents" ADD FOREIGN KEY ("client_id") REFERENCES "oauth_clients" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "oauth_consents" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "oauth_clients"."hashed_secret" IS 'sha256 of the client secret, empty for public clients which rely on pkce only';
+COMMENT ON COLUMN "oauth_authorization_codes"."code_hash" IS 'sha256 of the code handed to the client';
+COMMENT ON COLUMN "oauth_authorization_codes"."code_challenge" IS 'pkce S256 challenge the code verifier must match';
+COMMENT ON COLUMN "oauth_consents"."scopes" IS 'scopes the user has approved for the client';
@@ -0,0 +1,18 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "task_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "queue" varchar NOT NULL,
+  "max_retry" int NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz
+);
+
+CREATE INDEX ON "outbox" ("id") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON "outbox" ("published_at");
+
+COMMENT ON COLUMN "outbox"."attempts" IS 'number of times the relay tried to publish the task';
+COMMENT ON COLUMN "outbox"."published_at" IS 'set once the task was accepted by the task queue';
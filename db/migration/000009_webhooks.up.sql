@@ -0,0 +1,49 @@
+CREATE TABLE "webhook_endpoints" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_events" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "dispatched_at" timestamptz
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "endpoint_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "response_status" int,
+  "last_error" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz
+);
+
+CREATE INDEX ON "webhook_endpoints" ("owner");
+
+CREATE INDEX ON "webhook_events" ("id") WHERE "dispatched_at" IS NULL;
+
+CREATE INDEX ON "webhook_deliveries" ("endpoint_id", "id");
+
+ALTER TABLE "webhook_endpoints" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "webhook_events" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("endpoint_id") REFERENCES "webhook_endpoints" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "webhook_events" ("id");
+
+COMMENT ON COLUMN "webhook_endpoints"."secret" IS 'key the deliveries are signed with, only shown when the endpoint is created';
+COMMENT ON COLUMN "webhook_endpoints"."event_types" IS 'event types sent to the endpoint';
+COMMENT ON COLUMN "webhook_events"."dispatched_at" IS 'set once a delivery was created for every subscribed endpoint';
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, succeeded or failed once every attempt was used';
+COMMENT ON COLUMN "webhook_deliveries"."response_status" IS 'http status code of the last attempt';
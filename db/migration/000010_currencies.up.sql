@@ -0,0 +1,18 @@
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "exponent" int NOT NULL CHECK ("exponent" BETWEEN 0 AND 4),
+  "symbol" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+COMMENT ON COLUMN "currencies"."exponent" IS 'ISO 4217 minor unit, amounts are stored as 10^exponent minor units per unit';
+COMMENT ON COLUMN "currencies"."enabled" IS 'whether new accounts and transfers may use the currency';
+
+-- the currencies accounts could be opened in before the registry existed
+INSERT INTO "currencies" ("code", "exponent", "symbol") VALUES
+  ('USD', 2, '$'),
+  ('INR', 2, '₹');
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_currency_fkey" FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
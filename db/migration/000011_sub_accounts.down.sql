@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_name_key";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "type";
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "name";
+
+-- fails while a user still holds several accounts in a currency
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
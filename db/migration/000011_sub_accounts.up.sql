@@ -0,0 +1,13 @@
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts" ADD COLUMN "name" varchar;
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking';
+
+-- every existing account was the only one of its owner in its currency
+UPDATE "accounts" SET "name" = "currency";
+ALTER TABLE "accounts" ALTER COLUMN "name" SET NOT NULL;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_name_key" UNIQUE ("owner", "name");
+
+COMMENT ON COLUMN "accounts"."name" IS 'chosen by the owner, e.g. rent or groceries, unique among their accounts';
+COMMENT ON COLUMN "accounts"."type" IS 'checking or savings';
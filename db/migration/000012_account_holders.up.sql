@@ -0,0 +1,38 @@
+CREATE TABLE "account_holders" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "invited_by" varchar,
+  "accepted_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "account_id" bigint,
+  "subject" varchar,
+  "details" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_holders" ("username");
+
+CREATE INDEX ON "audit_events" ("account_id", "id");
+
+ALTER TABLE "account_holders" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "account_holders" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_holders" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username");
+
+-- the owner of every account is its primary holder
+INSERT INTO "account_holders" ("account_id", "username", "role", "accepted_at")
+SELECT "id", "owner", 'primary', "created_at" FROM "accounts";
+
+COMMENT ON COLUMN "account_holders"."role" IS 'primary, joint or view_only';
+COMMENT ON COLUMN "account_holders"."accepted_at" IS 'null while the invitation of the holder is pending';
+COMMENT ON COLUMN "audit_events"."account_id" IS 'not a foreign key, the trail outlives the account';
+COMMENT ON COLUMN "audit_events"."subject" IS 'user the action was done to, e.g. the invited holder';
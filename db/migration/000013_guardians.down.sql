@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "allowances";
+DROP TABLE IF EXISTS "child_transfer_approvals";
+DROP TABLE IF EXISTS "child_payees";
+DROP TABLE IF EXISTS "child_controls";
+DROP TABLE IF EXISTS "guardianships";
@@ -0,0 +1,117 @@
+CREATE TABLE "guardianships" (
+  "guardian" varchar NOT NULL,
+  "child" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("guardian", "child"),
+  CHECK ("guardian" <> "child")
+);
+
+CREATE TABLE "child_controls" (
+  "child" varchar PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "per_transaction_limit" bigint NOT NULL CHECK ("per_transaction_limit" >= 0),
+  "daily_limit" bigint NOT NULL CHECK ("daily_limit" >= 0),
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "child_payees" (
+  "child" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "added_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("child", "account_id")
+);
+
+CREATE TABLE "child_transfer_approvals" (
+  "id" bigserial PRIMARY KEY,
+  "child" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "currency" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "decided_by" varchar,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "allowances" (
+  "id" bigserial PRIMARY KEY,
+  "guardian" varchar NOT NULL,
+  "child" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "currency" varchar NOT NULL,
+  "period" varchar NOT NULL,
+  "next_payment_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "guardianships" ("child");
+
+CREATE INDEX ON "child_transfer_approvals" ("child", "id") WHERE "status" = 'pending';
+
+CREATE INDEX ON "allowances" ("child");
+
+CREATE INDEX ON "allowances" ("next_payment_at");
+
+CREATE INDEX ON "notifications" ("username", "id");
+
+ALTER TABLE "guardianships" ADD FOREIGN KEY ("guardian") REFERENCES "users" ("username");
+
+ALTER TABLE "guardianships" ADD FOREIGN KEY ("child") REFERENCES "users" ("username");
+
+ALTER TABLE "child_controls" ADD FOREIGN KEY ("child") REFERENCES "users" ("username");
+
+ALTER TABLE "child_controls" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "child_controls" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "child_payees" ADD FOREIGN KEY ("child") REFERENCES "users" ("username");
+
+ALTER TABLE "child_payees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "child_payees" ADD FOREIGN KEY ("added_by") REFERENCES "users" ("username");
+
+ALTER TABLE "child_transfer_approvals" ADD FOREIGN KEY ("child") REFERENCES "users" ("username");
+
+ALTER TABLE "child_transfer_approvals" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "child_transfer_approvals" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "child_transfer_approvals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "child_transfer_approvals" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "allowances" ADD FOREIGN KEY ("guardian") REFERENCES "users" ("username");
+
+ALTER TABLE "allowances" ADD FOREIGN KEY ("child") REFERENCES "users" ("username");
+
+ALTER TABLE "allowances" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "allowances" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "allowances" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "child_controls"."per_transaction_limit" IS 'in minor units of currency, transfers in other currencies need approval';
+COMMENT ON COLUMN "child_controls"."daily_limit" IS 'total the child may send to others per utc day, in minor units of currency';
+COMMENT ON COLUMN "child_transfer_approvals"."reason" IS 'why the transfer needs a guardian, e.g. a payee not on the allowlist';
+COMMENT ON COLUMN "child_transfer_approvals"."status" IS 'pending, approved or declined';
+COMMENT ON COLUMN "child_transfer_approvals"."transfer_id" IS 'transfer made once a guardian approved';
+COMMENT ON COLUMN "allowances"."period" IS 'weekly or monthly';
+COMMENT ON COLUMN "notifications"."kind" IS 'what happened, e.g. child_transfer.blocked';
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "household_spend_votes";
+DROP TABLE IF EXISTS "household_spends";
+DROP TABLE IF EXISTS "household_members";
+DROP TABLE IF EXISTS "households";
+
+-- pots are left as checking accounts of their creator
+UPDATE "accounts" SET "type" = 'checking' WHERE "type" = 'household';
+
+COMMENT ON COLUMN "accounts"."type" IS 'checking or savings';
@@ -0,0 +1,68 @@
+CREATE TABLE "households" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "created_by" varchar NOT NULL,
+  "account_id" bigint UNIQUE NOT NULL,
+  "spend_rule" varchar NOT NULL DEFAULT 'any_member',
+  "approval_threshold" bigint NOT NULL DEFAULT 0 CHECK ("approval_threshold" >= 0),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "household_members" (
+  "household_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("household_id", "username")
+);
+
+CREATE TABLE "household_spends" (
+  "id" bigserial PRIMARY KEY,
+  "household_id" bigint NOT NULL,
+  "requested_by" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "note" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "household_spend_votes" (
+  "spend_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "approve" boolean NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("spend_id", "username")
+);
+
+CREATE INDEX ON "household_members" ("username");
+
+CREATE INDEX ON "household_spends" ("household_id", "id");
+
+ALTER TABLE "households" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "households" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "household_members" ADD FOREIGN KEY ("household_id") REFERENCES "households" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "household_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "household_spends" ADD FOREIGN KEY ("household_id") REFERENCES "households" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "household_spends" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "household_spends" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "household_spends" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "household_spend_votes" ADD FOREIGN KEY ("spend_id") REFERENCES "household_spends" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "household_spend_votes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "accounts"."type" IS 'checking, savings or household, the pot of a household';
+COMMENT ON COLUMN "households"."account_id" IS 'the pot, held by the creator and seen by every member';
+COMMENT ON COLUMN "households"."spend_rule" IS 'any_member or majority';
+COMMENT ON COLUMN "households"."approval_threshold" IS 'with the majority rule, spends above it need the approval of more than half of the members';
+COMMENT ON COLUMN "household_spends"."status" IS 'pending, approved or declined';
+COMMENT ON COLUMN "household_spends"."transfer_id" IS 'transfer out of the pot once approved';
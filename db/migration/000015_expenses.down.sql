@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "expense_shares";
+DROP TABLE IF EXISTS "expenses";
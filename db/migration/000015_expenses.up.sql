@@ -0,0 +1,41 @@
+CREATE TABLE "expenses" (
+  "id" bigserial PRIMARY KEY,
+  "paid_by" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "currency" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "split" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "expense_shares" (
+  "expense_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" >= 0),
+  "transfer_id" bigint,
+  "settled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("expense_id", "username")
+);
+
+CREATE INDEX ON "expenses" ("paid_by");
+
+CREATE INDEX ON "expense_shares" ("username");
+
+ALTER TABLE "expenses" ADD FOREIGN KEY ("paid_by") REFERENCES "users" ("username");
+
+ALTER TABLE "expenses" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "expenses" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "expense_shares" ADD FOREIGN KEY ("expense_id") REFERENCES "expenses" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "expense_shares" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "expense_shares" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "expenses"."account_id" IS 'account of the payer the participants pay their shares back into';
+COMMENT ON COLUMN "expenses"."split" IS 'equal, percentage or exact';
+COMMENT ON COLUMN "expense_shares"."amount" IS 'what the participant owes the payer, in minor units of the currency of the expense';
+COMMENT ON COLUMN "expense_shares"."transfer_id" IS 'transfer which settled the share, null for the share of the payer';
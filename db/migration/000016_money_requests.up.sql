@@ -0,0 +1,34 @@
+CREATE TABLE "money_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "payer" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "money_requests" ("payer", "status");
+
+CREATE INDEX ON "money_requests" ("requester", "id");
+
+CREATE INDEX ON "money_requests" ("expires_at") WHERE "status" = 'pending';
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "money_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "money_requests"."to_account_id" IS 'account of the requester the money is paid into';
+COMMENT ON COLUMN "money_requests"."status" IS 'pending, paid, declined or expired';
+COMMENT ON COLUMN "money_requests"."transfer_id" IS 'transfer from the account the payer chose once paid';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "payees";
+DROP TABLE IF EXISTS "default_accounts";
@@ -0,0 +1,35 @@
+CREATE TABLE "default_accounts" (
+  "owner" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("owner", "currency")
+);
+
+CREATE TABLE "payees" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "verified_name" varchar NOT NULL,
+  "alias" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "owner_nickname_key" UNIQUE ("owner", "nickname")
+);
+
+CREATE INDEX ON "payees" ("account_id");
+
+ALTER TABLE "default_accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "default_accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "default_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "default_accounts"."account_id" IS 'account payments to an alias of the owner are made into, their oldest checking account in the currency when not chosen';
+COMMENT ON COLUMN "payees"."nickname" IS 'chosen by the owner of the payee book, unique in it';
+COMMENT ON COLUMN "payees"."verified_name" IS 'full name of the holder of the account when the payee was added';
+COMMENT ON COLUMN "payees"."alias" IS 'alias the account was resolved from, empty when added by account id';
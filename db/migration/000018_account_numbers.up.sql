@@ -0,0 +1,18 @@
+ALTER TABLE "accounts" ADD COLUMN "number" varchar;
+
+-- existing accounts get a random serial in the head office branch, with the check digits of ISO 13616,
+-- HB is 17 11 once its letters are turned into numbers
+WITH "generated" AS (
+  SELECT "id", '0001' || lpad(floor(random() * 10000000000)::bigint::text, 10, '0') AS "bban"
+  FROM "accounts"
+)
+UPDATE "accounts"
+SET "number" = 'HB' || lpad((98 - mod(("generated"."bban" || '171100')::numeric, 97))::text, 2, '0') || "generated"."bban"
+FROM "generated"
+WHERE "accounts"."id" = "generated"."id";
+
+ALTER TABLE "accounts" ALTER COLUMN "number" SET NOT NULL;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "number_key" UNIQUE ("number");
+
+COMMENT ON COLUMN "accounts"."number" IS 'public identifier of the account, bank code, check digits, branch code and random serial';
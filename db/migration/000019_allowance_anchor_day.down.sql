@@ -0,0 +1 @@
+ALTER TABLE "allowances" DROP COLUMN IF EXISTS "anchor_day";
@@ -0,0 +1,10 @@
+ALTER TABLE "allowances" ADD COLUMN "anchor_day" smallint;
+
+-- monthly allowances were due on the day of their first payment, unless a shorter month moved them since
+UPDATE "allowances" SET "anchor_day" = extract(day FROM "next_payment_at" AT TIME ZONE 'UTC');
+
+ALTER TABLE "allowances" ALTER COLUMN "anchor_day" SET NOT NULL;
+
+ALTER TABLE "allowances" ADD CONSTRAINT "anchor_day_check" CHECK ("anchor_day" BETWEEN 1 AND 31);
+
+COMMENT ON COLUMN "allowances"."anchor_day" IS 'day of the month a monthly allowance is due, or the last day of shorter months';
@@ -0,0 +1 @@
+ALTER TABLE "child_transfer_approvals" DROP COLUMN IF EXISTS "money_request_id";
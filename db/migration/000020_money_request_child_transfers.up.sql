@@ -0,0 +1,8 @@
+ALTER TABLE "child_transfer_approvals" ADD COLUMN "money_request_id" bigint;
+
+ALTER TABLE "child_transfer_approvals" ADD FOREIGN KEY ("money_request_id") REFERENCES "money_requests" ("id") ON DELETE CASCADE;
+
+-- a request a child pays waits for one guardian decision at a time
+CREATE UNIQUE INDEX ON "child_transfer_approvals" ("money_request_id") WHERE "status" = 'pending';
+
+COMMENT ON COLUMN "child_transfer_approvals"."money_request_id" IS 'money request the transfer pays, which is paid or declined along with it';
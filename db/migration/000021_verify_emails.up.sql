@@ -0,0 +1,19 @@
+CREATE TABLE "verify_emails" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "hashed_secret_code" varchar NOT NULL,
+  "is_used" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
+);
+
+CREATE INDEX ON "verify_emails" ("username");
+
+ALTER TABLE "verify_emails" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "verify_emails"."email" IS 'email the link was sent to, the user is only verified while it is still theirs';
+COMMENT ON COLUMN "verify_emails"."hashed_secret_code" IS 'the secret code of the link is only sent to the email';
+
+-- updating a user without an email_verified_at used to set it to the unix epoch, which counted as verified
+UPDATE "users" SET "email_verified_at" = NULL WHERE "email_verified_at" = '1970-01-01 00:00:00+00';
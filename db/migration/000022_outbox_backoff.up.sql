@@ -0,0 +1,3 @@
+ALTER TABLE "outbox" ADD COLUMN "last_attempted_at" timestamptz;
+
+COMMENT ON COLUMN "outbox"."last_attempted_at" IS 'when the relay last failed to publish the task, it waits longer after each failure';
@@ -12,6 +12,7 @@ package mockDB
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
 	uuid "github.com/google/uuid"
@@ -42,6 +43,36 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptAccountHolder mocks base method.
+func (m *MockStore) AcceptAccountHolder(ctx context.Context, arg db.AcceptAccountHolderParams) (db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptAccountHolder", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptAccountHolder indicates an expected call of AcceptAccountHolder.
+func (mr *MockStoreMockRecorder) AcceptAccountHolder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptAccountHolder", reflect.TypeOf((*MockStore)(nil).AcceptAccountHolder), ctx, arg)
+}
+
+// AcceptAccountHolderTx mocks base method.
+func (m *MockStore) AcceptAccountHolderTx(ctx context.Context, arg db.AcceptAccountHolderTxParams) (db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptAccountHolderTx", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptAccountHolderTx indicates an expected call of AcceptAccountHolderTx.
+func (mr *MockStoreMockRecorder) AcceptAccountHolderTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptAccountHolderTx", reflect.TypeOf((*MockStore)(nil).AcceptAccountHolderTx), ctx, arg)
+}
+
 // AddAccountBalance mocks base method.
 func (m *MockStore) AddAccountBalance(ctx context.Context, arg db.AddAccountBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -54,291 +85,2321 @@ func (m *MockStore) AddAccountBalance(ctx context.Context, arg db.AddAccountBala
 // AddAccountBalance indicates an expected call of AddAccountBalance.
 func (mr *MockStoreMockRecorder) AddAccountBalance(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), ctx, arg)
+}
+
+// AddAccountBalanceTx mocks base method.
+func (m *MockStore) AddAccountBalanceTx(ctx context.Context, arg db.AddAccountBalanceTxParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountBalanceTx", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountBalanceTx indicates an expected call of AddAccountBalanceTx.
+func (mr *MockStoreMockRecorder) AddAccountBalanceTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalanceTx", reflect.TypeOf((*MockStore)(nil).AddAccountBalanceTx), ctx, arg)
+}
+
+// AddHouseholdMemberTx mocks base method.
+func (m *MockStore) AddHouseholdMemberTx(ctx context.Context, arg db.AddHouseholdMemberTxParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHouseholdMemberTx", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddHouseholdMemberTx indicates an expected call of AddHouseholdMemberTx.
+func (mr *MockStoreMockRecorder) AddHouseholdMemberTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHouseholdMemberTx", reflect.TypeOf((*MockStore)(nil).AddHouseholdMemberTx), ctx, arg)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), ctx, username)
+}
+
+// ChildTransferTx mocks base method.
+func (m *MockStore) ChildTransferTx(ctx context.Context, arg db.ChildTransferTxParams) (db.ChildTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChildTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.ChildTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChildTransferTx indicates an expected call of ChildTransferTx.
+func (mr *MockStoreMockRecorder) ChildTransferTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChildTransferTx", reflect.TypeOf((*MockStore)(nil).ChildTransferTx), ctx, arg)
+}
+
+// CountAccountsByOwner mocks base method.
+func (m *MockStore) CountAccountsByOwner(ctx context.Context, owner string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAccountsByOwner", ctx, owner)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAccountsByOwner indicates an expected call of CountAccountsByOwner.
+func (mr *MockStoreMockRecorder) CountAccountsByOwner(ctx, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccountsByOwner", reflect.TypeOf((*MockStore)(nil).CountAccountsByOwner), ctx, owner)
+}
+
+// CountAccountsHeldAlone mocks base method.
+func (m *MockStore) CountAccountsHeldAlone(ctx context.Context, arg db.CountAccountsHeldAloneParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAccountsHeldAlone", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAccountsHeldAlone indicates an expected call of CountAccountsHeldAlone.
+func (mr *MockStoreMockRecorder) CountAccountsHeldAlone(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccountsHeldAlone", reflect.TypeOf((*MockStore)(nil).CountAccountsHeldAlone), ctx, arg)
+}
+
+// CountHouseholdMembers mocks base method.
+func (m *MockStore) CountHouseholdMembers(ctx context.Context, householdID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountHouseholdMembers", ctx, householdID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountHouseholdMembers indicates an expected call of CountHouseholdMembers.
+func (mr *MockStoreMockRecorder) CountHouseholdMembers(ctx, householdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountHouseholdMembers", reflect.TypeOf((*MockStore)(nil).CountHouseholdMembers), ctx, householdID)
+}
+
+// CountHouseholdSpendVotes mocks base method.
+func (m *MockStore) CountHouseholdSpendVotes(ctx context.Context, spendID int64) (db.CountHouseholdSpendVotesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountHouseholdSpendVotes", ctx, spendID)
+	ret0, _ := ret[0].(db.CountHouseholdSpendVotesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountHouseholdSpendVotes indicates an expected call of CountHouseholdSpendVotes.
+func (mr *MockStoreMockRecorder) CountHouseholdSpendVotes(ctx, spendID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountHouseholdSpendVotes", reflect.TypeOf((*MockStore)(nil).CountHouseholdSpendVotes), ctx, spendID)
+}
+
+// CreateAccount mocks base method.
+func (m *MockStore) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), ctx, arg)
+}
+
+// CreateAccountHolder mocks base method.
+func (m *MockStore) CreateAccountHolder(ctx context.Context, arg db.CreateAccountHolderParams) (db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountHolder", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountHolder indicates an expected call of CreateAccountHolder.
+func (mr *MockStoreMockRecorder) CreateAccountHolder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountHolder", reflect.TypeOf((*MockStore)(nil).CreateAccountHolder), ctx, arg)
+}
+
+// CreateAccountTx mocks base method.
+func (m *MockStore) CreateAccountTx(ctx context.Context, arg db.CreateAccountTxParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountTx", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountTx indicates an expected call of CreateAccountTx.
+func (mr *MockStoreMockRecorder) CreateAccountTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountTx", reflect.TypeOf((*MockStore)(nil).CreateAccountTx), ctx, arg)
+}
+
+// CreateAllowance mocks base method.
+func (m *MockStore) CreateAllowance(ctx context.Context, arg db.CreateAllowanceParams) (db.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAllowance", ctx, arg)
+	ret0, _ := ret[0].(db.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAllowance indicates an expected call of CreateAllowance.
+func (mr *MockStoreMockRecorder) CreateAllowance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAllowance", reflect.TypeOf((*MockStore)(nil).CreateAllowance), ctx, arg)
+}
+
+// CreateApiKey mocks base method.
+func (m *MockStore) CreateApiKey(ctx context.Context, arg db.CreateApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", ctx, arg)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockStoreMockRecorder) CreateApiKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockStore)(nil).CreateApiKey), ctx, arg)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockStore) CreateAuditEvent(ctx context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", ctx, arg)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStoreMockRecorder) CreateAuditEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStore)(nil).CreateAuditEvent), ctx, arg)
+}
+
+// CreateChildPayee mocks base method.
+func (m *MockStore) CreateChildPayee(ctx context.Context, arg db.CreateChildPayeeParams) (db.ChildPayee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChildPayee", ctx, arg)
+	ret0, _ := ret[0].(db.ChildPayee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChildPayee indicates an expected call of CreateChildPayee.
+func (mr *MockStoreMockRecorder) CreateChildPayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChildPayee", reflect.TypeOf((*MockStore)(nil).CreateChildPayee), ctx, arg)
+}
+
+// CreateChildTransferApproval mocks base method.
+func (m *MockStore) CreateChildTransferApproval(ctx context.Context, arg db.CreateChildTransferApprovalParams) (db.ChildTransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChildTransferApproval", ctx, arg)
+	ret0, _ := ret[0].(db.ChildTransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChildTransferApproval indicates an expected call of CreateChildTransferApproval.
+func (mr *MockStoreMockRecorder) CreateChildTransferApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChildTransferApproval", reflect.TypeOf((*MockStore)(nil).CreateChildTransferApproval), ctx, arg)
+}
+
+// CreateChildTx mocks base method.
+func (m *MockStore) CreateChildTx(ctx context.Context, arg db.CreateChildTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChildTx", ctx, arg)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChildTx indicates an expected call of CreateChildTx.
+func (mr *MockStoreMockRecorder) CreateChildTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChildTx", reflect.TypeOf((*MockStore)(nil).CreateChildTx), ctx, arg)
+}
+
+// CreateCurrency mocks base method.
+func (m *MockStore) CreateCurrency(ctx context.Context, arg db.CreateCurrencyParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrency", ctx, arg)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCurrency indicates an expected call of CreateCurrency.
+func (mr *MockStoreMockRecorder) CreateCurrency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrency", reflect.TypeOf((*MockStore)(nil).CreateCurrency), ctx, arg)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", ctx, arg)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), ctx, arg)
+}
+
+// CreateExpense mocks base method.
+func (m *MockStore) CreateExpense(ctx context.Context, arg db.CreateExpenseParams) (db.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpense", ctx, arg)
+	ret0, _ := ret[0].(db.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExpense indicates an expected call of CreateExpense.
+func (mr *MockStoreMockRecorder) CreateExpense(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpense", reflect.TypeOf((*MockStore)(nil).CreateExpense), ctx, arg)
+}
+
+// CreateExpenseShare mocks base method.
+func (m *MockStore) CreateExpenseShare(ctx context.Context, arg db.CreateExpenseShareParams) (db.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpenseShare", ctx, arg)
+	ret0, _ := ret[0].(db.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExpenseShare indicates an expected call of CreateExpenseShare.
+func (mr *MockStoreMockRecorder) CreateExpenseShare(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpenseShare", reflect.TypeOf((*MockStore)(nil).CreateExpenseShare), ctx, arg)
+}
+
+// CreateExpenseTx mocks base method.
+func (m *MockStore) CreateExpenseTx(ctx context.Context, arg db.CreateExpenseTxParams) (db.CreateExpenseTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpenseTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateExpenseTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExpenseTx indicates an expected call of CreateExpenseTx.
+func (mr *MockStoreMockRecorder) CreateExpenseTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpenseTx", reflect.TypeOf((*MockStore)(nil).CreateExpenseTx), ctx, arg)
+}
+
+// CreateGuardianship mocks base method.
+func (m *MockStore) CreateGuardianship(ctx context.Context, arg db.CreateGuardianshipParams) (db.Guardianship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuardianship", ctx, arg)
+	ret0, _ := ret[0].(db.Guardianship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGuardianship indicates an expected call of CreateGuardianship.
+func (mr *MockStoreMockRecorder) CreateGuardianship(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuardianship", reflect.TypeOf((*MockStore)(nil).CreateGuardianship), ctx, arg)
+}
+
+// CreateHousehold mocks base method.
+func (m *MockStore) CreateHousehold(ctx context.Context, arg db.CreateHouseholdParams) (db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHousehold", ctx, arg)
+	ret0, _ := ret[0].(db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHousehold indicates an expected call of CreateHousehold.
+func (mr *MockStoreMockRecorder) CreateHousehold(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHousehold", reflect.TypeOf((*MockStore)(nil).CreateHousehold), ctx, arg)
+}
+
+// CreateHouseholdMember mocks base method.
+func (m *MockStore) CreateHouseholdMember(ctx context.Context, arg db.CreateHouseholdMemberParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdMember", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdMember indicates an expected call of CreateHouseholdMember.
+func (mr *MockStoreMockRecorder) CreateHouseholdMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdMember", reflect.TypeOf((*MockStore)(nil).CreateHouseholdMember), ctx, arg)
+}
+
+// CreateHouseholdSpend mocks base method.
+func (m *MockStore) CreateHouseholdSpend(ctx context.Context, arg db.CreateHouseholdSpendParams) (db.HouseholdSpend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdSpend", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdSpend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdSpend indicates an expected call of CreateHouseholdSpend.
+func (mr *MockStoreMockRecorder) CreateHouseholdSpend(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdSpend", reflect.TypeOf((*MockStore)(nil).CreateHouseholdSpend), ctx, arg)
+}
+
+// CreateHouseholdSpendVote mocks base method.
+func (m *MockStore) CreateHouseholdSpendVote(ctx context.Context, arg db.CreateHouseholdSpendVoteParams) (db.HouseholdSpendVote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdSpendVote", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdSpendVote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdSpendVote indicates an expected call of CreateHouseholdSpendVote.
+func (mr *MockStoreMockRecorder) CreateHouseholdSpendVote(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdSpendVote", reflect.TypeOf((*MockStore)(nil).CreateHouseholdSpendVote), ctx, arg)
+}
+
+// CreateHouseholdTx mocks base method.
+func (m *MockStore) CreateHouseholdTx(ctx context.Context, arg db.CreateHouseholdTxParams) (db.CreateHouseholdTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateHouseholdTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdTx indicates an expected call of CreateHouseholdTx.
+func (mr *MockStoreMockRecorder) CreateHouseholdTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdTx", reflect.TypeOf((*MockStore)(nil).CreateHouseholdTx), ctx, arg)
+}
+
+// CreateMoneyRequest mocks base method.
+func (m *MockStore) CreateMoneyRequest(ctx context.Context, arg db.CreateMoneyRequestParams) (db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMoneyRequest", ctx, arg)
+	ret0, _ := ret[0].(db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMoneyRequest indicates an expected call of CreateMoneyRequest.
+func (mr *MockStoreMockRecorder) CreateMoneyRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMoneyRequest", reflect.TypeOf((*MockStore)(nil).CreateMoneyRequest), ctx, arg)
+}
+
+// CreateMoneyRequestTx mocks base method.
+func (m *MockStore) CreateMoneyRequestTx(ctx context.Context, arg db.CreateMoneyRequestParams) (db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMoneyRequestTx", ctx, arg)
+	ret0, _ := ret[0].(db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMoneyRequestTx indicates an expected call of CreateMoneyRequestTx.
+func (mr *MockStoreMockRecorder) CreateMoneyRequestTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMoneyRequestTx", reflect.TypeOf((*MockStore)(nil).CreateMoneyRequestTx), ctx, arg)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(ctx context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", ctx, arg)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), ctx, arg)
+}
+
+// CreateOauthAuthorizationCode mocks base method.
+func (m *MockStore) CreateOauthAuthorizationCode(ctx context.Context, arg db.CreateOauthAuthorizationCodeParams) (db.OauthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOauthAuthorizationCode", ctx, arg)
+	ret0, _ := ret[0].(db.OauthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOauthAuthorizationCode indicates an expected call of CreateOauthAuthorizationCode.
+func (mr *MockStoreMockRecorder) CreateOauthAuthorizationCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOauthAuthorizationCode", reflect.TypeOf((*MockStore)(nil).CreateOauthAuthorizationCode), ctx, arg)
+}
+
+// CreateOauthClient mocks base method.
+func (m *MockStore) CreateOauthClient(ctx context.Context, arg db.CreateOauthClientParams) (db.OauthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOauthClient", ctx, arg)
+	ret0, _ := ret[0].(db.OauthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOauthClient indicates an expected call of CreateOauthClient.
+func (mr *MockStoreMockRecorder) CreateOauthClient(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOauthClient", reflect.TypeOf((*MockStore)(nil).CreateOauthClient), ctx, arg)
+}
+
+// CreateOutboxMessage mocks base method.
+func (m *MockStore) CreateOutboxMessage(ctx context.Context, arg db.CreateOutboxMessageParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxMessage", ctx, arg)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxMessage indicates an expected call of CreateOutboxMessage.
+func (mr *MockStoreMockRecorder) CreateOutboxMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxMessage", reflect.TypeOf((*MockStore)(nil).CreateOutboxMessage), ctx, arg)
+}
+
+// CreatePayee mocks base method.
+func (m *MockStore) CreatePayee(ctx context.Context, arg db.CreatePayeeParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayee", ctx, arg)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayee indicates an expected call of CreatePayee.
+func (mr *MockStoreMockRecorder) CreatePayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayee", reflect.TypeOf((*MockStore)(nil).CreatePayee), ctx, arg)
+}
+
+// CreateRevokedToken mocks base method.
+func (m *MockStore) CreateRevokedToken(ctx context.Context, arg db.CreateRevokedTokenParams) (db.RevokedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRevokedToken", ctx, arg)
+	ret0, _ := ret[0].(db.RevokedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRevokedToken indicates an expected call of CreateRevokedToken.
+func (mr *MockStoreMockRecorder) CreateRevokedToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRevokedToken", reflect.TypeOf((*MockStore)(nil).CreateRevokedToken), ctx, arg)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, arg)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), ctx, arg)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(ctx context.Context, arg db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", ctx, arg)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), ctx, arg)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, arg)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockStore) CreateUserTx(ctx context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockStoreMockRecorder) CreateUserTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), ctx, arg)
+}
+
+// CreateVerifyEmail mocks base method.
+func (m *MockStore) CreateVerifyEmail(ctx context.Context, arg db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmail", ctx, arg)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
+func (mr *MockStoreMockRecorder) CreateVerifyEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), ctx, arg)
+}
+
+// CreateWebhookDeliveries mocks base method.
+func (m *MockStore) CreateWebhookDeliveries(ctx context.Context, arg db.CreateWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDeliveries indicates an expected call of CreateWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CreateWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeliveries), ctx, arg)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(ctx context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), ctx, arg)
+}
+
+// CreateWebhookDeliveryTx mocks base method.
+func (m *MockStore) CreateWebhookDeliveryTx(ctx context.Context, arg db.CreateWebhookDeliveryTxParams) (db.CreateWebhookDeliveryTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveryTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateWebhookDeliveryTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDeliveryTx indicates an expected call of CreateWebhookDeliveryTx.
+func (mr *MockStoreMockRecorder) CreateWebhookDeliveryTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveryTx", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeliveryTx), ctx, arg)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStore) CreateWebhookEndpoint(ctx context.Context, arg db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) CreateWebhookEndpoint(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).CreateWebhookEndpoint), ctx, arg)
+}
+
+// CreateWebhookEvent mocks base method.
+func (m *MockStore) CreateWebhookEvent(ctx context.Context, arg db.CreateWebhookEventParams) (db.WebhookEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEvent", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEvent indicates an expected call of CreateWebhookEvent.
+func (mr *MockStoreMockRecorder) CreateWebhookEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEvent", reflect.TypeOf((*MockStore)(nil).CreateWebhookEvent), ctx, arg)
+}
+
+// DecideChildTransferApproval mocks base method.
+func (m *MockStore) DecideChildTransferApproval(ctx context.Context, arg db.DecideChildTransferApprovalParams) (db.ChildTransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideChildTransferApproval", ctx, arg)
+	ret0, _ := ret[0].(db.ChildTransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideChildTransferApproval indicates an expected call of DecideChildTransferApproval.
+func (mr *MockStoreMockRecorder) DecideChildTransferApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideChildTransferApproval", reflect.TypeOf((*MockStore)(nil).DecideChildTransferApproval), ctx, arg)
+}
+
+// DecideChildTransferTx mocks base method.
+func (m *MockStore) DecideChildTransferTx(ctx context.Context, arg db.DecideChildTransferTxParams) (db.DecideChildTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideChildTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.DecideChildTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideChildTransferTx indicates an expected call of DecideChildTransferTx.
+func (mr *MockStoreMockRecorder) DecideChildTransferTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideChildTransferTx", reflect.TypeOf((*MockStore)(nil).DecideChildTransferTx), ctx, arg)
+}
+
+// DecideHouseholdSpend mocks base method.
+func (m *MockStore) DecideHouseholdSpend(ctx context.Context, arg db.DecideHouseholdSpendParams) (db.HouseholdSpend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideHouseholdSpend", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdSpend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideHouseholdSpend indicates an expected call of DecideHouseholdSpend.
+func (mr *MockStoreMockRecorder) DecideHouseholdSpend(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideHouseholdSpend", reflect.TypeOf((*MockStore)(nil).DecideHouseholdSpend), ctx, arg)
+}
+
+// DecideMoneyRequest mocks base method.
+func (m *MockStore) DecideMoneyRequest(ctx context.Context, arg db.DecideMoneyRequestParams) (db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideMoneyRequest", ctx, arg)
+	ret0, _ := ret[0].(db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideMoneyRequest indicates an expected call of DecideMoneyRequest.
+func (mr *MockStoreMockRecorder) DecideMoneyRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideMoneyRequest", reflect.TypeOf((*MockStore)(nil).DecideMoneyRequest), ctx, arg)
+}
+
+// DecideMoneyRequestTx mocks base method.
+func (m *MockStore) DecideMoneyRequestTx(ctx context.Context, arg db.DecideMoneyRequestTxParams) (db.DecideMoneyRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideMoneyRequestTx", ctx, arg)
+	ret0, _ := ret[0].(db.DecideMoneyRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideMoneyRequestTx indicates an expected call of DecideMoneyRequestTx.
+func (mr *MockStoreMockRecorder) DecideMoneyRequestTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideMoneyRequestTx", reflect.TypeOf((*MockStore)(nil).DecideMoneyRequestTx), ctx, arg)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), ctx, id)
+}
+
+// DeleteAccountHolder mocks base method.
+func (m *MockStore) DeleteAccountHolder(ctx context.Context, arg db.DeleteAccountHolderParams) (db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountHolder", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccountHolder indicates an expected call of DeleteAccountHolder.
+func (mr *MockStoreMockRecorder) DeleteAccountHolder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountHolder", reflect.TypeOf((*MockStore)(nil).DeleteAccountHolder), ctx, arg)
+}
+
+// DeleteAccountTx mocks base method.
+func (m *MockStore) DeleteAccountTx(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountTx", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountTx indicates an expected call of DeleteAccountTx.
+func (mr *MockStoreMockRecorder) DeleteAccountTx(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountTx", reflect.TypeOf((*MockStore)(nil).DeleteAccountTx), ctx, id)
+}
+
+// DeleteAllowance mocks base method.
+func (m *MockStore) DeleteAllowance(ctx context.Context, arg db.DeleteAllowanceParams) (db.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllowance", ctx, arg)
+	ret0, _ := ret[0].(db.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAllowance indicates an expected call of DeleteAllowance.
+func (mr *MockStoreMockRecorder) DeleteAllowance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllowance", reflect.TypeOf((*MockStore)(nil).DeleteAllowance), ctx, arg)
+}
+
+// DeleteChildPayee mocks base method.
+func (m *MockStore) DeleteChildPayee(ctx context.Context, arg db.DeleteChildPayeeParams) (db.ChildPayee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChildPayee", ctx, arg)
+	ret0, _ := ret[0].(db.ChildPayee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChildPayee indicates an expected call of DeleteChildPayee.
+func (mr *MockStoreMockRecorder) DeleteChildPayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChildPayee", reflect.TypeOf((*MockStore)(nil).DeleteChildPayee), ctx, arg)
+}
+
+// DeleteExpiredRevokedTokens mocks base method.
+func (m *MockStore) DeleteExpiredRevokedTokens(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRevokedTokens", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredRevokedTokens indicates an expected call of DeleteExpiredRevokedTokens.
+func (mr *MockStoreMockRecorder) DeleteExpiredRevokedTokens(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRevokedTokens", reflect.TypeOf((*MockStore)(nil).DeleteExpiredRevokedTokens), ctx)
+}
+
+// DeleteHouseholdMember mocks base method.
+func (m *MockStore) DeleteHouseholdMember(ctx context.Context, arg db.DeleteHouseholdMemberParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdMember", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHouseholdMember indicates an expected call of DeleteHouseholdMember.
+func (mr *MockStoreMockRecorder) DeleteHouseholdMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdMember", reflect.TypeOf((*MockStore)(nil).DeleteHouseholdMember), ctx, arg)
+}
+
+// DeleteHouseholdMemberPendingVotes mocks base method.
+func (m *MockStore) DeleteHouseholdMemberPendingVotes(ctx context.Context, arg db.DeleteHouseholdMemberPendingVotesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdMemberPendingVotes", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouseholdMemberPendingVotes indicates an expected call of DeleteHouseholdMemberPendingVotes.
+func (mr *MockStoreMockRecorder) DeleteHouseholdMemberPendingVotes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdMemberPendingVotes", reflect.TypeOf((*MockStore)(nil).DeleteHouseholdMemberPendingVotes), ctx, arg)
+}
+
+// DeleteOauthConsent mocks base method.
+func (m *MockStore) DeleteOauthConsent(ctx context.Context, arg db.DeleteOauthConsentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOauthConsent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOauthConsent indicates an expected call of DeleteOauthConsent.
+func (mr *MockStoreMockRecorder) DeleteOauthConsent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOauthConsent", reflect.TypeOf((*MockStore)(nil).DeleteOauthConsent), ctx, arg)
+}
+
+// DeletePayee mocks base method.
+func (m *MockStore) DeletePayee(ctx context.Context, arg db.DeletePayeeParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePayee", ctx, arg)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePayee indicates an expected call of DeletePayee.
+func (mr *MockStoreMockRecorder) DeletePayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePayee", reflect.TypeOf((*MockStore)(nil).DeletePayee), ctx, arg)
+}
+
+// DeletePublishedOutboxMessages mocks base method.
+func (m *MockStore) DeletePublishedOutboxMessages(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedOutboxMessages", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePublishedOutboxMessages indicates an expected call of DeletePublishedOutboxMessages.
+func (mr *MockStoreMockRecorder) DeletePublishedOutboxMessages(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedOutboxMessages", reflect.TypeOf((*MockStore)(nil).DeletePublishedOutboxMessages), ctx, before)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockStore) DeleteWebhookEndpoint(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DeleteWebhookEndpoint(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebhookEndpoint), ctx, id)
+}
+
+// DispatchWebhookEventsTx mocks base method.
+func (m *MockStore) DispatchWebhookEventsTx(ctx context.Context, arg db.DispatchWebhookEventsTxParams) (db.DispatchWebhookEventsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchWebhookEventsTx", ctx, arg)
+	ret0, _ := ret[0].(db.DispatchWebhookEventsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DispatchWebhookEventsTx indicates an expected call of DispatchWebhookEventsTx.
+func (mr *MockStoreMockRecorder) DispatchWebhookEventsTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchWebhookEventsTx", reflect.TypeOf((*MockStore)(nil).DispatchWebhookEventsTx), ctx, arg)
+}
+
+// ExpireMoneyRequest mocks base method.
+func (m *MockStore) ExpireMoneyRequest(ctx context.Context, id int64) (db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireMoneyRequest", ctx, id)
+	ret0, _ := ret[0].(db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireMoneyRequest indicates an expected call of ExpireMoneyRequest.
+func (mr *MockStoreMockRecorder) ExpireMoneyRequest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireMoneyRequest", reflect.TypeOf((*MockStore)(nil).ExpireMoneyRequest), ctx, id)
+}
+
+// ExpireMoneyRequestTx mocks base method.
+func (m *MockStore) ExpireMoneyRequestTx(ctx context.Context, id int64) (db.ExpireMoneyRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireMoneyRequestTx", ctx, id)
+	ret0, _ := ret[0].(db.ExpireMoneyRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireMoneyRequestTx indicates an expected call of ExpireMoneyRequestTx.
+func (mr *MockStoreMockRecorder) ExpireMoneyRequestTx(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireMoneyRequestTx", reflect.TypeOf((*MockStore)(nil).ExpireMoneyRequestTx), ctx, id)
+}
+
+// GetAccountById mocks base method.
+func (m *MockStore) GetAccountById(ctx context.Context, id int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountById", ctx, id)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountById indicates an expected call of GetAccountById.
+func (mr *MockStoreMockRecorder) GetAccountById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountById", reflect.TypeOf((*MockStore)(nil).GetAccountById), ctx, id)
+}
+
+// GetAccountByIdForUpdate mocks base method.
+func (m *MockStore) GetAccountByIdForUpdate(ctx context.Context, id int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByIdForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByIdForUpdate indicates an expected call of GetAccountByIdForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountByIdForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByIdForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountByIdForUpdate), ctx, id)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(ctx context.Context, number string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", ctx, number)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), ctx, number)
+}
+
+// GetAccountForHolder mocks base method.
+func (m *MockStore) GetAccountForHolder(ctx context.Context, arg db.GetAccountForHolderParams) (db.GetAccountForHolderRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForHolder", ctx, arg)
+	ret0, _ := ret[0].(db.GetAccountForHolderRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForHolder indicates an expected call of GetAccountForHolder.
+func (mr *MockStoreMockRecorder) GetAccountForHolder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForHolder", reflect.TypeOf((*MockStore)(nil).GetAccountForHolder), ctx, arg)
+}
+
+// GetAccountHolder mocks base method.
+func (m *MockStore) GetAccountHolder(ctx context.Context, arg db.GetAccountHolderParams) (db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountHolder", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountHolder indicates an expected call of GetAccountHolder.
+func (mr *MockStoreMockRecorder) GetAccountHolder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountHolder", reflect.TypeOf((*MockStore)(nil).GetAccountHolder), ctx, arg)
+}
+
+// GetAccounts mocks base method.
+func (m *MockStore) GetAccounts(ctx context.Context, arg db.GetAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccounts", ctx, arg)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccounts indicates an expected call of GetAccounts.
+func (mr *MockStoreMockRecorder) GetAccounts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccounts", reflect.TypeOf((*MockStore)(nil).GetAccounts), ctx, arg)
+}
+
+// GetAllTransferFromAAccount mocks base method.
+func (m *MockStore) GetAllTransferFromAAccount(ctx context.Context, arg db.GetAllTransferFromAAccountParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTransferFromAAccount", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTransferFromAAccount indicates an expected call of GetAllTransferFromAAccount.
+func (mr *MockStoreMockRecorder) GetAllTransferFromAAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransferFromAAccount", reflect.TypeOf((*MockStore)(nil).GetAllTransferFromAAccount), ctx, arg)
+}
+
+// GetAllTransfersBetweenTwoAccounts mocks base method.
+func (m *MockStore) GetAllTransfersBetweenTwoAccounts(ctx context.Context, arg db.GetAllTransfersBetweenTwoAccountsParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTransfersBetweenTwoAccounts", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTransfersBetweenTwoAccounts indicates an expected call of GetAllTransfersBetweenTwoAccounts.
+func (mr *MockStoreMockRecorder) GetAllTransfersBetweenTwoAccounts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransfersBetweenTwoAccounts", reflect.TypeOf((*MockStore)(nil).GetAllTransfersBetweenTwoAccounts), ctx, arg)
+}
+
+// GetApiKeyByPrefix mocks base method.
+func (m *MockStore) GetApiKeyByPrefix(ctx context.Context, prefix string) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeyByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKeyByPrefix indicates an expected call of GetApiKeyByPrefix.
+func (mr *MockStoreMockRecorder) GetApiKeyByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeyByPrefix", reflect.TypeOf((*MockStore)(nil).GetApiKeyByPrefix), ctx, prefix)
+}
+
+// GetChildControls mocks base method.
+func (m *MockStore) GetChildControls(ctx context.Context, child string) (db.ChildControl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildControls", ctx, child)
+	ret0, _ := ret[0].(db.ChildControl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildControls indicates an expected call of GetChildControls.
+func (mr *MockStoreMockRecorder) GetChildControls(ctx, child any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildControls", reflect.TypeOf((*MockStore)(nil).GetChildControls), ctx, child)
+}
+
+// GetChildPayee mocks base method.
+func (m *MockStore) GetChildPayee(ctx context.Context, arg db.GetChildPayeeParams) (db.ChildPayee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildPayee", ctx, arg)
+	ret0, _ := ret[0].(db.ChildPayee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildPayee indicates an expected call of GetChildPayee.
+func (mr *MockStoreMockRecorder) GetChildPayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildPayee", reflect.TypeOf((*MockStore)(nil).GetChildPayee), ctx, arg)
+}
+
+// GetChildTransferApprovalForUpdate mocks base method.
+func (m *MockStore) GetChildTransferApprovalForUpdate(ctx context.Context, id int64) (db.ChildTransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildTransferApprovalForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.ChildTransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildTransferApprovalForUpdate indicates an expected call of GetChildTransferApprovalForUpdate.
+func (mr *MockStoreMockRecorder) GetChildTransferApprovalForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildTransferApprovalForUpdate", reflect.TypeOf((*MockStore)(nil).GetChildTransferApprovalForUpdate), ctx, id)
+}
+
+// GetCurrency mocks base method.
+func (m *MockStore) GetCurrency(ctx context.Context, code string) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrency", ctx, code)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrency indicates an expected call of GetCurrency.
+func (mr *MockStoreMockRecorder) GetCurrency(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockStore)(nil).GetCurrency), ctx, code)
+}
+
+// GetDefaultAccount mocks base method.
+func (m *MockStore) GetDefaultAccount(ctx context.Context, arg db.GetDefaultAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultAccount", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultAccount indicates an expected call of GetDefaultAccount.
+func (mr *MockStoreMockRecorder) GetDefaultAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultAccount", reflect.TypeOf((*MockStore)(nil).GetDefaultAccount), ctx, arg)
+}
+
+// GetDueAllowanceForUpdate mocks base method.
+func (m *MockStore) GetDueAllowanceForUpdate(ctx context.Context, id int64) (db.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueAllowanceForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueAllowanceForUpdate indicates an expected call of GetDueAllowanceForUpdate.
+func (mr *MockStoreMockRecorder) GetDueAllowanceForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueAllowanceForUpdate", reflect.TypeOf((*MockStore)(nil).GetDueAllowanceForUpdate), ctx, id)
+}
+
+// GetEntriesByAccountId mocks base method.
+func (m *MockStore) GetEntriesByAccountId(ctx context.Context, arg db.GetEntriesByAccountIdParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesByAccountId", ctx, arg)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesByAccountId indicates an expected call of GetEntriesByAccountId.
+func (mr *MockStoreMockRecorder) GetEntriesByAccountId(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByAccountId", reflect.TypeOf((*MockStore)(nil).GetEntriesByAccountId), ctx, arg)
+}
+
+// GetEntryById mocks base method.
+func (m *MockStore) GetEntryById(ctx context.Context, id int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryById", ctx, id)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryById indicates an expected call of GetEntryById.
+func (mr *MockStoreMockRecorder) GetEntryById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryById", reflect.TypeOf((*MockStore)(nil).GetEntryById), ctx, id)
+}
+
+// GetExpense mocks base method.
+func (m *MockStore) GetExpense(ctx context.Context, id int64) (db.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpense", ctx, id)
+	ret0, _ := ret[0].(db.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpense indicates an expected call of GetExpense.
+func (mr *MockStoreMockRecorder) GetExpense(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpense", reflect.TypeOf((*MockStore)(nil).GetExpense), ctx, id)
+}
+
+// GetExpenseShareForUpdate mocks base method.
+func (m *MockStore) GetExpenseShareForUpdate(ctx context.Context, arg db.GetExpenseShareForUpdateParams) (db.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpenseShareForUpdate", ctx, arg)
+	ret0, _ := ret[0].(db.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpenseShareForUpdate indicates an expected call of GetExpenseShareForUpdate.
+func (mr *MockStoreMockRecorder) GetExpenseShareForUpdate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenseShareForUpdate", reflect.TypeOf((*MockStore)(nil).GetExpenseShareForUpdate), ctx, arg)
+}
+
+// GetGuardianship mocks base method.
+func (m *MockStore) GetGuardianship(ctx context.Context, arg db.GetGuardianshipParams) (db.Guardianship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuardianship", ctx, arg)
+	ret0, _ := ret[0].(db.Guardianship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuardianship indicates an expected call of GetGuardianship.
+func (mr *MockStoreMockRecorder) GetGuardianship(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuardianship", reflect.TypeOf((*MockStore)(nil).GetGuardianship), ctx, arg)
+}
+
+// GetHousehold mocks base method.
+func (m *MockStore) GetHousehold(ctx context.Context, id int64) (db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHousehold", ctx, id)
+	ret0, _ := ret[0].(db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHousehold indicates an expected call of GetHousehold.
+func (mr *MockStoreMockRecorder) GetHousehold(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHousehold", reflect.TypeOf((*MockStore)(nil).GetHousehold), ctx, id)
+}
+
+// GetHouseholdMember mocks base method.
+func (m *MockStore) GetHouseholdMember(ctx context.Context, arg db.GetHouseholdMemberParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdMember", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdMember indicates an expected call of GetHouseholdMember.
+func (mr *MockStoreMockRecorder) GetHouseholdMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdMember", reflect.TypeOf((*MockStore)(nil).GetHouseholdMember), ctx, arg)
+}
+
+// GetHouseholdSpendForUpdate mocks base method.
+func (m *MockStore) GetHouseholdSpendForUpdate(ctx context.Context, arg db.GetHouseholdSpendForUpdateParams) (db.HouseholdSpend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdSpendForUpdate", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdSpend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdSpendForUpdate indicates an expected call of GetHouseholdSpendForUpdate.
+func (mr *MockStoreMockRecorder) GetHouseholdSpendForUpdate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdSpendForUpdate", reflect.TypeOf((*MockStore)(nil).GetHouseholdSpendForUpdate), ctx, arg)
+}
+
+// GetLastEntryId mocks base method.
+func (m *MockStore) GetLastEntryId(ctx context.Context, accountID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastEntryId", ctx, accountID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastEntryId indicates an expected call of GetLastEntryId.
+func (mr *MockStoreMockRecorder) GetLastEntryId(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastEntryId", reflect.TypeOf((*MockStore)(nil).GetLastEntryId), ctx, accountID)
+}
+
+// GetMoneyRequestForUpdate mocks base method.
+func (m *MockStore) GetMoneyRequestForUpdate(ctx context.Context, id int64) (db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMoneyRequestForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMoneyRequestForUpdate indicates an expected call of GetMoneyRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetMoneyRequestForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoneyRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetMoneyRequestForUpdate), ctx, id)
+}
+
+// GetOauthClient mocks base method.
+func (m *MockStore) GetOauthClient(ctx context.Context, id uuid.UUID) (db.OauthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOauthClient", ctx, id)
+	ret0, _ := ret[0].(db.OauthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOauthClient indicates an expected call of GetOauthClient.
+func (mr *MockStoreMockRecorder) GetOauthClient(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOauthClient", reflect.TypeOf((*MockStore)(nil).GetOauthClient), ctx, id)
+}
+
+// GetOauthConsent mocks base method.
+func (m *MockStore) GetOauthConsent(ctx context.Context, arg db.GetOauthConsentParams) (db.OauthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOauthConsent", ctx, arg)
+	ret0, _ := ret[0].(db.OauthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOauthConsent indicates an expected call of GetOauthConsent.
+func (mr *MockStoreMockRecorder) GetOauthConsent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOauthConsent", reflect.TypeOf((*MockStore)(nil).GetOauthConsent), ctx, arg)
+}
+
+// GetPayee mocks base method.
+func (m *MockStore) GetPayee(ctx context.Context, arg db.GetPayeeParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayee", ctx, arg)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayee indicates an expected call of GetPayee.
+func (mr *MockStoreMockRecorder) GetPayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayee", reflect.TypeOf((*MockStore)(nil).GetPayee), ctx, arg)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, id)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), ctx, id)
+}
+
+// GetTransferById mocks base method.
+func (m *MockStore) GetTransferById(ctx context.Context, id int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferById", ctx, id)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferById indicates an expected call of GetTransferById.
+func (mr *MockStoreMockRecorder) GetTransferById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferById", reflect.TypeOf((*MockStore)(nil).GetTransferById), ctx, id)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockStore) GetUserByUsername(ctx context.Context, username string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", ctx, username)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockStoreMockRecorder) GetUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockStore)(nil).GetUserByUsername), ctx, username)
+}
+
+// GetUserByUsernameForUpdate mocks base method.
+func (m *MockStore) GetUserByUsernameForUpdate(ctx context.Context, username string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsernameForUpdate", ctx, username)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsernameForUpdate indicates an expected call of GetUserByUsernameForUpdate.
+func (mr *MockStoreMockRecorder) GetUserByUsernameForUpdate(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsernameForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserByUsernameForUpdate), ctx, username)
+}
+
+// GetUsersAccounts mocks base method.
+func (m *MockStore) GetUsersAccounts(ctx context.Context, username string) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersAccounts", ctx, username)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersAccounts indicates an expected call of GetUsersAccounts.
+func (mr *MockStoreMockRecorder) GetUsersAccounts(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersAccounts", reflect.TypeOf((*MockStore)(nil).GetUsersAccounts), ctx, username)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), ctx, id)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockStore) GetWebhookEndpoint(ctx context.Context, id int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", ctx, id)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockStoreMockRecorder) GetWebhookEndpoint(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).GetWebhookEndpoint), ctx, id)
+}
+
+// GetWebhookEvent mocks base method.
+func (m *MockStore) GetWebhookEvent(ctx context.Context, id int64) (db.WebhookEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEvent", ctx, id)
+	ret0, _ := ret[0].(db.WebhookEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEvent indicates an expected call of GetWebhookEvent.
+func (mr *MockStoreMockRecorder) GetWebhookEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEvent", reflect.TypeOf((*MockStore)(nil).GetWebhookEvent), ctx, id)
+}
+
+// InviteAccountHolderTx mocks base method.
+func (m *MockStore) InviteAccountHolderTx(ctx context.Context, arg db.InviteAccountHolderTxParams) (db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteAccountHolderTx", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InviteAccountHolderTx indicates an expected call of InviteAccountHolderTx.
+func (mr *MockStoreMockRecorder) InviteAccountHolderTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteAccountHolderTx", reflect.TypeOf((*MockStore)(nil).InviteAccountHolderTx), ctx, arg)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockStore) IsTokenRevoked(ctx context.Context, arg db.IsTokenRevokedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockStoreMockRecorder) IsTokenRevoked(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockStore)(nil).IsTokenRevoked), ctx, arg)
+}
+
+// ListAccountActivity mocks base method.
+func (m *MockStore) ListAccountActivity(ctx context.Context, arg db.ListAccountActivityParams) ([]db.ListAccountActivityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountActivity", ctx, arg)
+	ret0, _ := ret[0].([]db.ListAccountActivityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountActivity indicates an expected call of ListAccountActivity.
+func (mr *MockStoreMockRecorder) ListAccountActivity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountActivity", reflect.TypeOf((*MockStore)(nil).ListAccountActivity), ctx, arg)
+}
+
+// ListAccountAuditEvents mocks base method.
+func (m *MockStore) ListAccountAuditEvents(ctx context.Context, arg db.ListAccountAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountAuditEvents", ctx, arg)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountAuditEvents indicates an expected call of ListAccountAuditEvents.
+func (mr *MockStoreMockRecorder) ListAccountAuditEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAccountAuditEvents), ctx, arg)
+}
+
+// ListAccountHolders mocks base method.
+func (m *MockStore) ListAccountHolders(ctx context.Context, accountID int64) ([]db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountHolders", ctx, accountID)
+	ret0, _ := ret[0].([]db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountHolders indicates an expected call of ListAccountHolders.
+func (mr *MockStoreMockRecorder) ListAccountHolders(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountHolders", reflect.TypeOf((*MockStore)(nil).ListAccountHolders), ctx, accountID)
+}
+
+// ListAccountNumbers mocks base method.
+func (m *MockStore) ListAccountNumbers(ctx context.Context, ids []int64) ([]db.ListAccountNumbersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountNumbers", ctx, ids)
+	ret0, _ := ret[0].([]db.ListAccountNumbersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountNumbers indicates an expected call of ListAccountNumbers.
+func (mr *MockStoreMockRecorder) ListAccountNumbers(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountNumbers", reflect.TypeOf((*MockStore)(nil).ListAccountNumbers), ctx, ids)
+}
+
+// ListAccountTransfers mocks base method.
+func (m *MockStore) ListAccountTransfers(ctx context.Context, arg db.ListAccountTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransfers", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransfers indicates an expected call of ListAccountTransfers.
+func (mr *MockStoreMockRecorder) ListAccountTransfers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransfers", reflect.TypeOf((*MockStore)(nil).ListAccountTransfers), ctx, arg)
+}
+
+// ListApiKeys mocks base method.
+func (m *MockStore) ListApiKeys(ctx context.Context, arg db.ListApiKeysParams) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApiKeys", ctx, arg)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApiKeys indicates an expected call of ListApiKeys.
+func (mr *MockStoreMockRecorder) ListApiKeys(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApiKeys", reflect.TypeOf((*MockStore)(nil).ListApiKeys), ctx, arg)
+}
+
+// ListChildAllowances mocks base method.
+func (m *MockStore) ListChildAllowances(ctx context.Context, child string) ([]db.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChildAllowances", ctx, child)
+	ret0, _ := ret[0].([]db.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChildAllowances indicates an expected call of ListChildAllowances.
+func (mr *MockStoreMockRecorder) ListChildAllowances(ctx, child any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildAllowances", reflect.TypeOf((*MockStore)(nil).ListChildAllowances), ctx, child)
+}
+
+// ListChildPayees mocks base method.
+func (m *MockStore) ListChildPayees(ctx context.Context, child string) ([]db.ChildPayee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChildPayees", ctx, child)
+	ret0, _ := ret[0].([]db.ChildPayee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChildPayees indicates an expected call of ListChildPayees.
+func (mr *MockStoreMockRecorder) ListChildPayees(ctx, child any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildPayees", reflect.TypeOf((*MockStore)(nil).ListChildPayees), ctx, child)
+}
+
+// ListChildren mocks base method.
+func (m *MockStore) ListChildren(ctx context.Context, guardian string) ([]db.Guardianship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChildren", ctx, guardian)
+	ret0, _ := ret[0].([]db.Guardianship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChildren indicates an expected call of ListChildren.
+func (mr *MockStoreMockRecorder) ListChildren(ctx, guardian any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildren", reflect.TypeOf((*MockStore)(nil).ListChildren), ctx, guardian)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockStore) ListCurrencies(ctx context.Context) ([]db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", ctx)
+	ret0, _ := ret[0].([]db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockStoreMockRecorder) ListCurrencies(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockStore)(nil).ListCurrencies), ctx)
+}
+
+// ListDueAllowances mocks base method.
+func (m *MockStore) ListDueAllowances(ctx context.Context, arg db.ListDueAllowancesParams) ([]db.ListDueAllowancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueAllowances", ctx, arg)
+	ret0, _ := ret[0].([]db.ListDueAllowancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueAllowances indicates an expected call of ListDueAllowances.
+func (mr *MockStoreMockRecorder) ListDueAllowances(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueAllowances", reflect.TypeOf((*MockStore)(nil).ListDueAllowances), ctx, arg)
+}
+
+// ListExpenseShares mocks base method.
+func (m *MockStore) ListExpenseShares(ctx context.Context, expenseID int64) ([]db.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpenseShares", ctx, expenseID)
+	ret0, _ := ret[0].([]db.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpenseShares indicates an expected call of ListExpenseShares.
+func (mr *MockStoreMockRecorder) ListExpenseShares(ctx, expenseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpenseShares", reflect.TypeOf((*MockStore)(nil).ListExpenseShares), ctx, expenseID)
+}
+
+// ListExpiredMoneyRequests mocks base method.
+func (m *MockStore) ListExpiredMoneyRequests(ctx context.Context, arg db.ListExpiredMoneyRequestsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredMoneyRequests", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredMoneyRequests indicates an expected call of ListExpiredMoneyRequests.
+func (mr *MockStoreMockRecorder) ListExpiredMoneyRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredMoneyRequests", reflect.TypeOf((*MockStore)(nil).ListExpiredMoneyRequests), ctx, arg)
+}
+
+// ListGuardians mocks base method.
+func (m *MockStore) ListGuardians(ctx context.Context, child string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGuardians", ctx, child)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGuardians indicates an expected call of ListGuardians.
+func (mr *MockStoreMockRecorder) ListGuardians(ctx, child any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGuardians", reflect.TypeOf((*MockStore)(nil).ListGuardians), ctx, child)
+}
+
+// ListHeldAccounts mocks base method.
+func (m *MockStore) ListHeldAccounts(ctx context.Context, arg db.ListHeldAccountsParams) ([]db.ListHeldAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHeldAccounts", ctx, arg)
+	ret0, _ := ret[0].([]db.ListHeldAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHeldAccounts indicates an expected call of ListHeldAccounts.
+func (mr *MockStoreMockRecorder) ListHeldAccounts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHeldAccounts", reflect.TypeOf((*MockStore)(nil).ListHeldAccounts), ctx, arg)
+}
+
+// ListHouseholdContributions mocks base method.
+func (m *MockStore) ListHouseholdContributions(ctx context.Context, arg db.ListHouseholdContributionsParams) ([]db.ListHouseholdContributionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHouseholdContributions", ctx, arg)
+	ret0, _ := ret[0].([]db.ListHouseholdContributionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHouseholdContributions indicates an expected call of ListHouseholdContributions.
+func (mr *MockStoreMockRecorder) ListHouseholdContributions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHouseholdContributions", reflect.TypeOf((*MockStore)(nil).ListHouseholdContributions), ctx, arg)
+}
+
+// ListHouseholdMembers mocks base method.
+func (m *MockStore) ListHouseholdMembers(ctx context.Context, householdID int64) ([]db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHouseholdMembers", ctx, householdID)
+	ret0, _ := ret[0].([]db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHouseholdMembers indicates an expected call of ListHouseholdMembers.
+func (mr *MockStoreMockRecorder) ListHouseholdMembers(ctx, householdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHouseholdMembers", reflect.TypeOf((*MockStore)(nil).ListHouseholdMembers), ctx, householdID)
+}
+
+// ListHouseholdSpends mocks base method.
+func (m *MockStore) ListHouseholdSpends(ctx context.Context, arg db.ListHouseholdSpendsParams) ([]db.HouseholdSpend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHouseholdSpends", ctx, arg)
+	ret0, _ := ret[0].([]db.HouseholdSpend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHouseholdSpends indicates an expected call of ListHouseholdSpends.
+func (mr *MockStoreMockRecorder) ListHouseholdSpends(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHouseholdSpends", reflect.TypeOf((*MockStore)(nil).ListHouseholdSpends), ctx, arg)
+}
+
+// ListMemberHouseholds mocks base method.
+func (m *MockStore) ListMemberHouseholds(ctx context.Context, username string) ([]db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemberHouseholds", ctx, username)
+	ret0, _ := ret[0].([]db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemberHouseholds indicates an expected call of ListMemberHouseholds.
+func (mr *MockStoreMockRecorder) ListMemberHouseholds(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemberHouseholds", reflect.TypeOf((*MockStore)(nil).ListMemberHouseholds), ctx, username)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(ctx context.Context, arg db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, arg)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), ctx, arg)
+}
+
+// ListOauthConsents mocks base method.
+func (m *MockStore) ListOauthConsents(ctx context.Context, username string) ([]db.OauthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOauthConsents", ctx, username)
+	ret0, _ := ret[0].([]db.OauthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOauthConsents indicates an expected call of ListOauthConsents.
+func (mr *MockStoreMockRecorder) ListOauthConsents(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOauthConsents", reflect.TypeOf((*MockStore)(nil).ListOauthConsents), ctx, username)
+}
+
+// ListOutstandingBalances mocks base method.
+func (m *MockStore) ListOutstandingBalances(ctx context.Context, username string) ([]db.ListOutstandingBalancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutstandingBalances", ctx, username)
+	ret0, _ := ret[0].([]db.ListOutstandingBalancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutstandingBalances indicates an expected call of ListOutstandingBalances.
+func (mr *MockStoreMockRecorder) ListOutstandingBalances(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutstandingBalances", reflect.TypeOf((*MockStore)(nil).ListOutstandingBalances), ctx, username)
+}
+
+// ListPayees mocks base method.
+func (m *MockStore) ListPayees(ctx context.Context, owner string) ([]db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayees", ctx, owner)
+	ret0, _ := ret[0].([]db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayees indicates an expected call of ListPayees.
+func (mr *MockStoreMockRecorder) ListPayees(ctx, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayees", reflect.TypeOf((*MockStore)(nil).ListPayees), ctx, owner)
+}
+
+// ListPendingAccountHolders mocks base method.
+func (m *MockStore) ListPendingAccountHolders(ctx context.Context, username string) ([]db.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingAccountHolders", ctx, username)
+	ret0, _ := ret[0].([]db.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingAccountHolders indicates an expected call of ListPendingAccountHolders.
+func (mr *MockStoreMockRecorder) ListPendingAccountHolders(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingAccountHolders", reflect.TypeOf((*MockStore)(nil).ListPendingAccountHolders), ctx, username)
+}
+
+// ListPendingChildTransferApprovals mocks base method.
+func (m *MockStore) ListPendingChildTransferApprovals(ctx context.Context, guardian string) ([]db.ChildTransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingChildTransferApprovals", ctx, guardian)
+	ret0, _ := ret[0].([]db.ChildTransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingChildTransferApprovals indicates an expected call of ListPendingChildTransferApprovals.
+func (mr *MockStoreMockRecorder) ListPendingChildTransferApprovals(ctx, guardian any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingChildTransferApprovals", reflect.TypeOf((*MockStore)(nil).ListPendingChildTransferApprovals), ctx, guardian)
+}
+
+// ListPendingMoneyRequests mocks base method.
+func (m *MockStore) ListPendingMoneyRequests(ctx context.Context, payer string) ([]db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingMoneyRequests", ctx, payer)
+	ret0, _ := ret[0].([]db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingMoneyRequests indicates an expected call of ListPendingMoneyRequests.
+func (mr *MockStoreMockRecorder) ListPendingMoneyRequests(ctx, payer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingMoneyRequests", reflect.TypeOf((*MockStore)(nil).ListPendingMoneyRequests), ctx, payer)
+}
+
+// ListPendingOutboxMessages mocks base method.
+func (m *MockStore) ListPendingOutboxMessages(ctx context.Context, arg db.ListPendingOutboxMessagesParams) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingOutboxMessages", ctx, arg)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingOutboxMessages indicates an expected call of ListPendingOutboxMessages.
+func (mr *MockStoreMockRecorder) ListPendingOutboxMessages(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingOutboxMessages", reflect.TypeOf((*MockStore)(nil).ListPendingOutboxMessages), ctx, arg)
+}
+
+// ListPendingWebhookEvents mocks base method.
+func (m *MockStore) ListPendingWebhookEvents(ctx context.Context, limit int32) ([]db.WebhookEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingWebhookEvents", ctx, limit)
+	ret0, _ := ret[0].([]db.WebhookEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingWebhookEvents indicates an expected call of ListPendingWebhookEvents.
+func (mr *MockStoreMockRecorder) ListPendingWebhookEvents(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingWebhookEvents", reflect.TypeOf((*MockStore)(nil).ListPendingWebhookEvents), ctx, limit)
+}
+
+// ListRequesterMoneyRequests mocks base method.
+func (m *MockStore) ListRequesterMoneyRequests(ctx context.Context, arg db.ListRequesterMoneyRequestsParams) ([]db.MoneyRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequesterMoneyRequests", ctx, arg)
+	ret0, _ := ret[0].([]db.MoneyRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequesterMoneyRequests indicates an expected call of ListRequesterMoneyRequests.
+func (mr *MockStoreMockRecorder) ListRequesterMoneyRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequesterMoneyRequests", reflect.TypeOf((*MockStore)(nil).ListRequesterMoneyRequests), ctx, arg)
+}
+
+// ListUserExpenses mocks base method.
+func (m *MockStore) ListUserExpenses(ctx context.Context, arg db.ListUserExpensesParams) ([]db.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserExpenses", ctx, arg)
+	ret0, _ := ret[0].([]db.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserExpenses indicates an expected call of ListUserExpenses.
+func (mr *MockStoreMockRecorder) ListUserExpenses(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserExpenses", reflect.TypeOf((*MockStore)(nil).ListUserExpenses), ctx, arg)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(ctx context.Context, arg db.ListWebhookDeliveriesParams) ([]db.ListWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.ListWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), ctx, arg)
 }
 
-// CreateAccount mocks base method.
-func (m *MockStore) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+// ListWebhookEndpoints mocks base method.
+func (m *MockStore) ListWebhookEndpoints(ctx context.Context, owner string) ([]db.WebhookEndpoint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", ctx, arg)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", ctx, owner)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(ctx, arg any) *gomock.Call {
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListWebhookEndpoints(ctx, owner any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpoints), ctx, owner)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.Entry, error) {
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(ctx context.Context, arg db.MarkNotificationReadParams) (db.Notification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", ctx, arg)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret0, _ := ret[0].(db.Notification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(ctx, arg any) *gomock.Call {
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), ctx, arg)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+// MarkOutboxMessagePublished mocks base method.
+func (m *MockStore) MarkOutboxMessagePublished(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", ctx, arg)
-	ret0, _ := ret[0].(db.Session)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkOutboxMessagePublished", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
+// MarkOutboxMessagePublished indicates an expected call of MarkOutboxMessagePublished.
+func (mr *MockStoreMockRecorder) MarkOutboxMessagePublished(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessagePublished", reflect.TypeOf((*MockStore)(nil).MarkOutboxMessagePublished), ctx, id)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(ctx context.Context, arg db.CreateTransferParams) (db.Transfer, error) {
+// MarkWebhookEventDispatched mocks base method.
+func (m *MockStore) MarkWebhookEventDispatched(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", ctx, arg)
-	ret0, _ := ret[0].(db.Transfer)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkWebhookEventDispatched", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(ctx, arg any) *gomock.Call {
+// MarkWebhookEventDispatched indicates an expected call of MarkWebhookEventDispatched.
+func (mr *MockStoreMockRecorder) MarkWebhookEventDispatched(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookEventDispatched", reflect.TypeOf((*MockStore)(nil).MarkWebhookEventDispatched), ctx, id)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+// NotifyAccountActivity mocks base method.
+func (m *MockStore) NotifyAccountActivity(ctx context.Context, accountID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
-	ret0, _ := ret[0].(db.User)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "NotifyAccountActivity", ctx, accountID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+// NotifyAccountActivity indicates an expected call of NotifyAccountActivity.
+func (mr *MockStoreMockRecorder) NotifyAccountActivity(ctx, accountID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyAccountActivity", reflect.TypeOf((*MockStore)(nil).NotifyAccountActivity), ctx, accountID)
 }
 
-// CreateUserTx mocks base method.
-func (m *MockStore) CreateUserTx(ctx context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+// PayAllowanceTx mocks base method.
+func (m *MockStore) PayAllowanceTx(ctx context.Context, id int64) (db.PayAllowanceTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUserTx", ctx, arg)
-	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret := m.ctrl.Call(m, "PayAllowanceTx", ctx, id)
+	ret0, _ := ret[0].(db.PayAllowanceTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUserTx indicates an expected call of CreateUserTx.
-func (mr *MockStoreMockRecorder) CreateUserTx(ctx, arg any) *gomock.Call {
+// PayAllowanceTx indicates an expected call of PayAllowanceTx.
+func (mr *MockStoreMockRecorder) PayAllowanceTx(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayAllowanceTx", reflect.TypeOf((*MockStore)(nil).PayAllowanceTx), ctx, id)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(ctx context.Context, id int64) error {
+// PingWebhookEndpointTx mocks base method.
+func (m *MockStore) PingWebhookEndpointTx(ctx context.Context, arg db.PingWebhookEndpointTxParams) (db.PingWebhookEndpointTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "PingWebhookEndpointTx", ctx, arg)
+	ret0, _ := ret[0].(db.PingWebhookEndpointTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(ctx, id any) *gomock.Call {
+// PingWebhookEndpointTx indicates an expected call of PingWebhookEndpointTx.
+func (mr *MockStoreMockRecorder) PingWebhookEndpointTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingWebhookEndpointTx", reflect.TypeOf((*MockStore)(nil).PingWebhookEndpointTx), ctx, arg)
 }
 
-// GetAccountById mocks base method.
-func (m *MockStore) GetAccountById(ctx context.Context, id int64) (db.Account, error) {
+// PublishOutboxTx mocks base method.
+func (m *MockStore) PublishOutboxTx(ctx context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountById", ctx, id)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "PublishOutboxTx", ctx, arg)
+	ret0, _ := ret[0].(db.PublishOutboxTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountById indicates an expected call of GetAccountById.
-func (mr *MockStoreMockRecorder) GetAccountById(ctx, id any) *gomock.Call {
+// PublishOutboxTx indicates an expected call of PublishOutboxTx.
+func (mr *MockStoreMockRecorder) PublishOutboxTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountById", reflect.TypeOf((*MockStore)(nil).GetAccountById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutboxTx", reflect.TypeOf((*MockStore)(nil).PublishOutboxTx), ctx, arg)
 }
 
-// GetAccountByIdForUpdate mocks base method.
-func (m *MockStore) GetAccountByIdForUpdate(ctx context.Context, id int64) (db.Account, error) {
+// RecordOutboxMessageFailure mocks base method.
+func (m *MockStore) RecordOutboxMessageFailure(ctx context.Context, arg db.RecordOutboxMessageFailureParams) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByIdForUpdate", ctx, id)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "RecordOutboxMessageFailure", ctx, arg)
+	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByIdForUpdate indicates an expected call of GetAccountByIdForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountByIdForUpdate(ctx, id any) *gomock.Call {
+// RecordOutboxMessageFailure indicates an expected call of RecordOutboxMessageFailure.
+func (mr *MockStoreMockRecorder) RecordOutboxMessageFailure(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByIdForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountByIdForUpdate), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxMessageFailure", reflect.TypeOf((*MockStore)(nil).RecordOutboxMessageFailure), ctx, arg)
 }
 
-// GetAccounts mocks base method.
-func (m *MockStore) GetAccounts(ctx context.Context, arg db.GetAccountsParams) ([]db.Account, error) {
+// RecordWebhookDeliveryAttempt mocks base method.
+func (m *MockStore) RecordWebhookDeliveryAttempt(ctx context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccounts", ctx, arg)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "RecordWebhookDeliveryAttempt", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccounts indicates an expected call of GetAccounts.
-func (mr *MockStoreMockRecorder) GetAccounts(ctx, arg any) *gomock.Call {
+// RecordWebhookDeliveryAttempt indicates an expected call of RecordWebhookDeliveryAttempt.
+func (mr *MockStoreMockRecorder) RecordWebhookDeliveryAttempt(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccounts", reflect.TypeOf((*MockStore)(nil).GetAccounts), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).RecordWebhookDeliveryAttempt), ctx, arg)
 }
 
-// GetAllTransferFromAAccount mocks base method.
-func (m *MockStore) GetAllTransferFromAAccount(ctx context.Context, arg db.GetAllTransferFromAAccountParams) ([]db.Transfer, error) {
+// RemoveAccountHolderTx mocks base method.
+func (m *MockStore) RemoveAccountHolderTx(ctx context.Context, arg db.RemoveAccountHolderTxParams) (db.AccountHolder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTransferFromAAccount", ctx, arg)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "RemoveAccountHolderTx", ctx, arg)
+	ret0, _ := ret[0].(db.AccountHolder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllTransferFromAAccount indicates an expected call of GetAllTransferFromAAccount.
-func (mr *MockStoreMockRecorder) GetAllTransferFromAAccount(ctx, arg any) *gomock.Call {
+// RemoveAccountHolderTx indicates an expected call of RemoveAccountHolderTx.
+func (mr *MockStoreMockRecorder) RemoveAccountHolderTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransferFromAAccount", reflect.TypeOf((*MockStore)(nil).GetAllTransferFromAAccount), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccountHolderTx", reflect.TypeOf((*MockStore)(nil).RemoveAccountHolderTx), ctx, arg)
 }
 
-// GetAllTransfersBetweenTwoAccounts mocks base method.
-func (m *MockStore) GetAllTransfersBetweenTwoAccounts(ctx context.Context, arg db.GetAllTransfersBetweenTwoAccountsParams) ([]db.Transfer, error) {
+// RemoveHouseholdMemberTx mocks base method.
+func (m *MockStore) RemoveHouseholdMemberTx(ctx context.Context, arg db.RemoveHouseholdMemberTxParams) (db.HouseholdMember, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTransfersBetweenTwoAccounts", ctx, arg)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "RemoveHouseholdMemberTx", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllTransfersBetweenTwoAccounts indicates an expected call of GetAllTransfersBetweenTwoAccounts.
-func (mr *MockStoreMockRecorder) GetAllTransfersBetweenTwoAccounts(ctx, arg any) *gomock.Call {
+// RemoveHouseholdMemberTx indicates an expected call of RemoveHouseholdMemberTx.
+func (mr *MockStoreMockRecorder) RemoveHouseholdMemberTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransfersBetweenTwoAccounts", reflect.TypeOf((*MockStore)(nil).GetAllTransfersBetweenTwoAccounts), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveHouseholdMemberTx", reflect.TypeOf((*MockStore)(nil).RemoveHouseholdMemberTx), ctx, arg)
 }
 
-// GetEntriesByAccountId mocks base method.
-func (m *MockStore) GetEntriesByAccountId(ctx context.Context, arg db.GetEntriesByAccountIdParams) ([]db.Entry, error) {
+// RevokeApiKey mocks base method.
+func (m *MockStore) RevokeApiKey(ctx context.Context, arg db.RevokeApiKeyParams) (db.ApiKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntriesByAccountId", ctx, arg)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "RevokeApiKey", ctx, arg)
+	ret0, _ := ret[0].(db.ApiKey)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntriesByAccountId indicates an expected call of GetEntriesByAccountId.
-func (mr *MockStoreMockRecorder) GetEntriesByAccountId(ctx, arg any) *gomock.Call {
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockStoreMockRecorder) RevokeApiKey(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByAccountId", reflect.TypeOf((*MockStore)(nil).GetEntriesByAccountId), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockStore)(nil).RevokeApiKey), ctx, arg)
 }
 
-// GetEntryById mocks base method.
-func (m *MockStore) GetEntryById(ctx context.Context, id int64) (db.Entry, error) {
+// SetDefaultAccount mocks base method.
+func (m *MockStore) SetDefaultAccount(ctx context.Context, arg db.SetDefaultAccountParams) (db.DefaultAccount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntryById", ctx, id)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "SetDefaultAccount", ctx, arg)
+	ret0, _ := ret[0].(db.DefaultAccount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntryById indicates an expected call of GetEntryById.
-func (mr *MockStoreMockRecorder) GetEntryById(ctx, id any) *gomock.Call {
+// SetDefaultAccount indicates an expected call of SetDefaultAccount.
+func (mr *MockStoreMockRecorder) SetDefaultAccount(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryById", reflect.TypeOf((*MockStore)(nil).GetEntryById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultAccount", reflect.TypeOf((*MockStore)(nil).SetDefaultAccount), ctx, arg)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+// SettleExpenseShare mocks base method.
+func (m *MockStore) SettleExpenseShare(ctx context.Context, arg db.SettleExpenseShareParams) (db.ExpenseShare, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", ctx, id)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "SettleExpenseShare", ctx, arg)
+	ret0, _ := ret[0].(db.ExpenseShare)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(ctx, id any) *gomock.Call {
+// SettleExpenseShare indicates an expected call of SettleExpenseShare.
+func (mr *MockStoreMockRecorder) SettleExpenseShare(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleExpenseShare", reflect.TypeOf((*MockStore)(nil).SettleExpenseShare), ctx, arg)
 }
 
-// GetTransferById mocks base method.
-func (m *MockStore) GetTransferById(ctx context.Context, id int64) (db.Transfer, error) {
+// SettleExpenseShareTx mocks base method.
+func (m *MockStore) SettleExpenseShareTx(ctx context.Context, arg db.SettleExpenseShareTxParams) (db.SettleExpenseShareTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransferById", ctx, id)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "SettleExpenseShareTx", ctx, arg)
+	ret0, _ := ret[0].(db.SettleExpenseShareTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransferById indicates an expected call of GetTransferById.
-func (mr *MockStoreMockRecorder) GetTransferById(ctx, id any) *gomock.Call {
+// SettleExpenseShareTx indicates an expected call of SettleExpenseShareTx.
+func (mr *MockStoreMockRecorder) SettleExpenseShareTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferById", reflect.TypeOf((*MockStore)(nil).GetTransferById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleExpenseShareTx", reflect.TypeOf((*MockStore)(nil).SettleExpenseShareTx), ctx, arg)
 }
 
-// GetUserByEmail mocks base method.
-func (m *MockStore) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+// SpendFromHouseholdTx mocks base method.
+func (m *MockStore) SpendFromHouseholdTx(ctx context.Context, arg db.SpendFromHouseholdTxParams) (db.HouseholdSpendTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SpendFromHouseholdTx", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdSpendTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByEmail indicates an expected call of GetUserByEmail.
-func (mr *MockStoreMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+// SpendFromHouseholdTx indicates an expected call of SpendFromHouseholdTx.
+func (mr *MockStoreMockRecorder) SpendFromHouseholdTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpendFromHouseholdTx", reflect.TypeOf((*MockStore)(nil).SpendFromHouseholdTx), ctx, arg)
 }
 
-// GetUserByUsername mocks base method.
-func (m *MockStore) GetUserByUsername(ctx context.Context, username string) (db.User, error) {
+// SumAccountEntries mocks base method.
+func (m *MockStore) SumAccountEntries(ctx context.Context, arg db.SumAccountEntriesParams) (db.SumAccountEntriesRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByUsername", ctx, username)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SumAccountEntries", ctx, arg)
+	ret0, _ := ret[0].(db.SumAccountEntriesRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByUsername indicates an expected call of GetUserByUsername.
-func (mr *MockStoreMockRecorder) GetUserByUsername(ctx, username any) *gomock.Call {
+// SumAccountEntries indicates an expected call of SumAccountEntries.
+func (mr *MockStoreMockRecorder) SumAccountEntries(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockStore)(nil).GetUserByUsername), ctx, username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumAccountEntries", reflect.TypeOf((*MockStore)(nil).SumAccountEntries), ctx, arg)
 }
 
-// GetUsersAccounts mocks base method.
-func (m *MockStore) GetUsersAccounts(ctx context.Context, username string) ([]db.Account, error) {
+// SumChildSpending mocks base method.
+func (m *MockStore) SumChildSpending(ctx context.Context, arg db.SumChildSpendingParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsersAccounts", ctx, username)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "SumChildSpending", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUsersAccounts indicates an expected call of GetUsersAccounts.
-func (mr *MockStoreMockRecorder) GetUsersAccounts(ctx, username any) *gomock.Call {
+// SumChildSpending indicates an expected call of SumChildSpending.
+func (mr *MockStoreMockRecorder) SumChildSpending(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersAccounts", reflect.TypeOf((*MockStore)(nil).GetUsersAccounts), ctx, username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumChildSpending", reflect.TypeOf((*MockStore)(nil).SumChildSpending), ctx, arg)
 }
 
 // TransferMoneyTx mocks base method.
@@ -371,6 +2432,65 @@ func (mr *MockStoreMockRecorder) UpdateAccountBalance(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountBalance", reflect.TypeOf((*MockStore)(nil).UpdateAccountBalance), ctx, arg)
 }
 
+// UpdateAllowanceNextPayment mocks base method.
+func (m *MockStore) UpdateAllowanceNextPayment(ctx context.Context, arg db.UpdateAllowanceNextPaymentParams) (db.Allowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAllowanceNextPayment", ctx, arg)
+	ret0, _ := ret[0].(db.Allowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAllowanceNextPayment indicates an expected call of UpdateAllowanceNextPayment.
+func (mr *MockStoreMockRecorder) UpdateAllowanceNextPayment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAllowanceNextPayment", reflect.TypeOf((*MockStore)(nil).UpdateAllowanceNextPayment), ctx, arg)
+}
+
+// UpdateApiKeyLastUsed mocks base method.
+func (m *MockStore) UpdateApiKeyLastUsed(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApiKeyLastUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApiKeyLastUsed indicates an expected call of UpdateApiKeyLastUsed.
+func (mr *MockStoreMockRecorder) UpdateApiKeyLastUsed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApiKeyLastUsed", reflect.TypeOf((*MockStore)(nil).UpdateApiKeyLastUsed), ctx, id)
+}
+
+// UpdateCurrency mocks base method.
+func (m *MockStore) UpdateCurrency(ctx context.Context, arg db.UpdateCurrencyParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCurrency", ctx, arg)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCurrency indicates an expected call of UpdateCurrency.
+func (mr *MockStoreMockRecorder) UpdateCurrency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCurrency", reflect.TypeOf((*MockStore)(nil).UpdateCurrency), ctx, arg)
+}
+
+// UpdateHouseholdSpendRule mocks base method.
+func (m *MockStore) UpdateHouseholdSpendRule(ctx context.Context, arg db.UpdateHouseholdSpendRuleParams) (db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHouseholdSpendRule", ctx, arg)
+	ret0, _ := ret[0].(db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHouseholdSpendRule indicates an expected call of UpdateHouseholdSpendRule.
+func (mr *MockStoreMockRecorder) UpdateHouseholdSpendRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHouseholdSpendRule", reflect.TypeOf((*MockStore)(nil).UpdateHouseholdSpendRule), ctx, arg)
+}
+
 // UpdateSession mocks base method.
 func (m *MockStore) UpdateSession(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -399,3 +2519,138 @@ func (mr *MockStoreMockRecorder) UpdateUser(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), ctx, arg)
 }
+
+// UpdateUserTx mocks base method.
+func (m *MockStore) UpdateUserTx(ctx context.Context, arg db.UpdateUserTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTx", ctx, arg)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTx indicates an expected call of UpdateUserTx.
+func (mr *MockStoreMockRecorder) UpdateUserTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTx", reflect.TypeOf((*MockStore)(nil).UpdateUserTx), ctx, arg)
+}
+
+// UpsertChildControls mocks base method.
+func (m *MockStore) UpsertChildControls(ctx context.Context, arg db.UpsertChildControlsParams) (db.ChildControl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertChildControls", ctx, arg)
+	ret0, _ := ret[0].(db.ChildControl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertChildControls indicates an expected call of UpsertChildControls.
+func (mr *MockStoreMockRecorder) UpsertChildControls(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertChildControls", reflect.TypeOf((*MockStore)(nil).UpsertChildControls), ctx, arg)
+}
+
+// UpsertOauthConsent mocks base method.
+func (m *MockStore) UpsertOauthConsent(ctx context.Context, arg db.UpsertOauthConsentParams) (db.OauthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOauthConsent", ctx, arg)
+	ret0, _ := ret[0].(db.OauthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOauthConsent indicates an expected call of UpsertOauthConsent.
+func (mr *MockStoreMockRecorder) UpsertOauthConsent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOauthConsent", reflect.TypeOf((*MockStore)(nil).UpsertOauthConsent), ctx, arg)
+}
+
+// UpsertUserTokenRevocation mocks base method.
+func (m *MockStore) UpsertUserTokenRevocation(ctx context.Context, arg db.UpsertUserTokenRevocationParams) (db.UserTokenRevocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserTokenRevocation", ctx, arg)
+	ret0, _ := ret[0].(db.UserTokenRevocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserTokenRevocation indicates an expected call of UpsertUserTokenRevocation.
+func (mr *MockStoreMockRecorder) UpsertUserTokenRevocation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserTokenRevocation", reflect.TypeOf((*MockStore)(nil).UpsertUserTokenRevocation), ctx, arg)
+}
+
+// UseOauthAuthorizationCode mocks base method.
+func (m *MockStore) UseOauthAuthorizationCode(ctx context.Context, codeHash string) (db.OauthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseOauthAuthorizationCode", ctx, codeHash)
+	ret0, _ := ret[0].(db.OauthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseOauthAuthorizationCode indicates an expected call of UseOauthAuthorizationCode.
+func (mr *MockStoreMockRecorder) UseOauthAuthorizationCode(ctx, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOauthAuthorizationCode", reflect.TypeOf((*MockStore)(nil).UseOauthAuthorizationCode), ctx, codeHash)
+}
+
+// UseVerifyEmail mocks base method.
+func (m *MockStore) UseVerifyEmail(ctx context.Context, arg db.UseVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseVerifyEmail", ctx, arg)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseVerifyEmail indicates an expected call of UseVerifyEmail.
+func (mr *MockStoreMockRecorder) UseVerifyEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseVerifyEmail", reflect.TypeOf((*MockStore)(nil).UseVerifyEmail), ctx, arg)
+}
+
+// VerifyEmailTx mocks base method.
+func (m *MockStore) VerifyEmailTx(ctx context.Context, arg db.VerifyEmailTxParams) (db.VerifyEmailTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailTx", ctx, arg)
+	ret0, _ := ret[0].(db.VerifyEmailTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmailTx indicates an expected call of VerifyEmailTx.
+func (mr *MockStoreMockRecorder) VerifyEmailTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailTx", reflect.TypeOf((*MockStore)(nil).VerifyEmailTx), ctx, arg)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStore) VerifyUserEmail(ctx context.Context, arg db.VerifyUserEmailParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", ctx, arg)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStoreMockRecorder) VerifyUserEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStore)(nil).VerifyUserEmail), ctx, arg)
+}
+
+// VoteHouseholdSpendTx mocks base method.
+func (m *MockStore) VoteHouseholdSpendTx(ctx context.Context, arg db.VoteHouseholdSpendTxParams) (db.HouseholdSpendTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteHouseholdSpendTx", ctx, arg)
+	ret0, _ := ret[0].(db.HouseholdSpendTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoteHouseholdSpendTx indicates an expected call of VoteHouseholdSpendTx.
+func (mr *MockStoreMockRecorder) VoteHouseholdSpendTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteHouseholdSpendTx", reflect.TypeOf((*MockStore)(nil).VoteHouseholdSpendTx), ctx, arg)
+}
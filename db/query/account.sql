@@ -1,12 +1,25 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (owner,balance,currency)
-VALUES ($1,$2,$3)
+INSERT INTO accounts (owner,balance,currency,name,type,number)
+VALUES ($1,$2,$3,$4,$5,$6)
 RETURNING *;
 
+-- name: CountAccountsByOwner :one
+SELECT COUNT(*) FROM accounts
+WHERE owner = $1;
+
 -- name: GetAccountById :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE number = $1 LIMIT 1;
+
+-- name: ListAccountNumbers :many
+-- maps the internal ids of the accounts to their public numbers
+SELECT id, number FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
 -- name: GetAccountByIdForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
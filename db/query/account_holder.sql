@@ -0,0 +1,62 @@
+-- name: CreateAccountHolder :one
+INSERT INTO account_holders (
+    account_id,
+    username,
+    role,
+    invited_by,
+    accepted_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetAccountHolder :one
+SELECT * FROM account_holders
+WHERE account_id = $1 AND username = $2;
+
+-- name: GetAccountForHolder :one
+-- role and accepted_at are null when the user does not hold the account
+SELECT sqlc.embed(accounts), account_holders.role, account_holders.accepted_at
+FROM accounts
+LEFT JOIN account_holders ON account_holders.account_id = accounts.id AND account_holders.username = sqlc.arg(username)
+WHERE accounts.number = sqlc.arg(number);
+
+-- name: ListAccountHolders :many
+SELECT * FROM account_holders
+WHERE account_id = $1
+ORDER BY created_at;
+
+-- name: ListPendingAccountHolders :many
+SELECT * FROM account_holders
+WHERE username = $1 AND accepted_at IS NULL
+ORDER BY created_at;
+
+-- name: ListHeldAccounts :many
+SELECT sqlc.embed(accounts), account_holders.role
+FROM accounts
+JOIN account_holders ON account_holders.account_id = accounts.id
+WHERE account_holders.username = $1 AND account_holders.accepted_at IS NOT NULL
+ORDER BY accounts.id
+LIMIT $2
+OFFSET $3;
+
+-- name: AcceptAccountHolder :one
+UPDATE account_holders
+SET accepted_at = now()
+WHERE account_id = $1 AND username = $2 AND accepted_at IS NULL
+RETURNING *;
+
+-- name: DeleteAccountHolder :one
+DELETE FROM account_holders
+WHERE account_id = $1 AND username = $2
+RETURNING *;
+
+-- name: CountAccountsHeldAlone :one
+-- how many of the accounts the user owns and nobody else holds, or is invited to hold
+SELECT COUNT(*) FROM accounts
+WHERE accounts.id = ANY(sqlc.arg(ids)::bigint[])
+  AND accounts.owner = sqlc.arg(username)
+  AND NOT EXISTS (
+    SELECT 1 FROM account_holders
+    WHERE account_holders.account_id = accounts.id AND account_holders.username <> sqlc.arg(username)
+  );
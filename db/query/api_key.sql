@@ -0,0 +1,37 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+    id,
+    username,
+    name,
+    prefix,
+    hashed_secret,
+    scopes,
+    account_ids,
+    allowed_ips,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetApiKeyByPrefix :one
+SELECT * FROM api_keys
+WHERE prefix = $1;
+
+-- name: ListApiKeys :many
+SELECT * FROM api_keys
+WHERE username = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND username = $2
+RETURNING *;
+
+-- name: UpdateApiKeyLastUsed :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    actor,
+    action,
+    account_id,
+    subject,
+    details
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListAccountAuditEvents :many
+SELECT * FROM audit_events
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
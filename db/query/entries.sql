@@ -18,4 +18,34 @@ OFFSET $3;
 SELECT * FROM entries
 WHERE id = $1;
 
- 
\ No newline at end of file
+-- name: GetLastEntryId :one
+-- id of the latest entry of the account, 0 when it has none
+SELECT COALESCE(MAX(id), 0)::bigint AS id FROM entries
+WHERE account_id = $1;
+
+-- name: ListAccountActivity :many
+-- entries after the given one, oldest first, with the account balance right after each of them
+SELECT e.id, e.account_id, e.amount, e.created_at,
+    (a.balance - COALESCE(SUM(e.amount) OVER (
+        ORDER BY e.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+    ), 0))::bigint AS balance
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+WHERE e.account_id = sqlc.arg(account_id) AND e.id > sqlc.arg(after_id)
+ORDER BY e.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: NotifyAccountActivity :exec
+-- wakes up the watchers of the account once the transaction commits
+SELECT pg_notify('account_activity', sqlc.arg(account_id)::bigint::text);
+
+
+-- name: SumAccountEntries :one
+-- money which came into and went out of the account between the two times
+SELECT
+    COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0)::bigint AS paid_in,
+    COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0)::bigint AS paid_out
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since)
+  AND created_at < sqlc.arg(until);
@@ -0,0 +1,72 @@
+-- name: CreateExpense :one
+INSERT INTO expenses (
+    paid_by,
+    account_id,
+    amount,
+    currency,
+    description,
+    split
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetExpense :one
+SELECT * FROM expenses
+WHERE id = $1;
+
+-- name: ListUserExpenses :many
+-- expenses the user paid or has a share of
+SELECT * FROM expenses
+WHERE paid_by = sqlc.arg(username)
+   OR id IN (SELECT expense_id FROM expense_shares WHERE expense_shares.username = sqlc.arg(username))
+ORDER BY id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: CreateExpenseShare :one
+INSERT INTO expense_shares (
+    expense_id,
+    username,
+    amount,
+    settled_at
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListExpenseShares :many
+SELECT * FROM expense_shares
+WHERE expense_id = $1
+ORDER BY username;
+
+-- name: GetExpenseShareForUpdate :one
+SELECT * FROM expense_shares
+WHERE expense_id = $1 AND username = $2
+FOR NO KEY UPDATE;
+
+-- name: SettleExpenseShare :one
+UPDATE expense_shares
+SET transfer_id = $3,
+    settled_at = now()
+WHERE expense_id = $1 AND username = $2
+RETURNING *;
+
+-- name: ListOutstandingBalances :many
+-- what each other user owes the user, net of what the user owes them, in every currency with unsettled shares.
+-- A negative amount is owed by the user.
+SELECT counterparty::varchar AS counterparty, currency, SUM(amount)::bigint AS amount
+FROM (
+    SELECT expense_shares.username AS counterparty, expenses.currency, expense_shares.amount
+    FROM expense_shares
+    JOIN expenses ON expenses.id = expense_shares.expense_id
+    WHERE expenses.paid_by = sqlc.arg(username) AND expense_shares.username <> sqlc.arg(username) AND expense_shares.settled_at IS NULL
+    UNION ALL
+    SELECT expenses.paid_by AS counterparty, expenses.currency, -expense_shares.amount
+    FROM expense_shares
+    JOIN expenses ON expenses.id = expense_shares.expense_id
+    WHERE expense_shares.username = sqlc.arg(username) AND expenses.paid_by <> sqlc.arg(username) AND expense_shares.settled_at IS NULL
+) AS ious
+GROUP BY counterparty, currency
+HAVING SUM(amount) <> 0
+ORDER BY counterparty, currency;
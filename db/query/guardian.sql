@@ -0,0 +1,172 @@
+-- name: CreateGuardianship :one
+INSERT INTO guardianships (
+    guardian,
+    child
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetGuardianship :one
+SELECT * FROM guardianships
+WHERE guardian = $1 AND child = $2;
+
+-- name: ListChildren :many
+SELECT * FROM guardianships
+WHERE guardian = $1
+ORDER BY created_at;
+
+-- name: ListGuardians :many
+SELECT guardian FROM guardianships
+WHERE child = $1
+ORDER BY guardian;
+
+-- name: GetChildControls :one
+SELECT * FROM child_controls
+WHERE child = $1;
+
+-- name: UpsertChildControls :one
+INSERT INTO child_controls (
+    child,
+    currency,
+    per_transaction_limit,
+    daily_limit,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (child) DO UPDATE
+SET currency = EXCLUDED.currency,
+    per_transaction_limit = EXCLUDED.per_transaction_limit,
+    daily_limit = EXCLUDED.daily_limit,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: CreateChildPayee :one
+INSERT INTO child_payees (
+    child,
+    account_id,
+    added_by
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetChildPayee :one
+SELECT * FROM child_payees
+WHERE child = $1 AND account_id = $2;
+
+-- name: ListChildPayees :many
+SELECT * FROM child_payees
+WHERE child = $1
+ORDER BY created_at;
+
+-- name: DeleteChildPayee :one
+DELETE FROM child_payees
+WHERE child = $1 AND account_id = $2
+RETURNING *;
+
+-- name: SumChildSpending :one
+-- what the child sent from their accounts since the given time, except to accounts they own and nobody else holds,
+-- and what they spent from the pots of their households
+SELECT COALESCE(SUM(transfers.amount), 0)::bigint
+FROM transfers
+JOIN accounts AS from_accounts ON from_accounts.id = transfers.from_account_id
+JOIN accounts AS to_accounts ON to_accounts.id = transfers.to_account_id
+WHERE (
+    from_accounts.owner = sqlc.arg(child)
+    AND (
+      to_accounts.owner <> sqlc.arg(child)
+      OR EXISTS (
+        SELECT 1 FROM account_holders
+        WHERE account_holders.account_id = to_accounts.id AND account_holders.username <> sqlc.arg(child)
+      )
+    )
+    OR EXISTS (
+      SELECT 1 FROM household_spends
+      WHERE household_spends.transfer_id = transfers.id AND household_spends.requested_by = sqlc.arg(child)
+    )
+  )
+  AND from_accounts.currency = sqlc.arg(currency)
+  AND transfers.created_at >= sqlc.arg(since);
+
+-- name: CreateChildTransferApproval :one
+INSERT INTO child_transfer_approvals (
+    child,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    reason,
+    money_request_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetChildTransferApprovalForUpdate :one
+SELECT * FROM child_transfer_approvals
+WHERE id = $1
+FOR NO KEY UPDATE;
+
+-- name: ListPendingChildTransferApprovals :many
+-- the pending approvals of every child of the guardian
+SELECT child_transfer_approvals.* FROM child_transfer_approvals
+JOIN guardianships ON guardianships.child = child_transfer_approvals.child
+WHERE guardianships.guardian = $1 AND child_transfer_approvals.status = 'pending'
+ORDER BY child_transfer_approvals.id;
+
+-- name: DecideChildTransferApproval :one
+UPDATE child_transfer_approvals
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_by = sqlc.arg(decided_by),
+    decided_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateAllowance :one
+INSERT INTO allowances (
+    guardian,
+    child,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    period,
+    next_payment_at,
+    anchor_day
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, extract(day FROM $8::timestamptz AT TIME ZONE 'UTC')
+)
+RETURNING *;
+
+-- name: ListChildAllowances :many
+SELECT * FROM allowances
+WHERE child = $1
+ORDER BY id;
+
+-- name: DeleteAllowance :one
+DELETE FROM allowances
+WHERE id = $1 AND child = $2
+RETURNING *;
+
+-- name: ListDueAllowances :many
+-- pages through the due allowances by id, so allowances which keep failing do not hide the ones after them
+SELECT id, next_payment_at FROM allowances
+WHERE next_payment_at <= now() AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetDueAllowanceForUpdate :one
+-- skips allowances another payer is paying, and ones paid since they were listed
+SELECT * FROM allowances
+WHERE id = $1 AND next_payment_at <= now()
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateAllowanceNextPayment :one
+UPDATE allowances
+SET next_payment_at = $2
+WHERE id = $1
+RETURNING *;
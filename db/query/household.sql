@@ -0,0 +1,130 @@
+-- name: CreateHousehold :one
+INSERT INTO households (
+    name,
+    created_by,
+    account_id,
+    spend_rule,
+    approval_threshold
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetHousehold :one
+SELECT * FROM households
+WHERE id = $1;
+
+-- name: ListMemberHouseholds :many
+SELECT households.* FROM households
+JOIN household_members ON household_members.household_id = households.id
+WHERE household_members.username = $1
+ORDER BY households.id;
+
+-- name: UpdateHouseholdSpendRule :one
+UPDATE households
+SET spend_rule = $2,
+    approval_threshold = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateHouseholdMember :one
+INSERT INTO household_members (
+    household_id,
+    username
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetHouseholdMember :one
+SELECT * FROM household_members
+WHERE household_id = $1 AND username = $2;
+
+-- name: ListHouseholdMembers :many
+SELECT * FROM household_members
+WHERE household_id = $1
+ORDER BY created_at;
+
+-- name: CountHouseholdMembers :one
+SELECT COUNT(*) FROM household_members
+WHERE household_id = $1;
+
+-- name: DeleteHouseholdMember :one
+DELETE FROM household_members
+WHERE household_id = $1 AND username = $2
+RETURNING *;
+
+-- name: CreateHouseholdSpend :one
+INSERT INTO household_spends (
+    household_id,
+    requested_by,
+    to_account_id,
+    amount,
+    note
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetHouseholdSpendForUpdate :one
+SELECT * FROM household_spends
+WHERE id = $1 AND household_id = $2
+FOR NO KEY UPDATE;
+
+-- name: ListHouseholdSpends :many
+SELECT * FROM household_spends
+WHERE household_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: DecideHouseholdSpend :one
+UPDATE household_spends
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateHouseholdSpendVote :one
+INSERT INTO household_spend_votes (
+    spend_id,
+    username,
+    approve
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: CountHouseholdSpendVotes :one
+-- only the votes of who is still a member count
+SELECT
+    COUNT(*) FILTER (WHERE approve)::bigint AS approvals,
+    COUNT(*) FILTER (WHERE NOT approve)::bigint AS declines
+FROM household_spend_votes
+JOIN household_spends ON household_spends.id = household_spend_votes.spend_id
+JOIN household_members ON household_members.household_id = household_spends.household_id
+  AND household_members.username = household_spend_votes.username
+WHERE household_spend_votes.spend_id = $1;
+
+-- name: DeleteHouseholdMemberPendingVotes :exec
+DELETE FROM household_spend_votes
+USING household_spends
+WHERE household_spends.id = household_spend_votes.spend_id
+  AND household_spends.household_id = $1
+  AND household_spends.status = 'pending'
+  AND household_spend_votes.username = $2;
+
+-- name: ListHouseholdContributions :many
+-- who paid into the pot how much between the two times, by the owner of the paying account
+SELECT
+    from_accounts.owner AS username,
+    COUNT(*)::bigint AS transfers,
+    SUM(transfers.amount)::bigint AS amount
+FROM transfers
+JOIN accounts AS from_accounts ON from_accounts.id = transfers.from_account_id
+WHERE transfers.to_account_id = sqlc.arg(account_id)
+  AND transfers.created_at >= sqlc.arg(since)
+  AND transfers.created_at < sqlc.arg(until)
+GROUP BY from_accounts.owner
+ORDER BY amount DESC, username;
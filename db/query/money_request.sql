@@ -0,0 +1,54 @@
+-- name: CreateMoneyRequest :one
+INSERT INTO money_requests (
+    requester,
+    payer,
+    to_account_id,
+    amount,
+    currency,
+    memo,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetMoneyRequestForUpdate :one
+SELECT * FROM money_requests
+WHERE id = $1
+FOR NO KEY UPDATE;
+
+-- name: ListPendingMoneyRequests :many
+-- the requests the payer may still pay, oldest first
+SELECT * FROM money_requests
+WHERE payer = $1 AND status = 'pending' AND expires_at > now()
+ORDER BY id;
+
+-- name: ListRequesterMoneyRequests :many
+SELECT * FROM money_requests
+WHERE requester = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: DecideMoneyRequest :one
+UPDATE money_requests
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListExpiredMoneyRequests :many
+-- pages through the expired requests by id, so requests which keep failing do not hide the ones after them
+SELECT id FROM money_requests
+WHERE status = 'pending' AND expires_at <= now() AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(limit_count);
+
+-- name: ExpireMoneyRequest :one
+-- skips requests decided or expired since they were listed
+UPDATE money_requests
+SET status = 'expired',
+    decided_at = now()
+WHERE id = $1 AND status = 'pending' AND expires_at <= now()
+RETURNING *;
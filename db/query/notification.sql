@@ -0,0 +1,22 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+    username,
+    kind,
+    payload
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND username = $2
+RETURNING *;
@@ -0,0 +1,65 @@
+-- name: CreateOauthClient :one
+INSERT INTO oauth_clients (
+    id,
+    owner,
+    name,
+    hashed_secret,
+    redirect_uris,
+    scopes
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetOauthClient :one
+SELECT * FROM oauth_clients
+WHERE id = $1;
+
+-- name: CreateOauthAuthorizationCode :one
+INSERT INTO oauth_authorization_codes (
+    code_hash,
+    client_id,
+    username,
+    redirect_uri,
+    scopes,
+    code_challenge,
+    nonce,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: UseOauthAuthorizationCode :one
+UPDATE oauth_authorization_codes
+SET used_at = now()
+WHERE code_hash = $1
+AND used_at IS NULL
+AND expires_at > now()
+RETURNING *;
+
+-- name: GetOauthConsent :one
+SELECT * FROM oauth_consents
+WHERE username = $1 AND client_id = $2;
+
+-- name: UpsertOauthConsent :one
+INSERT INTO oauth_consents (
+    username,
+    client_id,
+    scopes
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (username, client_id) DO UPDATE
+SET scopes = EXCLUDED.scopes,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListOauthConsents :many
+SELECT * FROM oauth_consents
+WHERE username = $1
+ORDER BY updated_at DESC;
+
+-- name: DeleteOauthConsent :exec
+DELETE FROM oauth_consents
+WHERE username = $1 AND client_id = $2;
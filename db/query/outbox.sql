@@ -0,0 +1,38 @@
+-- name: CreateOutboxMessage :one
+INSERT INTO outbox (
+    task_type,
+    payload,
+    queue,
+    max_retry
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListPendingOutboxMessages :many
+-- a message waits the backoff once more after every failure, and is left for an operator after the last attempt
+SELECT * FROM outbox
+WHERE published_at IS NULL
+  AND attempts < sqlc.arg(max_attempts)
+  AND (last_attempted_at IS NULL OR last_attempted_at + sqlc.arg(backoff)::interval * attempts <= now())
+ORDER BY id
+LIMIT sqlc.arg(limit_count)
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxMessagePublished :exec
+UPDATE outbox
+SET published_at = now(),
+    attempts = attempts + 1
+WHERE id = $1;
+
+-- name: RecordOutboxMessageFailure :one
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = $2,
+    last_attempted_at = now()
+WHERE id = $1
+RETURNING attempts;
+
+-- name: DeletePublishedOutboxMessages :exec
+DELETE FROM outbox
+WHERE published_at < sqlc.arg(before)::timestamptz;
@@ -0,0 +1,45 @@
+-- name: CreatePayee :one
+INSERT INTO payees (
+    owner,
+    nickname,
+    account_id,
+    verified_name,
+    alias
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetPayee :one
+SELECT * FROM payees
+WHERE id = $1 AND owner = $2;
+
+-- name: ListPayees :many
+SELECT * FROM payees
+WHERE owner = $1
+ORDER BY nickname;
+
+-- name: DeletePayee :one
+DELETE FROM payees
+WHERE id = $1 AND owner = $2
+RETURNING *;
+
+-- name: SetDefaultAccount :one
+INSERT INTO default_accounts (
+    owner,
+    currency,
+    account_id
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (owner, currency) DO UPDATE
+SET account_id = EXCLUDED.account_id, updated_at = now()
+RETURNING *;
+
+-- name: GetDefaultAccount :one
+-- the account the owner chose for the currency, else their oldest checking account in it
+SELECT a.* FROM accounts a
+LEFT JOIN default_accounts d ON d.account_id = a.id AND d.owner = a.owner AND d.currency = a.currency
+WHERE a.owner = $1 AND a.currency = $2 AND (d.account_id IS NOT NULL OR a.type = 'checking')
+ORDER BY d.account_id IS NULL, a.id
+LIMIT 1;
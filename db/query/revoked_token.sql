@@ -0,0 +1,39 @@
+-- name: CreateRevokedToken :one
+INSERT INTO revoked_tokens (
+    id,
+    username,
+    revoked_by,
+    expired_at
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (id) DO UPDATE
+SET revoked_by = EXCLUDED.revoked_by
+RETURNING *;
+
+-- name: UpsertUserTokenRevocation :one
+INSERT INTO user_token_revocations (
+    username,
+    revoked_by,
+    revoked_before
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (username) DO UPDATE
+SET revoked_by = EXCLUDED.revoked_by,
+    revoked_before = EXCLUDED.revoked_before
+RETURNING *;
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_tokens
+    WHERE id = sqlc.arg(id)
+) OR EXISTS (
+    SELECT 1 FROM user_token_revocations
+    WHERE username = sqlc.arg(username)
+    AND revoked_before >= sqlc.arg(issued_at)
+) AS revoked;
+
+-- name: DeleteExpiredRevokedTokens :exec
+DELETE FROM revoked_tokens
+WHERE expired_at < now();
@@ -22,4 +22,9 @@ INSERT INTO sessions (
 VALUES (
     $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1;
\ No newline at end of file
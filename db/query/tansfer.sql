@@ -29,3 +29,10 @@ ORDER BY created_at desc
 LIMIT $2
 OFFSET $3;
 
+
+-- name: ListAccountTransfers :many
+SELECT * FROM transfers
+WHERE from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
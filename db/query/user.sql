@@ -16,6 +16,12 @@ INSERT INTO users (
 SELECT * FROM users
 WHERE username = $1;
 
+-- name: GetUserByUsernameForUpdate :one
+-- serializes the transactions which check limits on the accounts of the user
+SELECT * FROM users
+WHERE username = $1
+FOR NO KEY UPDATE;
+
 -- name: GetUserByEmail :one
 SELECT * FROM users
 WHERE email = $1;
@@ -25,8 +31,16 @@ UPDATE users
 SET
     full_name = COALESCE(sqlc.narg(full_name), full_name),
     email = COALESCE(sqlc.narg(email), email),
-    email_verified_at = COALESCE(sqlc.narg(email_verified_at), email_verified_at),
+    -- a new email is not verified, it only is once a link sent to it is used
+    email_verified_at = CASE WHEN COALESCE(sqlc.narg(email), email) = email THEN email_verified_at END,
     password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
     hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password)
 WHERE username = sqlc.narg(username)
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+-- verifies the email of the user, unless it changed since the link was sent to it
+UPDATE users
+SET email_verified_at = now()
+WHERE username = $1 AND email = $2
 RETURNING *; 
\ No newline at end of file
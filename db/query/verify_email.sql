@@ -0,0 +1,19 @@
+-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails (
+    username,
+    email,
+    hashed_secret_code
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: UseVerifyEmail :one
+-- a link can only be used once, and until it expires
+UPDATE verify_emails
+SET is_used = TRUE
+WHERE id = sqlc.arg(id)
+  AND hashed_secret_code = sqlc.arg(hashed_secret_code)
+  AND is_used = FALSE
+  AND expired_at > now()
+RETURNING *;
@@ -0,0 +1,92 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+    owner,
+    url,
+    secret,
+    event_types
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE owner = $1
+ORDER BY id;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: CreateWebhookEvent :one
+INSERT INTO webhook_events (
+    owner,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetWebhookEvent :one
+SELECT * FROM webhook_events
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPendingWebhookEvents :many
+SELECT * FROM webhook_events
+WHERE dispatched_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkWebhookEventDispatched :exec
+UPDATE webhook_events
+SET dispatched_at = now()
+WHERE id = $1;
+
+-- name: CreateWebhookDeliveries :many
+-- creates a delivery of the event for every endpoint of its owner subscribed to its type
+INSERT INTO webhook_deliveries (
+    endpoint_id,
+    event_id
+)
+SELECT id, sqlc.arg(event_id)::bigint
+FROM webhook_endpoints
+WHERE owner = sqlc.arg(owner) AND sqlc.arg(event_type)::varchar = ANY(event_types)
+RETURNING *;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+    endpoint_id,
+    event_id
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT d.*, e.event_type
+FROM webhook_deliveries d
+JOIN webhook_events e ON e.id = d.event_id
+WHERE d.endpoint_id = $1
+ORDER BY d.id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: RecordWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+    status = sqlc.arg(status),
+    response_status = sqlc.arg(response_status),
+    last_error = sqlc.arg(last_error),
+    delivered_at = CASE WHEN sqlc.arg(status) = 'succeeded' THEN now() ELSE delivered_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, name, type, number
 `
 
 type AddAccountBalanceParams struct {
@@ -30,24 +30,49 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Number,
 	)
 	return i, err
 }
 
+const countAccountsByOwner = `-- name: CountAccountsByOwner :one
+SELECT COUNT(*) FROM accounts
+WHERE owner = $1
+`
+
+func (q *Queries) CountAccountsByOwner(ctx context.Context, owner string) (int64, error) {
+	row := q.db.QueryRow(ctx, countAccountsByOwner, owner)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts (owner,balance,currency)
-VALUES ($1,$2,$3)
-RETURNING id, owner, balance, currency, created_at
+INSERT INTO accounts (owner,balance,currency,name,type,number)
+VALUES ($1,$2,$3,$4,$5,$6)
+RETURNING id, owner, balance, currency, created_at, name, type, number
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Number   string `json:"number"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Name,
+		arg.Type,
+		arg.Number,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -55,6 +80,9 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Number,
 	)
 	return i, err
 }
@@ -69,7 +97,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccountById = `-- name: GetAccountById :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, name, type, number FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -82,12 +110,15 @@ func (q *Queries) GetAccountById(ctx context.Context, id int64) (Account, error)
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Number,
 	)
 	return i, err
 }
 
 const getAccountByIdForUpdate = `-- name: GetAccountByIdForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, name, type, number FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -101,12 +132,36 @@ func (q *Queries) GetAccountByIdForUpdate(ctx context.Context, id int64) (Accoun
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Number,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency, created_at, name, type, number FROM accounts
+WHERE number = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, number string) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByNumber, number)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Number,
 	)
 	return i, err
 }
 
 const getAccounts = `-- name: GetAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, name, type, number FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -134,6 +189,9 @@ func (q *Queries) GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Acc
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Name,
+			&i.Type,
+			&i.Number,
 		); err != nil {
 			return nil, err
 		}
@@ -146,7 +204,7 @@ func (q *Queries) GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Acc
 }
 
 const getUsersAccounts = `-- name: GetUsersAccounts :many
-SELECT a.id, a.owner, a.balance, a.currency, a.created_at
+SELECT a.id, a.owner, a.balance, a.currency, a.created_at, a.name, a.type, a.number
 FROM accounts a
 JOIN users u ON u.username = a.owner
 WHERE u.username = $1
@@ -167,6 +225,9 @@ func (q *Queries) GetUsersAccounts(ctx context.Context, username string) ([]Acco
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Name,
+			&i.Type,
+			&i.Number,
 		); err != nil {
 			return nil, err
 		}
@@ -178,11 +239,42 @@ func (q *Queries) GetUsersAccounts(ctx context.Context, username string) ([]Acco
 	return items, nil
 }
 
+const listAccountNumbers = `-- name: ListAccountNumbers :many
+SELECT id, number FROM accounts
+WHERE id = ANY($1::bigint[])
+`
+
+type ListAccountNumbersRow struct {
+	ID     int64  `json:"id"`
+	Number string `json:"number"`
+}
+
+// maps the internal ids of the accounts to their public numbers
+func (q *Queries) ListAccountNumbers(ctx context.Context, ids []int64) ([]ListAccountNumbersRow, error) {
+	rows, err := q.db.Query(ctx, listAccountNumbers, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountNumbersRow{}
+	for rows.Next() {
+		var i ListAccountNumbersRow
+		if err := rows.Scan(&i.ID, &i.Number); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateAccountBalance = `-- name: UpdateAccountBalance :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, name, type, number
 `
 
 type UpdateAccountBalanceParams struct {
@@ -199,6 +291,9 @@ func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBal
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Number,
 	)
 	return i, err
 }
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHeldAccount(t *testing.T, store Store, user User) Account {
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Currency: util.USD,
+			Name:     util.RandomString(6),
+			Type:     AccountTypeChecking,
+		},
+		MaxAccounts: 10,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestRoleAllows(t *testing.T) {
+	require.True(t, RoleAllows(AccountHolderPrimary, AccountAccessManage))
+	require.True(t, RoleAllows(AccountHolderJoint, AccountAccessTransact))
+	require.False(t, RoleAllows(AccountHolderJoint, AccountAccessManage))
+	require.True(t, RoleAllows(AccountHolderViewOnly, AccountAccessView))
+	require.False(t, RoleAllows(AccountHolderViewOnly, AccountAccessTransact))
+	require.False(t, RoleAllows("", AccountAccessView))
+}
+
+func TestJointAccountHolders(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	owner := createRandomUser(t)
+	coHolder := createRandomUser(t)
+	account := createRandomHeldAccount(t, store, owner)
+
+	held, err := store.GetAccountForHolder(ctx, GetAccountForHolderParams{Username: owner.Username, Number: account.Number})
+	require.NoError(t, err)
+	require.Equal(t, AccountHolderPrimary, held.Role.String)
+	require.True(t, held.Allows(AccountAccessManage))
+
+	holder, err := store.InviteAccountHolderTx(ctx, InviteAccountHolderTxParams{
+		AccountID: account.ID,
+		Username:  coHolder.Username,
+		Role:      AccountHolderJoint,
+		InvitedBy: owner.Username,
+	})
+	require.NoError(t, err)
+	require.False(t, holder.AcceptedAt.Valid)
+
+	// pending holders have no access yet
+	held, err = store.GetAccountForHolder(ctx, GetAccountForHolderParams{Username: coHolder.Username, Number: account.Number})
+	require.NoError(t, err)
+	require.False(t, held.Allows(AccountAccessView))
+
+	pending, err := store.ListPendingAccountHolders(ctx, coHolder.Username)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	holder, err = store.AcceptAccountHolderTx(ctx, AcceptAccountHolderTxParams{AccountID: account.ID, Username: coHolder.Username})
+	require.NoError(t, err)
+	require.True(t, holder.AcceptedAt.Valid)
+
+	_, err = store.AcceptAccountHolderTx(ctx, AcceptAccountHolderTxParams{AccountID: account.ID, Username: coHolder.Username})
+	require.ErrorIs(t, err, ErrInvitationNotFound)
+
+	held, err = store.GetAccountForHolder(ctx, GetAccountForHolderParams{Username: coHolder.Username, Number: account.Number})
+	require.NoError(t, err)
+	require.True(t, held.Allows(AccountAccessTransact))
+	require.False(t, held.Allows(AccountAccessManage))
+
+	accounts, err := store.ListHeldAccounts(ctx, ListHeldAccountsParams{Username: coHolder.Username, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].Account.ID)
+	require.Equal(t, AccountHolderJoint, accounts[0].Role)
+
+	_, err = store.RemoveAccountHolderTx(ctx, RemoveAccountHolderTxParams{
+		AccountID: account.ID,
+		Username:  owner.Username,
+		RemovedBy: owner.Username,
+	})
+	require.ErrorIs(t, err, ErrPrimaryHolder)
+
+	_, err = store.RemoveAccountHolderTx(ctx, RemoveAccountHolderTxParams{
+		AccountID: account.ID,
+		Username:  coHolder.Username,
+		RemovedBy: owner.Username,
+	})
+	require.NoError(t, err)
+
+	holders, err := store.ListAccountHolders(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, holders, 1)
+	require.Equal(t, owner.Username, holders[0].Username)
+
+	events, err := store.ListAccountAuditEvents(ctx, ListAccountAuditEventsParams{
+		AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, AuditAccountHolderRemoved, events[0].Action)
+	require.Equal(t, AuditAccountHolderAccepted, events[1].Action)
+	require.Equal(t, AuditAccountHolderInvited, events[2].Action)
+	require.Equal(t, coHolder.Username, events[2].Subject.String)
+}
+
+func TestGuardedChildAccountHolders(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	childAccount := createRandomHeldAccount(t, store, child)
+	other := createRandomUser(t)
+	otherAccount := createRandomHeldAccount(t, store, other)
+
+	_, err := store.InviteAccountHolderTx(ctx, InviteAccountHolderTxParams{
+		AccountID: childAccount.ID,
+		Username:  other.Username,
+		Role:      AccountHolderJoint,
+		InvitedBy: child.Username,
+	})
+	require.ErrorIs(t, err, ErrGuardedChild)
+
+	_, err = store.InviteAccountHolderTx(ctx, InviteAccountHolderTxParams{
+		AccountID: otherAccount.ID,
+		Username:  child.Username,
+		Role:      AccountHolderJoint,
+		InvitedBy: other.Username,
+	})
+	require.ErrorIs(t, err, ErrGuardedChild)
+
+	// invited before they came under guardianship
+	_, err = store.CreateAccountHolder(ctx, CreateAccountHolderParams{
+		AccountID: otherAccount.ID,
+		Username:  child.Username,
+		Role:      AccountHolderJoint,
+		InvitedBy: pgtype.Text{String: other.Username, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.AcceptAccountHolderTx(ctx, AcceptAccountHolderTxParams{AccountID: otherAccount.ID, Username: child.Username})
+	require.ErrorIs(t, err, ErrGuardedChild)
+
+	holders, err := store.ListAccountHolders(ctx, childAccount.ID)
+	require.NoError(t, err)
+	require.Len(t, holders, 1)
+}
@@ -5,16 +5,33 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AnkitNayan83/houseBank/accountnumber"
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/stretchr/testify/require"
 )
 
 func createRandomAccount(t *testing.T) Account {
-	user := createRandomUser(t)
+	return createRandomAccountInCurrency(t, util.RandomCurrency())
+}
+
+func createRandomAccountInCurrency(t *testing.T, currency string) Account {
+	return createRandomOwnedAccount(t, createRandomUser(t), currency)
+}
+
+func randomAccountNumber(t *testing.T) string {
+	number, err := accountnumber.New(accountnumber.HeadOfficeBranch)
+	require.NoError(t, err)
+	return number
+}
+
+func createRandomOwnedAccount(t *testing.T, user User, currency string) Account {
 	arg := CreateAccountParams{
 		Owner:    user.Username,
 		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Currency: currency,
+		Name:     util.RandomString(6),
+		Type:     AccountTypeChecking,
+		Number:   randomAccountNumber(t),
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -24,6 +41,9 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, arg.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Name, account.Name)
+	require.Equal(t, arg.Type, account.Type)
+	require.Equal(t, arg.Number, account.Number)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -49,6 +69,36 @@ func TestGetAccountById(t *testing.T) {
 	require.WithinDuration(t, account.CreatedAt, accountInDb.CreatedAt, time.Second)
 }
 
+func TestGetAccountByNumber(t *testing.T) {
+	account := createRandomAccount(t)
+
+	accountInDb, err := testQueries.GetAccountByNumber(context.Background(), account.Number)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, accountInDb.ID)
+
+	// numbers are unique
+	_, err = testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+		Name:     util.RandomString(6),
+		Type:     AccountTypeChecking,
+		Number:   account.Number,
+	})
+	require.Error(t, err)
+}
+
+func TestListAccountNumbers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	rows, err := testQueries.ListAccountNumbers(context.Background(), []int64{account1.ID, account2.ID, 0})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []ListAccountNumbersRow{
+		{ID: account1.ID, Number: account1.Number},
+		{ID: account2.ID, Number: account2.Number},
+	}, rows)
+}
+
 func TestUpdateAccount(t *testing.T) {
 	account := createRandomAccount(t)
 
@@ -102,3 +152,46 @@ func TestListAccount(t *testing.T) {
 		require.Equal(t, owner, account.Owner)
 	}
 }
+
+func TestSubAccountsInOneCurrency(t *testing.T) {
+	user := createRandomUser(t)
+
+	rent := createRandomOwnedAccount(t, user, util.USD)
+	groceries := createRandomOwnedAccount(t, user, util.USD)
+	require.NotEqual(t, rent.ID, groceries.ID)
+
+	// names are unique among the accounts of an owner
+	_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+		Name:     rent.Name,
+		Type:     AccountTypeSavings,
+		Number:   randomAccountNumber(t),
+	})
+	require.Error(t, err)
+}
+
+func TestCreateAccountTxLimit(t *testing.T) {
+	store := NewStore(testDb)
+	user := createRandomUser(t)
+	createRandomOwnedAccount(t, user, util.USD)
+
+	arg := CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Currency: util.USD,
+			Name:     util.RandomString(6),
+			Type:     AccountTypeSavings,
+		},
+		MaxAccounts: 2,
+	}
+
+	account, err := store.CreateAccountTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, AccountTypeSavings, account.Type)
+	require.NoError(t, accountnumber.Validate(account.Number))
+
+	arg.Name = util.RandomString(6)
+	_, err = store.CreateAccountTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrAccountLimitReached)
+}
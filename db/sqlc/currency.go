@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AnkitNayan83/houseBank/util"
+)
+
+// LoadCurrencyRegistry replaces the currencies util validates and formats amounts with by the ones of the currencies table
+func LoadCurrencyRegistry(ctx context.Context, q Querier) error {
+	currencies, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot list currencies: %w", err)
+	}
+
+	registry := make([]util.Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		registry = append(registry, util.Currency{
+			Code:     currency.Code,
+			Exponent: currency.Exponent,
+			Symbol:   currency.Symbol,
+			Enabled:  currency.Enabled,
+		})
+	}
+
+	util.SetCurrencies(registry)
+	return nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCurrency(t *testing.T) Currency {
+	arg := CreateCurrencyParams{
+		// lowercase codes cannot clash with the ISO ones seeded by the migration
+		Code:     strings.ToLower(util.RandomString(3)),
+		Exponent: int32(util.RandomInt(0, 4)),
+		Symbol:   util.RandomString(2),
+		Enabled:  true,
+	}
+
+	currency, err := testQueries.CreateCurrency(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Code, currency.Code)
+	require.Equal(t, arg.Exponent, currency.Exponent)
+	require.Equal(t, arg.Symbol, currency.Symbol)
+	require.True(t, currency.Enabled)
+	require.NotZero(t, currency.CreatedAt)
+
+	return currency
+}
+
+func TestSeededCurrencies(t *testing.T) {
+	for _, code := range []string{util.USD, util.INR} {
+		currency, err := testQueries.GetCurrency(context.Background(), code)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), currency.Exponent)
+	}
+}
+
+func TestUpdateCurrency(t *testing.T) {
+	currency := createRandomCurrency(t)
+
+	updated, err := testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:    currency.Code,
+		Enabled: pgtype.Bool{Bool: false, Valid: true},
+	})
+	require.NoError(t, err)
+	require.False(t, updated.Enabled)
+	require.Equal(t, currency.Symbol, updated.Symbol)
+	require.Equal(t, currency.Exponent, updated.Exponent)
+}
+
+func TestAccountCurrencyMustBeRegistered(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: "XXX",
+		Name:     util.RandomString(6),
+		Type:     AccountTypeChecking,
+		Number:   randomAccountNumber(t),
+	})
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
@@ -91,3 +92,108 @@ func (q *Queries) GetEntryById(ctx context.Context, id int64) (Entry, error) {
 	)
 	return i, err
 }
+
+const getLastEntryId = `-- name: GetLastEntryId :one
+SELECT COALESCE(MAX(id), 0)::bigint AS id FROM entries
+WHERE account_id = $1
+`
+
+// id of the latest entry of the account, 0 when it has none
+func (q *Queries) GetLastEntryId(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, getLastEntryId, accountID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listAccountActivity = `-- name: ListAccountActivity :many
+SELECT e.id, e.account_id, e.amount, e.created_at,
+    (a.balance - COALESCE(SUM(e.amount) OVER (
+        ORDER BY e.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+    ), 0))::bigint AS balance
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+WHERE e.account_id = $1 AND e.id > $2
+ORDER BY e.id
+LIMIT $3
+`
+
+type ListAccountActivityParams struct {
+	AccountID  int64 `json:"account_id"`
+	AfterID    int64 `json:"after_id"`
+	LimitCount int32 `json:"limit_count"`
+}
+
+type ListAccountActivityRow struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"account_id"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+	Balance   int64     `json:"balance"`
+}
+
+// entries after the given one, oldest first, with the account balance right after each of them
+func (q *Queries) ListAccountActivity(ctx context.Context, arg ListAccountActivityParams) ([]ListAccountActivityRow, error) {
+	rows, err := q.db.Query(ctx, listAccountActivity, arg.AccountID, arg.AfterID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountActivityRow{}
+	for rows.Next() {
+		var i ListAccountActivityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Balance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const notifyAccountActivity = `-- name: NotifyAccountActivity :exec
+SELECT pg_notify('account_activity', $1::bigint::text)
+`
+
+// wakes up the watchers of the account once the transaction commits
+func (q *Queries) NotifyAccountActivity(ctx context.Context, accountID int64) error {
+	_, err := q.db.Exec(ctx, notifyAccountActivity, accountID)
+	return err
+}
+
+const sumAccountEntries = `-- name: SumAccountEntries :one
+SELECT
+    COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0)::bigint AS paid_in,
+    COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0)::bigint AS paid_out
+FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+`
+
+type SumAccountEntriesParams struct {
+	AccountID int64     `json:"account_id"`
+	Since     time.Time `json:"since"`
+	Until     time.Time `json:"until"`
+}
+
+type SumAccountEntriesRow struct {
+	PaidIn  int64 `json:"paid_in"`
+	PaidOut int64 `json:"paid_out"`
+}
+
+// money which came into and went out of the account between the two times
+func (q *Queries) SumAccountEntries(ctx context.Context, arg SumAccountEntriesParams) (SumAccountEntriesRow, error) {
+	row := q.db.QueryRow(ctx, sumAccountEntries, arg.AccountID, arg.Since, arg.Until)
+	var i SumAccountEntriesRow
+	err := row.Scan(&i.PaidIn, &i.PaidOut)
+	return i, err
+}
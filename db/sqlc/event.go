@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// accountNumbers maps the internal ids of the accounts to their public numbers.
+// The payloads of webhook events and notifications leave the bank, so they carry the numbers and never the ids.
+func accountNumbers(ctx context.Context, q *Queries, ids ...int64) (map[int64]string, error) {
+	rows, err := q.ListAccountNumbers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		numbers[row.ID] = row.Number
+	}
+
+	return numbers, nil
+}
+
+// accountEvent is an account as the payloads of webhook events carry it
+type accountEvent struct {
+	Number    string    `json:"number"`
+	Owner     string    `json:"owner"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Balance   int64     `json:"balance"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newAccountEvent(account Account) accountEvent {
+	return accountEvent{
+		Number:    account.Number,
+		Owner:     account.Owner,
+		Name:      account.Name,
+		Type:      account.Type,
+		Balance:   account.Balance,
+		Currency:  account.Currency,
+		CreatedAt: account.CreatedAt,
+	}
+}
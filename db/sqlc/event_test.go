@@ -0,0 +1,149 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// requireNoAccountIDs fails when the payload carries the internal id of an account, under any *account_id key
+// or as the id of an account, which is the object carrying its number
+func requireNoAccountIDs(t *testing.T, payload []byte) {
+	var decoded any
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+
+	var walk func(value any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			_, isAccount := value["number"]
+			for key, field := range value {
+				require.False(t, strings.HasSuffix(key, "account_id"), "payload %s has %s", payload, key)
+				require.False(t, isAccount && key == "id", "payload %s has the id of an account", payload)
+				walk(field)
+			}
+		case []any:
+			for _, item := range value {
+				walk(item)
+			}
+		}
+	}
+
+	walk(decoded)
+}
+
+func TestTransferEventPayload(t *testing.T) {
+	from := Account{ID: 101, Number: "HB1200010000000001", Owner: util.RandomOwner(), Currency: util.USD}
+	to := Account{ID: 102, Number: "HB1200010000000002", Owner: util.RandomOwner(), Currency: util.USD}
+	entry := Entry{ID: 7, AccountID: from.ID, Amount: -10}
+
+	result := TransfeMoneyTxResult{
+		Transfer:    &Transfer{ID: 3, FromAccountID: from.ID, ToAccountID: to.ID, Amount: 10},
+		FromAccount: &from,
+		ToAccount:   &to,
+	}
+
+	payload, err := json.Marshal(newTransferEvent(result, from, entry))
+	require.NoError(t, err)
+	requireNoAccountIDs(t, payload)
+
+	var event transferEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, from.Number, event.FromAccountNumber)
+	require.Equal(t, to.Number, event.ToAccountNumber)
+	require.Equal(t, from.Number, event.Account.Number)
+	require.Equal(t, int64(-10), event.EntryAmount)
+}
+
+func TestEventPayloadsCarryAccountNumbers(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	user := createRandomUser(t)
+	other := createRandomUser(t)
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	endpoint := createRandomWebhookEndpoint(t, user, WebhookEventTypes...)
+
+	account, err := store.CreateAccountTx(ctx, CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Currency: util.USD,
+			Name:     util.RandomString(6),
+			Type:     AccountTypeChecking,
+		},
+		MaxAccounts: 10,
+	})
+	require.NoError(t, err)
+	otherAccount := createRandomOwnedAccount(t, other, util.USD)
+	childAccount := createRandomOwnedAccount(t, child, util.USD)
+	holdAccount(t, childAccount, child)
+
+	_, err = store.AddAccountBalanceTx(ctx, AddAccountBalanceTxParams{ID: account.ID, Amount: money.New(100, util.USD)})
+	require.NoError(t, err)
+
+	_, err = store.TransferMoneyTx(ctx, TransferMoneyTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   otherAccount.ID,
+		Amount:        money.New(10, util.USD),
+	})
+	require.NoError(t, err)
+
+	// the payer declining tells the requester
+	request := createRandomMoneyRequest(t, store, user, other, time.Now().Add(time.Hour))
+	_, err = store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{ID: request.ID, Payer: other.Username, Now: time.Now()})
+	require.NoError(t, err)
+
+	// without limits the transfer of the child waits for the guardian, who declines it
+	held, err := store.ChildTransferTx(ctx, ChildTransferTxParams{
+		TransferMoneyTxParams: TransferMoneyTxParams{
+			FromAccountID: childAccount.ID,
+			ToAccountID:   account.ID,
+			Amount:        money.New(1, util.USD),
+		},
+		Child: child.Username,
+		Now:   time.Now(),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, held.Approval)
+
+	_, err = store.DecideChildTransferTx(ctx, DecideChildTransferTxParams{ID: held.Approval.ID, Guardian: guardian.Username})
+	require.NoError(t, err)
+
+	deliveries := dispatchWebhookEvents(t, store)[endpoint.ID]
+	require.NotEmpty(t, deliveries)
+
+	var payloads [][]byte
+	for _, delivery := range deliveries {
+		event, err := testQueries.GetWebhookEvent(ctx, delivery.EventID)
+		require.NoError(t, err)
+		payloads = append(payloads, event.Payload)
+	}
+
+	for _, username := range []string{user.Username, other.Username, guardian.Username, child.Username} {
+		notifications, err := testQueries.ListNotifications(ctx, ListNotificationsParams{Username: username, Limit: 10})
+		require.NoError(t, err)
+		require.NotEmpty(t, notifications)
+
+		for _, notification := range notifications {
+			payloads = append(payloads, notification.Payload)
+		}
+	}
+
+	for _, payload := range payloads {
+		requireNoAccountIDs(t, payload)
+	}
+
+	// the accounts are told apart by their numbers instead
+	all := string(bytes.Join(payloads, nil))
+	require.Contains(t, all, account.Number)
+	require.Contains(t, all, otherAccount.Number)
+	require.Contains(t, all, childAccount.Number)
+}
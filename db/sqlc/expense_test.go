@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitExpense(t *testing.T) {
+	amount := money.New(1000, util.USD)
+	amounts := func(shares []money.Money) []int64 {
+		res := make([]int64, 0, len(shares))
+		for _, share := range shares {
+			res = append(res, share.Amount)
+		}
+		return res
+	}
+
+	shares, err := splitExpense(amount, ExpenseSplitEqual, "ann", []ExpenseParticipant{{Username: "ann"}, {Username: "bob"}, {Username: "cat"}})
+	require.NoError(t, err)
+	require.Equal(t, []int64{334, 333, 333}, amounts(shares))
+
+	shares, err = splitExpense(amount, ExpenseSplitPercentage, "ann", []ExpenseParticipant{{Username: "bob", Percentage: 33}, {Username: "cat", Percentage: 67}})
+	require.NoError(t, err)
+	require.Equal(t, []int64{330, 670}, amounts(shares))
+
+	shares, err = splitExpense(amount, ExpenseSplitExact, "ann", []ExpenseParticipant{{Username: "ann", Amount: 100}, {Username: "bob", Amount: 900}})
+	require.NoError(t, err)
+	require.Equal(t, []int64{100, 900}, amounts(shares))
+
+	_, err = splitExpense(amount, ExpenseSplitPercentage, "ann", []ExpenseParticipant{{Username: "bob", Percentage: 60}})
+	require.ErrorIs(t, err, ErrInvalidSplit)
+
+	_, err = splitExpense(amount, ExpenseSplitExact, "ann", []ExpenseParticipant{{Username: "bob", Amount: 999}})
+	require.ErrorIs(t, err, ErrInvalidSplit)
+
+	_, err = splitExpense(amount, ExpenseSplitEqual, "ann", []ExpenseParticipant{{Username: "bob"}, {Username: "bob"}})
+	require.ErrorIs(t, err, ErrInvalidSplit)
+
+	_, err = splitExpense(amount, ExpenseSplitEqual, "ann", []ExpenseParticipant{{Username: "ann"}})
+	require.ErrorIs(t, err, ErrInvalidSplit)
+}
+
+func TestExpenseTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	payer := createRandomUser(t)
+	friend := createRandomUser(t)
+	payerAccount := createRandomOwnedAccount(t, payer, util.USD)
+	friendAccount := createRandomOwnedAccount(t, friend, util.USD)
+
+	_, err := store.AddAccountBalanceTx(ctx, AddAccountBalanceTxParams{ID: friendAccount.ID, Amount: money.New(1000, util.USD)})
+	require.NoError(t, err)
+
+	created, err := store.CreateExpenseTx(ctx, CreateExpenseTxParams{
+		PaidBy:       payer.Username,
+		AccountID:    payerAccount.ID,
+		Amount:       money.New(1001, util.USD),
+		Description:  "dinner",
+		Split:        ExpenseSplitEqual,
+		Participants: []ExpenseParticipant{{Username: payer.Username}, {Username: friend.Username}},
+	})
+	require.NoError(t, err)
+	require.Len(t, created.Shares, 2)
+	require.True(t, created.Shares[0].SettledAt.Valid)
+	require.False(t, created.Shares[1].SettledAt.Valid)
+	require.Equal(t, int64(500), created.Shares[1].Amount)
+	requireNotification(t, friend.Username, NotificationExpenseShareRequested)
+
+	// the friend also paid for something the payer owes half of
+	_, err = store.CreateExpenseTx(ctx, CreateExpenseTxParams{
+		PaidBy:       friend.Username,
+		AccountID:    friendAccount.ID,
+		Amount:       money.New(200, util.USD),
+		Split:        ExpenseSplitExact,
+		Participants: []ExpenseParticipant{{Username: friend.Username, Amount: 100}, {Username: payer.Username, Amount: 100}},
+	})
+	require.NoError(t, err)
+
+	balances, err := store.ListOutstandingBalances(ctx, payer.Username)
+	require.NoError(t, err)
+	require.Equal(t, []ListOutstandingBalancesRow{{Counterparty: friend.Username, Currency: util.USD, Amount: 400}}, balances)
+
+	_, err = store.SettleExpenseShareTx(ctx, SettleExpenseShareTxParams{ExpenseID: created.Expense.ID, Username: payer.Username, FromAccountID: payerAccount.ID})
+	require.ErrorIs(t, err, ErrAlreadySettled)
+
+	settled, err := store.SettleExpenseShareTx(ctx, SettleExpenseShareTxParams{ExpenseID: created.Expense.ID, Username: friend.Username, FromAccountID: friendAccount.ID})
+	require.NoError(t, err)
+	require.Equal(t, settled.Transfer.Transfer.ID, settled.Share.TransferID.Int64)
+	require.Equal(t, payerAccount.ID, settled.Transfer.ToAccount.ID)
+	requireNotification(t, payer.Username, NotificationExpenseShareSettled)
+
+	balances, err = store.ListOutstandingBalances(ctx, payer.Username)
+	require.NoError(t, err)
+	require.Equal(t, []ListOutstandingBalancesRow{{Counterparty: friend.Username, Currency: util.USD, Amount: -100}}, balances)
+}
+
+func TestSettleExpenseShareTxChild(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	payer := createRandomUser(t)
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	payerAccount := createRandomOwnedAccount(t, payer, util.USD)
+	childAccount := createRandomOwnedAccount(t, child, util.USD)
+
+	_, err := store.AddAccountBalanceTx(ctx, AddAccountBalanceTxParams{ID: childAccount.ID, Amount: money.New(1000, util.USD)})
+	require.NoError(t, err)
+
+	created, err := store.CreateExpenseTx(ctx, CreateExpenseTxParams{
+		PaidBy:       payer.Username,
+		AccountID:    payerAccount.ID,
+		Amount:       money.New(400, util.USD),
+		Split:        ExpenseSplitEqual,
+		Participants: []ExpenseParticipant{{Username: payer.Username}, {Username: child.Username}},
+	})
+	require.NoError(t, err)
+
+	settle := func() (SettleExpenseShareTxResult, error) {
+		return store.SettleExpenseShareTx(ctx, SettleExpenseShareTxParams{
+			ExpenseID:     created.Expense.ID,
+			Username:      child.Username,
+			FromAccountID: childAccount.ID,
+			Now:           time.Now(),
+		})
+	}
+
+	// without limits, and with the payer off the allowlist, the share would need a guardian
+	_, err = settle()
+	require.ErrorIs(t, err, ErrChildControls)
+
+	_, err = store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 100,
+		DailyLimit:          1000,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CreateChildPayee(ctx, CreateChildPayeeParams{Child: child.Username, AccountID: payerAccount.ID, AddedBy: guardian.Username})
+	require.NoError(t, err)
+
+	_, err = settle()
+	require.ErrorIs(t, err, ErrChildControls)
+	require.ErrorContains(t, err, "per-transaction limit")
+
+	_, err = store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 200,
+		DailyLimit:          1000,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	settled, err := settle()
+	require.NoError(t, err)
+	require.True(t, settled.Share.SettledAt.Valid)
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomChild(t *testing.T, store Store, guardian User) User {
+	child, err := store.CreateChildTx(context.Background(), CreateChildTxParams{
+		CreateUserParams: randomCreateUserParams(t),
+		Guardian:         guardian.Username,
+	})
+	require.NoError(t, err)
+
+	return child
+}
+
+func requireNotification(t *testing.T, username string, kind string) {
+	notifications, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{Username: username, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, kind, notifications[0].Kind)
+}
+
+// holdAccount makes the owner of an account created without its holders its primary holder
+func holdAccount(t *testing.T, account Account, owner User) {
+	_, err := testQueries.CreateAccountHolder(context.Background(), CreateAccountHolderParams{
+		AccountID:  account.ID,
+		Username:   owner.Username,
+		Role:       AccountHolderPrimary,
+		AcceptedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+}
+
+func TestNextAllowancePayment(t *testing.T) {
+	last := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	require.Equal(t, time.Date(2026, time.February, 7, 9, 0, 0, 0, time.UTC), nextAllowancePayment(last, AllowanceWeekly, 31))
+
+	// an allowance due at the end of the month stays there, whatever the length of the months in between
+	feb := nextAllowancePayment(last, AllowanceMonthly, 31)
+	require.Equal(t, time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC), feb)
+	require.Equal(t, time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC), nextAllowancePayment(feb, AllowanceMonthly, 31))
+
+	leap := time.Date(2028, time.January, 30, 9, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2028, time.February, 29, 9, 0, 0, 0, time.UTC), nextAllowancePayment(leap, AllowanceMonthly, 30))
+
+	dec := time.Date(2026, time.December, 15, 9, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2027, time.January, 15, 9, 0, 0, 0, time.UTC), nextAllowancePayment(dec, AllowanceMonthly, 15))
+}
+
+func TestChildTransferTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	childAccount := createRandomOwnedAccount(t, child, util.USD)
+	shop := createRandomAccountInCurrency(t, util.USD)
+
+	holdAccount(t, childAccount, child)
+
+	transfer := func(amount int64) ChildTransferTxResult {
+		result, err := store.ChildTransferTx(ctx, ChildTransferTxParams{
+			TransferMoneyTxParams: TransferMoneyTxParams{
+				FromAccountID: childAccount.ID,
+				ToAccountID:   shop.ID,
+				Amount:        money.New(amount, util.USD),
+			},
+			Child: child.Username,
+			Now:   time.Now(),
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// without limits every transfer to others waits for a guardian
+	result := transfer(1)
+	require.Nil(t, result.Transfer)
+	require.NotNil(t, result.Approval)
+	require.Equal(t, ChildTransferPending, result.Approval.Status)
+	requireNotification(t, guardian.Username, NotificationChildTransferApprovalRequested)
+
+	_, err := store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 5,
+		DailyLimit:          8,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	result = transfer(6)
+	require.Contains(t, result.Blocked, "per-transaction limit")
+	requireNotification(t, guardian.Username, NotificationChildTransferBlocked)
+
+	// within the limits, but the shop is not on the allowlist yet
+	result = transfer(5)
+	require.NotNil(t, result.Approval)
+
+	_, err = store.CreateChildPayee(ctx, CreateChildPayeeParams{Child: child.Username, AccountID: shop.ID, AddedBy: guardian.Username})
+	require.NoError(t, err)
+
+	result = transfer(5)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, childAccount.Balance-5, result.Transfer.FromAccount.Balance)
+
+	result = transfer(4)
+	require.Contains(t, result.Blocked, "daily limit")
+
+	// the first transfer waiting for approval is made once approved
+	approvals, err := store.ListPendingChildTransferApprovals(ctx, guardian.Username)
+	require.NoError(t, err)
+	require.Len(t, approvals, 2)
+
+	_, err = store.DecideChildTransferTx(ctx, DecideChildTransferTxParams{ID: approvals[0].ID, Guardian: child.Username, Approve: true})
+	require.ErrorIs(t, err, ErrNotGuardian)
+
+	decided, err := store.DecideChildTransferTx(ctx, DecideChildTransferTxParams{ID: approvals[0].ID, Guardian: guardian.Username, Approve: true})
+	require.NoError(t, err)
+	require.Equal(t, ChildTransferApproved, decided.Approval.Status)
+	require.Equal(t, decided.Transfer.Transfer.ID, decided.Approval.TransferID.Int64)
+	requireNotification(t, child.Username, NotificationChildTransferApproved)
+
+	_, err = store.DecideChildTransferTx(ctx, DecideChildTransferTxParams{ID: approvals[0].ID, Guardian: guardian.Username, Approve: false})
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+
+	// the child does not hold the shop's account, so cannot have money moved out of it
+	foreign, err := store.CreateChildTransferApproval(ctx, CreateChildTransferApprovalParams{
+		Child:         child.Username,
+		FromAccountID: shop.ID,
+		ToAccountID:   childAccount.ID,
+		Amount:        1,
+		Currency:      util.USD,
+		Reason:        "payee is not on the allowlist",
+	})
+	require.NoError(t, err)
+
+	_, err = store.DecideChildTransferTx(ctx, DecideChildTransferTxParams{ID: foreign.ID, Guardian: guardian.Username, Approve: true})
+	require.ErrorIs(t, err, ErrChildNoAccess)
+}
+
+func TestChildTransferTxOwnAccounts(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	checking := createRandomOwnedAccount(t, child, util.USD)
+	savings := createRandomOwnedAccount(t, child, util.USD)
+
+	transfer := func(to Account) ChildTransferTxResult {
+		result, err := store.ChildTransferTx(ctx, ChildTransferTxParams{
+			TransferMoneyTxParams: TransferMoneyTxParams{
+				FromAccountID: checking.ID,
+				ToAccountID:   to.ID,
+				Amount:        money.New(1, util.USD),
+			},
+			Child: child.Username,
+			Now:   time.Now(),
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// the money never leaves accounts only the child holds
+	result := transfer(savings)
+	require.NotNil(t, result.Transfer)
+
+	// another holder of an account the child owns could take the money out of the controls
+	_, err := store.CreateAccountHolder(ctx, CreateAccountHolderParams{
+		AccountID:  savings.ID,
+		Username:   createRandomUser(t).Username,
+		Role:       AccountHolderJoint,
+		InvitedBy:  pgtype.Text{String: child.Username, Valid: true},
+		AcceptedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	result = transfer(savings)
+	require.Nil(t, result.Transfer)
+	require.NotNil(t, result.Approval)
+	requireNotification(t, guardian.Username, NotificationChildTransferApprovalRequested)
+}
+
+func TestPayAllowanceTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	guardianAccount := createRandomOwnedAccount(t, guardian, util.USD)
+	holdAccount(t, guardianAccount, guardian)
+	childAccount := createRandomOwnedAccount(t, child, util.USD)
+
+	due := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	allowance, err := store.CreateAllowance(ctx, CreateAllowanceParams{
+		Guardian:      guardian.Username,
+		Child:         child.Username,
+		FromAccountID: guardianAccount.ID,
+		ToAccountID:   childAccount.ID,
+		Amount:        guardianAccount.Balance,
+		Currency:      util.USD,
+		Period:        AllowanceWeekly,
+		NextPaymentAt: due,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int16(due.UTC().Day()), allowance.AnchorDay)
+
+	result, err := store.PayAllowanceTx(ctx, allowance.ID)
+	require.NoError(t, err)
+	require.True(t, result.Paid)
+	require.WithinDuration(t, due.AddDate(0, 0, 7), result.Allowance.NextPaymentAt, time.Second)
+	requireNotification(t, child.Username, NotificationAllowancePaid)
+
+	// not due anymore
+	result, err = store.PayAllowanceTx(ctx, allowance.ID)
+	require.NoError(t, err)
+	require.False(t, result.Paid)
+
+	// the guardian's account is empty now, the payment is skipped until the next one
+	_, err = store.UpdateAllowanceNextPayment(ctx, UpdateAllowanceNextPaymentParams{ID: allowance.ID, NextPaymentAt: due})
+	require.NoError(t, err)
+
+	result, err = store.PayAllowanceTx(ctx, allowance.ID)
+	require.NoError(t, err)
+	require.False(t, result.Paid)
+	require.True(t, result.Allowance.NextPaymentAt.After(time.Now()))
+	requireNotification(t, guardian.Username, NotificationAllowanceFailed)
+}
+
+func TestPayAllowanceTxGuardianLeftAccount(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	guardianAccount := createRandomOwnedAccount(t, guardian, util.USD)
+	holdAccount(t, guardianAccount, guardian)
+	childAccount := createRandomOwnedAccount(t, child, util.USD)
+
+	due := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	allowance, err := store.CreateAllowance(ctx, CreateAllowanceParams{
+		Guardian:      guardian.Username,
+		Child:         child.Username,
+		FromAccountID: guardianAccount.ID,
+		ToAccountID:   childAccount.ID,
+		Amount:        1,
+		Currency:      util.USD,
+		Period:        AllowanceWeekly,
+		NextPaymentAt: due,
+	})
+	require.NoError(t, err)
+
+	// the guardian no longer holds the paying account, nothing is moved out of it
+	_, err = store.DeleteAccountHolder(ctx, DeleteAccountHolderParams{AccountID: guardianAccount.ID, Username: guardian.Username})
+	require.NoError(t, err)
+
+	result, err := store.PayAllowanceTx(ctx, allowance.ID)
+	require.NoError(t, err)
+	require.False(t, result.Paid)
+	require.True(t, result.Allowance.NextPaymentAt.After(time.Now()))
+	requireNotification(t, guardian.Username, NotificationAllowanceFailed)
+
+	account, err := store.GetAccountById(ctx, guardianAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, guardianAccount.Balance, account.Balance)
+}
@@ -2,13 +2,38 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/money"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
-// execTx executes a function within a database transeaction
+// maxTxAttempts is how many times a transaction is run when it keeps hitting serialization failures or deadlocks
+const maxTxAttempts = 3
+
+// execTx executes a function within a database transeaction,
+// it is run again when postgres aborted it because of a serialization failure or a deadlock
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	for attempt := 1; ; attempt++ {
+		err := store.runTx(ctx, fn)
+		if err == nil {
+			metrics.ObserveTransaction(metrics.TxCommit)
+			return nil
+		}
+
+		if attempt >= maxTxAttempts || !isRetryableTxError(err) || ctx.Err() != nil {
+			metrics.ObserveTransaction(metrics.TxRollback)
+			return err
+		}
+
+		metrics.ObserveTransaction(metrics.TxRetry)
+	}
+}
+
+func (store *SQLStore) runTx(ctx context.Context, fn func(*Queries) error) error {
 	tx, err := store.db.BeginTx(ctx, pgx.TxOptions{})
 
 	if err != nil {
@@ -20,7 +45,7 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 
 	if err != nil {
 		if rbErr := tx.Rollback(context.Background()); rbErr != nil {
-			return fmt.Errorf("tx error: %v, rb error: %v", err, rbErr)
+			return fmt.Errorf("tx error: %w, rb error: %v", err, rbErr)
 		}
 		return err
 	}
@@ -28,27 +53,54 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	return tx.Commit(context.Background())
 }
 
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return true
+	}
+
+	return false
+}
+
+// execTxWithOutbox executes a function within a database transaction and writes the outbox messages it returns
+// in the same transaction, so tasks are only published for data that was actually committed
+func (store *SQLStore) execTxWithOutbox(ctx context.Context, fn func(*Queries) ([]CreateOutboxMessageParams, error)) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		messages, err := fn(q)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if _, err := q.CreateOutboxMessage(ctx, message); err != nil {
+				return fmt.Errorf("cannot write outbox message: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
 func addMoney(
 	ctx context.Context,
 	q *Queries,
 	account1Id int64,
-	amount1 int64,
+	amount1 money.Money,
 	account2Id int64,
-	amount2 int64,
+	amount2 money.Money,
 ) (account1, account2 Account, err error) {
-	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     account1Id,
-		Amount: amount1,
-	})
+	account1, err = addAccountMoney(ctx, q, account1Id, amount1)
 
 	if err != nil {
 		return
 	}
 
-	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     account2Id,
-		Amount: amount2,
-	})
+	account2, err = addAccountMoney(ctx, q, account2Id, amount2)
 
 	if err != nil {
 		return
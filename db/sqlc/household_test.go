@@ -0,0 +1,308 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHouseholdNeedsApproval(t *testing.T) {
+	household := Household{SpendRule: HouseholdSpendAnyMember, ApprovalThreshold: 100}
+	require.False(t, household.NeedsApproval(1000))
+
+	household.SpendRule = HouseholdSpendMajority
+	require.False(t, household.NeedsApproval(100))
+	require.True(t, household.NeedsApproval(101))
+}
+
+func TestHouseholdTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	creator := createRandomUser(t)
+	member := createRandomUser(t)
+	housemate := createRandomUser(t)
+	memberAccount := createRandomOwnedAccount(t, member, util.USD)
+	shop := createRandomAccountInCurrency(t, util.USD)
+
+	created, err := store.CreateHouseholdTx(ctx, CreateHouseholdTxParams{
+		Name:              util.RandomString(6),
+		Currency:          util.USD,
+		CreatedBy:         creator.Username,
+		SpendRule:         HouseholdSpendMajority,
+		ApprovalThreshold: 10,
+		MaxAccounts:       10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountTypeHousehold, created.Account.Type)
+	household := created.Household
+
+	for _, user := range []User{member, housemate} {
+		_, err = store.AddHouseholdMemberTx(ctx, AddHouseholdMemberTxParams{Household: household, Username: user.Username})
+		require.NoError(t, err)
+	}
+
+	// members pay into the pot with regular transfers
+	_, err = store.AddAccountBalanceTx(ctx, AddAccountBalanceTxParams{ID: memberAccount.ID, Amount: money.New(100, util.USD)})
+	require.NoError(t, err)
+
+	_, err = store.TransferMoneyTx(ctx, TransferMoneyTxParams{
+		FromAccountID: memberAccount.ID,
+		ToAccountID:   household.AccountID,
+		Amount:        money.New(100, util.USD),
+	})
+	require.NoError(t, err)
+
+	contributions, err := store.ListHouseholdContributions(ctx, ListHouseholdContributionsParams{
+		AccountID: household.AccountID,
+		Since:     time.Now().Add(-time.Hour),
+		Until:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, contributions, 1)
+	require.Equal(t, member.Username, contributions[0].Username)
+	require.Equal(t, int64(100), contributions[0].Amount)
+
+	// the pot is only seen by the members, money leaves it through spends
+	held, err := store.GetAccountForHolder(ctx, GetAccountForHolderParams{Number: created.Account.Number, Username: creator.Username})
+	require.NoError(t, err)
+	require.True(t, held.Allows(AccountAccessView))
+	require.False(t, held.Allows(AccountAccessTransact))
+
+	// within the threshold a member spends alone
+	result, err := store.SpendFromHouseholdTx(ctx, SpendFromHouseholdTxParams{
+		Household:   household,
+		RequestedBy: member.Username,
+		ToAccountID: shop.ID,
+		Amount:      money.New(10, util.USD),
+	})
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendApproved, result.Spend.Status)
+	require.NotNil(t, result.Transfer)
+
+	_, err = store.SpendFromHouseholdTx(ctx, SpendFromHouseholdTxParams{
+		Household:   household,
+		RequestedBy: shop.Owner,
+		ToAccountID: shop.ID,
+		Amount:      money.New(10, util.USD),
+	})
+	require.ErrorIs(t, err, ErrNotHouseholdMember)
+
+	// above it, the requester's approval is one of the two needed out of three members
+	result, err = store.SpendFromHouseholdTx(ctx, SpendFromHouseholdTxParams{
+		Household:   household,
+		RequestedBy: member.Username,
+		ToAccountID: shop.ID,
+		Amount:      money.New(11, util.USD),
+	})
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendPending, result.Spend.Status)
+	require.Nil(t, result.Transfer)
+	requireNotification(t, creator.Username, NotificationHouseholdSpendApprovalRequested)
+
+	voted, err := store.VoteHouseholdSpendTx(ctx, VoteHouseholdSpendTxParams{Household: household, SpendID: result.Spend.ID, Username: housemate.Username, Approve: true})
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendApproved, voted.Spend.Status)
+	require.Equal(t, voted.Transfer.Transfer.ID, voted.Spend.TransferID.Int64)
+	requireNotification(t, member.Username, NotificationHouseholdSpendApproved)
+
+	_, err = store.VoteHouseholdSpendTx(ctx, VoteHouseholdSpendTxParams{Household: household, SpendID: result.Spend.ID, Username: creator.Username, Approve: false})
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+
+	totals, err := store.SumAccountEntries(ctx, SumAccountEntriesParams{
+		AccountID: household.AccountID,
+		Since:     time.Now().Add(-time.Hour),
+		Until:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), totals.PaidIn)
+	require.Equal(t, int64(21), totals.PaidOut)
+
+	// the creator holds the pot and cannot leave
+	_, err = store.RemoveHouseholdMemberTx(ctx, RemoveHouseholdMemberTxParams{Household: household, Username: creator.Username})
+	require.ErrorIs(t, err, ErrHouseholdCreator)
+
+	_, err = store.RemoveHouseholdMemberTx(ctx, RemoveHouseholdMemberTxParams{Household: household, Username: housemate.Username})
+	require.NoError(t, err)
+}
+
+func TestRemoveHouseholdMemberVotes(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	creator := createRandomUser(t)
+	shop := createRandomAccountInCurrency(t, util.USD)
+
+	created, err := store.CreateHouseholdTx(ctx, CreateHouseholdTxParams{
+		Name:              util.RandomString(6),
+		Currency:          util.USD,
+		CreatedBy:         creator.Username,
+		SpendRule:         HouseholdSpendMajority,
+		ApprovalThreshold: 0,
+		MaxAccounts:       10,
+	})
+	require.NoError(t, err)
+	household := created.Household
+
+	members := []User{createRandomUser(t), createRandomUser(t), createRandomUser(t)}
+	for _, user := range members {
+		_, err = store.AddHouseholdMemberTx(ctx, AddHouseholdMemberTxParams{Household: household, Username: user.Username})
+		require.NoError(t, err)
+	}
+
+	result, err := store.SpendFromHouseholdTx(ctx, SpendFromHouseholdTxParams{
+		Household:   household,
+		RequestedBy: creator.Username,
+		ToAccountID: shop.ID,
+		Amount:      money.New(10, util.USD),
+	})
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendPending, result.Spend.Status)
+
+	voted, err := store.VoteHouseholdSpendTx(ctx, VoteHouseholdSpendTxParams{Household: household, SpendID: result.Spend.ID, Username: members[0].Username, Approve: false})
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendPending, voted.Spend.Status)
+
+	// leaving takes the votes on pending spends along
+	_, err = store.RemoveHouseholdMemberTx(ctx, RemoveHouseholdMemberTxParams{Household: household, Username: members[0].Username})
+	require.NoError(t, err)
+
+	votes, err := store.CountHouseholdSpendVotes(ctx, result.Spend.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), votes.Approvals)
+	require.Zero(t, votes.Declines)
+
+	_, err = store.CreateHouseholdSpendVote(ctx, CreateHouseholdSpendVoteParams{SpendID: result.Spend.ID, Username: members[1].Username, Approve: false})
+	require.NoError(t, err)
+
+	// a vote left behind by who is no longer a member is not counted
+	_, err = store.DeleteHouseholdMember(ctx, DeleteHouseholdMemberParams{HouseholdID: household.ID, Username: members[1].Username})
+	require.NoError(t, err)
+
+	votes, err = store.CountHouseholdSpendVotes(ctx, result.Spend.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), votes.Approvals)
+	require.Zero(t, votes.Declines)
+}
+
+func TestHouseholdSpendTxChild(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	creator := createRandomUser(t)
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	creatorAccount := createRandomOwnedAccount(t, creator, util.USD)
+	shop := createRandomAccountInCurrency(t, util.USD)
+	other := createRandomAccountInCurrency(t, util.USD)
+
+	created, err := store.CreateHouseholdTx(ctx, CreateHouseholdTxParams{
+		Name:              util.RandomString(6),
+		Currency:          util.USD,
+		CreatedBy:         creator.Username,
+		SpendRule:         HouseholdSpendMajority,
+		ApprovalThreshold: 10,
+		MaxAccounts:       10,
+	})
+	require.NoError(t, err)
+	household := created.Household
+
+	_, err = store.AddHouseholdMemberTx(ctx, AddHouseholdMemberTxParams{Household: household, Username: child.Username})
+	require.NoError(t, err)
+
+	_, err = store.AddAccountBalanceTx(ctx, AddAccountBalanceTxParams{ID: creatorAccount.ID, Amount: money.New(100, util.USD)})
+	require.NoError(t, err)
+
+	_, err = store.TransferMoneyTx(ctx, TransferMoneyTxParams{
+		FromAccountID: creatorAccount.ID,
+		ToAccountID:   household.AccountID,
+		Amount:        money.New(100, util.USD),
+	})
+	require.NoError(t, err)
+
+	_, err = store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 50,
+		DailyLimit:          1000,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CreateChildPayee(ctx, CreateChildPayeeParams{Child: child.Username, AccountID: shop.ID, AddedBy: guardian.Username})
+	require.NoError(t, err)
+
+	spend := func(to Account, amount int64) (HouseholdSpendTxResult, error) {
+		return store.SpendFromHouseholdTx(ctx, SpendFromHouseholdTxParams{
+			Household:   household,
+			RequestedBy: child.Username,
+			ToAccountID: to.ID,
+			Amount:      money.New(amount, util.USD),
+			Now:         time.Now(),
+		})
+	}
+
+	// a child cannot spend from the pot what their guardians would not let them send
+	_, err = spend(other, 10)
+	require.ErrorIs(t, err, ErrChildControls)
+	require.ErrorContains(t, err, "allowlist")
+
+	_, err = spend(shop, 60)
+	require.ErrorIs(t, err, ErrChildControls)
+	require.ErrorContains(t, err, "per-transaction limit")
+
+	result, err := spend(shop, 10)
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendApproved, result.Spend.Status)
+
+	// a spend waiting for votes is declined when the controls no longer let it be made
+	result, err = spend(shop, 40)
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendPending, result.Spend.Status)
+
+	_, err = store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 20,
+		DailyLimit:          1000,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	voted, err := store.VoteHouseholdSpendTx(ctx, VoteHouseholdSpendTxParams{
+		Household: household,
+		SpendID:   result.Spend.ID,
+		Username:  creator.Username,
+		Approve:   true,
+		Now:       time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, HouseholdSpendDeclined, voted.Spend.Status)
+	require.Nil(t, voted.Transfer)
+	requireNotification(t, child.Username, NotificationHouseholdSpendDeclined)
+
+	pot, err := store.GetAccountById(ctx, household.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), pot.Balance)
+
+	// what the child spent from the pot counts toward their daily limit
+	_, err = store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 50,
+		DailyLimit:          20,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = spend(shop, 10)
+	require.NoError(t, err)
+
+	_, err = spend(shop, 10)
+	require.ErrorIs(t, err, ErrChildControls)
+	require.ErrorContains(t, err, "daily limit")
+}
@@ -17,6 +17,127 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// chosen by the owner, e.g. rent or groceries, unique among their accounts
+	Name string `json:"name"`
+	// checking, savings or household, the pot of a household
+	Type string `json:"type"`
+	// public identifier of the account, bank code, check digits, branch code and random serial
+	Number string `json:"number"`
+}
+
+type AccountHolder struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	// primary, joint or view_only
+	Role      string      `json:"role"`
+	InvitedBy pgtype.Text `json:"invited_by"`
+	// null while the invitation of the holder is pending
+	AcceptedAt pgtype.Timestamptz `json:"accepted_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Allowance struct {
+	ID            int64  `json:"id"`
+	Guardian      string `json:"guardian"`
+	Child         string `json:"child"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	// weekly or monthly
+	Period        string    `json:"period"`
+	NextPaymentAt time.Time `json:"next_payment_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	// day of the month a monthly allowance is due, or the last day of shorter months
+	AnchorDay int16 `json:"anchor_day"`
+}
+
+type ApiKey struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Name     string    `json:"name"`
+	// public part of the key used to look it up
+	Prefix string `json:"prefix"`
+	// sha256 of the secret part of the key
+	HashedSecret string   `json:"hashed_secret"`
+	Scopes       []string `json:"scopes"`
+	// accounts the key can transfer from, empty means all of the owner's accounts
+	AccountIds []int64 `json:"account_ids"`
+	// ips or cidr ranges the key can be used from, empty means any
+	AllowedIps []string           `json:"allowed_ips"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID     int64  `json:"id"`
+	Actor  string `json:"actor"`
+	Action string `json:"action"`
+	// not a foreign key, the trail outlives the account
+	AccountID pgtype.Int8 `json:"account_id"`
+	// user the action was done to, e.g. the invited holder
+	Subject   pgtype.Text `json:"subject"`
+	Details   []byte      `json:"details"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type ChildControl struct {
+	Child    string `json:"child"`
+	Currency string `json:"currency"`
+	// in minor units of currency, transfers in other currencies need approval
+	PerTransactionLimit int64 `json:"per_transaction_limit"`
+	// total the child may send to others per utc day, in minor units of currency
+	DailyLimit int64     `json:"daily_limit"`
+	UpdatedBy  string    `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ChildPayee struct {
+	Child     string    `json:"child"`
+	AccountID int64     `json:"account_id"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChildTransferApproval struct {
+	ID            int64  `json:"id"`
+	Child         string `json:"child"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	// why the transfer needs a guardian, e.g. a payee not on the allowlist
+	Reason string `json:"reason"`
+	// pending, approved or declined
+	Status string `json:"status"`
+	// transfer made once a guardian approved
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	DecidedBy  pgtype.Text        `json:"decided_by"`
+	DecidedAt  pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	// money request the transfer pays, which is paid or declined along with it
+	MoneyRequestID pgtype.Int8 `json:"money_request_id"`
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// ISO 4217 minor unit, amounts are stored as 10^exponent minor units per unit
+	Exponent int32  `json:"exponent"`
+	Symbol   string `json:"symbol"`
+	// whether new accounts and transfers may use the currency
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DefaultAccount struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	// account payments to an alias of the owner are made into, their oldest checking account in the currency when not chosen
+	AccountID int64     `json:"account_id"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Entry struct {
@@ -27,6 +148,177 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type Expense struct {
+	ID     int64  `json:"id"`
+	PaidBy string `json:"paid_by"`
+	// account of the payer the participants pay their shares back into
+	AccountID   int64  `json:"account_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	// equal, percentage or exact
+	Split     string    `json:"split"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExpenseShare struct {
+	ExpenseID int64  `json:"expense_id"`
+	Username  string `json:"username"`
+	// what the participant owes the payer, in minor units of the currency of the expense
+	Amount int64 `json:"amount"`
+	// transfer which settled the share, null for the share of the payer
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	SettledAt  pgtype.Timestamptz `json:"settled_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Guardianship struct {
+	Guardian  string    `json:"guardian"`
+	Child     string    `json:"child"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Household struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedBy string `json:"created_by"`
+	// the pot, held by the creator and seen by every member
+	AccountID int64 `json:"account_id"`
+	// any_member or majority
+	SpendRule string `json:"spend_rule"`
+	// with the majority rule, spends above it need the approval of more than half of the members
+	ApprovalThreshold int64     `json:"approval_threshold"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type HouseholdMember struct {
+	HouseholdID int64     `json:"household_id"`
+	Username    string    `json:"username"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type HouseholdSpend struct {
+	ID          int64  `json:"id"`
+	HouseholdID int64  `json:"household_id"`
+	RequestedBy string `json:"requested_by"`
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Note        string `json:"note"`
+	// pending, approved or declined
+	Status string `json:"status"`
+	// transfer out of the pot once approved
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	DecidedAt  pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type HouseholdSpendVote struct {
+	SpendID   int64     `json:"spend_id"`
+	Username  string    `json:"username"`
+	Approve   bool      `json:"approve"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MoneyRequest struct {
+	ID        int64  `json:"id"`
+	Requester string `json:"requester"`
+	Payer     string `json:"payer"`
+	// account of the requester the money is paid into
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Memo        string `json:"memo"`
+	// pending, paid, declined or expired
+	Status string `json:"status"`
+	// transfer from the account the payer chose once paid
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	DecidedAt  pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Notification struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// what happened, e.g. child_transfer.blocked
+	Kind      string             `json:"kind"`
+	Payload   []byte             `json:"payload"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type OauthAuthorizationCode struct {
+	// sha256 of the code handed to the client
+	CodeHash    string    `json:"code_hash"`
+	ClientID    uuid.UUID `json:"client_id"`
+	Username    string    `json:"username"`
+	RedirectUri string    `json:"redirect_uri"`
+	Scopes      []string  `json:"scopes"`
+	// pkce S256 challenge the code verifier must match
+	CodeChallenge string             `json:"code_challenge"`
+	Nonce         string             `json:"nonce"`
+	ExpiresAt     time.Time          `json:"expires_at"`
+	UsedAt        pgtype.Timestamptz `json:"used_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type OauthClient struct {
+	ID    uuid.UUID `json:"id"`
+	Owner string    `json:"owner"`
+	Name  string    `json:"name"`
+	// sha256 of the client secret, empty for public clients which rely on pkce only
+	HashedSecret string    `json:"hashed_secret"`
+	RedirectUris []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OauthConsent struct {
+	Username string    `json:"username"`
+	ClientID uuid.UUID `json:"client_id"`
+	// scopes the user has approved for the client
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID       int64  `json:"id"`
+	TaskType string `json:"task_type"`
+	Payload  []byte `json:"payload"`
+	Queue    string `json:"queue"`
+	MaxRetry int32  `json:"max_retry"`
+	// number of times the relay tried to publish the task
+	Attempts  int32       `json:"attempts"`
+	LastError pgtype.Text `json:"last_error"`
+	CreatedAt time.Time   `json:"created_at"`
+	// set once the task was accepted by the task queue
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+	// when the relay last failed to publish the task, it waits longer after each failure
+	LastAttemptedAt pgtype.Timestamptz `json:"last_attempted_at"`
+}
+
+type Payee struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	// chosen by the owner of the payee book, unique in it
+	Nickname  string `json:"nickname"`
+	AccountID int64  `json:"account_id"`
+	// full name of the holder of the account when the payee was added
+	VerifiedName string `json:"verified_name"`
+	// alias the account was resolved from, empty when added by account id
+	Alias     string    `json:"alias"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RevokedToken struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	RevokedBy string    `json:"revoked_by"`
+	ExpiredAt time.Time `json:"expired_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
@@ -55,4 +347,60 @@ type User struct {
 	EmailVerifiedAt   pgtype.Timestamptz `json:"email_verified_at"`
 	PasswordChangedAt time.Time          `json:"password_changed_at"`
 	CreatedAt         time.Time          `json:"created_at"`
+	Role              string             `json:"role"`
+}
+
+type UserTokenRevocation struct {
+	Username  string `json:"username"`
+	RevokedBy string `json:"revoked_by"`
+	// every token issued at or before this time is revoked
+	RevokedBefore time.Time `json:"revoked_before"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type VerifyEmail struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// email the link was sent to, the user is only verified while it is still theirs
+	Email string `json:"email"`
+	// the secret code of the link is only sent to the email
+	HashedSecretCode string    `json:"hashed_secret_code"`
+	IsUsed           bool      `json:"is_used"`
+	CreatedAt        time.Time `json:"created_at"`
+	ExpiredAt        time.Time `json:"expired_at"`
+}
+
+type WebhookDelivery struct {
+	ID         int64 `json:"id"`
+	EndpointID int64 `json:"endpoint_id"`
+	EventID    int64 `json:"event_id"`
+	// pending, succeeded or failed once every attempt was used
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	// http status code of the last attempt
+	ResponseStatus pgtype.Int4        `json:"response_status"`
+	LastError      pgtype.Text        `json:"last_error"`
+	CreatedAt      time.Time          `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+}
+
+type WebhookEndpoint struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// key the deliveries are signed with, only shown when the endpoint is created
+	Secret string `json:"secret"`
+	// event types sent to the endpoint
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookEvent struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	EventType string    `json:"event_type"`
+	Payload   []byte    `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+	// set once a delivery was created for every subscribed endpoint
+	DispatchedAt pgtype.Timestamptz `json:"dispatched_at"`
 }
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Money returns the balance of the account in its currency
+func (account Account) Money() money.Money {
+	return money.New(account.Balance, account.Currency)
+}
+
+// Money returns the amount of the transfer waiting for approval in its currency
+func (approval ChildTransferApproval) Money() money.Money {
+	return money.New(approval.Amount, approval.Currency)
+}
+
+// Money returns the amount of the allowance in its currency
+func (allowance Allowance) Money() money.Money {
+	return money.New(allowance.Amount, allowance.Currency)
+}
+
+// Money returns the amount of the expense in its currency
+func (expense Expense) Money() money.Money {
+	return money.New(expense.Amount, expense.Currency)
+}
+
+// Money returns the amount of the request in its currency
+func (request MoneyRequest) Money() money.Money {
+	return money.New(request.Amount, request.Currency)
+}
+
+// addAccountMoney adds the amount to the balance of the account. It fails when the account is in another currency,
+// or when the balance would not fit in a bigint, in which case the caller must roll the transaction back.
+func addAccountMoney(ctx context.Context, q *Queries, accountID int64, amount money.Money) (Account, error) {
+	account, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     accountID,
+		Amount: amount.Amount,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "22003" { // numeric_value_out_of_range
+			return account, fmt.Errorf("%w: balance of account [%d]", money.ErrOverflow, accountID)
+		}
+		return account, err
+	}
+
+	if account.Currency != amount.Currency {
+		return account, fmt.Errorf("%w: account [%d] is in %s, not %s", money.ErrCurrencyMismatch, accountID, account.Currency, amount.Currency)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomMoneyRequest(t *testing.T, store Store, requester User, payer User, expiresAt time.Time) MoneyRequest {
+	account := createRandomOwnedAccount(t, requester, util.USD)
+
+	request, err := store.CreateMoneyRequestTx(context.Background(), CreateMoneyRequestParams{
+		Requester:   requester.Username,
+		Payer:       payer.Username,
+		ToAccountID: account.ID,
+		Amount:      10,
+		Currency:    util.USD,
+		Memo:        util.RandomString(6),
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, MoneyRequestPending, request.Status)
+
+	return request
+}
+
+func TestDecideMoneyRequestTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	requester := createRandomUser(t)
+	payer := createRandomUser(t)
+	payerAccount := createRandomOwnedAccount(t, payer, util.USD)
+
+	request := createRandomMoneyRequest(t, store, requester, payer, time.Now().Add(time.Hour))
+	requireNotification(t, payer.Username, NotificationMoneyRequestReceived)
+
+	pending, err := store.ListPendingMoneyRequests(ctx, payer.Username)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	_, err = store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{ID: request.ID, Payer: requester.Username, Now: time.Now()})
+	require.ErrorIs(t, err, ErrNotMoneyRequestPayer)
+
+	_, err = store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{ID: request.ID, Payer: payer.Username, Now: request.ExpiresAt})
+	require.ErrorIs(t, err, ErrMoneyRequestExpired)
+
+	funded, err := store.AddAccountBalanceTx(ctx, AddAccountBalanceTxParams{ID: payerAccount.ID, Amount: money.New(request.Amount, util.USD)})
+	require.NoError(t, err)
+
+	result, err := store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{
+		ID:            request.ID,
+		Payer:         payer.Username,
+		FromAccountID: payerAccount.ID,
+		Approve:       true,
+		Now:           time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, MoneyRequestPaid, result.Request.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Request.TransferID.Int64)
+	require.Equal(t, funded.Balance-request.Amount, result.Transfer.FromAccount.Balance)
+	requireNotification(t, requester.Username, NotificationMoneyRequestPaid)
+
+	declined := createRandomMoneyRequest(t, store, requester, payer, time.Now().Add(time.Hour))
+	result, err = store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{ID: declined.ID, Payer: payer.Username, Now: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, MoneyRequestDeclined, result.Request.Status)
+	require.Nil(t, result.Transfer)
+	requireNotification(t, requester.Username, NotificationMoneyRequestDeclined)
+
+	_, err = store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{ID: declined.ID, Payer: payer.Username, Now: time.Now()})
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+}
+
+func TestDecideMoneyRequestTxChild(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	requester := createRandomUser(t)
+	guardian := createRandomUser(t)
+	child := createRandomChild(t, store, guardian)
+	childAccount := createRandomOwnedAccount(t, child, util.USD)
+	holdAccount(t, childAccount, child)
+
+	pay := func(request MoneyRequest) (DecideMoneyRequestTxResult, error) {
+		return store.DecideMoneyRequestTx(ctx, DecideMoneyRequestTxParams{
+			ID:            request.ID,
+			Payer:         child.Username,
+			FromAccountID: childAccount.ID,
+			Approve:       true,
+			Now:           time.Now(),
+		})
+	}
+
+	// without limits the payment waits for a guardian, and the request stays pending until then
+	request := createRandomMoneyRequest(t, store, requester, child, time.Now().Add(time.Hour))
+	result, err := pay(request)
+	require.NoError(t, err)
+	require.Nil(t, result.Transfer)
+	require.NotNil(t, result.Approval)
+	require.Equal(t, request.ID, result.Approval.MoneyRequestID.Int64)
+	require.Equal(t, MoneyRequestPending, result.Request.Status)
+	requireNotification(t, guardian.Username, NotificationChildTransferApprovalRequested)
+
+	// one payment at a time waits for the guardian
+	_, err = pay(request)
+	require.Error(t, err)
+
+	decided, err := store.DecideChildTransferTx(ctx, DecideChildTransferTxParams{ID: result.Approval.ID, Guardian: guardian.Username, Approve: true})
+	require.NoError(t, err)
+	require.NotNil(t, decided.Transfer)
+	require.Equal(t, MoneyRequestPaid, decided.MoneyRequest.Status)
+	require.Equal(t, decided.Transfer.Transfer.ID, decided.MoneyRequest.TransferID.Int64)
+	requireNotification(t, requester.Username, NotificationMoneyRequestPaid)
+
+	// over the limits the payment is blocked, and the request declined
+	_, err = store.UpsertChildControls(ctx, UpsertChildControlsParams{
+		Child:               child.Username,
+		Currency:            util.USD,
+		PerTransactionLimit: 5,
+		DailyLimit:          50,
+		UpdatedBy:           guardian.Username,
+	})
+	require.NoError(t, err)
+
+	blocked := createRandomMoneyRequest(t, store, requester, child, time.Now().Add(time.Hour))
+	result, err = pay(blocked)
+	require.NoError(t, err)
+	require.Contains(t, result.Blocked, "per-transaction limit")
+	require.Nil(t, result.Transfer)
+	require.Equal(t, MoneyRequestDeclined, result.Request.Status)
+	requireNotification(t, guardian.Username, NotificationChildTransferBlocked)
+	requireNotification(t, requester.Username, NotificationMoneyRequestDeclined)
+}
+
+func TestExpireMoneyRequestTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	requester := createRandomUser(t)
+	payer := createRandomUser(t)
+
+	request := createRandomMoneyRequest(t, store, requester, payer, time.Now().Add(-time.Second))
+
+	ids, err := store.ListExpiredMoneyRequests(ctx, ListExpiredMoneyRequestsParams{AfterID: request.ID - 1, LimitCount: 1})
+	require.NoError(t, err)
+	require.Contains(t, ids, request.ID)
+
+	result, err := store.ExpireMoneyRequestTx(ctx, request.ID)
+	require.NoError(t, err)
+	require.True(t, result.Expired)
+	require.Equal(t, MoneyRequestExpired, result.Request.Status)
+	requireNotification(t, requester.Username, NotificationMoneyRequestExpired)
+	requireNotification(t, payer.Username, NotificationMoneyRequestExpired)
+
+	// already expired
+	result, err = store.ExpireMoneyRequestTx(ctx, request.ID)
+	require.NoError(t, err)
+	require.False(t, result.Expired)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOauthClient(t *testing.T, owner User) OauthClient {
+	arg := CreateOauthClientParams{
+		ID:           uuid.New(),
+		Owner:        owner.Username,
+		Name:         util.RandomString(8),
+		HashedSecret: util.RandomString(64),
+		RedirectUris: []string{"https://" + util.RandomString(6) + ".example/callback"},
+		Scopes:       []string{"openid", "accounts:read"},
+	}
+
+	client, err := testQueries.CreateOauthClient(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, client.ID)
+	require.Equal(t, arg.Owner, client.Owner)
+	require.Equal(t, arg.RedirectUris, client.RedirectUris)
+	require.Equal(t, arg.Scopes, client.Scopes)
+	require.NotZero(t, client.CreatedAt)
+
+	return client
+}
+
+func TestGetOauthClient(t *testing.T) {
+	client1 := createRandomOauthClient(t, createRandomUser(t))
+
+	client2, err := testQueries.GetOauthClient(context.Background(), client1.ID)
+	require.NoError(t, err)
+	require.Equal(t, client1.Name, client2.Name)
+	require.Equal(t, client1.HashedSecret, client2.HashedSecret)
+}
+
+func TestUseOauthAuthorizationCode(t *testing.T) {
+	user := createRandomUser(t)
+	client := createRandomOauthClient(t, user)
+
+	arg := CreateOauthAuthorizationCodeParams{
+		CodeHash:      util.RandomString(64),
+		ClientID:      client.ID,
+		Username:      user.Username,
+		RedirectUri:   client.RedirectUris[0],
+		Scopes:        client.Scopes,
+		CodeChallenge: util.RandomString(43),
+		Nonce:         util.RandomString(10),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+
+	_, err := testQueries.CreateOauthAuthorizationCode(context.Background(), arg)
+	require.NoError(t, err)
+
+	code, err := testQueries.UseOauthAuthorizationCode(context.Background(), arg.CodeHash)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, code.Username)
+	require.Equal(t, arg.CodeChallenge, code.CodeChallenge)
+	require.True(t, code.UsedAt.Valid)
+
+	// a code can only be exchanged once
+	_, err = testQueries.UseOauthAuthorizationCode(context.Background(), arg.CodeHash)
+	require.Error(t, err)
+
+	arg.CodeHash = util.RandomString(64)
+	arg.ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = testQueries.CreateOauthAuthorizationCode(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testQueries.UseOauthAuthorizationCode(context.Background(), arg.CodeHash)
+	require.Error(t, err)
+}
+
+func TestOauthConsent(t *testing.T) {
+	user := createRandomUser(t)
+	client := createRandomOauthClient(t, createRandomUser(t))
+
+	consent, err := testQueries.UpsertOauthConsent(context.Background(), UpsertOauthConsentParams{
+		Username: user.Username,
+		ClientID: client.ID,
+		Scopes:   []string{"openid"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"openid"}, consent.Scopes)
+
+	consent, err = testQueries.UpsertOauthConsent(context.Background(), UpsertOauthConsentParams{
+		Username: user.Username,
+		ClientID: client.ID,
+		Scopes:   client.Scopes,
+	})
+	require.NoError(t, err)
+	require.Equal(t, client.Scopes, consent.Scopes)
+
+	consents, err := testQueries.ListOauthConsents(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, consents, 1)
+
+	err = testQueries.DeleteOauthConsent(context.Background(), DeleteOauthConsentParams{
+		Username: user.Username,
+		ClientID: client.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetOauthConsent(context.Background(), GetOauthConsentParams{
+		Username: user.Username,
+		ClientID: client.ID,
+	})
+	require.Error(t, err)
+}
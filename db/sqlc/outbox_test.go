@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomCreateUserParams(t *testing.T) CreateUserParams {
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	return CreateUserParams{
+		Username:       util.RandomString(7),
+		FullName:       util.RandomString(10),
+		Email:          util.RandomString(10) + "@gmail.com",
+		HashedPassword: hashedPassword,
+	}
+}
+
+func TestCreateUserTxWritesOutbox(t *testing.T) {
+	store := NewStore(testDb)
+	arg := randomCreateUserParams(t)
+
+	message := CreateOutboxMessageParams{
+		TaskType: "task:test",
+		Payload:  []byte(`{"username":"` + arg.Username + `"}`),
+		Queue:    "default",
+		MaxRetry: 3,
+	}
+
+	_, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: arg,
+		AfterCreateUser: func(user User) ([]CreateOutboxMessageParams, error) {
+			return []CreateOutboxMessageParams{message}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	var published []Outbox
+	_, err = store.PublishOutboxTx(context.Background(), PublishOutboxTxParams{
+		Limit:       1000,
+		MaxAttempts: 10,
+		Publish: func(message Outbox) error {
+			published = append(published, message)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.True(t, containsOutboxPayload(published, message.Payload))
+}
+
+func TestCreateUserTxRollsBackOutbox(t *testing.T) {
+	store := NewStore(testDb)
+	arg := randomCreateUserParams(t)
+
+	message := CreateOutboxMessageParams{
+		TaskType: "task:test",
+		Payload:  []byte(`{"username":"` + arg.Username + `"}`),
+		Queue:    "default",
+		MaxRetry: 3,
+	}
+
+	// the user already exists, so the outbox message must not survive
+	_, err := testQueries.CreateUser(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: arg,
+		AfterCreateUser: func(user User) ([]CreateOutboxMessageParams, error) {
+			return []CreateOutboxMessageParams{message}, nil
+		},
+	})
+	require.Error(t, err)
+
+	var published []Outbox
+	_, err = store.PublishOutboxTx(context.Background(), PublishOutboxTxParams{
+		Limit:       1000,
+		MaxAttempts: 10,
+		Publish: func(message Outbox) error {
+			published = append(published, message)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, containsOutboxPayload(published, message.Payload))
+}
+
+func TestPublishOutboxTxRecordsFailure(t *testing.T) {
+	message, err := testQueries.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+		TaskType: "task:test",
+		Payload:  []byte(`{"value":"` + util.RandomString(10) + `"}`),
+		Queue:    "default",
+		MaxRetry: 3,
+	})
+	require.NoError(t, err)
+	require.Zero(t, message.Attempts)
+	require.False(t, message.PublishedAt.Valid)
+
+	store := NewStore(testDb)
+	_, err = store.PublishOutboxTx(context.Background(), PublishOutboxTxParams{
+		Limit:       1000,
+		MaxAttempts: 10,
+		Publish: func(message Outbox) error {
+			return errors.New("queue is down")
+		},
+	})
+	require.NoError(t, err)
+
+	// the failed message is still pending and published on the next run
+	var published []Outbox
+	_, err = store.PublishOutboxTx(context.Background(), PublishOutboxTxParams{
+		Limit:       1000,
+		MaxAttempts: 10,
+		Publish: func(message Outbox) error {
+			published = append(published, message)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	for _, m := range published {
+		if m.ID == message.ID {
+			require.Equal(t, int32(1), m.Attempts)
+			require.True(t, m.LastError.Valid)
+			return
+		}
+	}
+	t.Fatal("failed outbox message was not published again")
+}
+
+func TestPublishOutboxTxBacksOff(t *testing.T) {
+	message, err := testQueries.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+		TaskType: "task:test",
+		Payload:  []byte(`{"value":"` + util.RandomString(10) + `"}`),
+		Queue:    "default",
+		MaxRetry: 3,
+	})
+	require.NoError(t, err)
+
+	store := NewStore(testDb)
+	publish := func(backoff time.Duration) []Outbox {
+		var attempted []Outbox
+		_, err := store.PublishOutboxTx(context.Background(), PublishOutboxTxParams{
+			Limit:       1000,
+			MaxAttempts: 2,
+			Backoff:     backoff,
+			Publish: func(m Outbox) error {
+				if m.ID != message.ID {
+					return nil
+				}
+				attempted = append(attempted, m)
+				return errors.New("queue is down")
+			},
+		})
+		require.NoError(t, err)
+		return attempted
+	}
+
+	require.Len(t, publish(time.Hour), 1)
+
+	// the failed message waits the backoff before it is tried again
+	require.Empty(t, publish(time.Hour))
+
+	attempted := publish(0)
+	require.Len(t, attempted, 1)
+	require.Equal(t, int32(1), attempted[0].Attempts)
+	require.True(t, attempted[0].LastAttemptedAt.Valid)
+
+	// and is left alone once it failed its last attempt
+	require.Empty(t, publish(0))
+}
+
+func containsOutboxPayload(messages []Outbox, payload []byte) bool {
+	for _, message := range messages {
+		if string(message.Payload) == string(payload) {
+			return true
+		}
+	}
+	return false
+}
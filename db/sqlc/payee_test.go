@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDefaultAccount(t *testing.T) {
+	ctx := context.Background()
+	user := createRandomUser(t)
+
+	_, err := testQueries.GetDefaultAccount(ctx, GetDefaultAccountParams{Owner: user.Username, Currency: util.USD})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	savings, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+		Name:     util.RandomString(6),
+		Type:     AccountTypeSavings,
+		Number:   randomAccountNumber(t),
+	})
+	require.NoError(t, err)
+
+	// savings are only paid into when chosen
+	_, err = testQueries.GetDefaultAccount(ctx, GetDefaultAccountParams{Owner: user.Username, Currency: util.USD})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	oldest := createRandomOwnedAccount(t, user, util.USD)
+	createRandomOwnedAccount(t, user, util.USD)
+
+	account, err := testQueries.GetDefaultAccount(ctx, GetDefaultAccountParams{Owner: user.Username, Currency: util.USD})
+	require.NoError(t, err)
+	require.Equal(t, oldest.ID, account.ID)
+
+	_, err = testQueries.SetDefaultAccount(ctx, SetDefaultAccountParams{Owner: user.Username, Currency: util.USD, AccountID: savings.ID})
+	require.NoError(t, err)
+
+	account, err = testQueries.GetDefaultAccount(ctx, GetDefaultAccountParams{Owner: user.Username, Currency: util.USD})
+	require.NoError(t, err)
+	require.Equal(t, savings.ID, account.ID)
+
+	_, err = testQueries.SetDefaultAccount(ctx, SetDefaultAccountParams{Owner: user.Username, Currency: util.USD, AccountID: oldest.ID})
+	require.NoError(t, err)
+
+	account, err = testQueries.GetDefaultAccount(ctx, GetDefaultAccountParams{Owner: user.Username, Currency: util.USD})
+	require.NoError(t, err)
+	require.Equal(t, oldest.ID, account.ID)
+}
+
+func TestPayees(t *testing.T) {
+	ctx := context.Background()
+	user := createRandomUser(t)
+	recipient := createRandomUser(t)
+	account := createRandomOwnedAccount(t, recipient, util.USD)
+
+	payee, err := testQueries.CreatePayee(ctx, CreatePayeeParams{
+		Owner:        user.Username,
+		Nickname:     "landlord",
+		AccountID:    account.ID,
+		VerifiedName: recipient.FullName,
+	})
+	require.NoError(t, err)
+	require.Empty(t, payee.Alias)
+
+	_, err = testQueries.CreatePayee(ctx, CreatePayeeParams{
+		Owner:        user.Username,
+		Nickname:     "landlord",
+		AccountID:    account.ID,
+		VerifiedName: recipient.FullName,
+	})
+	require.Error(t, err)
+
+	_, err = testQueries.GetPayee(ctx, GetPayeeParams{ID: payee.ID, Owner: recipient.Username})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	payees, err := testQueries.ListPayees(ctx, user.Username)
+	require.NoError(t, err)
+	require.Equal(t, []Payee{payee}, payees)
+
+	// the payee goes along with the account
+	require.NoError(t, testQueries.DeleteAccount(ctx, account.ID))
+
+	_, err = testQueries.GetPayee(ctx, GetPayeeParams{ID: payee.ID, Owner: user.Username})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
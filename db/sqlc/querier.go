@@ -6,33 +6,186 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AcceptAccountHolder(ctx context.Context, arg AcceptAccountHolderParams) (AccountHolder, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	BlockUserSessions(ctx context.Context, username string) error
+	CountAccountsByOwner(ctx context.Context, owner string) (int64, error)
+	// how many of the accounts the user owns and nobody else holds, or is invited to hold
+	CountAccountsHeldAlone(ctx context.Context, arg CountAccountsHeldAloneParams) (int64, error)
+	CountHouseholdMembers(ctx context.Context, householdID int64) (int64, error)
+	// only the votes of who is still a member count
+	CountHouseholdSpendVotes(ctx context.Context, spendID int64) (CountHouseholdSpendVotesRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountHolder(ctx context.Context, arg CreateAccountHolderParams) (AccountHolder, error)
+	CreateAllowance(ctx context.Context, arg CreateAllowanceParams) (Allowance, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateChildPayee(ctx context.Context, arg CreateChildPayeeParams) (ChildPayee, error)
+	CreateChildTransferApproval(ctx context.Context, arg CreateChildTransferApprovalParams) (ChildTransferApproval, error)
+	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error)
+	CreateExpenseShare(ctx context.Context, arg CreateExpenseShareParams) (ExpenseShare, error)
+	CreateGuardianship(ctx context.Context, arg CreateGuardianshipParams) (Guardianship, error)
+	CreateHousehold(ctx context.Context, arg CreateHouseholdParams) (Household, error)
+	CreateHouseholdMember(ctx context.Context, arg CreateHouseholdMemberParams) (HouseholdMember, error)
+	CreateHouseholdSpend(ctx context.Context, arg CreateHouseholdSpendParams) (HouseholdSpend, error)
+	CreateHouseholdSpendVote(ctx context.Context, arg CreateHouseholdSpendVoteParams) (HouseholdSpendVote, error)
+	CreateMoneyRequest(ctx context.Context, arg CreateMoneyRequestParams) (MoneyRequest, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateOauthAuthorizationCode(ctx context.Context, arg CreateOauthAuthorizationCodeParams) (OauthAuthorizationCode, error)
+	CreateOauthClient(ctx context.Context, arg CreateOauthClientParams) (OauthClient, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (Outbox, error)
+	CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payee, error)
+	CreateRevokedToken(ctx context.Context, arg CreateRevokedTokenParams) (RevokedToken, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	// creates a delivery of the event for every endpoint of its owner subscribed to its type
+	CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	CreateWebhookEvent(ctx context.Context, arg CreateWebhookEventParams) (WebhookEvent, error)
+	DecideChildTransferApproval(ctx context.Context, arg DecideChildTransferApprovalParams) (ChildTransferApproval, error)
+	DecideHouseholdSpend(ctx context.Context, arg DecideHouseholdSpendParams) (HouseholdSpend, error)
+	DecideMoneyRequest(ctx context.Context, arg DecideMoneyRequestParams) (MoneyRequest, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountHolder(ctx context.Context, arg DeleteAccountHolderParams) (AccountHolder, error)
+	DeleteAllowance(ctx context.Context, arg DeleteAllowanceParams) (Allowance, error)
+	DeleteChildPayee(ctx context.Context, arg DeleteChildPayeeParams) (ChildPayee, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) error
+	DeleteHouseholdMember(ctx context.Context, arg DeleteHouseholdMemberParams) (HouseholdMember, error)
+	DeleteHouseholdMemberPendingVotes(ctx context.Context, arg DeleteHouseholdMemberPendingVotesParams) error
+	DeleteOauthConsent(ctx context.Context, arg DeleteOauthConsentParams) error
+	DeletePayee(ctx context.Context, arg DeletePayeeParams) (Payee, error)
+	DeletePublishedOutboxMessages(ctx context.Context, before time.Time) error
+	DeleteWebhookEndpoint(ctx context.Context, id int64) error
+	// skips requests decided or expired since they were listed
+	ExpireMoneyRequest(ctx context.Context, id int64) (MoneyRequest, error)
 	GetAccountById(ctx context.Context, id int64) (Account, error)
 	GetAccountByIdForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountByNumber(ctx context.Context, number string) (Account, error)
+	// role and accepted_at are null when the user does not hold the account
+	GetAccountForHolder(ctx context.Context, arg GetAccountForHolderParams) (GetAccountForHolderRow, error)
+	GetAccountHolder(ctx context.Context, arg GetAccountHolderParams) (AccountHolder, error)
 	GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Account, error)
 	GetAllTransferFromAAccount(ctx context.Context, arg GetAllTransferFromAAccountParams) ([]Transfer, error)
 	GetAllTransfersBetweenTwoAccounts(ctx context.Context, arg GetAllTransfersBetweenTwoAccountsParams) ([]Transfer, error)
+	GetApiKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetChildControls(ctx context.Context, child string) (ChildControl, error)
+	GetChildPayee(ctx context.Context, arg GetChildPayeeParams) (ChildPayee, error)
+	GetChildTransferApprovalForUpdate(ctx context.Context, id int64) (ChildTransferApproval, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	// the account the owner chose for the currency, else their oldest checking account in it
+	GetDefaultAccount(ctx context.Context, arg GetDefaultAccountParams) (Account, error)
+	// skips allowances another payer is paying, and ones paid since they were listed
+	GetDueAllowanceForUpdate(ctx context.Context, id int64) (Allowance, error)
 	GetEntriesByAccountId(ctx context.Context, arg GetEntriesByAccountIdParams) ([]Entry, error)
 	GetEntryById(ctx context.Context, id int64) (Entry, error)
+	GetExpense(ctx context.Context, id int64) (Expense, error)
+	GetExpenseShareForUpdate(ctx context.Context, arg GetExpenseShareForUpdateParams) (ExpenseShare, error)
+	GetGuardianship(ctx context.Context, arg GetGuardianshipParams) (Guardianship, error)
+	GetHousehold(ctx context.Context, id int64) (Household, error)
+	GetHouseholdMember(ctx context.Context, arg GetHouseholdMemberParams) (HouseholdMember, error)
+	GetHouseholdSpendForUpdate(ctx context.Context, arg GetHouseholdSpendForUpdateParams) (HouseholdSpend, error)
+	// id of the latest entry of the account, 0 when it has none
+	GetLastEntryId(ctx context.Context, accountID int64) (int64, error)
+	GetMoneyRequestForUpdate(ctx context.Context, id int64) (MoneyRequest, error)
+	GetOauthClient(ctx context.Context, id uuid.UUID) (OauthClient, error)
+	GetOauthConsent(ctx context.Context, arg GetOauthConsentParams) (OauthConsent, error)
+	GetPayee(ctx context.Context, arg GetPayeeParams) (Payee, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransferById(ctx context.Context, id int64) (Transfer, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	// serializes the transactions which check limits on the accounts of the user
+	GetUserByUsernameForUpdate(ctx context.Context, username string) (User, error)
 	GetUsersAccounts(ctx context.Context, username string) ([]Account, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	GetWebhookEvent(ctx context.Context, id int64) (WebhookEvent, error)
+	IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error)
+	// entries after the given one, oldest first, with the account balance right after each of them
+	ListAccountActivity(ctx context.Context, arg ListAccountActivityParams) ([]ListAccountActivityRow, error)
+	ListAccountAuditEvents(ctx context.Context, arg ListAccountAuditEventsParams) ([]AuditEvent, error)
+	ListAccountHolders(ctx context.Context, accountID int64) ([]AccountHolder, error)
+	// maps the internal ids of the accounts to their public numbers
+	ListAccountNumbers(ctx context.Context, ids []int64) ([]ListAccountNumbersRow, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
+	ListApiKeys(ctx context.Context, arg ListApiKeysParams) ([]ApiKey, error)
+	ListChildAllowances(ctx context.Context, child string) ([]Allowance, error)
+	ListChildPayees(ctx context.Context, child string) ([]ChildPayee, error)
+	ListChildren(ctx context.Context, guardian string) ([]Guardianship, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	// pages through the due allowances by id, so allowances which keep failing do not hide the ones after them
+	ListDueAllowances(ctx context.Context, arg ListDueAllowancesParams) ([]ListDueAllowancesRow, error)
+	ListExpenseShares(ctx context.Context, expenseID int64) ([]ExpenseShare, error)
+	// pages through the expired requests by id, so requests which keep failing do not hide the ones after them
+	ListExpiredMoneyRequests(ctx context.Context, arg ListExpiredMoneyRequestsParams) ([]int64, error)
+	ListGuardians(ctx context.Context, child string) ([]string, error)
+	ListHeldAccounts(ctx context.Context, arg ListHeldAccountsParams) ([]ListHeldAccountsRow, error)
+	// who paid into the pot how much between the two times, by the owner of the paying account
+	ListHouseholdContributions(ctx context.Context, arg ListHouseholdContributionsParams) ([]ListHouseholdContributionsRow, error)
+	ListHouseholdMembers(ctx context.Context, householdID int64) ([]HouseholdMember, error)
+	ListHouseholdSpends(ctx context.Context, arg ListHouseholdSpendsParams) ([]HouseholdSpend, error)
+	ListMemberHouseholds(ctx context.Context, username string) ([]Household, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOauthConsents(ctx context.Context, username string) ([]OauthConsent, error)
+	// what each other user owes the user, net of what the user owes them, in every currency with unsettled shares.
+	// A negative amount is owed by the user.
+	ListOutstandingBalances(ctx context.Context, username string) ([]ListOutstandingBalancesRow, error)
+	ListPayees(ctx context.Context, owner string) ([]Payee, error)
+	ListPendingAccountHolders(ctx context.Context, username string) ([]AccountHolder, error)
+	// the pending approvals of every child of the guardian
+	ListPendingChildTransferApprovals(ctx context.Context, guardian string) ([]ChildTransferApproval, error)
+	// the requests the payer may still pay, oldest first
+	ListPendingMoneyRequests(ctx context.Context, payer string) ([]MoneyRequest, error)
+	// a message waits the backoff once more after every failure, and is left for an operator after the last attempt
+	ListPendingOutboxMessages(ctx context.Context, arg ListPendingOutboxMessagesParams) ([]Outbox, error)
+	ListPendingWebhookEvents(ctx context.Context, limit int32) ([]WebhookEvent, error)
+	ListRequesterMoneyRequests(ctx context.Context, arg ListRequesterMoneyRequestsParams) ([]MoneyRequest, error)
+	// expenses the user paid or has a share of
+	ListUserExpenses(ctx context.Context, arg ListUserExpensesParams) ([]Expense, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]ListWebhookDeliveriesRow, error)
+	ListWebhookEndpoints(ctx context.Context, owner string) ([]WebhookEndpoint, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	MarkOutboxMessagePublished(ctx context.Context, id int64) error
+	MarkWebhookEventDispatched(ctx context.Context, id int64) error
+	// wakes up the watchers of the account once the transaction commits
+	NotifyAccountActivity(ctx context.Context, accountID int64) error
+	RecordOutboxMessageFailure(ctx context.Context, arg RecordOutboxMessageFailureParams) (int32, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (ApiKey, error)
+	SetDefaultAccount(ctx context.Context, arg SetDefaultAccountParams) (DefaultAccount, error)
+	SettleExpenseShare(ctx context.Context, arg SettleExpenseShareParams) (ExpenseShare, error)
+	// money which came into and went out of the account between the two times
+	SumAccountEntries(ctx context.Context, arg SumAccountEntriesParams) (SumAccountEntriesRow, error)
+	// what the child sent from their accounts since the given time, except to accounts they own and nobody else holds,
+	// and what they spent from the pots of their households
+	SumChildSpending(ctx context.Context, arg SumChildSpendingParams) (int64, error)
 	UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error)
+	UpdateAllowanceNextPayment(ctx context.Context, arg UpdateAllowanceNextPaymentParams) (Allowance, error)
+	UpdateApiKeyLastUsed(ctx context.Context, id uuid.UUID) error
+	// the exponent is fixed once created, changing it would rescale every stored amount
+	UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error)
+	UpdateHouseholdSpendRule(ctx context.Context, arg UpdateHouseholdSpendRuleParams) (Household, error)
 	UpdateSession(ctx context.Context, id uuid.UUID) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpsertChildControls(ctx context.Context, arg UpsertChildControlsParams) (ChildControl, error)
+	UpsertOauthConsent(ctx context.Context, arg UpsertOauthConsentParams) (OauthConsent, error)
+	UpsertUserTokenRevocation(ctx context.Context, arg UpsertUserTokenRevocationParams) (UserTokenRevocation, error)
+	UseOauthAuthorizationCode(ctx context.Context, codeHash string) (OauthAuthorizationCode, error)
+	// a link can only be used once, and until it expires
+	UseVerifyEmail(ctx context.Context, arg UseVerifyEmailParams) (VerifyEmail, error)
+	// verifies the email of the user, unless it changed since the link was sent to it
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRevokedToken(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateRevokedTokenParams{
+		ID:        uuid.New(),
+		Username:  user.Username,
+		RevokedBy: user.Username,
+		ExpiredAt: time.Now().Add(time.Minute),
+	}
+
+	revokedToken, err := testQueries.CreateRevokedToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, revokedToken.ID)
+	require.Equal(t, arg.Username, revokedToken.Username)
+	require.WithinDuration(t, arg.ExpiredAt, revokedToken.ExpiredAt, time.Second)
+
+	// revoking the same token twice is not an error
+	_, err = testQueries.CreateRevokedToken(context.Background(), arg)
+	require.NoError(t, err)
+
+	revoked, err := testQueries.IsTokenRevoked(context.Background(), IsTokenRevokedParams{
+		ID:       arg.ID,
+		Username: user.Username,
+		IssuedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestUpsertUserTokenRevocation(t *testing.T) {
+	user := createRandomUser(t)
+	issuedAt := time.Now()
+
+	check := IsTokenRevokedParams{
+		ID:       uuid.New(),
+		Username: user.Username,
+		IssuedAt: issuedAt,
+	}
+
+	revoked, err := testQueries.IsTokenRevoked(context.Background(), check)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	_, err = testQueries.UpsertUserTokenRevocation(context.Background(), UpsertUserTokenRevocationParams{
+		Username:      user.Username,
+		RevokedBy:     user.Username,
+		RevokedBefore: issuedAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+
+	revoked, err = testQueries.IsTokenRevoked(context.Background(), check)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	check.IssuedAt = issuedAt.Add(time.Minute)
+	revoked, err = testQueries.IsTokenRevoked(context.Background(), check)
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
@@ -10,6 +10,32 @@ type Store interface {
 	Querier
 	TransferMoneyTx(ctx context.Context, arg TransferMoneyTxParams) (TransfeMoneyTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	PublishOutboxTx(ctx context.Context, arg PublishOutboxTxParams) (PublishOutboxTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (Account, error)
+	AddAccountBalanceTx(ctx context.Context, arg AddAccountBalanceTxParams) (Account, error)
+	DeleteAccountTx(ctx context.Context, id int64) error
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	DispatchWebhookEventsTx(ctx context.Context, arg DispatchWebhookEventsTxParams) (DispatchWebhookEventsTxResult, error)
+	CreateWebhookDeliveryTx(ctx context.Context, arg CreateWebhookDeliveryTxParams) (CreateWebhookDeliveryTxResult, error)
+	PingWebhookEndpointTx(ctx context.Context, arg PingWebhookEndpointTxParams) (PingWebhookEndpointTxResult, error)
+	InviteAccountHolderTx(ctx context.Context, arg InviteAccountHolderTxParams) (AccountHolder, error)
+	AcceptAccountHolderTx(ctx context.Context, arg AcceptAccountHolderTxParams) (AccountHolder, error)
+	RemoveAccountHolderTx(ctx context.Context, arg RemoveAccountHolderTxParams) (AccountHolder, error)
+	CreateChildTx(ctx context.Context, arg CreateChildTxParams) (User, error)
+	ChildTransferTx(ctx context.Context, arg ChildTransferTxParams) (ChildTransferTxResult, error)
+	DecideChildTransferTx(ctx context.Context, arg DecideChildTransferTxParams) (DecideChildTransferTxResult, error)
+	PayAllowanceTx(ctx context.Context, id int64) (PayAllowanceTxResult, error)
+	CreateHouseholdTx(ctx context.Context, arg CreateHouseholdTxParams) (CreateHouseholdTxResult, error)
+	AddHouseholdMemberTx(ctx context.Context, arg AddHouseholdMemberTxParams) (HouseholdMember, error)
+	RemoveHouseholdMemberTx(ctx context.Context, arg RemoveHouseholdMemberTxParams) (HouseholdMember, error)
+	SpendFromHouseholdTx(ctx context.Context, arg SpendFromHouseholdTxParams) (HouseholdSpendTxResult, error)
+	VoteHouseholdSpendTx(ctx context.Context, arg VoteHouseholdSpendTxParams) (HouseholdSpendTxResult, error)
+	CreateExpenseTx(ctx context.Context, arg CreateExpenseTxParams) (CreateExpenseTxResult, error)
+	SettleExpenseShareTx(ctx context.Context, arg SettleExpenseShareTxParams) (SettleExpenseShareTxResult, error)
+	CreateMoneyRequestTx(ctx context.Context, arg CreateMoneyRequestParams) (MoneyRequest, error)
+	DecideMoneyRequestTx(ctx context.Context, arg DecideMoneyRequestTxParams) (DecideMoneyRequestTxResult, error)
+	ExpireMoneyRequestTx(ctx context.Context, id int64) (ExpireMoneyRequestTxResult, error)
 }
 
 // store provides all the functions to execute db queries and transactions
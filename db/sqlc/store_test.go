@@ -3,8 +3,11 @@ package db
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +15,7 @@ func TestTransferMoneyTx(t *testing.T) {
 	store := NewStore(testDb)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountInCurrency(t, account1.Currency)
 
 	fmt.Println(">> before: ", account1.Balance, account2.Balance)
 
@@ -27,7 +30,7 @@ func TestTransferMoneyTx(t *testing.T) {
 			result, err := store.TransferMoneyTx(context.Background(), TransferMoneyTxParams{
 				FromAccountID: account1.ID,
 				ToAccountID:   account2.ID,
-				Amount:        amount,
+				Amount:        money.New(amount, account1.Currency),
 			})
 
 			errs <- err
@@ -117,14 +120,14 @@ func TestTransferMoneyTxDL(t *testing.T) {
 	store := NewStore(testDb)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountInCurrency(t, account1.Currency)
 
 	fmt.Println(">> before: ", account1.Balance, account2.Balance)
 
 	// run n concurent transfer transactions
 	n := 10
 	errs := make(chan error)
-	amount := int64(10_000)
+	amount := money.New(10_000, account1.Currency)
 
 	for i := range n {
 		fromAccountId := account1.ID
@@ -162,3 +165,41 @@ func TestTransferMoneyTxDL(t *testing.T) {
 	require.Equal(t, account2.Balance, updateAccount2.Balance)
 
 }
+
+func TestTransferMoneyTxCurrencyMismatch(t *testing.T) {
+	store := NewStore(testDb)
+
+	account1 := createRandomAccountInCurrency(t, util.USD)
+	account2 := createRandomAccountInCurrency(t, util.INR)
+
+	_, err := store.TransferMoneyTx(context.Background(), TransferMoneyTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        money.New(10, util.USD),
+	})
+	require.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	// the transaction was rolled back
+	gotAccount1, err := testQueries.GetAccountById(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, gotAccount1.Balance)
+}
+
+func TestAddAccountBalanceTxOverflow(t *testing.T) {
+	store := NewStore(testDb)
+
+	account := createRandomAccount(t)
+
+	account, err := store.AddAccountBalanceTx(context.Background(), AddAccountBalanceTxParams{
+		ID:     account.ID,
+		Amount: money.New(math.MaxInt64-account.Balance, account.Currency),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MaxInt64), account.Balance)
+
+	_, err = store.AddAccountBalanceTx(context.Background(), AddAccountBalanceTxParams{
+		ID:     account.ID,
+		Amount: money.New(1, account.Currency),
+	})
+	require.ErrorIs(t, err, money.ErrOverflow)
+}
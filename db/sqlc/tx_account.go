@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AnkitNayan83/houseBank/accountnumber"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Account types, an owner may hold any number of each in a currency, within their account limit
+const (
+	AccountTypeChecking = "checking"
+	AccountTypeSavings  = "savings"
+	// AccountTypeHousehold is the pot of a household, only created along with it
+	AccountTypeHousehold = "household"
+)
+
+var ErrAccountLimitReached = errors.New("account limit reached")
+
+type depositEvent struct {
+	Account accountEvent `json:"account"`
+	Amount  int64        `json:"amount"`
+}
+
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	// MaxAccounts is how many accounts the owner may hold, including the new one
+	MaxAccounts int64
+}
+
+// CreateAccountTx creates the account, with its owner as primary holder, along with its account.created webhook event,
+// unless the owner already holds MaxAccounts accounts
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		account, err = createHeldAccount(ctx, q, arg)
+		return err
+	})
+
+	return account, err
+}
+
+// createHeldAccount creates the account within the transaction of q, for the transactions which create one as one of their steps
+func createHeldAccount(ctx context.Context, q *Queries, arg CreateAccountTxParams) (Account, error) {
+	// locking the owner makes concurrent creations wait for each other's count
+	if _, err := q.GetUserByUsernameForUpdate(ctx, arg.Owner); err != nil {
+		return Account{}, err
+	}
+
+	count, err := q.CountAccountsByOwner(ctx, arg.Owner)
+	if err != nil {
+		return Account{}, err
+	}
+
+	if count >= arg.MaxAccounts {
+		return Account{}, fmt.Errorf("%w: %s already holds %d accounts", ErrAccountLimitReached, arg.Owner, count)
+	}
+
+	if arg.Number == "" {
+		arg.Number, err = accountnumber.New(accountnumber.HeadOfficeBranch)
+		if err != nil {
+			return Account{}, err
+		}
+	}
+
+	account, err := q.CreateAccount(ctx, arg.CreateAccountParams)
+	if err != nil {
+		return account, err
+	}
+
+	_, err = q.CreateAccountHolder(ctx, CreateAccountHolderParams{
+		AccountID:  account.ID,
+		Username:   account.Owner,
+		Role:       AccountHolderPrimary,
+		AcceptedAt: pgtype.Timestamptz{Time: account.CreatedAt, Valid: true},
+	})
+	if err != nil {
+		return account, err
+	}
+
+	return account, writeWebhookEvent(ctx, q, account.Owner, WebhookEventAccountCreated, newAccountEvent(account))
+}
+
+type AddAccountBalanceTxParams struct {
+	ID     int64       `json:"id"`
+	Amount money.Money `json:"amount"`
+}
+
+// AddAccountBalanceTx deposits money on the account along with its deposit.created webhook event,
+// the amount must be in the currency of the account
+func (store *SQLStore) AddAccountBalanceTx(ctx context.Context, arg AddAccountBalanceTxParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		account, err = addAccountMoney(ctx, q, arg.ID, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		return writeWebhookEvent(ctx, q, account.Owner, WebhookEventDepositCreated, depositEvent{
+			Account: newAccountEvent(account),
+			Amount:  arg.Amount.Amount,
+		})
+	})
+
+	return account, err
+}
+
+// DeleteAccountTx deletes the account along with its account.deleted webhook event
+func (store *SQLStore) DeleteAccountTx(ctx context.Context, id int64) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteAccount(ctx, id); err != nil {
+			return err
+		}
+
+		return writeWebhookEvent(ctx, q, account.Owner, WebhookEventAccountDeleted, newAccountEvent(account))
+	})
+}
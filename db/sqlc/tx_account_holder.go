@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Roles of an account holder, every account has exactly one primary holder: its owner
+const (
+	AccountHolderPrimary  = "primary"
+	AccountHolderJoint    = "joint"
+	AccountHolderViewOnly = "view_only"
+)
+
+// AccountAccess is what a holder wants to do with an account
+type AccountAccess int
+
+const (
+	AccountAccessView     AccountAccess = iota // read the account and its transfers
+	AccountAccessTransact                      // move money in and out of the account
+	AccountAccessManage                        // invite and remove holders, delete the account
+)
+
+// RoleAllows reports whether a holder with the role may access the account
+func RoleAllows(role string, access AccountAccess) bool {
+	switch role {
+	case AccountHolderPrimary:
+		return true
+	case AccountHolderJoint:
+		return access <= AccountAccessTransact
+	case AccountHolderViewOnly:
+		return access == AccountAccessView
+	default:
+		return false
+	}
+}
+
+// Allows reports whether the user the account was loaded for holds it, has accepted it and may access it
+func (row GetAccountForHolderRow) Allows(access AccountAccess) bool {
+	// money only leaves a pot through the spend rule of its household, whatever the role of the holder
+	if row.Account.Type == AccountTypeHousehold && access != AccountAccessView {
+		return false
+	}
+
+	return row.Role.Valid && row.AcceptedAt.Valid && RoleAllows(row.Role.String, access)
+}
+
+// Actions of the audit trail
+const (
+	AuditAccountHolderInvited  = "account_holder.invited"
+	AuditAccountHolderAccepted = "account_holder.accepted"
+	AuditAccountHolderRemoved  = "account_holder.removed"
+)
+
+var (
+	ErrInvitationNotFound = errors.New("no pending invitation")
+	ErrPrimaryHolder      = errors.New("the primary holder cannot be invited or removed")
+	ErrGuardedChild       = errors.New("a child under guardianship cannot share accounts")
+)
+
+// writeAuditEvent writes the event in the transaction of the action it records
+func writeAuditEvent(ctx context.Context, q *Queries, actor string, action string, accountID int64, subject string, details any) error {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("cannot marshal audit event: %w", err)
+	}
+
+	_, err = q.CreateAuditEvent(ctx, CreateAuditEventParams{
+		Actor:     actor,
+		Action:    action,
+		AccountID: pgtype.Int8{Int64: accountID, Valid: true},
+		Subject:   pgtype.Text{String: subject, Valid: subject != ""},
+		Details:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write audit event: %w", err)
+	}
+
+	return nil
+}
+
+// refuseGuardedChild keeps a child under guardianship from sharing accounts, through which money could leave the controls of their guardians
+func refuseGuardedChild(ctx context.Context, q *Queries, username string) error {
+	guardians, err := q.ListGuardians(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if len(guardians) > 0 {
+		return fmt.Errorf("%w: %s", ErrGuardedChild, username)
+	}
+
+	return nil
+}
+
+type holderEvent struct {
+	Role string `json:"role"`
+}
+
+type InviteAccountHolderTxParams struct {
+	AccountID int64
+	Username  string
+	Role      string
+	InvitedBy string
+}
+
+// InviteAccountHolderTx creates a pending holder of the account, who has no access until they accept.
+// Neither the inviter nor the invited user may be a child under guardianship.
+func (store *SQLStore) InviteAccountHolderTx(ctx context.Context, arg InviteAccountHolderTxParams) (AccountHolder, error) {
+	var holder AccountHolder
+
+	if arg.Role == AccountHolderPrimary {
+		return holder, ErrPrimaryHolder
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, username := range []string{arg.InvitedBy, arg.Username} {
+			if err := refuseGuardedChild(ctx, q, username); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		holder, err = q.CreateAccountHolder(ctx, CreateAccountHolderParams{
+			AccountID: arg.AccountID,
+			Username:  arg.Username,
+			Role:      arg.Role,
+			InvitedBy: pgtype.Text{String: arg.InvitedBy, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeAuditEvent(ctx, q, arg.InvitedBy, AuditAccountHolderInvited, arg.AccountID, arg.Username, holderEvent{Role: holder.Role})
+	})
+
+	return holder, err
+}
+
+type AcceptAccountHolderTxParams struct {
+	AccountID int64
+	Username  string
+}
+
+// AcceptAccountHolderTx accepts the pending invitation of the user to hold the account,
+// unless the user has become a child under guardianship since they were invited
+func (store *SQLStore) AcceptAccountHolderTx(ctx context.Context, arg AcceptAccountHolderTxParams) (AccountHolder, error) {
+	var holder AccountHolder
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := refuseGuardedChild(ctx, q, arg.Username); err != nil {
+			return err
+		}
+
+		var err error
+		holder, err = q.AcceptAccountHolder(ctx, AcceptAccountHolderParams(arg))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: account: [%d]", ErrInvitationNotFound, arg.AccountID)
+			}
+			return err
+		}
+
+		return writeAuditEvent(ctx, q, arg.Username, AuditAccountHolderAccepted, arg.AccountID, arg.Username, holderEvent{Role: holder.Role})
+	})
+
+	return holder, err
+}
+
+type RemoveAccountHolderTxParams struct {
+	AccountID int64
+	Username  string
+	RemovedBy string
+}
+
+// RemoveAccountHolderTx removes a holder, or withdraws a pending invitation, of the account.
+// The primary holder cannot be removed, the account is deleted instead.
+func (store *SQLStore) RemoveAccountHolderTx(ctx context.Context, arg RemoveAccountHolderTxParams) (AccountHolder, error) {
+	var holder AccountHolder
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		holder, err = q.DeleteAccountHolder(ctx, DeleteAccountHolderParams{
+			AccountID: arg.AccountID,
+			Username:  arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		if holder.Role == AccountHolderPrimary {
+			return ErrPrimaryHolder
+		}
+
+		return writeAuditEvent(ctx, q, arg.RemovedBy, AuditAccountHolderRemoved, arg.AccountID, arg.Username, holderEvent{Role: holder.Role})
+	})
+
+	return holder, err
+}
@@ -4,7 +4,7 @@ import "context"
 
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterCreateUser func(user User) error // callback func to send task to queue
+	AfterCreateUser func(user User) ([]CreateOutboxMessageParams, error) // callback func returning the tasks to write to the outbox
 }
 
 type CreateUserTxResult struct {
@@ -14,14 +14,17 @@ type CreateUserTxResult struct {
 func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
 	var result CreateUserTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTxWithOutbox(ctx, func(q *Queries) ([]CreateOutboxMessageParams, error) {
 		var err error
 		user, err := q.CreateUser(ctx, arg.CreateUserParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		result.User = user
 
+		if arg.AfterCreateUser == nil {
+			return nil, nil
+		}
 		return arg.AfterCreateUser(user)
 	})
 
@@ -0,0 +1,265 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Ways of splitting an expense between its participants
+const (
+	ExpenseSplitEqual      = "equal"
+	ExpenseSplitPercentage = "percentage"
+	ExpenseSplitExact      = "exact"
+)
+
+// Kinds of the notifications about the shares of an expense
+const (
+	NotificationExpenseShareRequested = "expense_share.requested"
+	NotificationExpenseShareSettled   = "expense_share.settled"
+)
+
+var (
+	ErrInvalidSplit   = errors.New("invalid split")
+	ErrAlreadySettled = errors.New("share already settled")
+)
+
+// ExpenseParticipant is a user sharing an expense, Percentage is only read by the percentage split
+// and Amount by the exact one
+type ExpenseParticipant struct {
+	Username   string
+	Percentage int64
+	Amount     int64
+}
+
+// splitExpense works out what each participant owes of the amount, in the order of the participants.
+// Equal and percentage splits hand the minor units left over by rounding to the first participants.
+func splitExpense(amount money.Money, split string, paidBy string, participants []ExpenseParticipant) ([]money.Money, error) {
+	seen := make(map[string]bool, len(participants))
+	owing := false
+	for _, participant := range participants {
+		if seen[participant.Username] {
+			return nil, fmt.Errorf("%w: %s takes part twice", ErrInvalidSplit, participant.Username)
+		}
+		seen[participant.Username] = true
+		owing = owing || participant.Username != paidBy
+	}
+
+	if !owing {
+		return nil, fmt.Errorf("%w: nobody but %s takes part", ErrInvalidSplit, paidBy)
+	}
+
+	switch split {
+	case ExpenseSplitEqual:
+		return amount.Split(len(participants))
+	case ExpenseSplitPercentage:
+		percentages := make([]int64, len(participants))
+		var total int64
+		for i, participant := range participants {
+			if participant.Percentage < 0 || participant.Percentage > 100 {
+				return nil, fmt.Errorf("%w: %d%% for %s", ErrInvalidSplit, participant.Percentage, participant.Username)
+			}
+			percentages[i] = participant.Percentage
+			total += participant.Percentage
+		}
+
+		if total != 100 {
+			return nil, fmt.Errorf("%w: percentages add up to %d%%", ErrInvalidSplit, total)
+		}
+
+		return amount.Allocate(percentages...)
+	case ExpenseSplitExact:
+		shares := make([]money.Money, len(participants))
+		total := money.New(0, amount.Currency)
+		for i, participant := range participants {
+			if participant.Amount < 0 {
+				return nil, fmt.Errorf("%w: negative amount for %s", ErrInvalidSplit, participant.Username)
+			}
+
+			var err error
+			shares[i] = money.New(participant.Amount, amount.Currency)
+			total, err = total.Add(shares[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if total.Amount != amount.Amount {
+			return nil, fmt.Errorf("%w: shares add up to %s, not %s", ErrInvalidSplit, total, amount)
+		}
+
+		return shares, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown split %q", ErrInvalidSplit, split)
+	}
+}
+
+type CreateExpenseTxParams struct {
+	PaidBy string
+	// AccountID is the account of the payer the shares are paid back into
+	AccountID    int64
+	Amount       money.Money
+	Description  string
+	Split        string
+	Participants []ExpenseParticipant
+}
+
+type CreateExpenseTxResult struct {
+	Expense Expense
+	Shares  []ExpenseShare
+}
+
+type expenseShareEvent struct {
+	ExpenseID   int64  `json:"expense_id"`
+	PaidBy      string `json:"paid_by"`
+	Username    string `json:"username"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description,omitempty"`
+}
+
+func newExpenseShareEvent(expense Expense, share ExpenseShare) expenseShareEvent {
+	return expenseShareEvent{
+		ExpenseID:   expense.ID,
+		PaidBy:      expense.PaidBy,
+		Username:    share.Username,
+		Amount:      share.Amount,
+		Currency:    expense.Currency,
+		Description: expense.Description,
+	}
+}
+
+// CreateExpenseTx records the expense with the share of each participant, and asks the others than the payer
+// to settle theirs. The share of the payer, and empty shares, are settled from the start.
+func (store *SQLStore) CreateExpenseTx(ctx context.Context, arg CreateExpenseTxParams) (CreateExpenseTxResult, error) {
+	var result CreateExpenseTxResult
+
+	amounts, err := splitExpense(arg.Amount, arg.Split, arg.PaidBy, arg.Participants)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Expense, err = q.CreateExpense(ctx, CreateExpenseParams{
+			PaidBy:      arg.PaidBy,
+			AccountID:   arg.AccountID,
+			Amount:      arg.Amount.Amount,
+			Currency:    arg.Amount.Currency,
+			Description: arg.Description,
+			Split:       arg.Split,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Shares = make([]ExpenseShare, 0, len(arg.Participants))
+		for i, participant := range arg.Participants {
+			owed := participant.Username != arg.PaidBy && amounts[i].IsPositive()
+
+			var settledAt pgtype.Timestamptz
+			if !owed {
+				settledAt = pgtype.Timestamptz{Time: result.Expense.CreatedAt, Valid: true}
+			}
+
+			share, err := q.CreateExpenseShare(ctx, CreateExpenseShareParams{
+				ExpenseID: result.Expense.ID,
+				Username:  participant.Username,
+				Amount:    amounts[i].Amount,
+				SettledAt: settledAt,
+			})
+			if err != nil {
+				return err
+			}
+			result.Shares = append(result.Shares, share)
+
+			if !owed {
+				continue
+			}
+
+			err = writeNotification(ctx, q, share.Username, NotificationExpenseShareRequested, newExpenseShareEvent(result.Expense, share))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type SettleExpenseShareTxParams struct {
+	ExpenseID     int64
+	Username      string
+	FromAccountID int64
+	Now           time.Time
+}
+
+type SettleExpenseShareTxResult struct {
+	Share    ExpenseShare
+	Transfer TransfeMoneyTxResult
+}
+
+// SettleExpenseShareTx pays the share of the user back to the payer of the expense, from an account of the user.
+// A user under guardianship only settles within the controls of their guardians, there is no waiting for their approval.
+func (store *SQLStore) SettleExpenseShareTx(ctx context.Context, arg SettleExpenseShareTxParams) (SettleExpenseShareTxResult, error) {
+	var result SettleExpenseShareTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		share, err := q.GetExpenseShareForUpdate(ctx, GetExpenseShareForUpdateParams{
+			ExpenseID: arg.ExpenseID,
+			Username:  arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		if share.SettledAt.Valid {
+			return ErrAlreadySettled
+		}
+
+		expense, err := q.GetExpense(ctx, arg.ExpenseID)
+		if err != nil {
+			return err
+		}
+
+		payment := TransferMoneyTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   expense.AccountID,
+			Amount:        money.New(share.Amount, expense.Currency),
+		}
+
+		err = requireChildControls(ctx, q, ChildTransferTxParams{TransferMoneyTxParams: payment, Child: arg.Username, Now: arg.Now})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = transferAvailableMoney(ctx, q, payment)
+		if err != nil {
+			return err
+		}
+
+		result.Share, err = q.SettleExpenseShare(ctx, SettleExpenseShareParams{
+			ExpenseID:  share.ExpenseID,
+			Username:   share.Username,
+			TransferID: pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeNotification(ctx, q, expense.PaidBy, NotificationExpenseShareSettled, newExpenseShareEvent(expense, result.Share))
+	})
+
+	if err == nil {
+		metrics.ObserveTransfer(result.Transfer.FromAccount.Currency, result.Share.Amount)
+	}
+
+	return result, err
+}
@@ -0,0 +1,535 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a child's transfer waiting for a guardian
+const (
+	ChildTransferPending  = "pending"
+	ChildTransferApproved = "approved"
+	ChildTransferDeclined = "declined"
+)
+
+// How often an allowance is paid
+const (
+	AllowanceWeekly  = "weekly"
+	AllowanceMonthly = "monthly"
+)
+
+var (
+	ErrNotGuardian    = errors.New("not a guardian of the child")
+	ErrAlreadyDecided = errors.New("transfer was already decided")
+	ErrChildNoAccess  = errors.New("child can no longer move money out of the account")
+	ErrChildControls  = errors.New("transfer is not within the controls set by the guardians")
+)
+
+type CreateChildTxParams struct {
+	CreateUserParams
+	Guardian string
+}
+
+// CreateChildTx creates the user of a child along with their guardianship
+func (store *SQLStore) CreateChildTx(ctx context.Context, arg CreateChildTxParams) (User, error) {
+	var child User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		child, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateGuardianship(ctx, CreateGuardianshipParams{
+			Guardian: arg.Guardian,
+			Child:    child.Username,
+		})
+		return err
+	})
+
+	return child, err
+}
+
+type ChildTransferTxParams struct {
+	TransferMoneyTxParams
+	Child string
+	// Now is when the transfer is made, the daily limit counts what was sent since the start of its utc day
+	Now time.Time
+}
+
+// ChildTransferTxResult holds one of the three outcomes of a child's transfer
+type ChildTransferTxResult struct {
+	// Transfer is the transfer made right away, as it was within the controls
+	Transfer *TransfeMoneyTxResult
+	// Approval is the transfer waiting for a guardian
+	Approval *ChildTransferApproval
+	// Blocked is why the transfer was refused
+	Blocked string
+}
+
+type childTransferEvent struct {
+	Child             string `json:"child"`
+	FromAccountNumber string `json:"from_account_number"`
+	ToAccountNumber   string `json:"to_account_number"`
+	Amount            int64  `json:"amount"`
+	Currency          string `json:"currency"`
+	Reason            string `json:"reason,omitempty"`
+	ApprovalID        int64  `json:"approval_id,omitempty"`
+	// set once a guardian decided on the transfer
+	Status    string `json:"status,omitempty"`
+	DecidedBy string `json:"decided_by,omitempty"`
+}
+
+func newChildTransferApprovalEvent(ctx context.Context, q *Queries, approval ChildTransferApproval) (childTransferEvent, error) {
+	numbers, err := accountNumbers(ctx, q, approval.FromAccountID, approval.ToAccountID)
+	if err != nil {
+		return childTransferEvent{}, err
+	}
+
+	return childTransferEvent{
+		Child:             approval.Child,
+		FromAccountNumber: numbers[approval.FromAccountID],
+		ToAccountNumber:   numbers[approval.ToAccountID],
+		Amount:            approval.Amount,
+		Currency:          approval.Currency,
+		Reason:            approval.Reason,
+		ApprovalID:        approval.ID,
+		Status:            approval.Status,
+		DecidedBy:         approval.DecidedBy.String,
+	}, nil
+}
+
+type allowanceEvent struct {
+	AllowanceID       int64     `json:"allowance_id"`
+	Guardian          string    `json:"guardian"`
+	Child             string    `json:"child"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	Currency          string    `json:"currency"`
+	Period            string    `json:"period"`
+	NextPaymentAt     time.Time `json:"next_payment_at"`
+}
+
+func newAllowanceEvent(ctx context.Context, q *Queries, allowance Allowance) (allowanceEvent, error) {
+	numbers, err := accountNumbers(ctx, q, allowance.FromAccountID, allowance.ToAccountID)
+	if err != nil {
+		return allowanceEvent{}, err
+	}
+
+	return allowanceEvent{
+		AllowanceID:       allowance.ID,
+		Guardian:          allowance.Guardian,
+		Child:             allowance.Child,
+		FromAccountNumber: numbers[allowance.FromAccountID],
+		ToAccountNumber:   numbers[allowance.ToAccountID],
+		Amount:            allowance.Amount,
+		Currency:          allowance.Currency,
+		Period:            allowance.Period,
+		NextPaymentAt:     allowance.NextPaymentAt,
+	}, nil
+}
+
+// ChildTransferTx makes a transfer of a user under guardianship within the controls their guardians set.
+// Transfers over a limit are blocked, transfers to payees off the allowlist or without limits to check wait for approval,
+// and the guardians are notified of both. Transfers of users without a guardian, and between accounts the child holds alone, are made right away.
+func (store *SQLStore) ChildTransferTx(ctx context.Context, arg ChildTransferTxParams) (ChildTransferTxResult, error) {
+	var result ChildTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = ChildTransferTxResult{}
+
+		var err error
+		result.Blocked, result.Approval, err = holdChildTransfer(ctx, q, arg, pgtype.Int8{})
+		if err != nil || result.Blocked != "" || result.Approval != nil {
+			return err
+		}
+
+		transfer, err := transferMoney(ctx, q, arg.TransferMoneyTxParams)
+		result.Transfer = &transfer
+		return err
+	})
+
+	if err == nil && result.Transfer != nil {
+		metrics.ObserveTransfer(arg.Amount.Currency, arg.Amount.Amount)
+	}
+
+	return result, err
+}
+
+// childControls is how the controls the guardians of a user set apply to a transfer the user makes
+type childControls struct {
+	Guardians []string
+	// Reason is why the transfer is blocked, or why it needs approval when Blocked is false.
+	// It is empty when the transfer may be made right away.
+	Reason  string
+	Blocked bool
+}
+
+// applyChildControls locks the user and checks the transfer they make against the controls their guardians set.
+// Every transaction moving money at the request of a user goes through it, so none of them escapes the controls.
+// Users without a guardian, and transfers between accounts the child owns and nobody else holds, are not held to any.
+func applyChildControls(ctx context.Context, q *Queries, arg ChildTransferTxParams) (childControls, error) {
+	var controls childControls
+
+	// locking the child makes concurrent transfers wait for each other's daily total
+	if _, err := q.GetUserByUsernameForUpdate(ctx, arg.Child); err != nil {
+		return controls, err
+	}
+
+	guardians, err := q.ListGuardians(ctx, arg.Child)
+	if err != nil || len(guardians) == 0 {
+		return controls, err
+	}
+	controls.Guardians = guardians
+
+	// a joint holder of either account could take the money out of the controls
+	heldAlone, err := q.CountAccountsHeldAlone(ctx, CountAccountsHeldAloneParams{
+		Ids:      []int64{arg.FromAccountID, arg.ToAccountID},
+		Username: arg.Child,
+	})
+	if err != nil || heldAlone == 2 {
+		return controls, err
+	}
+
+	controls.Reason, controls.Blocked, err = checkChildControls(ctx, q, arg)
+	return controls, err
+}
+
+// requireChildControls refuses the transfer of a transaction which cannot wait for a guardian's approval,
+// unless the controls of the child's guardians let it be made right away
+func requireChildControls(ctx context.Context, q *Queries, arg ChildTransferTxParams) error {
+	controls, err := applyChildControls(ctx, q, arg)
+	if err != nil {
+		return err
+	}
+
+	if controls.Reason != "" {
+		return fmt.Errorf("%w: %s", ErrChildControls, controls.Reason)
+	}
+
+	return nil
+}
+
+// holdChildTransfer blocks the transfer of the child, or has it wait for a guardian to approve it, when the controls require it,
+// and tells their guardians. The transfer may be made right away when it is neither blocked nor waiting.
+// A transfer paying a money request is linked to it, so the guardian's decision pays or declines the request.
+func holdChildTransfer(ctx context.Context, q *Queries, arg ChildTransferTxParams, moneyRequestID pgtype.Int8) (blocked string, approval *ChildTransferApproval, err error) {
+	controls, err := applyChildControls(ctx, q, arg)
+	if err != nil || controls.Reason == "" {
+		return "", nil, err
+	}
+
+	numbers, err := accountNumbers(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	event := childTransferEvent{
+		Child:             arg.Child,
+		FromAccountNumber: numbers[arg.FromAccountID],
+		ToAccountNumber:   numbers[arg.ToAccountID],
+		Amount:            arg.Amount.Amount,
+		Currency:          arg.Amount.Currency,
+		Reason:            controls.Reason,
+	}
+
+	if controls.Blocked {
+		return controls.Reason, nil, notifyAll(ctx, q, controls.Guardians, NotificationChildTransferBlocked, event)
+	}
+
+	pending, err := q.CreateChildTransferApproval(ctx, CreateChildTransferApprovalParams{
+		Child:          arg.Child,
+		FromAccountID:  arg.FromAccountID,
+		ToAccountID:    arg.ToAccountID,
+		Amount:         arg.Amount.Amount,
+		Currency:       arg.Amount.Currency,
+		Reason:         controls.Reason,
+		MoneyRequestID: moneyRequestID,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	event.ApprovalID = pending.ID
+	return "", &pending, notifyAll(ctx, q, controls.Guardians, NotificationChildTransferApprovalRequested, event)
+}
+
+// checkChildControls returns why the transfer is blocked, or why it needs approval when blocked is false.
+// An empty reason means the transfer is within the controls.
+func checkChildControls(ctx context.Context, q *Queries, arg ChildTransferTxParams) (reason string, blocked bool, err error) {
+	controls, err := q.GetChildControls(ctx, arg.Child)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "no spending limits are set", false, nil
+		}
+		return "", false, err
+	}
+
+	if controls.Currency != arg.Amount.Currency {
+		return fmt.Sprintf("spending limits are only set in %s", controls.Currency), false, nil
+	}
+
+	if arg.Amount.Amount > controls.PerTransactionLimit {
+		return fmt.Sprintf("over the per-transaction limit of %d", controls.PerTransactionLimit), true, nil
+	}
+
+	now := arg.Now.UTC()
+	spent, err := q.SumChildSpending(ctx, SumChildSpendingParams{
+		Child:    arg.Child,
+		Currency: arg.Amount.Currency,
+		Since:    time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if spent > controls.DailyLimit-arg.Amount.Amount {
+		return fmt.Sprintf("over the daily limit of %d, %d was already sent today", controls.DailyLimit, spent), true, nil
+	}
+
+	if _, err := q.GetChildPayee(ctx, GetChildPayeeParams{Child: arg.Child, AccountID: arg.ToAccountID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "payee is not on the allowlist", false, nil
+		}
+		return "", false, err
+	}
+
+	return "", false, nil
+}
+
+type DecideChildTransferTxParams struct {
+	ID       int64
+	Guardian string
+	Approve  bool
+}
+
+type DecideChildTransferTxResult struct {
+	Approval ChildTransferApproval
+	// Transfer is set when the transfer was approved
+	Transfer *TransfeMoneyTxResult
+	// MoneyRequest is set when the transfer pays a request, which is paid or declined along with it
+	MoneyRequest *MoneyRequest
+}
+
+// DecideChildTransferTx lets a guardian of the child approve a pending transfer, which is then made, or decline it.
+// A transfer is only approved while the child may still move money out of the paying account. The child is notified either way,
+// and the requester too when the transfer pays a money request.
+func (store *SQLStore) DecideChildTransferTx(ctx context.Context, arg DecideChildTransferTxParams) (DecideChildTransferTxResult, error) {
+	var result DecideChildTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = DecideChildTransferTxResult{}
+
+		approval, err := q.GetChildTransferApprovalForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetGuardianship(ctx, GetGuardianshipParams{Guardian: arg.Guardian, Child: approval.Child})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrNotGuardian, approval.Child)
+			}
+			return err
+		}
+
+		if approval.Status != ChildTransferPending {
+			return fmt.Errorf("%w: %s", ErrAlreadyDecided, approval.Status)
+		}
+
+		decision := DecideChildTransferApprovalParams{
+			ID:        approval.ID,
+			Status:    ChildTransferDeclined,
+			DecidedBy: pgtype.Text{String: arg.Guardian, Valid: true},
+		}
+		kind := NotificationChildTransferDeclined
+
+		var request *MoneyRequest
+		if approval.MoneyRequestID.Valid {
+			linked, err := q.GetMoneyRequestForUpdate(ctx, approval.MoneyRequestID.Int64)
+			if err != nil {
+				return err
+			}
+			request = &linked
+		}
+
+		if arg.Approve {
+			// the requester may have been paid some other way, or the request expired, while it waited
+			if request != nil {
+				if request.Status != MoneyRequestPending {
+					return fmt.Errorf("%w: money request %s", ErrAlreadyDecided, request.Status)
+				}
+				if !time.Now().Before(request.ExpiresAt) {
+					return fmt.Errorf("%w at %s", ErrMoneyRequestExpired, request.ExpiresAt.Format(time.RFC3339))
+				}
+			}
+
+			// the child may have been removed from the account, or lost the role to transact on it, since they asked
+			canTransact, err := canTransact(ctx, q, approval.FromAccountID, approval.Child)
+			if err != nil {
+				return err
+			}
+			if !canTransact {
+				return fmt.Errorf("%w: [%d]", ErrChildNoAccess, approval.FromAccountID)
+			}
+
+			transfer, err := transferAvailableMoney(ctx, q, TransferMoneyTxParams{
+				FromAccountID: approval.FromAccountID,
+				ToAccountID:   approval.ToAccountID,
+				Amount:        approval.Money(),
+			})
+			if err != nil {
+				return err
+			}
+			result.Transfer = &transfer
+
+			decision.Status = ChildTransferApproved
+			decision.TransferID = pgtype.Int8{Int64: transfer.Transfer.ID, Valid: true}
+			kind = NotificationChildTransferApproved
+		}
+
+		result.Approval, err = q.DecideChildTransferApproval(ctx, decision)
+		if err != nil {
+			return err
+		}
+
+		if request != nil && request.Status == MoneyRequestPending {
+			decided, err := closeMoneyRequest(ctx, q, *request, result.Transfer)
+			if err != nil {
+				return err
+			}
+			result.MoneyRequest = &decided
+		}
+
+		event, err := newChildTransferApprovalEvent(ctx, q, result.Approval)
+		if err != nil {
+			return err
+		}
+
+		return writeNotification(ctx, q, approval.Child, kind, event)
+	})
+
+	if err == nil && result.Transfer != nil {
+		metrics.ObserveTransfer(result.Approval.Currency, result.Approval.Amount)
+	}
+
+	return result, err
+}
+
+// canTransact reports whether the user holds the account, and may move money out of it, right now
+func canTransact(ctx context.Context, q *Queries, accountID int64, username string) (bool, error) {
+	account, err := q.GetAccountById(ctx, accountID)
+	if err != nil {
+		return false, err
+	}
+
+	// money only leaves a pot through the spend rule of its household
+	if account.Type == AccountTypeHousehold {
+		return false, nil
+	}
+
+	holder, err := q.GetAccountHolder(ctx, GetAccountHolderParams{AccountID: accountID, Username: username})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return holder.AcceptedAt.Valid && RoleAllows(holder.Role, AccountAccessTransact), nil
+}
+
+type PayAllowanceTxResult struct {
+	Allowance Allowance
+	// Paid is false when the allowance was not due anymore, or the paying account could not cover it
+	Paid bool
+}
+
+// nextAllowancePayment is when the allowance is due after the payment due at last.
+// Monthly allowances are due on their anchor day, or on the last day of months too short for it.
+func nextAllowancePayment(last time.Time, period string, anchorDay int16) time.Time {
+	if period != AllowanceMonthly {
+		return last.AddDate(0, 0, 7)
+	}
+
+	last = last.UTC()
+	// the first of the next month never overflows into the month after it, as the 31st can
+	first := time.Date(last.Year(), last.Month()+1, 1, last.Hour(), last.Minute(), last.Second(), last.Nanosecond(), time.UTC)
+	days := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(int(anchorDay), days)-1)
+}
+
+// PayAllowanceTx pays the allowance if it is due and moves it to its next payment, notifying the child.
+// When the paying account cannot cover it, or the guardian can no longer transact on it,
+// the payment is skipped, and the guardian notified, until the next one.
+func (store *SQLStore) PayAllowanceTx(ctx context.Context, id int64) (PayAllowanceTxResult, error) {
+	var result PayAllowanceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = PayAllowanceTxResult{}
+
+		allowance, err := q.GetDueAllowanceForUpdate(ctx, id)
+		if err != nil {
+			// another payer holds it, or paid it since it was listed
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		result.Allowance = allowance
+
+		// the guardian may have left, or been downgraded on, the paying account since setting it up
+		result.Paid, err = canTransact(ctx, q, allowance.FromAccountID, allowance.Guardian)
+		if err != nil {
+			return err
+		}
+
+		if result.Paid {
+			_, err = transferAvailableMoney(ctx, q, TransferMoneyTxParams{
+				FromAccountID: allowance.FromAccountID,
+				ToAccountID:   allowance.ToAccountID,
+				Amount:        allowance.Money(),
+			})
+
+			if err != nil && !errors.Is(err, ErrInsufficientBalance) {
+				return err
+			}
+			result.Paid = err == nil
+		}
+
+		event, err := newAllowanceEvent(ctx, q, allowance)
+		if err != nil {
+			return err
+		}
+
+		if result.Paid {
+			err = writeNotification(ctx, q, allowance.Child, NotificationAllowancePaid, event)
+		} else {
+			err = writeNotification(ctx, q, allowance.Guardian, NotificationAllowanceFailed, event)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Allowance, err = q.UpdateAllowanceNextPayment(ctx, UpdateAllowanceNextPaymentParams{
+			ID:            allowance.ID,
+			NextPaymentAt: nextAllowancePayment(allowance.NextPaymentAt, allowance.Period, allowance.AnchorDay),
+		})
+		return err
+	})
+
+	if err == nil && result.Paid {
+		metrics.ObserveTransfer(result.Allowance.Currency, result.Allowance.Amount)
+	}
+
+	return result, err
+}
@@ -0,0 +1,475 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/money"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Rules for spending from the pot of a household
+const (
+	// HouseholdSpendAnyMember lets any member spend any amount
+	HouseholdSpendAnyMember = "any_member"
+	// HouseholdSpendMajority needs more than half of the members to approve spends above the approval threshold
+	HouseholdSpendMajority = "majority"
+)
+
+// Statuses of a spend from the pot of a household
+const (
+	HouseholdSpendPending  = "pending"
+	HouseholdSpendApproved = "approved"
+	HouseholdSpendDeclined = "declined"
+)
+
+// Kinds of the notifications about spends from the pot of a household
+const (
+	NotificationHouseholdSpendApprovalRequested = "household_spend.approval_requested"
+	NotificationHouseholdSpendApproved          = "household_spend.approved"
+	NotificationHouseholdSpendDeclined          = "household_spend.declined"
+)
+
+var (
+	ErrNotHouseholdMember = errors.New("not a member of the household")
+	ErrHouseholdCreator   = errors.New("the creator of a household cannot be removed")
+)
+
+// NeedsApproval reports whether spending the amount from the pot needs the approval of a majority of the members
+func (household Household) NeedsApproval(amount int64) bool {
+	return household.SpendRule == HouseholdSpendMajority && amount > household.ApprovalThreshold
+}
+
+type CreateHouseholdTxParams struct {
+	Name              string
+	Currency          string
+	CreatedBy         string
+	SpendRule         string
+	ApprovalThreshold int64
+	// MaxAccounts is how many accounts the creator may hold, including the pot
+	MaxAccounts int64
+}
+
+type CreateHouseholdTxResult struct {
+	Household Household
+	Account   Account
+}
+
+// CreateHouseholdTx creates the household along with its pot, an account held by the creator as its first member
+func (store *SQLStore) CreateHouseholdTx(ctx context.Context, arg CreateHouseholdTxParams) (CreateHouseholdTxResult, error) {
+	var result CreateHouseholdTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Account, err = createHeldAccount(ctx, q, CreateAccountTxParams{
+			CreateAccountParams: CreateAccountParams{
+				Owner:    arg.CreatedBy,
+				Currency: arg.Currency,
+				Name:     arg.Name,
+				Type:     AccountTypeHousehold,
+			},
+			MaxAccounts: arg.MaxAccounts,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Household, err = q.CreateHousehold(ctx, CreateHouseholdParams{
+			Name:              arg.Name,
+			CreatedBy:         arg.CreatedBy,
+			AccountID:         result.Account.ID,
+			SpendRule:         arg.SpendRule,
+			ApprovalThreshold: arg.ApprovalThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateHouseholdMember(ctx, CreateHouseholdMemberParams{
+			HouseholdID: result.Household.ID,
+			Username:    arg.CreatedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type AddHouseholdMemberTxParams struct {
+	Household Household
+	Username  string
+}
+
+// AddHouseholdMemberTx adds the user to the household, who then sees the pot as a view only holder
+func (store *SQLStore) AddHouseholdMemberTx(ctx context.Context, arg AddHouseholdMemberTxParams) (HouseholdMember, error) {
+	var member HouseholdMember
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		member, err = q.CreateHouseholdMember(ctx, CreateHouseholdMemberParams{
+			HouseholdID: arg.Household.ID,
+			Username:    arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAccountHolder(ctx, CreateAccountHolderParams{
+			AccountID:  arg.Household.AccountID,
+			Username:   member.Username,
+			Role:       AccountHolderViewOnly,
+			InvitedBy:  pgtype.Text{String: arg.Household.CreatedBy, Valid: true},
+			AcceptedAt: pgtype.Timestamptz{Time: member.CreatedAt, Valid: true},
+		})
+		return err
+	})
+
+	return member, err
+}
+
+type RemoveHouseholdMemberTxParams struct {
+	Household Household
+	Username  string
+}
+
+// RemoveHouseholdMemberTx removes the user from the household along with their view of the pot.
+// The creator holds the pot and cannot be removed.
+func (store *SQLStore) RemoveHouseholdMemberTx(ctx context.Context, arg RemoveHouseholdMemberTxParams) (HouseholdMember, error) {
+	var member HouseholdMember
+
+	if arg.Username == arg.Household.CreatedBy {
+		return member, ErrHouseholdCreator
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		member, err = q.DeleteHouseholdMember(ctx, DeleteHouseholdMemberParams{
+			HouseholdID: arg.Household.ID,
+			Username:    arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		// what they voted on spends still waiting goes with them
+		err = q.DeleteHouseholdMemberPendingVotes(ctx, DeleteHouseholdMemberPendingVotesParams{
+			HouseholdID: arg.Household.ID,
+			Username:    member.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.DeleteAccountHolder(ctx, DeleteAccountHolderParams{
+			AccountID: arg.Household.AccountID,
+			Username:  member.Username,
+		})
+		return err
+	})
+
+	return member, err
+}
+
+type SpendFromHouseholdTxParams struct {
+	Household   Household
+	RequestedBy string
+	ToAccountID int64
+	Amount      money.Money
+	Note        string
+	Now         time.Time
+}
+
+// HouseholdSpendTxResult holds the spend, and the transfer out of the pot once it was approved
+type HouseholdSpendTxResult struct {
+	Spend    HouseholdSpend
+	Transfer *TransfeMoneyTxResult
+}
+
+type householdSpendEvent struct {
+	HouseholdID     int64  `json:"household_id"`
+	SpendID         int64  `json:"spend_id"`
+	RequestedBy     string `json:"requested_by"`
+	ToAccountNumber string `json:"to_account_number"`
+	Amount          int64  `json:"amount"`
+	Note            string `json:"note,omitempty"`
+}
+
+// SpendFromHouseholdTx spends from the pot of the household when its spend rule lets the member do it alone.
+// Otherwise the spend waits for the votes of the other members, the requester's counting as an approval.
+// A child may only request what the controls of their guardians let them send.
+func (store *SQLStore) SpendFromHouseholdTx(ctx context.Context, arg SpendFromHouseholdTxParams) (HouseholdSpendTxResult, error) {
+	var result HouseholdSpendTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = HouseholdSpendTxResult{}
+
+		if err := requireHouseholdMember(ctx, q, arg.Household.ID, arg.RequestedBy); err != nil {
+			return err
+		}
+
+		pot, err := q.GetAccountById(ctx, arg.Household.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if pot.Currency != arg.Amount.Currency {
+			return fmt.Errorf("%w: the pot holds %s, not %s", money.ErrCurrencyMismatch, pot.Currency, arg.Amount.Currency)
+		}
+
+		err = requireChildControls(ctx, q, ChildTransferTxParams{
+			TransferMoneyTxParams: TransferMoneyTxParams{
+				FromAccountID: arg.Household.AccountID,
+				ToAccountID:   arg.ToAccountID,
+				Amount:        arg.Amount,
+			},
+			Child: arg.RequestedBy,
+			Now:   arg.Now,
+		})
+		if err != nil {
+			return err
+		}
+
+		spend, err := q.CreateHouseholdSpend(ctx, CreateHouseholdSpendParams{
+			HouseholdID: arg.Household.ID,
+			RequestedBy: arg.RequestedBy,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount.Amount,
+			Note:        arg.Note,
+		})
+		if err != nil {
+			return err
+		}
+		result.Spend = spend
+
+		if !arg.Household.NeedsApproval(spend.Amount) {
+			return approveHouseholdSpend(ctx, q, arg.Household, arg.Now, &result)
+		}
+
+		_, err = q.CreateHouseholdSpendVote(ctx, CreateHouseholdSpendVoteParams{
+			SpendID:  spend.ID,
+			Username: arg.RequestedBy,
+			Approve:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		decided, err := settleHouseholdSpend(ctx, q, arg.Household, arg.Now, &result)
+		if err != nil || decided {
+			return err
+		}
+
+		members, err := q.ListHouseholdMembers(ctx, arg.Household.ID)
+		if err != nil {
+			return err
+		}
+
+		event, err := newHouseholdSpendEvent(ctx, q, spend)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if member.Username == arg.RequestedBy {
+				continue
+			}
+			err := writeNotification(ctx, q, member.Username, NotificationHouseholdSpendApprovalRequested, event)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err == nil && result.Transfer != nil {
+		metrics.ObserveTransfer(arg.Amount.Currency, arg.Amount.Amount)
+	}
+
+	return result, err
+}
+
+type VoteHouseholdSpendTxParams struct {
+	Household Household
+	SpendID   int64
+	Username  string
+	Approve   bool
+	Now       time.Time
+}
+
+// VoteHouseholdSpendTx records the vote of a member on a pending spend. The spend is made once more than half
+// of the members approved it, and declined once that cannot happen anymore, or when it is no longer within the
+// controls of the guardians of the child who requested it.
+func (store *SQLStore) VoteHouseholdSpendTx(ctx context.Context, arg VoteHouseholdSpendTxParams) (HouseholdSpendTxResult, error) {
+	var result HouseholdSpendTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = HouseholdSpendTxResult{}
+
+		if err := requireHouseholdMember(ctx, q, arg.Household.ID, arg.Username); err != nil {
+			return err
+		}
+
+		spend, err := q.GetHouseholdSpendForUpdate(ctx, GetHouseholdSpendForUpdateParams{
+			ID:          arg.SpendID,
+			HouseholdID: arg.Household.ID,
+		})
+		if err != nil {
+			return err
+		}
+		result.Spend = spend
+
+		if spend.Status != HouseholdSpendPending {
+			return fmt.Errorf("%w: %s", ErrAlreadyDecided, spend.Status)
+		}
+
+		_, err = q.CreateHouseholdSpendVote(ctx, CreateHouseholdSpendVoteParams{
+			SpendID:  spend.ID,
+			Username: arg.Username,
+			Approve:  arg.Approve,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = settleHouseholdSpend(ctx, q, arg.Household, arg.Now, &result)
+		return err
+	})
+
+	if err == nil && result.Transfer != nil {
+		metrics.ObserveTransfer(result.Transfer.FromAccount.Currency, result.Spend.Amount)
+	}
+
+	return result, err
+}
+
+func newHouseholdSpendEvent(ctx context.Context, q *Queries, spend HouseholdSpend) (householdSpendEvent, error) {
+	numbers, err := accountNumbers(ctx, q, spend.ToAccountID)
+	if err != nil {
+		return householdSpendEvent{}, err
+	}
+
+	return householdSpendEvent{
+		HouseholdID:     spend.HouseholdID,
+		SpendID:         spend.ID,
+		RequestedBy:     spend.RequestedBy,
+		ToAccountNumber: numbers[spend.ToAccountID],
+		Amount:          spend.Amount,
+		Note:            spend.Note,
+	}, nil
+}
+
+// notifyHouseholdSpend tells the requester of the spend what became of it
+func notifyHouseholdSpend(ctx context.Context, q *Queries, kind string, spend HouseholdSpend) error {
+	event, err := newHouseholdSpendEvent(ctx, q, spend)
+	if err != nil {
+		return err
+	}
+
+	return writeNotification(ctx, q, spend.RequestedBy, kind, event)
+}
+
+func requireHouseholdMember(ctx context.Context, q *Queries, householdID int64, username string) error {
+	_, err := q.GetHouseholdMember(ctx, GetHouseholdMemberParams{HouseholdID: householdID, Username: username})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrNotHouseholdMember, username)
+		}
+		return err
+	}
+	return nil
+}
+
+// settleHouseholdSpend counts the votes on the pending spend of the result, and approves or declines it once
+// the members decided. It reports whether the spend was decided.
+func settleHouseholdSpend(ctx context.Context, q *Queries, household Household, now time.Time, result *HouseholdSpendTxResult) (bool, error) {
+	members, err := q.CountHouseholdMembers(ctx, household.ID)
+	if err != nil {
+		return false, err
+	}
+
+	votes, err := q.CountHouseholdSpendVotes(ctx, result.Spend.ID)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case votes.Approvals*2 > members:
+		return true, approveHouseholdSpend(ctx, q, household, now, result)
+	case votes.Declines*2 >= members:
+		return true, declineHouseholdSpend(ctx, q, result)
+	default:
+		return false, nil
+	}
+}
+
+// declineHouseholdSpend declines the pending spend of the result and tells the requester
+func declineHouseholdSpend(ctx context.Context, q *Queries, result *HouseholdSpendTxResult) error {
+	var err error
+
+	result.Spend, err = q.DecideHouseholdSpend(ctx, DecideHouseholdSpendParams{
+		ID:     result.Spend.ID,
+		Status: HouseholdSpendDeclined,
+	})
+	if err != nil {
+		return err
+	}
+
+	return notifyHouseholdSpend(ctx, q, NotificationHouseholdSpendDeclined, result.Spend)
+}
+
+// approveHouseholdSpend transfers the spend of the result out of the pot, and tells the requester when it had to wait for votes.
+// A spend which waited is declined instead when it is no longer within the controls of the requesting child's guardians.
+func approveHouseholdSpend(ctx context.Context, q *Queries, household Household, now time.Time, result *HouseholdSpendTxResult) error {
+	pot, err := q.GetAccountById(ctx, household.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if household.NeedsApproval(result.Spend.Amount) {
+		err := requireChildControls(ctx, q, ChildTransferTxParams{
+			TransferMoneyTxParams: TransferMoneyTxParams{
+				FromAccountID: household.AccountID,
+				ToAccountID:   result.Spend.ToAccountID,
+				Amount:        money.New(result.Spend.Amount, pot.Currency),
+			},
+			Child: result.Spend.RequestedBy,
+			Now:   now,
+		})
+		if errors.Is(err, ErrChildControls) {
+			return declineHouseholdSpend(ctx, q, result)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	transfer, err := transferAvailableMoney(ctx, q, TransferMoneyTxParams{
+		FromAccountID: household.AccountID,
+		ToAccountID:   result.Spend.ToAccountID,
+		Amount:        money.New(result.Spend.Amount, pot.Currency),
+	})
+	if err != nil {
+		return err
+	}
+	result.Transfer = &transfer
+
+	result.Spend, err = q.DecideHouseholdSpend(ctx, DecideHouseholdSpendParams{
+		ID:         result.Spend.ID,
+		Status:     HouseholdSpendApproved,
+		TransferID: pgtype.Int8{Int64: transfer.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if !household.NeedsApproval(result.Spend.Amount) {
+		return nil
+	}
+
+	return notifyHouseholdSpend(ctx, q, NotificationHouseholdSpendApproved, result.Spend)
+}
@@ -0,0 +1,237 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a money request
+const (
+	MoneyRequestPending  = "pending"
+	MoneyRequestPaid     = "paid"
+	MoneyRequestDeclined = "declined"
+	MoneyRequestExpired  = "expired"
+)
+
+// Kinds of the notifications about money requests
+const (
+	NotificationMoneyRequestReceived = "money_request.received"
+	NotificationMoneyRequestPaid     = "money_request.paid"
+	NotificationMoneyRequestDeclined = "money_request.declined"
+	NotificationMoneyRequestExpired  = "money_request.expired"
+)
+
+type moneyRequestEvent struct {
+	MoneyRequestID  int64       `json:"money_request_id"`
+	Requester       string      `json:"requester"`
+	Payer           string      `json:"payer"`
+	ToAccountNumber string      `json:"to_account_number"`
+	Amount          int64       `json:"amount"`
+	Currency        string      `json:"currency"`
+	Memo            string      `json:"memo,omitempty"`
+	Status          string      `json:"status"`
+	TransferID      pgtype.Int8 `json:"transfer_id"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+}
+
+func newMoneyRequestEvent(ctx context.Context, q *Queries, request MoneyRequest) (moneyRequestEvent, error) {
+	numbers, err := accountNumbers(ctx, q, request.ToAccountID)
+	if err != nil {
+		return moneyRequestEvent{}, err
+	}
+
+	return moneyRequestEvent{
+		MoneyRequestID:  request.ID,
+		Requester:       request.Requester,
+		Payer:           request.Payer,
+		ToAccountNumber: numbers[request.ToAccountID],
+		Amount:          request.Amount,
+		Currency:        request.Currency,
+		Memo:            request.Memo,
+		Status:          request.Status,
+		TransferID:      request.TransferID,
+		ExpiresAt:       request.ExpiresAt,
+	}, nil
+}
+
+var (
+	ErrNotMoneyRequestPayer = errors.New("not the payer of the money request")
+	ErrMoneyRequestExpired  = errors.New("money request expired")
+)
+
+// CreateMoneyRequestTx asks the payer for the money, who is notified of the request
+func (store *SQLStore) CreateMoneyRequestTx(ctx context.Context, arg CreateMoneyRequestParams) (MoneyRequest, error) {
+	var request MoneyRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		request, err = q.CreateMoneyRequest(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		event, err := newMoneyRequestEvent(ctx, q, request)
+		if err != nil {
+			return err
+		}
+
+		return writeNotification(ctx, q, request.Payer, NotificationMoneyRequestReceived, event)
+	})
+
+	return request, err
+}
+
+type DecideMoneyRequestTxParams struct {
+	ID    int64
+	Payer string
+	// FromAccountID is the account of the payer the money is paid from, only read when approving
+	FromAccountID int64
+	Approve       bool
+	Now           time.Time
+}
+
+// DecideMoneyRequestTxResult holds the request, and the transfer when it was paid.
+// A payer under guardianship may have the payment blocked, which declines the request,
+// or waiting for a guardian's approval, which leaves it pending until a guardian decides.
+type DecideMoneyRequestTxResult struct {
+	Request  MoneyRequest
+	Transfer *TransfeMoneyTxResult
+	Approval *ChildTransferApproval
+	Blocked  string
+}
+
+// DecideMoneyRequestTx pays or declines a pending request for its payer, and tells the requester.
+// The payment is held to the controls the guardians of the payer set, as any other transfer of theirs.
+func (store *SQLStore) DecideMoneyRequestTx(ctx context.Context, arg DecideMoneyRequestTxParams) (DecideMoneyRequestTxResult, error) {
+	var result DecideMoneyRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = DecideMoneyRequestTxResult{}
+
+		request, err := q.GetMoneyRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		result.Request = request
+
+		if request.Payer != arg.Payer {
+			return fmt.Errorf("%w: %s", ErrNotMoneyRequestPayer, arg.Payer)
+		}
+
+		if request.Status != MoneyRequestPending {
+			return fmt.Errorf("%w: %s", ErrAlreadyDecided, request.Status)
+		}
+
+		// the expirer may not have got to it yet
+		if !arg.Now.Before(request.ExpiresAt) {
+			return fmt.Errorf("%w at %s", ErrMoneyRequestExpired, request.ExpiresAt.Format(time.RFC3339))
+		}
+
+		if !arg.Approve {
+			result.Request, err = closeMoneyRequest(ctx, q, request, nil)
+			return err
+		}
+
+		payment := TransferMoneyTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   request.ToAccountID,
+			Amount:        request.Money(),
+		}
+
+		result.Blocked, result.Approval, err = holdChildTransfer(ctx, q, ChildTransferTxParams{
+			TransferMoneyTxParams: payment,
+			Child:                 arg.Payer,
+			Now:                   arg.Now,
+		}, pgtype.Int8{Int64: request.ID, Valid: true})
+		if err != nil || result.Approval != nil {
+			return err
+		}
+
+		if result.Blocked != "" {
+			result.Request, err = closeMoneyRequest(ctx, q, request, nil)
+			return err
+		}
+
+		transfer, err := transferAvailableMoney(ctx, q, payment)
+		if err != nil {
+			return err
+		}
+		result.Transfer = &transfer
+
+		result.Request, err = closeMoneyRequest(ctx, q, request, &transfer)
+		return err
+	})
+
+	if err == nil && result.Transfer != nil {
+		metrics.ObserveTransfer(result.Request.Currency, result.Request.Amount)
+	}
+
+	return result, err
+}
+
+// closeMoneyRequest records the request as paid by the transfer, or declined when there is none, and tells the requester
+func closeMoneyRequest(ctx context.Context, q *Queries, request MoneyRequest, transfer *TransfeMoneyTxResult) (MoneyRequest, error) {
+	decision := DecideMoneyRequestParams{
+		ID:     request.ID,
+		Status: MoneyRequestDeclined,
+	}
+	kind := NotificationMoneyRequestDeclined
+
+	if transfer != nil {
+		decision.Status = MoneyRequestPaid
+		decision.TransferID = pgtype.Int8{Int64: transfer.Transfer.ID, Valid: true}
+		kind = NotificationMoneyRequestPaid
+	}
+
+	decided, err := q.DecideMoneyRequest(ctx, decision)
+	if err != nil {
+		return decided, err
+	}
+
+	event, err := newMoneyRequestEvent(ctx, q, decided)
+	if err != nil {
+		return decided, err
+	}
+
+	return decided, writeNotification(ctx, q, request.Requester, kind, event)
+}
+
+type ExpireMoneyRequestTxResult struct {
+	Request MoneyRequest
+	Expired bool
+}
+
+// ExpireMoneyRequestTx expires the request if it is still pending past its expiry, and tells both sides
+func (store *SQLStore) ExpireMoneyRequestTx(ctx context.Context, id int64) (ExpireMoneyRequestTxResult, error) {
+	var result ExpireMoneyRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = ExpireMoneyRequestTxResult{}
+
+		request, err := q.ExpireMoneyRequest(ctx, id)
+		if err != nil {
+			// decided, or expired by another expirer, since it was listed
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		result.Request = request
+		result.Expired = true
+
+		event, err := newMoneyRequestEvent(ctx, q, request)
+		if err != nil {
+			return err
+		}
+
+		return notifyAll(ctx, q, []string{request.Requester, request.Payer}, NotificationMoneyRequestExpired, event)
+	})
+
+	return result, err
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Kinds of the notifications users find in their inbox
+const (
+	NotificationChildTransferBlocked           = "child_transfer.blocked"
+	NotificationChildTransferApprovalRequested = "child_transfer.approval_requested"
+	NotificationChildTransferApproved          = "child_transfer.approved"
+	NotificationChildTransferDeclined          = "child_transfer.declined"
+	NotificationAllowancePaid                  = "allowance.paid"
+	NotificationAllowanceFailed                = "allowance.failed"
+)
+
+// writeNotification writes the notification in the transaction of what it is about
+func writeNotification(ctx context.Context, q *Queries, username string, kind string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("cannot marshal notification: %w", err)
+	}
+
+	_, err = q.CreateNotification(ctx, CreateNotificationParams{
+		Username: username,
+		Kind:     kind,
+		Payload:  payload,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write notification: %w", err)
+	}
+
+	return nil
+}
+
+// notifyAll writes the same notification for each of the users
+func notifyAll(ctx context.Context, q *Queries, usernames []string, kind string, data any) error {
+	for _, username := range usernames {
+		if err := writeNotification(ctx, q, username, kind, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
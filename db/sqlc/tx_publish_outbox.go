@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PublishOutboxTxParams struct {
+	Limit       int32
+	MaxAttempts int32                      // a message failing this many times is no longer published
+	Backoff     time.Duration              // a failed message waits it once more after every failure
+	Publish     func(message Outbox) error // callback func to hand the task to the task queue
+}
+
+type PublishOutboxTxResult struct {
+	Published int
+	Failed    int
+	Abandoned int // failed for the last time, left in the outbox for an operator
+}
+
+// PublishOutboxTx locks a batch of pending outbox messages and publishes them.
+// Locked rows are skipped by other relays, and a message is only marked published
+// once Publish succeeded, so every task is delivered at least once.
+// Failed messages back off, so one the queue keeps refusing doesn't hold the newer ones up.
+func (store *SQLStore) PublishOutboxTx(ctx context.Context, arg PublishOutboxTxParams) (PublishOutboxTxResult, error) {
+	var result PublishOutboxTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = PublishOutboxTxResult{}
+
+		messages, err := q.ListPendingOutboxMessages(ctx, ListPendingOutboxMessagesParams{
+			MaxAttempts: arg.MaxAttempts,
+			Backoff:     pgtype.Interval{Microseconds: arg.Backoff.Microseconds(), Valid: true},
+			LimitCount:  arg.Limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if publishErr := arg.Publish(message); publishErr != nil {
+				attempts, err := q.RecordOutboxMessageFailure(ctx, RecordOutboxMessageFailureParams{
+					ID:        message.ID,
+					LastError: pgtype.Text{String: publishErr.Error(), Valid: true},
+				})
+				if err != nil {
+					return err
+				}
+				result.Failed++
+				if attempts >= arg.MaxAttempts {
+					result.Abandoned++
+				}
+				continue
+			}
+
+			if err := q.MarkOutboxMessagePublished(ctx, message.ID); err != nil {
+				return err
+			}
+			result.Published++
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -1,11 +1,21 @@
 package db
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/money"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient balance")
 
 type TransferMoneyTxParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        money.Money `json:"amount"`
 }
 
 type TransfeMoneyTxResult struct {
@@ -16,6 +26,34 @@ type TransfeMoneyTxResult struct {
 	ToEntry     *Entry    `json:"to_entry"`
 }
 
+// transferEvent tells the owner of one of the accounts about their side of the transfer
+type transferEvent struct {
+	TransferID        int64        `json:"transfer_id"`
+	FromAccountNumber string       `json:"from_account_number"`
+	ToAccountNumber   string       `json:"to_account_number"`
+	Amount            int64        `json:"amount"`
+	Currency          string       `json:"currency"`
+	Account           accountEvent `json:"account"`
+	EntryID           int64        `json:"entry_id"`
+	// negative on the side of the sender
+	EntryAmount int64     `json:"entry_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newTransferEvent(result TransfeMoneyTxResult, account Account, entry Entry) transferEvent {
+	return transferEvent{
+		TransferID:        result.Transfer.ID,
+		FromAccountNumber: result.FromAccount.Number,
+		ToAccountNumber:   result.ToAccount.Number,
+		Amount:            result.Transfer.Amount,
+		Currency:          account.Currency,
+		Account:           newAccountEvent(account),
+		EntryID:           entry.ID,
+		EntryAmount:       entry.Amount,
+		CreatedAt:         result.Transfer.CreatedAt,
+	}
+}
+
 // txKey is a custom key for transaction context. It will allow us to pass the name of the transaction
 // to the context so we can log it later
 // type txKetType struct{}
@@ -26,63 +64,121 @@ func (store *SQLStore) TransferMoneyTx(ctx context.Context, arg TransferMoneyTxP
 	var result TransfeMoneyTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = transferMoney(ctx, q, arg)
+		return err
+	})
 
-		// txName := ctx.Value(txKey)
+	if err == nil {
+		metrics.ObserveTransfer(arg.Amount.Currency, arg.Amount.Amount)
+	}
 
-		// fmt.Println(txName, ">> create transfer")
-		// create transfer
-		transfer, err := q.CreateTransfer(ctx, CreateTransferParams(arg))
-		if err != nil {
-			return err
-		}
-		result.Transfer = &transfer
+	return result, err
+}
+
+// transferMoney moves the money within the transaction of q, for the transactions which transfer as one of their steps
+func transferMoney(ctx context.Context, q *Queries, arg TransferMoneyTxParams) (TransfeMoneyTxResult, error) {
+	var result TransfeMoneyTxResult
+
+	debit, err := arg.Amount.Neg()
+	if err != nil {
+		return result, err
+	}
 
-		// create two entries
-		// from entry
-		fromEntry, err := q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+	// txName := ctx.Value(txKey)
+
+	// fmt.Println(txName, ">> create transfer")
+	// create transfer
+	transfer, err := q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Transfer = &transfer
+
+	// create two entries
+	// from entry
+	fromEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount:    debit.Amount,
+	})
+
+	if err != nil {
+		return result, err
+	}
+	result.FromEntry = &fromEntry
+
+	// to entry
+	toEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount:    arg.Amount.Amount,
+	})
+
+	if err != nil {
+		return result, err
+	}
+	result.ToEntry = &toEntry
+	// to avaoid dl
+	if arg.FromAccountID < arg.ToAccountID {
+
+		fromAcc, toAcc, err := addMoney(ctx, q, arg.FromAccountID, debit, arg.ToAccountID, arg.Amount)
 
 		if err != nil {
-			return err
+			return result, err
 		}
-		result.FromEntry = &fromEntry
 
-		// to entry
-		toEntry, err := q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+		result.FromAccount = &fromAcc
+		result.ToAccount = &toAcc
+	} else {
+		toAcc, fromAcc, err := addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, debit)
 
 		if err != nil {
-			return err
+			return result, err
 		}
-		result.ToEntry = &toEntry
-		// to avaoid dl
-		if arg.FromAccountID < arg.ToAccountID {
-
-			fromAcc, toAcc, err := addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
 
-			if err != nil {
-				return err
-			}
+		result.FromAccount = &fromAcc
+		result.ToAccount = &toAcc
+	}
 
-			result.FromAccount = &fromAcc
-			result.ToAccount = &toAcc
-		} else {
-			toAcc, fromAcc, err := addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	// each owner is only told about their side of the transfer
+	err = writeWebhookEvent(ctx, q, result.FromAccount.Owner, WebhookEventTransferSent, newTransferEvent(result, *result.FromAccount, fromEntry))
+	if err != nil {
+		return result, err
+	}
 
-			if err != nil {
-				return err
-			}
+	err = writeWebhookEvent(ctx, q, result.ToAccount.Owner, WebhookEventTransferReceived, newTransferEvent(result, *result.ToAccount, toEntry))
+	if err != nil {
+		return result, err
+	}
 
-			result.FromAccount = &fromAcc
-			result.ToAccount = &toAcc
-		}
+	// watchers of both accounts are only woken up once the transfer is committed
+	if err := q.NotifyAccountActivity(ctx, arg.FromAccountID); err != nil {
+		return result, err
+	}
 
-		return nil
-	})
+	return result, q.NotifyAccountActivity(ctx, arg.ToAccountID)
+}
 
-	return result, err
+// transferAvailableMoney locks the paying account and only transfers when its balance covers the amount.
+// It is for transfers nobody checked the balance for just before, like scheduled or approved ones.
+// ErrInsufficientBalance leaves the transaction usable, so the caller may record the failure and commit.
+func transferAvailableMoney(ctx context.Context, q *Queries, arg TransferMoneyTxParams) (TransfeMoneyTxResult, error) {
+	from, err := q.GetAccountByIdForUpdate(ctx, arg.FromAccountID)
+	if err != nil {
+		return TransfeMoneyTxResult{}, err
+	}
+
+	remaining, err := from.Money().Sub(arg.Amount)
+	if err != nil {
+		return TransfeMoneyTxResult{}, err
+	}
+
+	if remaining.IsNegative() {
+		return TransfeMoneyTxResult{}, fmt.Errorf("%w: account [%d] holds %s, not %s", ErrInsufficientBalance, from.ID, from.Money(), arg.Amount)
+	}
+
+	return transferMoney(ctx, q, arg)
 }
@@ -0,0 +1,34 @@
+package db
+
+import "context"
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	AfterEmailChange func(user User) ([]CreateOutboxMessageParams, error) // callback func returning the tasks to write to the outbox when the email changed
+}
+
+// UpdateUserTx updates the user. A changed email is no longer verified, the tasks of AfterEmailChange are written to the outbox
+// to verify it again.
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error) {
+	var user User
+
+	err := store.execTxWithOutbox(ctx, func(q *Queries) ([]CreateOutboxMessageParams, error) {
+		before, err := q.GetUserByUsername(ctx, arg.Username.String)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return nil, err
+		}
+
+		if user.Email == before.Email || arg.AfterEmailChange == nil {
+			return nil, nil
+		}
+
+		return arg.AfterEmailChange(user)
+	})
+
+	return user, err
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrInvalidVerifyEmail is returned for a verification link which is wrong, used, expired, or sent to an email the user changed since
+var ErrInvalidVerifyEmail = errors.New("verification link is invalid or expired")
+
+type VerifyEmailTxParams struct {
+	EmailID          int64
+	HashedSecretCode string
+}
+
+type VerifyEmailTxResult struct {
+	User        User
+	VerifyEmail VerifyEmail
+}
+
+type userVerifiedEvent struct {
+	Username        string    `json:"username"`
+	Email           string    `json:"email"`
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+}
+
+// VerifyEmailTx uses the verification link sent to the email of a user, verifies that email, and writes the user.verified webhook event.
+// It is the only way an email gets verified, so the only place the event is written.
+func (store *SQLStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.VerifyEmail, err = q.UseVerifyEmail(ctx, UseVerifyEmailParams{
+			ID:               arg.EmailID,
+			HashedSecretCode: arg.HashedSecretCode,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidVerifyEmail
+			}
+			return err
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			Username: result.VerifyEmail.Username,
+			Email:    result.VerifyEmail.Email,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidVerifyEmail
+			}
+			return err
+		}
+
+		return writeWebhookEvent(ctx, q, result.User.Username, WebhookEventUserVerified, userVerifiedEvent{
+			Username:        result.User.Username,
+			Email:           result.User.Email,
+			EmailVerifiedAt: result.User.EmailVerifiedAt.Time,
+		})
+	})
+
+	return result, err
+}
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Types of the webhook events, endpoints subscribe to some of them
+const (
+	WebhookEventTransferSent     = "transfer.sent"
+	WebhookEventTransferReceived = "transfer.received"
+	WebhookEventDepositCreated   = "deposit.created"
+	WebhookEventAccountCreated   = "account.created"
+	WebhookEventAccountDeleted   = "account.deleted"
+	WebhookEventUserVerified     = "user.verified"
+
+	// sent to a single endpoint when its owner asks for it, endpoints cannot subscribe to it
+	WebhookEventPing = "webhook.ping"
+)
+
+// WebhookEventTypes are the event types endpoints can subscribe to
+var WebhookEventTypes = []string{
+	WebhookEventTransferSent,
+	WebhookEventTransferReceived,
+	WebhookEventDepositCreated,
+	WebhookEventAccountCreated,
+	WebhookEventAccountDeleted,
+	WebhookEventUserVerified,
+}
+
+// Statuses of a webhook delivery
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// writeWebhookEvent writes the event in the transaction of the change it is about,
+// so events are only sent for data that was actually committed
+func writeWebhookEvent(ctx context.Context, q *Queries, owner string, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("cannot marshal webhook event: %w", err)
+	}
+
+	_, err = q.CreateWebhookEvent(ctx, CreateWebhookEventParams{
+		Owner:     owner,
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write webhook event: %w", err)
+	}
+
+	return nil
+}
+
+type DispatchWebhookEventsTxParams struct {
+	Limit    int32
+	Dispatch func(delivery WebhookDelivery) error // callback func to hand the delivery to the task queue
+}
+
+type DispatchWebhookEventsTxResult struct {
+	Events     int
+	Deliveries int
+}
+
+// DispatchWebhookEventsTx locks a batch of pending webhook events and creates a delivery for every subscribed endpoint.
+// When a delivery cannot be dispatched the whole batch is rolled back and dispatched again by the next run,
+// the delivery ids are never reused so a task dispatched before the rollback finds no delivery.
+func (store *SQLStore) DispatchWebhookEventsTx(ctx context.Context, arg DispatchWebhookEventsTxParams) (DispatchWebhookEventsTxResult, error) {
+	var result DispatchWebhookEventsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = DispatchWebhookEventsTxResult{}
+
+		events, err := q.ListPendingWebhookEvents(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			deliveries, err := q.CreateWebhookDeliveries(ctx, CreateWebhookDeliveriesParams{
+				EventID:   event.ID,
+				Owner:     event.Owner,
+				EventType: event.EventType,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, delivery := range deliveries {
+				if err := arg.Dispatch(delivery); err != nil {
+					return fmt.Errorf("cannot dispatch webhook delivery: %w", err)
+				}
+			}
+
+			if err := q.MarkWebhookEventDispatched(ctx, event.ID); err != nil {
+				return err
+			}
+
+			result.Events++
+			result.Deliveries += len(deliveries)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type CreateWebhookDeliveryTxParams struct {
+	CreateWebhookDeliveryParams
+	AfterCreateDelivery func(delivery WebhookDelivery) ([]CreateOutboxMessageParams, error) // callback func returning the tasks to write to the outbox
+}
+
+type CreateWebhookDeliveryTxResult struct {
+	Delivery WebhookDelivery
+}
+
+// CreateWebhookDeliveryTx sends an event to an endpoint once more, with a delivery of its own
+func (store *SQLStore) CreateWebhookDeliveryTx(ctx context.Context, arg CreateWebhookDeliveryTxParams) (CreateWebhookDeliveryTxResult, error) {
+	var result CreateWebhookDeliveryTxResult
+
+	err := store.execTxWithOutbox(ctx, func(q *Queries) ([]CreateOutboxMessageParams, error) {
+		delivery, err := q.CreateWebhookDelivery(ctx, arg.CreateWebhookDeliveryParams)
+		if err != nil {
+			return nil, err
+		}
+		result.Delivery = delivery
+
+		if arg.AfterCreateDelivery == nil {
+			return nil, nil
+		}
+		return arg.AfterCreateDelivery(delivery)
+	})
+
+	return result, err
+}
+
+type PingWebhookEndpointTxParams struct {
+	Endpoint            WebhookEndpoint
+	AfterCreateDelivery func(delivery WebhookDelivery) ([]CreateOutboxMessageParams, error) // callback func returning the tasks to write to the outbox
+}
+
+type PingWebhookEndpointTxResult struct {
+	Event    WebhookEvent
+	Delivery WebhookDelivery
+}
+
+// PingWebhookEndpointTx writes a ping event delivered to the given endpoint only
+func (store *SQLStore) PingWebhookEndpointTx(ctx context.Context, arg PingWebhookEndpointTxParams) (PingWebhookEndpointTxResult, error) {
+	var result PingWebhookEndpointTxResult
+
+	err := store.execTxWithOutbox(ctx, func(q *Queries) ([]CreateOutboxMessageParams, error) {
+		payload, err := json.Marshal(map[string]any{
+			"endpoint_id": arg.Endpoint.ID,
+			"sent_at":     time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		event, err := q.CreateWebhookEvent(ctx, CreateWebhookEventParams{
+			Owner:     arg.Endpoint.Owner,
+			EventType: WebhookEventPing,
+			Payload:   payload,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// the event is not for the other endpoints of the owner
+		if err := q.MarkWebhookEventDispatched(ctx, event.ID); err != nil {
+			return nil, err
+		}
+
+		delivery, err := q.CreateWebhookDelivery(ctx, CreateWebhookDeliveryParams{
+			EndpointID: arg.Endpoint.ID,
+			EventID:    event.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Event = event
+		result.Delivery = delivery
+
+		if arg.AfterCreateDelivery == nil {
+			return nil, nil
+		}
+		return arg.AfterCreateDelivery(delivery)
+	})
+
+	return result, err
+}
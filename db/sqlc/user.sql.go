@@ -22,7 +22,7 @@ INSERT INTO users (
     $2,
     $3,
     $4
-) RETURNING username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at
+) RETURNING username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at, role
 `
 
 type CreateUserParams struct {
@@ -48,12 +48,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.EmailVerifiedAt,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at, role FROM users
 WHERE email = $1
 `
 
@@ -68,12 +69,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.EmailVerifiedAt,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at, role FROM users
 WHERE username = $1
 `
 
@@ -88,6 +90,30 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.EmailVerifiedAt,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUserByUsernameForUpdate = `-- name: GetUserByUsernameForUpdate :one
+SELECT username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at, role FROM users
+WHERE username = $1
+FOR NO KEY UPDATE
+`
+
+// serializes the transactions which check limits on the accounts of the user
+func (q *Queries) GetUserByUsernameForUpdate(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByUsernameForUpdate, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -97,17 +123,17 @@ UPDATE users
 SET
     full_name = COALESCE($1, full_name),
     email = COALESCE($2, email),
-    email_verified_at = COALESCE($3, email_verified_at),
-    password_changed_at = COALESCE($4, password_changed_at),
-    hashed_password = COALESCE($5, hashed_password)
-WHERE username = $6
-RETURNING username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at
+    -- a new email is not verified, it only is once a link sent to it is used
+    email_verified_at = CASE WHEN COALESCE($2, email) = email THEN email_verified_at END,
+    password_changed_at = COALESCE($3, password_changed_at),
+    hashed_password = COALESCE($4, hashed_password)
+WHERE username = $5
+RETURNING username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at, role
 `
 
 type UpdateUserParams struct {
 	FullName          pgtype.Text        `json:"full_name"`
 	Email             pgtype.Text        `json:"email"`
-	EmailVerifiedAt   pgtype.Timestamptz `json:"email_verified_at"`
 	PasswordChangedAt pgtype.Timestamptz `json:"password_changed_at"`
 	HashedPassword    pgtype.Text        `json:"hashed_password"`
 	Username          pgtype.Text        `json:"username"`
@@ -117,7 +143,6 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 	row := q.db.QueryRow(ctx, updateUser,
 		arg.FullName,
 		arg.Email,
-		arg.EmailVerifiedAt,
 		arg.PasswordChangedAt,
 		arg.HashedPassword,
 		arg.Username,
@@ -131,6 +156,36 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.EmailVerifiedAt,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = now()
+WHERE username = $1 AND email = $2
+RETURNING username, hashed_password, full_name, email, email_verified_at, password_changed_at, created_at, role
+`
+
+type VerifyUserEmailParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// verifies the email of the user, unless it changed since the link was sent to it
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
 	)
 	return i, err
 }
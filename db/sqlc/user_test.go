@@ -28,6 +28,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, arg.FullName, arg.FullName)
 	require.Equal(t, arg.Email, user.Email)
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
+	require.Equal(t, util.DepositorRole, user.Role)
 
 	require.NotZero(t, user.CreatedAt)
 
@@ -88,21 +89,38 @@ func TestChangeUserPassword(t *testing.T) {
 }
 
 func TestUpdateUserEmailVerification(t *testing.T) {
-	user := createRandomUser(t)
-
-	arg := UpdateUserParams{
-		Username:        util.NewPgText(user.Username),
-		EmailVerifiedAt: util.NewPgTime(time.Now()),
-	}
-
-	changedUser, err := testQueries.UpdateUser(context.Background(), arg)
+	store := NewStore(testDb)
+	user := verifyRandomUser(t, store)
+
+	// updates leaving the email alone keep it verified
+	changedUser, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: util.NewPgText(user.Username),
+			FullName: util.NewPgText(util.RandomString(10)),
+			Email:    util.NewPgText(user.Email),
+		},
+		AfterEmailChange: func(user User) ([]CreateOutboxMessageParams, error) {
+			t.Fatal("the email did not change")
+			return nil, nil
+		},
+	})
 	require.NoError(t, err)
-	require.NotEmpty(t, changedUser)
-	require.NotEmpty(t, changedUser.EmailVerifiedAt)
-
-	require.Equal(t, user.FullName, changedUser.FullName)
-	require.Equal(t, user.Username, changedUser.Username)
-	require.Equal(t, user.Email, changedUser.Email)
-	require.Equal(t, user.HashedPassword, changedUser.HashedPassword)
-	require.WithinDuration(t, user.CreatedAt, changedUser.CreatedAt, time.Second)
+	require.Equal(t, user.EmailVerifiedAt, changedUser.EmailVerifiedAt)
+
+	// a new email is not verified until a link sent to it is used
+	var changed []User
+	changedUser, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: util.NewPgText(user.Username),
+			Email:    util.NewPgText(util.RandomEmail()),
+		},
+		AfterEmailChange: func(user User) ([]CreateOutboxMessageParams, error) {
+			changed = append(changed, user)
+			return nil, nil
+		},
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, user.Email, changedUser.Email)
+	require.False(t, changedUser.EmailVerifiedAt.Valid)
+	require.Equal(t, []User{changedUser}, changed)
 }
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomVerifyEmail sends a verification link to the current email of the user, and returns the hash of its secret code
+func createRandomVerifyEmail(t *testing.T, user User) (VerifyEmail, string) {
+	hashedSecretCode := util.RandomString(64)
+
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:         user.Username,
+		Email:            user.Email,
+		HashedSecretCode: hashedSecretCode,
+	})
+	require.NoError(t, err)
+	require.False(t, verifyEmail.IsUsed)
+	require.True(t, verifyEmail.ExpiredAt.After(verifyEmail.CreatedAt))
+
+	return verifyEmail, hashedSecretCode
+}
+
+// verifyRandomUser creates a user whose email was verified with a link sent to it
+func verifyRandomUser(t *testing.T, store Store) User {
+	verifyEmail, hashedSecretCode := createRandomVerifyEmail(t, createRandomUser(t))
+
+	result, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:          verifyEmail.ID,
+		HashedSecretCode: hashedSecretCode,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.EmailVerifiedAt.Valid)
+
+	return result.User
+}
+
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	user := createRandomUser(t)
+	require.False(t, user.EmailVerifiedAt.Valid)
+
+	verifyEmail, hashedSecretCode := createRandomVerifyEmail(t, user)
+
+	_, err := store.VerifyEmailTx(ctx, VerifyEmailTxParams{
+		EmailID:          verifyEmail.ID,
+		HashedSecretCode: util.RandomString(64),
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+
+	unverified, err := store.GetUserByUsername(ctx, user.Username)
+	require.NoError(t, err)
+	require.False(t, unverified.EmailVerifiedAt.Valid)
+
+	arg := VerifyEmailTxParams{
+		EmailID:          verifyEmail.ID,
+		HashedSecretCode: hashedSecretCode,
+	}
+
+	result, err := store.VerifyEmailTx(ctx, arg)
+	require.NoError(t, err)
+	require.True(t, result.VerifyEmail.IsUsed)
+	require.Equal(t, user.Username, result.User.Username)
+	require.True(t, result.User.EmailVerifiedAt.Valid)
+
+	// a link is used once
+	_, err = store.VerifyEmailTx(ctx, arg)
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+}
+
+func TestVerifyEmailTxChangedEmail(t *testing.T) {
+	store := NewStore(testDb)
+	ctx := context.Background()
+
+	user := createRandomUser(t)
+	verifyEmail, hashedSecretCode := createRandomVerifyEmail(t, user)
+
+	_, err := store.UpdateUserTx(ctx, UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: util.NewPgText(user.Username),
+			Email:    util.NewPgText(util.RandomEmail()),
+		},
+	})
+	require.NoError(t, err)
+
+	// the link sent to the old email does not verify the new one
+	_, err = store.VerifyEmailTx(ctx, VerifyEmailTxParams{
+		EmailID:          verifyEmail.ID,
+		HashedSecretCode: hashedSecretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+
+	changedUser, err := store.GetUserByUsername(ctx, user.Username)
+	require.NoError(t, err)
+	require.False(t, changedUser.EmailVerifiedAt.Valid)
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookEndpoint(t *testing.T, owner User, eventTypes ...string) WebhookEndpoint {
+	arg := CreateWebhookEndpointParams{
+		Owner:      owner.Username,
+		Url:        "https://" + util.RandomString(6) + ".example/hook",
+		Secret:     util.RandomString(32),
+		EventTypes: eventTypes,
+	}
+
+	endpoint, err := testQueries.CreateWebhookEndpoint(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, endpoint.Owner)
+	require.Equal(t, arg.Url, endpoint.Url)
+	require.Equal(t, arg.EventTypes, endpoint.EventTypes)
+	require.NotZero(t, endpoint.CreatedAt)
+
+	return endpoint
+}
+
+// dispatchWebhookEvents dispatches every pending event and returns the deliveries created, by endpoint
+func dispatchWebhookEvents(t *testing.T, store Store) map[int64][]WebhookDelivery {
+	deliveries := map[int64][]WebhookDelivery{}
+
+	_, err := store.DispatchWebhookEventsTx(context.Background(), DispatchWebhookEventsTxParams{
+		Limit: 1000,
+		Dispatch: func(delivery WebhookDelivery) error {
+			deliveries[delivery.EndpointID] = append(deliveries[delivery.EndpointID], delivery)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	return deliveries
+}
+
+func TestCreateAccountTxWritesWebhookEvent(t *testing.T) {
+	store := NewStore(testDb)
+	user := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, user, WebhookEventAccountCreated)
+	other := createRandomWebhookEndpoint(t, user, WebhookEventDepositCreated)
+
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  0,
+			Currency: util.RandomCurrency(),
+			Name:     util.RandomString(6),
+			Type:     AccountTypeChecking,
+		},
+		MaxAccounts: 1,
+	})
+	require.NoError(t, err)
+
+	dispatched := dispatchWebhookEvents(t, store)
+
+	// endpoints only get the event types they subscribed to
+	require.Empty(t, dispatched[other.ID])
+
+	deliveries := dispatched[endpoint.ID]
+	require.Len(t, deliveries, 1)
+	require.Equal(t, WebhookDeliveryPending, deliveries[0].Status)
+
+	event, err := testQueries.GetWebhookEvent(context.Background(), deliveries[0].EventID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookEventAccountCreated, event.EventType)
+	require.True(t, event.DispatchedAt.Valid)
+
+	var payload accountEvent
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	require.Equal(t, account.Number, payload.Number)
+	requireNoAccountIDs(t, event.Payload)
+}
+
+func TestVerifyEmailTxWritesWebhookEvent(t *testing.T) {
+	store := NewStore(testDb)
+	user := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, user, WebhookEventUserVerified)
+
+	// updates are not verifications
+	_, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: util.NewPgText(user.Username),
+			FullName: util.NewPgText(util.RandomString(10)),
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, dispatchWebhookEvents(t, store)[endpoint.ID])
+
+	verifyEmail, hashedSecretCode := createRandomVerifyEmail(t, user)
+
+	result, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:          verifyEmail.ID,
+		HashedSecretCode: hashedSecretCode,
+	})
+	require.NoError(t, err)
+
+	deliveries := dispatchWebhookEvents(t, store)[endpoint.ID]
+	require.Len(t, deliveries, 1)
+
+	event, err := testQueries.GetWebhookEvent(context.Background(), deliveries[0].EventID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookEventUserVerified, event.EventType)
+
+	var payload userVerifiedEvent
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	require.Equal(t, user.Username, payload.Username)
+	require.Equal(t, user.Email, payload.Email)
+	require.WithinDuration(t, result.User.EmailVerifiedAt.Time, payload.EmailVerifiedAt, time.Microsecond)
+}
+
+func TestRecordWebhookDeliveryAttempt(t *testing.T) {
+	store := NewStore(testDb)
+	user := createRandomUser(t)
+	endpoint := createRandomWebhookEndpoint(t, user, WebhookEventUserVerified)
+
+	result, err := store.PingWebhookEndpointTx(context.Background(), PingWebhookEndpointTxParams{Endpoint: endpoint})
+	require.NoError(t, err)
+	require.Equal(t, WebhookEventPing, result.Event.EventType)
+
+	delivery, err := testQueries.RecordWebhookDeliveryAttempt(context.Background(), RecordWebhookDeliveryAttemptParams{
+		ID:             result.Delivery.ID,
+		Status:         WebhookDeliveryPending,
+		ResponseStatus: pgtype.Int4{Int32: 500, Valid: true},
+		LastError:      pgtype.Text{String: "unexpected response status 500", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), delivery.Attempts)
+	require.False(t, delivery.DeliveredAt.Valid)
+
+	delivery, err = testQueries.RecordWebhookDeliveryAttempt(context.Background(), RecordWebhookDeliveryAttemptParams{
+		ID:             result.Delivery.ID,
+		Status:         WebhookDeliverySucceeded,
+		ResponseStatus: pgtype.Int4{Int32: 200, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), delivery.Attempts)
+	require.True(t, delivery.DeliveredAt.Valid)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, WebhookEventPing, deliveries[0].EventType)
+}
@@ -12,9 +12,12 @@ import (
 const (
 	authorizationHeader = "authorization"
 	authorizationBearer = "bearer"
+	authorizationApiKey = "apikey"
 )
 
-func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+// authorizeUser authenticates a bearer token, or an api key or third-party app token holding one of the given scopes.
+// When no scope is given only the user's own bearer tokens are accepted.
+func (server *Server) authorizeUser(ctx context.Context, scopes ...string) (*token.Payload, error) {
 
 	md, ok := metadata.FromIncomingContext(ctx)
 
@@ -36,15 +39,51 @@ func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error)
 		return nil, fmt.Errorf("invalid authorization header format")
 	}
 
-	if strings.ToLower(fields[0]) != authorizationBearer {
+	var payload *token.Payload
+
+	switch strings.ToLower(fields[0]) {
+	case authorizationBearer:
+		accessToken := fields[1]
+		tokenPayload, err := server.tokenMaker.VerifyToken(accessToken)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid access token: %s", err)
+		}
+
+		// tokens issued to third-party apps are limited to the scopes the user consented to
+		if tokenPayload.IsDelegated() && !tokenPayload.HasScope(scopes...) {
+			return nil, fmt.Errorf("access token is missing scope for this method")
+		}
+
+		payload = tokenPayload
+	case authorizationApiKey:
+		if len(scopes) == 0 {
+			return nil, fmt.Errorf("api keys cannot be used for this method")
+		}
+
+		apiKeyPayload, err := server.apiKeys.VerifyAPIKey(ctx, fields[1], server.extractMetaData(ctx).ClientIp)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid api key: %s", err)
+		}
+
+		if !apiKeyPayload.HasScope(scopes...) {
+			return nil, fmt.Errorf("api key is missing scope: %s", strings.Join(scopes, " or "))
+		}
+
+		payload = &apiKeyPayload.Payload
+	default:
 		return nil, fmt.Errorf("invalid authorization header type")
 	}
 
-	accessToken := fields[1]
-	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	revoked, err := server.revocations.IsRevoked(ctx, payload)
 
 	if err != nil {
-		return nil, fmt.Errorf("invalid access token: %s", err)
+		return nil, fmt.Errorf("cannot check access token: %s", err)
+	}
+
+	if revoked {
+		return nil, fmt.Errorf("access token has been revoked")
 	}
 
 	return payload, nil
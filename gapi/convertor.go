@@ -2,6 +2,7 @@ package gapi
 
 import (
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/money"
 	"github.com/AnkitNayan83/houseBank/pb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -15,3 +16,16 @@ func convertUser(user db.User) *pb.User {
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertAccountActivity(row db.ListAccountActivityRow, account db.Account) *pb.AccountActivity {
+	return &pb.AccountActivity{
+		EntryId:          row.ID,
+		AccountNumber:    account.Number,
+		Amount:           row.Amount,
+		Balance:          row.Balance,
+		Currency:         account.Currency,
+		CreatedAt:        timestamppb.New(row.CreatedAt),
+		AmountFormatted:  money.New(row.Amount, account.Currency).Decimal(),
+		BalanceFormatted: money.New(row.Balance, account.Currency).Decimal(),
+	}
+}
@@ -1,7 +1,16 @@
 package gapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -25,3 +34,103 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 
 	return statusDetails.Err()
 }
+
+// publicStatus is the status returned to clients: internal failures lose their message, which was logged instead,
+// and every error carries the request id so it can be matched with the logs
+func publicStatus(ctx context.Context, err error) *status.Status {
+	st := status.Convert(err)
+
+	if util.IsInternalCode(st.Code()) {
+		st = status.New(st.Code(), util.InternalErrorMessage)
+	}
+
+	requestID := util.RequestIDFromContext(ctx)
+	if requestID == "" {
+		return st
+	}
+
+	withRequestID, detailErr := st.WithDetails(&errdetails.RequestInfo{RequestId: requestID})
+	if detailErr != nil {
+		return st
+	}
+
+	return withRequestID
+}
+
+// GrpcErrors hides the details of internal errors from clients, it must run outside of GrpcLogger
+// so the original error still gets logged
+func GrpcErrors(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	result, err := handler(ctx, req)
+	if err != nil {
+		return result, publicStatus(ctx, err).Err()
+	}
+
+	return result, nil
+}
+
+// GatewayErrorHandler writes gateway errors with the same error model as the REST api
+func GatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+
+	httpStatus := runtime.HTTPStatusFromCode(st.Code())
+	var customStatus *runtime.HTTPStatusError
+	if errors.As(err, &customStatus) {
+		httpStatus = customStatus.HTTPStatus
+		st = status.Convert(customStatus.Err)
+	}
+
+	detail := util.ErrorDetail{
+		Code:      util.ErrorCode(st.Code()),
+		Message:   st.Message(),
+		RequestID: util.RequestIDFromContext(ctx),
+	}
+
+	if util.IsInternalCode(st.Code()) {
+		log.Error().
+			Err(err).
+			Str("request_id", detail.RequestID).
+			Str("path", r.URL.Path).
+			Msg("gateway request failed")
+
+		detail.Message = util.InternalErrorMessage
+	}
+
+	for _, statusDetail := range st.Details() {
+		if badRequest, ok := statusDetail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				detail.Violations = append(detail.Violations, util.FieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	w.Header().Del("Trailer")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+
+	if err := json.NewEncoder(w).Encode(util.ErrorResponse{Error: detail}); err != nil {
+		log.Error().Err(err).Msg("cannot write gateway error response")
+	}
+}
+
+// GrpcStreamErrors does the same as GrpcErrors for streaming rpcs
+func GrpcStreamErrors(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	err := handler(srv, stream)
+	if err != nil {
+		return publicStatus(stream.Context(), err).Err()
+	}
+
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,7 +35,12 @@ func GrpcLogger(
 		logger = log.Error().Err(err)
 	}
 
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		logger = logger.Str("trace_id", spanContext.TraceID().String())
+	}
+
 	logger.Str("protocol", "grpc").
+		Str("request_id", util.RequestIDFromContext(ctx)).
 		Str("method", info.FullMethod).
 		Int("status_code", int(statusCode)).
 		Str("status", statusCode.String()).
@@ -43,6 +50,44 @@ func GrpcLogger(
 	return result, err
 }
 
+// GrpcStreamLogger does the same as GrpcLogger for streaming rpcs, once the stream ends
+func GrpcStreamLogger(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+	err := handler(srv, stream)
+	duration := time.Since(startTime)
+
+	statusCode := codes.Unknown
+
+	if st, ok := status.FromError(err); ok {
+		statusCode = st.Code()
+	}
+
+	logger := log.Info()
+	if err != nil {
+		logger = log.Error().Err(err)
+	}
+
+	ctx := stream.Context()
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		logger = logger.Str("trace_id", spanContext.TraceID().String())
+	}
+
+	logger.Str("protocol", "grpc").
+		Str("request_id", util.RequestIDFromContext(ctx)).
+		Str("method", info.FullMethod).
+		Int("status_code", int(statusCode)).
+		Str("status", statusCode.String()).
+		Dur("duration", duration).
+		Msg("recieved grpc stream")
+
+	return err
+}
+
 // override the default http.ResponseWriter to capture the status code
 type ResponseWriter struct {
 	http.ResponseWriter
@@ -60,6 +105,11 @@ func (rec *ResponseWriter) Write(body []byte) (int, error) {
 	return rec.ResponseWriter.Write(body)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, event streams need to flush it
+func (rec *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
 func HttpLogger(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		startTime := time.Now()
@@ -73,8 +123,13 @@ func HttpLogger(handler http.Handler) http.Handler {
 			logger = log.Error().Bytes("body", rec.Body)
 		}
 
+		if spanContext := trace.SpanContextFromContext(req.Context()); spanContext.HasTraceID() {
+			logger = logger.Str("trace_id", spanContext.TraceID().String())
+		}
+
 		logger.
 			Str("protocol", "http").
+			Str("request_id", util.RequestIDFromContext(req.Context())).
 			Int("status_code", rec.statusCode).
 			Str("status", http.StatusText(rec.statusCode)).
 			Str("method", req.Method).
@@ -0,0 +1,174 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/AnkitNayan83/houseBank/pb"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type authPayloadKey struct{}
+
+func withAuthPayload(ctx context.Context, payload *token.Payload) context.Context {
+	return context.WithValue(ctx, authPayloadKey{}, payload)
+}
+
+// authPayloadFromContext returns the caller authenticated by the auth interceptor, it is nil for public methods
+func authPayloadFromContext(ctx context.Context) *token.Payload {
+	payload, _ := ctx.Value(authPayloadKey{}).(*token.Payload)
+	return payload
+}
+
+// pathVariable matches the variables of http rule templates, which the gateway patterns write as {name=*}
+var pathVariable = regexp.MustCompile(`\{([^=}]+)\}`)
+
+// methodAuth holds the auth option of every rpc declared in the proto files
+type methodAuth struct {
+	rules map[string]*pb.AuthRule
+	// full method of the gateway routes, by "METHOD /path/template"
+	gatewayMethods map[string]string
+}
+
+func newMethodAuth(services ...protoreflect.ServiceDescriptor) *methodAuth {
+	auth := &methodAuth{
+		rules:          map[string]*pb.AuthRule{},
+		gatewayMethods: map[string]string{},
+	}
+
+	for _, service := range services {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			fullMethod := "/" + string(service.FullName()) + "/" + string(method.Name())
+
+			auth.rules[fullMethod] = proto.GetExtension(method.Options(), pb.E_Auth).(*pb.AuthRule)
+
+			httpRule := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+			for _, rule := range append([]*annotations.HttpRule{httpRule}, httpRule.GetAdditionalBindings()...) {
+				if route := gatewayRoute(rule); route != "" {
+					auth.gatewayMethods[route] = fullMethod
+				}
+			}
+		}
+	}
+
+	return auth
+}
+
+func gatewayRoute(rule *annotations.HttpRule) string {
+	var method, path string
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		method, path = http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Post:
+		method, path = http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Put:
+		method, path = http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Patch:
+		method, path = http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Delete:
+		method, path = http.MethodDelete, pattern.Delete
+	default:
+		return ""
+	}
+
+	return method + " " + pathVariable.ReplaceAllString(path, "{$1=*}")
+}
+
+// authorizeMethod enforces the auth option of the method, and returns ctx with the authenticated caller.
+// Methods of services not declared in the proto files, like health and reflection, are left alone.
+func (server *Server) authorizeMethod(ctx context.Context, fullMethod string) (context.Context, error) {
+	rule, ok := server.methodAuth.rules[fullMethod]
+	if !ok || rule.GetLevel() == pb.AuthLevel_AUTH_LEVEL_PUBLIC {
+		return ctx, nil
+	}
+
+	payload, err := server.authorizeUser(ctx, rule.GetScopes()...)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated: %v", err)
+	}
+
+	if len(rule.GetRoles()) > 0 && !slices.Contains(rule.GetRoles(), payload.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "role %s cannot call this method", payload.Role)
+	}
+
+	return withAuthPayload(ctx, payload), nil
+}
+
+// GrpcAuth authenticates the caller as declared by the auth option of the method,
+// handlers read the caller with authPayloadFromContext
+func (server *Server) GrpcAuth(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	ctx, err = server.authorizeMethod(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// GatewayAuth does the same as GrpcAuth for the gateway, which calls the handlers without going through the interceptors
+func (server *Server) GatewayAuth(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		pattern, ok := runtime.HTTPPattern(req.Context())
+		if !ok {
+			next(res, req, pathParams)
+			return
+		}
+
+		fullMethod, ok := server.methodAuth.gatewayMethods[req.Method+" "+pattern.String()]
+		if !ok {
+			next(res, req, pathParams)
+			return
+		}
+
+		md := metadata.Pairs(
+			authorizationHeader, req.Header.Get("Authorization"),
+			userAgent, req.UserAgent(),
+			xForwardedFor, req.RemoteAddr,
+		)
+
+		ctx, err := server.authorizeMethod(metadata.NewIncomingContext(req.Context(), md), fullMethod)
+		if err != nil {
+			GatewayErrorHandler(req.Context(), nil, nil, res, req, err)
+			return
+		}
+
+		// the gateway builds the handler context from the request context, which is how the handler gets the caller
+		if payload := authPayloadFromContext(ctx); payload != nil {
+			req = req.WithContext(withAuthPayload(req.Context(), payload))
+		}
+
+		next(res, req, pathParams)
+	}
+}
+
+// GrpcStreamAuth does the same as GrpcAuth for streaming rpcs
+func (server *Server) GrpcStreamAuth(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := server.authorizeMethod(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,110 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const unmatchedRoute = "unmatched"
+
+type routeKey struct{}
+
+func GrpcMetrics(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	startTime := time.Now()
+	result, err := handler(ctx, req)
+
+	statusCode := codes.Unknown
+	if st, ok := status.FromError(err); ok {
+		statusCode = st.Code()
+	}
+
+	metrics.ObserveGRPCRequest(info.FullMethod, statusCode, time.Since(startTime))
+
+	return result, err
+}
+
+// HttpMetrics counts the gateway requests and their latency by the route template they matched,
+// so path parameters do not end up as separate label values
+func HttpMetrics(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		startTime := time.Now()
+
+		route := new(string)
+		req = req.WithContext(context.WithValue(req.Context(), routeKey{}, route))
+
+		rec := &ResponseWriter{ResponseWriter: res, statusCode: http.StatusOK}
+		handler.ServeHTTP(rec, req)
+
+		// routes outside of the grpc gateway mux are matched by an http.ServeMux, which sets the pattern on the request
+		if *route == "" {
+			*route = httpMuxRoute(req.Pattern)
+		}
+
+		metrics.ObserveHTTPRequest("gateway", req.Method, *route, rec.statusCode, time.Since(startTime))
+	})
+}
+
+// GatewayRoute reports the path template the grpc gateway mux matched to HttpMetrics and names the request span after it
+func GatewayRoute(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(req.Context()); ok {
+			if route, ok := req.Context().Value(routeKey{}).(*string); ok {
+				*route = pattern.String()
+			}
+
+			span := trace.SpanFromContext(req.Context())
+			span.SetName(req.Method + " " + pattern.String())
+			span.SetAttributes(attribute.String("http.route", pattern.String()))
+		}
+
+		next(res, req, pathParams)
+	}
+}
+
+func httpMuxRoute(pattern string) string {
+	if pattern == "" {
+		return unmatchedRoute
+	}
+
+	// drop the method of patterns like "GET /oauth/jwks", it already is a label of its own
+	if _, path, found := strings.Cut(pattern, " "); found {
+		return path
+	}
+
+	return pattern
+}
+
+// GrpcStreamMetrics does the same as GrpcMetrics for streaming rpcs, the latency is how long the stream stayed open
+func GrpcStreamMetrics(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+	err := handler(srv, stream)
+
+	statusCode := codes.Unknown
+	if st, ok := status.FromError(err); ok {
+		statusCode = st.Code()
+	}
+
+	metrics.ObserveGRPCRequest(info.FullMethod, statusCode, time.Since(startTime))
+
+	return err
+}
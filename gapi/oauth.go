@@ -0,0 +1,703 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	oauthCodeDuration = 5 * time.Minute
+	maxOAuthBodyBytes = 1 << 20
+)
+
+// OAuthHandler serves the OAuth2 authorization server and OpenID Connect endpoints
+// that let third-party apps act on behalf of a user after the user has consented.
+func (server *Server) OAuthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", server.getOpenIDConfiguration)
+	mux.HandleFunc("GET /oauth/jwks", server.getOAuthJWKS)
+	mux.HandleFunc("POST /oauth/clients", server.registerOAuthClient)
+	mux.HandleFunc("GET /oauth/authorize", server.getOAuthAuthorization)
+	mux.HandleFunc("POST /oauth/authorize", server.approveOAuthAuthorization)
+	mux.HandleFunc("POST /oauth/token", server.exchangeOAuthToken)
+	mux.HandleFunc("GET /oauth/userinfo", server.getOAuthUserInfo)
+	mux.HandleFunc("POST /oauth/userinfo", server.getOAuthUserInfo)
+	mux.HandleFunc("GET /oauth/consents", server.listOAuthConsents)
+	mux.HandleFunc("DELETE /oauth/consents/{client_id}", server.revokeOAuthConsent)
+	return mux
+}
+
+// oauthError is an error response as described in RFC 6749 section 5.2
+type oauthError struct {
+	status      int
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *oauthError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func newOAuthError(status int, code string, format string, args ...any) error {
+	return &oauthError{
+		status:      status,
+		Code:        code,
+		Description: fmt.Sprintf(format, args...),
+	}
+}
+
+// writeOAuthError writes the error the way rfc 6749 describes, errors that are not oauth errors
+// are logged and their details hidden from the client as the error model of the api does
+func writeOAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	var oauthErr *oauthError
+	if !errors.As(err, &oauthErr) {
+		requestID := util.RequestIDFromContext(r.Context())
+		log.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("path", r.URL.Path).
+			Msg("oauth request failed")
+
+		oauthErr = &oauthError{
+			status:      http.StatusInternalServerError,
+			Code:        "server_error",
+			Description: util.InternalErrorMessage,
+		}
+	}
+
+	if oauthErr.status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="%s"`, oauthErr.Code))
+	}
+
+	writeJSON(w, oauthErr.status, oauthErr)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows)
+}
+
+// authorizeHTTPUser authenticates a plain http request the same way grpc calls are authenticated
+func (server *Server) authorizeHTTPUser(r *http.Request, scopes ...string) (*token.Payload, error) {
+	md := metadata.Pairs(
+		authorizationHeader, r.Header.Get("Authorization"),
+		userAgent, r.UserAgent(),
+		xForwardedFor, r.RemoteAddr,
+	)
+
+	payload, err := server.authorizeUser(metadata.NewIncomingContext(r.Context(), md), scopes...)
+	if err != nil {
+		return nil, newOAuthError(http.StatusUnauthorized, "invalid_token", "%s", err)
+	}
+
+	return payload, nil
+}
+
+func (server *Server) oauthIssuer() string {
+	if server.config.OAuthIssuer != "" {
+		return strings.TrimSuffix(server.config.OAuthIssuer, "/")
+	}
+	return "http://" + server.config.HttpServerAddress
+}
+
+type openIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	RegistrationEndpoint              string   `json:"registration_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+func (server *Server) getOpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	issuer := server.oauthIssuer()
+
+	writeJSON(w, http.StatusOK, openIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		UserinfoEndpoint:                  issuer + "/oauth/userinfo",
+		JwksURI:                           issuer + "/oauth/jwks",
+		RegistrationEndpoint:              issuer + "/oauth/clients",
+		ScopesSupported:                   token.OAuthScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwt.SigningMethodEdDSA.Alg()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{token.CodeChallengeMethodS256},
+		ClaimsSupported:                   []string{"sub", "name", "email", "email_verified", "nonce"},
+	})
+}
+
+func (server *Server) getOAuthJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]token.JSONWebKey{
+		"keys": {server.idTokens.PublicKey()},
+	})
+}
+
+type registerOAuthClientRequest struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+	// public clients such as mobile apps cannot keep a secret and rely on pkce only
+	Public bool `json:"public"`
+}
+
+type oauthClientResponse struct {
+	ClientID     uuid.UUID `json:"client_id"`
+	ClientSecret string    `json:"client_secret,omitempty"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (server *Server) registerOAuthClient(w http.ResponseWriter, r *http.Request) {
+	authPayload, err := server.authorizeHTTPUser(r)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	var req registerOAuthClientRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOAuthBodyBytes)).Decode(&req); err != nil {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_client_metadata", "cannot decode request: %s", err))
+		return
+	}
+
+	if err := validateRegisterOAuthClientRequest(&req); err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	arg := db.CreateOauthClientParams{
+		ID:           uuid.New(),
+		Owner:        authPayload.Username,
+		Name:         req.Name,
+		RedirectUris: req.RedirectURIs,
+		Scopes:       req.Scopes,
+	}
+
+	var secret string
+	if !req.Public {
+		secret, arg.HashedSecret, err = token.GenerateOAuthSecret()
+		if err != nil {
+			writeOAuthError(w, r, err)
+			return
+		}
+	}
+
+	client, err := server.store.CreateOauthClient(r.Context(), arg)
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot create oauth client: %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, oauthClientResponse{
+		ClientID:     client.ID,
+		ClientSecret: secret,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectUris,
+		Scopes:       client.Scopes,
+		CreatedAt:    client.CreatedAt,
+	})
+}
+
+func validateRegisterOAuthClientRequest(req *registerOAuthClientRequest) error {
+	if req.Name == "" || len(req.Name) > 64 {
+		return newOAuthError(http.StatusBadRequest, "invalid_client_metadata", "name must contain 1-64 characters")
+	}
+
+	if len(req.RedirectURIs) == 0 {
+		return newOAuthError(http.StatusBadRequest, "invalid_redirect_uri", "at least one redirect uri is required")
+	}
+
+	for _, redirectURI := range req.RedirectURIs {
+		if err := validateRedirectURI(redirectURI); err != nil {
+			return newOAuthError(http.StatusBadRequest, "invalid_redirect_uri", "%s: %s", redirectURI, err)
+		}
+	}
+
+	if len(req.Scopes) == 0 {
+		return newOAuthError(http.StatusBadRequest, "invalid_client_metadata", "at least one scope is required")
+	}
+
+	for _, scope := range req.Scopes {
+		if !slices.Contains(token.OAuthScopes, scope) {
+			return newOAuthError(http.StatusBadRequest, "invalid_client_metadata", "unknown scope: %s", scope)
+		}
+	}
+
+	return nil
+}
+
+// validateRedirectURI only allows https redirects, plain http is accepted for local development
+func validateRedirectURI(redirectURI string) error {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return err
+	}
+
+	if u.Fragment != "" {
+		return fmt.Errorf("must not contain a fragment")
+	}
+
+	switch {
+	case u.Scheme == "https" && u.Host != "":
+		return nil
+	case u.Scheme == "http" && (u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1"):
+		return nil
+	default:
+		return fmt.Errorf("must be an absolute https url")
+	}
+}
+
+type oauthAuthorizationRequest struct {
+	client        db.OauthClient
+	redirectURI   string
+	scopes        []string
+	state         string
+	nonce         string
+	codeChallenge string
+}
+
+func (server *Server) parseOAuthAuthorizationRequest(ctx context.Context, values url.Values) (*oauthAuthorizationRequest, error) {
+	if values.Get("response_type") != "code" {
+		return nil, newOAuthError(http.StatusBadRequest, "unsupported_response_type", "only the code response type is supported")
+	}
+
+	clientID, err := uuid.Parse(values.Get("client_id"))
+	if err != nil {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_request", "invalid client_id")
+	}
+
+	client, err := server.store.GetOauthClient(ctx, clientID)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, newOAuthError(http.StatusBadRequest, "invalid_request", "unknown client_id")
+		}
+		return nil, fmt.Errorf("cannot get oauth client: %w", err)
+	}
+
+	// the redirect uri must match a registered one exactly, otherwise codes could leak to an attacker
+	redirectURI := values.Get("redirect_uri")
+	if !slices.Contains(client.RedirectUris, redirectURI) {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_request", "redirect_uri is not registered for the client")
+	}
+
+	var scopes []string
+	for _, scope := range strings.Fields(values.Get("scope")) {
+		if !slices.Contains(client.Scopes, scope) {
+			return nil, newOAuthError(http.StatusBadRequest, "invalid_scope", "scope is not allowed for the client: %s", scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_scope", "at least one scope is required")
+	}
+
+	codeChallenge := values.Get("code_challenge")
+	if codeChallenge == "" || values.Get("code_challenge_method") != token.CodeChallengeMethodS256 {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_request", "pkce with the S256 method is required")
+	}
+
+	return &oauthAuthorizationRequest{
+		client:        client,
+		redirectURI:   redirectURI,
+		scopes:        scopes,
+		state:         values.Get("state"),
+		nonce:         values.Get("nonce"),
+		codeChallenge: codeChallenge,
+	}, nil
+}
+
+type oauthAuthorizationResponse struct {
+	ClientID        uuid.UUID `json:"client_id"`
+	ClientName      string    `json:"client_name"`
+	Scopes          []string  `json:"scopes"`
+	ConsentRequired bool      `json:"consent_required"`
+}
+
+// getOAuthAuthorization tells the bank's frontend which app is asking for what,
+// so it can show the consent screen or skip it when the user already agreed.
+func (server *Server) getOAuthAuthorization(w http.ResponseWriter, r *http.Request) {
+	authPayload, err := server.authorizeHTTPUser(r)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	req, err := server.parseOAuthAuthorizationRequest(r.Context(), r.URL.Query())
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	consentRequired := true
+	consent, err := server.store.GetOauthConsent(r.Context(), db.GetOauthConsentParams{
+		Username: authPayload.Username,
+		ClientID: req.client.ID,
+	})
+	if err == nil {
+		consentRequired = slices.ContainsFunc(req.scopes, func(scope string) bool {
+			return !slices.Contains(consent.Scopes, scope)
+		})
+	} else if !isNoRows(err) {
+		writeOAuthError(w, r, fmt.Errorf("cannot get oauth consent: %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, oauthAuthorizationResponse{
+		ClientID:        req.client.ID,
+		ClientName:      req.client.Name,
+		Scopes:          req.scopes,
+		ConsentRequired: consentRequired,
+	})
+}
+
+type oauthRedirectResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// approveOAuthAuthorization records the user's decision and returns where the user agent should be sent next
+func (server *Server) approveOAuthAuthorization(w http.ResponseWriter, r *http.Request) {
+	authPayload, err := server.authorizeHTTPUser(r)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxOAuthBodyBytes)
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_request", "cannot parse form: %s", err))
+		return
+	}
+
+	req, err := server.parseOAuthAuthorizationRequest(r.Context(), r.Form)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	redirectTo, err := url.Parse(req.redirectURI)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	query := redirectTo.Query()
+	if req.state != "" {
+		query.Set("state", req.state)
+	}
+
+	if r.Form.Get("approve") != "true" {
+		query.Set("error", "access_denied")
+		redirectTo.RawQuery = query.Encode()
+		writeJSON(w, http.StatusOK, oauthRedirectResponse{RedirectTo: redirectTo.String()})
+		return
+	}
+
+	_, err = server.store.UpsertOauthConsent(r.Context(), db.UpsertOauthConsentParams{
+		Username: authPayload.Username,
+		ClientID: req.client.ID,
+		Scopes:   req.scopes,
+	})
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot save oauth consent: %w", err))
+		return
+	}
+
+	code, codeHash, err := token.GenerateOAuthSecret()
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	_, err = server.store.CreateOauthAuthorizationCode(r.Context(), db.CreateOauthAuthorizationCodeParams{
+		CodeHash:      codeHash,
+		ClientID:      req.client.ID,
+		Username:      authPayload.Username,
+		RedirectUri:   req.redirectURI,
+		Scopes:        req.scopes,
+		CodeChallenge: req.codeChallenge,
+		Nonce:         req.nonce,
+		ExpiresAt:     time.Now().Add(oauthCodeDuration),
+	})
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot create authorization code: %w", err))
+		return
+	}
+
+	query.Set("code", code)
+	redirectTo.RawQuery = query.Encode()
+
+	writeJSON(w, http.StatusOK, oauthRedirectResponse{RedirectTo: redirectTo.String()})
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+	IDToken     string `json:"id_token,omitempty"`
+}
+
+// exchangeOAuthToken trades an authorization code for an access token limited to the consented scopes
+func (server *Server) exchangeOAuthToken(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxOAuthBodyBytes)
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_request", "cannot parse form: %s", err))
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "unsupported_grant_type", "only the authorization_code grant is supported"))
+		return
+	}
+
+	client, err := server.authenticateOAuthClient(r)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	code, err := server.store.UseOauthAuthorizationCode(r.Context(), token.HashOAuthSecret(r.PostForm.Get("code")))
+	if err != nil {
+		if isNoRows(err) {
+			writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_grant", "authorization code is invalid, expired or already used"))
+			return
+		}
+		writeOAuthError(w, r, fmt.Errorf("cannot use authorization code: %w", err))
+		return
+	}
+
+	if code.ClientID != client.ID || code.RedirectUri != r.PostForm.Get("redirect_uri") {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_grant", "authorization code was issued to another client or redirect_uri"))
+		return
+	}
+
+	if !token.VerifyCodeChallenge(r.PostForm.Get("code_verifier"), code.CodeChallenge) {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_grant", "code_verifier does not match the code challenge"))
+		return
+	}
+
+	// delegated tokens never carry banker privileges, whatever the user's role is
+	accessToken, accessPayload, err := server.tokenMaker.CreateScopedToken(
+		code.Username,
+		util.DepositorRole,
+		client.ID.String(),
+		code.Scopes,
+		server.config.ACCESS_TOKEN_DURATION,
+	)
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot create access token: %w", err))
+		return
+	}
+
+	res := oauthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(accessPayload.ExpiresAt).Seconds()),
+		Scope:       strings.Join(code.Scopes, " "),
+	}
+
+	if slices.Contains(code.Scopes, token.ScopeOpenID) {
+		user, err := server.store.GetUserByUsername(r.Context(), code.Username)
+		if err != nil {
+			writeOAuthError(w, r, fmt.Errorf("cannot get user: %w", err))
+			return
+		}
+
+		userInfo := newOAuthUserInfo(user, code.Scopes)
+		res.IDToken, err = server.idTokens.SignIDToken(token.IDTokenClaims{
+			Nonce:         code.Nonce,
+			Name:          userInfo.Name,
+			Email:         userInfo.Email,
+			EmailVerified: userInfo.EmailVerified,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:   server.oauthIssuer(),
+				Subject:  userInfo.Subject,
+				Audience: jwt.ClaimStrings{client.ID.String()},
+			},
+		}, server.config.ACCESS_TOKEN_DURATION)
+		if err != nil {
+			writeOAuthError(w, r, fmt.Errorf("cannot create id token: %w", err))
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// authenticateOAuthClient accepts client_secret_basic, client_secret_post,
+// and no secret at all for public clients which are protected by pkce instead.
+func (server *Server) authenticateOAuthClient(r *http.Request) (db.OauthClient, error) {
+	rawClientID, secret, ok := r.BasicAuth()
+	if !ok {
+		rawClientID = r.PostForm.Get("client_id")
+		secret = r.PostForm.Get("client_secret")
+	}
+
+	clientID, err := uuid.Parse(rawClientID)
+	if err != nil {
+		return db.OauthClient{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "invalid client_id")
+	}
+
+	client, err := server.store.GetOauthClient(r.Context(), clientID)
+	if err != nil {
+		if isNoRows(err) {
+			return db.OauthClient{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "unknown client_id")
+		}
+		return db.OauthClient{}, fmt.Errorf("cannot get oauth client: %w", err)
+	}
+
+	if client.HashedSecret != "" && !token.CheckOAuthSecret(secret, client.HashedSecret) {
+		return db.OauthClient{}, newOAuthError(http.StatusUnauthorized, "invalid_client", "client authentication failed")
+	}
+
+	return client, nil
+}
+
+type oauthUserInfo struct {
+	Subject       string `json:"sub"`
+	Name          string `json:"name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+}
+
+// newOAuthUserInfo only discloses the claims covered by the granted scopes
+func newOAuthUserInfo(user db.User, scopes []string) oauthUserInfo {
+	pbUser := convertUser(user)
+	userInfo := oauthUserInfo{
+		Subject: pbUser.GetUsername(),
+	}
+
+	if slices.Contains(scopes, token.ScopeProfile) {
+		userInfo.Name = pbUser.GetFullName()
+	}
+
+	if slices.Contains(scopes, token.ScopeEmail) {
+		// only the link sent to the email verifies it, and changing the email unverifies it, so relying parties may trust the claim
+		emailVerified := user.EmailVerifiedAt.Valid
+		userInfo.Email = pbUser.GetEmail()
+		userInfo.EmailVerified = &emailVerified
+	}
+
+	return userInfo
+}
+
+func (server *Server) getOAuthUserInfo(w http.ResponseWriter, r *http.Request) {
+	authPayload, err := server.authorizeHTTPUser(r, token.ScopeOpenID)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(r.Context(), authPayload.Username)
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot get user: %w", err))
+		return
+	}
+
+	// the user's own tokens are not limited by any consent
+	scopes := authPayload.Scopes
+	if !authPayload.IsDelegated() {
+		scopes = token.OAuthScopes
+	}
+
+	writeJSON(w, http.StatusOK, newOAuthUserInfo(user, scopes))
+}
+
+type oauthConsentResponse struct {
+	ClientID  uuid.UUID `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (server *Server) listOAuthConsents(w http.ResponseWriter, r *http.Request) {
+	authPayload, err := server.authorizeHTTPUser(r)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	consents, err := server.store.ListOauthConsents(r.Context(), authPayload.Username)
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot list oauth consents: %w", err))
+		return
+	}
+
+	res := make([]oauthConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		res = append(res, oauthConsentResponse{
+			ClientID:  consent.ClientID,
+			Scopes:    consent.Scopes,
+			CreatedAt: consent.CreatedAt,
+			UpdatedAt: consent.UpdatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// revokeOAuthConsent stops the app from getting new tokens, tokens already issued stay valid until they expire
+func (server *Server) revokeOAuthConsent(w http.ResponseWriter, r *http.Request) {
+	authPayload, err := server.authorizeHTTPUser(r)
+	if err != nil {
+		writeOAuthError(w, r, err)
+		return
+	}
+
+	clientID, err := uuid.Parse(r.PathValue("client_id"))
+	if err != nil {
+		writeOAuthError(w, r, newOAuthError(http.StatusBadRequest, "invalid_request", "invalid client_id"))
+		return
+	}
+
+	err = server.store.DeleteOauthConsent(r.Context(), db.DeleteOauthConsentParams{
+		Username: authPayload.Username,
+		ClientID: clientID,
+	})
+	if err != nil {
+		writeOAuthError(w, r, fmt.Errorf("cannot delete oauth consent: %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "consent revoked"})
+}
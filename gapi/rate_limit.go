@@ -0,0 +1,140 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/AnkitNayan83/houseBank/ratelimit"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimit rejects the rpcs over the policies of their full method with ResourceExhausted.
+// Users are identified by their bearer token, rpcs without a valid one are counted by ip.
+func (server *Server) RateLimit(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		client := ratelimit.Client{
+			IP:       peerIP(ctx),
+			Username: server.bearerUsername(ctx),
+		}
+
+		result, ok := limiter.Allow(ctx, info.FullMethod, client)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		header := metadata.MD{}
+		for key, value := range result.Headers() {
+			header.Set(strings.ToLower(key), value)
+		}
+		_ = grpc.SetHeader(ctx, header)
+
+		if !result.Allowed {
+			return nil, rateLimitError(result)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// GatewayRateLimit does the same as RateLimit for the gateway, by method and route template
+func (server *Server) GatewayRateLimit(limiter *ratelimit.Limiter) runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(res http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+			pattern, ok := runtime.HTTPPattern(req.Context())
+			if !ok {
+				next(res, req, pathParams)
+				return
+			}
+
+			host, _, err := net.SplitHostPort(req.RemoteAddr)
+			if err != nil {
+				host = req.RemoteAddr
+			}
+
+			client := ratelimit.Client{
+				IP:       host,
+				Username: server.tokenUsername(req.Header.Get(authorizationHeader)),
+			}
+
+			result, ok := limiter.Allow(req.Context(), req.Method+" "+pattern.String(), client)
+			if !ok {
+				next(res, req, pathParams)
+				return
+			}
+
+			for key, value := range result.Headers() {
+				res.Header().Set(key, value)
+			}
+
+			if !result.Allowed {
+				GatewayErrorHandler(req.Context(), nil, nil, res, req, rateLimitError(result))
+				return
+			}
+
+			next(res, req, pathParams)
+		}
+	}
+}
+
+func rateLimitError(result ratelimit.Result) error {
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+
+	withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(result.RetryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+
+	return withRetry.Err()
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// bearerUsername returns the user of a valid bearer token in the metadata, or an empty string
+func (server *Server) bearerUsername(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return server.tokenUsername(values[0])
+}
+
+func (server *Server) tokenUsername(authHeader string) string {
+	fields := strings.Fields(authHeader)
+	if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationBearer {
+		return ""
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(fields[1])
+	if err != nil {
+		return ""
+	}
+
+	return payload.Username
+}
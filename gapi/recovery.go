@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcRecovery turns a panicking handler into an Internal error instead of crashing the server
+func GrpcRecovery(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Str("request_id", util.RequestIDFromContext(ctx)).
+				Str("method", info.FullMethod).
+				Interface("panic", r).
+				Bytes("stack", debug.Stack()).
+				Msg("recovered from panic in grpc handler")
+
+			err = status.Errorf(codes.Internal, "panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// GrpcStreamRecovery does the same as GrpcRecovery for streaming rpcs
+func GrpcStreamRecovery(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Str("request_id", util.RequestIDFromContext(stream.Context())).
+				Str("method", info.FullMethod).
+				Interface("panic", r).
+				Bytes("stack", debug.Stack()).
+				Msg("recovered from panic in grpc handler")
+
+			err = status.Errorf(codes.Internal, "panic: %v", r)
+		}
+	}()
+
+	return handler(srv, stream)
+}
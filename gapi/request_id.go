@@ -0,0 +1,76 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// GrpcRequestID keeps the request id sent in the metadata or creates one, and returns it in the response header.
+// It must be the first interceptor so every other one can log the id.
+func GrpcRequestID(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	var clientID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(util.RequestIDMetadataKey); len(values) > 0 {
+			clientID = values[0]
+		}
+	}
+
+	requestID := util.RequestID(clientID)
+	ctx = util.WithRequestID(ctx, requestID)
+
+	// the header is only sent with the response, failing to set it must not fail the rpc
+	_ = grpc.SetHeader(ctx, metadata.Pairs(util.RequestIDMetadataKey, requestID))
+
+	return handler(ctx, req)
+}
+
+// HttpRequestID does the same as GrpcRequestID for the gateway, the rpcs it calls read the id from the context
+func HttpRequestID(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requestID := util.RequestID(req.Header.Get(util.RequestIDHeader))
+
+		res.Header().Set(util.RequestIDHeader, requestID)
+		handler.ServeHTTP(res, req.WithContext(util.WithRequestID(req.Context(), requestID)))
+	})
+}
+
+// serverStream replaces the context of a grpc stream, stream interceptors have no other way to pass values to the handler
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *serverStream) Context() context.Context {
+	return stream.ctx
+}
+
+// GrpcStreamRequestID does the same as GrpcRequestID for streaming rpcs
+func GrpcStreamRequestID(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx := stream.Context()
+
+	var clientID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(util.RequestIDMetadataKey); len(values) > 0 {
+			clientID = values[0]
+		}
+	}
+
+	requestID := util.RequestID(clientID)
+	_ = stream.SetHeader(metadata.Pairs(util.RequestIDMetadataKey, requestID))
+
+	return handler(srv, &serverStream{ServerStream: stream, ctx: util.WithRequestID(ctx, requestID)})
+}
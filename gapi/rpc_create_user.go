@@ -9,7 +9,6 @@ import (
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/AnkitNayan83/houseBank/validators"
 	"github.com/AnkitNayan83/houseBank/workers"
-	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgconn"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -39,19 +38,18 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 
 	createUserTxPayload := db.CreateUserTxParams{
 		CreateUserParams: arg,
-		AfterCreateUser: func(user db.User) error {
-			// Send verification email task to the queue
+		AfterCreateUser: func(user db.User) ([]db.CreateOutboxMessageParams, error) {
+			// Send verification email task to the queue once the user is committed
 			taskPayload := &workers.PayloadSendVerifyEmail{
 				Username: user.Username,
 			}
 
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				// asynq.ProcessIn(10 * time.Second), for testing
-				asynq.Queue(workers.QueueueCritical),
+			message, err := workers.SendVerifyEmail.OutboxMessage(ctx, taskPayload)
+			if err != nil {
+				return nil, err
 			}
 
-			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+			return []db.CreateOutboxMessageParams{message}, nil
 		},
 	}
 
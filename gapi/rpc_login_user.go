@@ -10,7 +10,6 @@ import (
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/AnkitNayan83/houseBank/validators"
 	"github.com/AnkitNayan83/houseBank/workers"
-	"github.com/hibiken/asynq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -45,11 +44,7 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		taskPayload := &workers.PayloadSendVerifyEmail{
 			Username: user.Username,
 		}
-		opts := []asynq.Option{
-			asynq.MaxRetry(10),
-			asynq.Queue(workers.QueueueCritical),
-		}
-		err := server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+		err := workers.SendVerifyEmail.Enqueue(ctx, server.taskDistributor, taskPayload)
 
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "cannot send verification email: %v", err)
@@ -58,13 +53,13 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		return nil, status.Errorf(codes.Unauthenticated, "user is not verified, verification email sent")
 	}
 
-	accessToken, accessTokenPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.ACCESS_TOKEN_DURATION)
+	accessToken, accessTokenPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.ACCESS_TOKEN_DURATION)
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot create access token: %v", err)
 	}
 
-	refreshToken, refreshTokenPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.REFRESH_TOKEN_DURATION)
+	refreshToken, refreshTokenPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.REFRESH_TOKEN_DURATION)
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot create refresh token: %v", err)
@@ -8,19 +8,19 @@ import (
 	"github.com/AnkitNayan83/houseBank/pb"
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/AnkitNayan83/houseBank/validators"
+	"github.com/AnkitNayan83/houseBank/workers"
 	"github.com/jackc/pgx/v5/pgconn"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (res *pb.UpdateUserResponse, err error) {
+// errEmailVerifiedAtSet refuses to verify an email on request, only the link sent to the email verifies it
+var errEmailVerifiedAtSet = errors.New("cannot be set, it is set by the link sent to the email")
 
-	authPayload, err := server.authorizeUser(ctx)
+func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (res *pb.UpdateUserResponse, err error) {
 
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated: %v", err)
-	}
+	authPayload := authPayloadFromContext(ctx)
 
 	violations := validateUpdateUserRequest(req)
 
@@ -32,14 +32,26 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 		return nil, status.Error(codes.PermissionDenied, "user does not have permission to update other user")
 	}
 
-	arg := db.UpdateUserParams{
-		Username:        util.NewPgText(req.GetUsername()),
-		FullName:        util.NewPgText(req.GetFullName()),
-		Email:           util.NewPgText(req.GetEmail()),
-		EmailVerifiedAt: util.NewPgTime(req.GetEmailVerifiedAt().AsTime()),
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username: util.NewPgText(req.GetUsername()),
+			FullName: util.NewPgText(req.GetFullName()),
+			Email:    util.NewPgText(req.GetEmail()),
+		},
+		AfterEmailChange: func(user db.User) ([]db.CreateOutboxMessageParams, error) {
+			// the new email is verified with a link sent to it, once the update is committed
+			message, err := workers.SendVerifyEmail.OutboxMessage(ctx, &workers.PayloadSendVerifyEmail{
+				Username: user.Username,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return []db.CreateOutboxMessageParams{message}, nil
+		},
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
+	user, err := server.store.UpdateUserTx(ctx, arg)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -78,5 +90,9 @@ func validateUpdateUserRequest(req *pb.UpdateUserRequest) (violations []*errdeta
 		}
 	}
 
+	if req.EmailVerifiedAt != nil {
+		violations = append(violations, fieldViolation("email_verified_at", errEmailVerifiedAtSet))
+	}
+
 	return violations
 }
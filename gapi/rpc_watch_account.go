@@ -0,0 +1,239 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/accountnumber"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/pb"
+	"github.com/AnkitNayan83/houseBank/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// AccountActivityPath serves WatchAccount as server-sent events on the gateway
+	AccountActivityPath = "GET /v1/accounts/{account_number}/activity"
+
+	activityBatchSize = 100
+	// comments sent on idle event streams so proxies do not close them
+	activityKeepAlive = 30 * time.Second
+)
+
+func (server *Server) WatchAccount(req *pb.WatchAccountRequest, stream grpc.ServerStreamingServer[pb.AccountActivity]) error {
+	ctx := stream.Context()
+
+	violations := validateWatchAccountRequest(req)
+	if violations != nil {
+		return invalidArgumentError(violations)
+	}
+
+	account, err := server.authorizeAccount(ctx, authPayloadFromContext(ctx), req.GetAccountNumber())
+	if err != nil {
+		return err
+	}
+
+	return server.watchAccount(ctx, account, req.GetAfterEntryId(), stream.Send, nil)
+}
+
+// AccountActivityHandler serves WatchAccount to browsers as server-sent events, which the gateway cannot do for streaming rpcs.
+// Every event carries the entry id, so a reconnecting EventSource resumes with the Last-Event-ID header.
+func (server *Server) AccountActivityHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := metadata.Pairs(
+			authorizationHeader, r.Header.Get("Authorization"),
+			userAgent, r.UserAgent(),
+			xForwardedFor, r.RemoteAddr,
+		)
+
+		ctx, err := server.authorizeMethod(metadata.NewIncomingContext(r.Context(), md), pb.HouseBank_WatchAccount_FullMethodName)
+		if err != nil {
+			GatewayErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+
+		req, err := parseAccountActivityRequest(r)
+		if err != nil {
+			GatewayErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+
+		account, err := server.authorizeAccount(ctx, authPayloadFromContext(ctx), req.GetAccountNumber())
+		if err != nil {
+			GatewayErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+
+		controller := http.NewResponseController(w)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		if err := controller.Flush(); err != nil {
+			return
+		}
+
+		send := func(activity *pb.AccountActivity) error {
+			data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(activity)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: activity\ndata: %s\n\n", activity.GetEntryId(), data); err != nil {
+				return err
+			}
+
+			return controller.Flush()
+		}
+
+		keepAlive := func() error {
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return err
+			}
+
+			return controller.Flush()
+		}
+
+		// the response has started, errors can only end the stream
+		_ = server.watchAccount(r.Context(), account, req.GetAfterEntryId(), send, keepAlive)
+	})
+}
+
+func parseAccountActivityRequest(r *http.Request) (*pb.WatchAccountRequest, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	req := &pb.WatchAccountRequest{}
+
+	req.AccountNumber = r.PathValue("account_number")
+
+	afterEntryID := r.Header.Get("Last-Event-ID")
+	if afterEntryID == "" {
+		afterEntryID = r.URL.Query().Get("after_entry_id")
+	}
+
+	if afterEntryID != "" {
+		var err error
+		req.AfterEntryId, err = strconv.ParseInt(afterEntryID, 10, 64)
+		if err != nil {
+			violations = append(violations, fieldViolation("after_entry_id", fmt.Errorf("must be a number")))
+		}
+	}
+
+	violations = append(violations, validateWatchAccountRequest(req)...)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	return req, nil
+}
+
+func (server *Server) authorizeAccount(ctx context.Context, authPayload *token.Payload, accountNumber string) (db.Account, error) {
+	held, err := server.store.GetAccountForHolder(ctx, db.GetAccountForHolderParams{
+		Username: authPayload.Username,
+		Number:   accountNumber,
+	})
+	if err != nil {
+		if isNoRows(err) {
+			return db.Account{}, status.Errorf(codes.NotFound, "account not found")
+		}
+
+		return db.Account{}, status.Errorf(codes.Internal, "cannot get account: %v", err)
+	}
+
+	if !held.Allows(db.AccountAccessView) {
+		return db.Account{}, status.Errorf(codes.PermissionDenied, "account isn't held by the authenticated user")
+	}
+
+	return held.Account, nil
+}
+
+// watchAccount sends the entries of the account after afterEntryID, then every new one as it is committed,
+// until ctx is done or the activity hub stops. keepAlive may be nil.
+func (server *Server) watchAccount(
+	ctx context.Context,
+	account db.Account,
+	afterEntryID int64,
+	send func(*pb.AccountActivity) error,
+	keepAlive func() error,
+) error {
+	// subscribe before reading the entries, so one committed in between still wakes the loop up
+	wake, unsubscribe := server.activity.Subscribe(account.ID)
+	defer unsubscribe()
+
+	if afterEntryID == 0 {
+		lastEntryID, err := server.store.GetLastEntryId(ctx, account.ID)
+		if err != nil {
+			return status.Errorf(codes.Internal, "cannot get last entry: %v", err)
+		}
+		afterEntryID = lastEntryID
+	}
+
+	var keepAliveTicks <-chan time.Time
+	if keepAlive != nil {
+		ticker := time.NewTicker(activityKeepAlive)
+		defer ticker.Stop()
+		keepAliveTicks = ticker.C
+	}
+
+	for {
+		rows, err := server.store.ListAccountActivity(ctx, db.ListAccountActivityParams{
+			AccountID:  account.ID,
+			AfterID:    afterEntryID,
+			LimitCount: activityBatchSize,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return status.Errorf(codes.Internal, "cannot list account activity: %v", err)
+		}
+
+		for _, row := range rows {
+			if err := send(convertAccountActivity(row, account)); err != nil {
+				return err
+			}
+			afterEntryID = row.ID
+		}
+
+		// a full batch means there may be more entries waiting
+		if len(rows) == activityBatchSize {
+			continue
+		}
+
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case _, ok := <-wake:
+				if !ok {
+					return status.Error(codes.Unavailable, "server is shutting down")
+				}
+				waiting = false
+			case <-keepAliveTicks:
+				if err := keepAlive(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func validateWatchAccountRequest(req *pb.WatchAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := accountnumber.Validate(req.GetAccountNumber()); err != nil {
+		violations = append(violations, fieldViolation("account_number", err))
+	}
+
+	if req.GetAfterEntryId() < 0 {
+		violations = append(violations, fieldViolation("after_entry_id", fmt.Errorf("must not be negative")))
+	}
+
+	return violations
+}
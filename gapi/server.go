@@ -3,6 +3,7 @@ package gapi
 import (
 	"fmt"
 
+	"github.com/AnkitNayan83/houseBank/activity"
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
 	"github.com/AnkitNayan83/houseBank/pb"
 	"github.com/AnkitNayan83/houseBank/token"
@@ -16,18 +17,33 @@ type Server struct {
 	tokenMaker      token.Maker
 	config          util.Config
 	taskDistributor workers.TaskDistributor
+	revocations     token.RevocationList
+	apiKeys         token.APIKeyVerifier
+	idTokens        *token.IDTokenSigner
+	methodAuth      *methodAuth
+	activity        *activity.Hub
 }
 
-func NewServer(store db.Store, config util.Config, taskDistributor workers.TaskDistributor) (*Server, error) {
+func NewServer(store db.Store, config util.Config, taskDistributor workers.TaskDistributor, revocations token.RevocationList, activity *activity.Hub) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TOKEN_SYMMETRIC_KEY)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %v", err)
 	}
+
+	idTokens, err := token.NewIDTokenSigner(config.TOKEN_SYMMETRIC_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create id token signer: %v", err)
+	}
 	server := &Server{
 		store:           store,
 		tokenMaker:      tokenMaker,
 		config:          config,
 		taskDistributor: taskDistributor,
+		revocations:     revocations,
+		apiKeys:         token.NewStoreAPIKeyVerifier(store),
+		idTokens:        idTokens,
+		methodAuth:      newMethodAuth(pb.File_service_house_bank_proto.Services().Get(0)),
+		activity:        activity,
 	}
 
 	return server, nil
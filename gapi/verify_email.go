@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VerifyEmailPath serves the link sent by the send verify email task, using it is the only way to verify an email
+const VerifyEmailPath = "GET /v1/verify_email"
+
+type verifyEmailResponse struct {
+	IsVerified bool `json:"is_verified"`
+}
+
+// VerifyEmailHandler verifies the email of a user with the id and the secret code of the link sent to it
+func (server *Server) VerifyEmailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arg, err := parseVerifyEmailRequest(r)
+		if err != nil {
+			GatewayErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+
+		_, err = server.store.VerifyEmailTx(r.Context(), arg)
+		if err != nil {
+			if errors.Is(err, db.ErrInvalidVerifyEmail) {
+				err = status.Error(codes.NotFound, err.Error())
+			} else {
+				err = status.Errorf(codes.Internal, "cannot verify email: %v", err)
+			}
+			GatewayErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, verifyEmailResponse{IsVerified: true})
+	})
+}
+
+func parseVerifyEmailRequest(r *http.Request) (db.VerifyEmailTxParams, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	query := r.URL.Query()
+
+	emailID, err := strconv.ParseInt(query.Get("email_id"), 10, 64)
+	if err != nil || emailID < 1 {
+		violations = append(violations, fieldViolation("email_id", fmt.Errorf("must be a positive number")))
+	}
+
+	secretCode := query.Get("secret_code")
+	if secretCode == "" {
+		violations = append(violations, fieldViolation("secret_code", fmt.Errorf("is required")))
+	}
+
+	if violations != nil {
+		return db.VerifyEmailTxParams{}, invalidArgumentError(violations)
+	}
+
+	return db.VerifyEmailTxParams{
+		EmailID:          emailID,
+		HashedSecretCode: token.HashVerifyEmailCode(secretCode),
+	}, nil
+}
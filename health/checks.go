@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func PostgresCheck(pool *pgxpool.Pool) Check {
+	return func(ctx context.Context) error {
+		return pool.Ping(ctx)
+	}
+}
+
+func RedisCheck(client *asynq.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping()
+	}
+}
+
+// MigrationCheck fails while the database is not at the latest migration of the source, or is dirty
+func MigrationCheck(migration *migrate.Migrate, sourceURL string) (Check, error) {
+	latest, err := latestMigrationVersion(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		version, dirty, err := migration.Version()
+		if err != nil {
+			if errors.Is(err, migrate.ErrNilVersion) {
+				return fmt.Errorf("no migration applied, want version %d", latest)
+			}
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+
+		if version != latest {
+			return fmt.Errorf("database at migration %d, want %d", version, latest)
+		}
+
+		return nil
+	}, nil
+}
+
+func latestMigrationVersion(sourceURL string) (uint, error) {
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return 0, fmt.Errorf("cannot open migration source: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("cannot read first migration: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("cannot read migrations: %w", err)
+		}
+		version = next
+	}
+}
@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// WatchGRPC keeps the grpc.health.v1 service in line with the readiness checks until ctx is cancelled.
+// The empty service name reports the health of the whole server.
+func (checker *Checker) WatchGRPC(ctx context.Context, server *grpchealth.Server, interval time.Duration, services ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	services = append([]string{""}, services...)
+
+	for {
+		report := checker.Check(ctx)
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if !report.Healthy() {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			log.Warn().Interface("checks", report.Checks).Msg("server is not ready")
+		}
+
+		for _, service := range services {
+			server.SetServingStatus(service, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
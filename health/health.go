@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Check reports whether a dependency can be used, a nil error means it is healthy
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the readiness checks of every dependency the servers need
+type Checker struct {
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a dependency check, it must be called before the checker is used
+func (checker *Checker) Register(name string, check Check) {
+	checker.checks = append(checker.checks, namedCheck{name: name, check: check})
+}
+
+// Report tells which dependency is failing and why
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (report Report) Healthy() bool {
+	return report.Status == StatusOK
+}
+
+// Check runs every check concurrently, each one is bounded by the checker timeout
+func (checker *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]string, len(checker.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, c := range checker.checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+
+			result := StatusOK
+			if err := c.check(ctx); err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[c.name] = result
+			if result != StatusOK {
+				report.Status = StatusFail
+			}
+		}(c)
+	}
+
+	wg.Wait()
+	return report
+}
+
+// Wrap serves the liveness and readiness endpoints in front of the next handler
+func (checker *Checker) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LivenessPath:
+			// the process is up and serving, dependencies are not looked at
+			writeReport(w, Report{Status: StatusOK, Checks: map[string]string{}})
+		case ReadinessPath:
+			writeReport(w, checker.Check(r.Context()))
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Register("postgres", func(ctx context.Context) error {
+		return nil
+	})
+
+	report := checker.Check(context.Background())
+	require.True(t, report.Healthy())
+	require.Equal(t, StatusOK, report.Checks["postgres"])
+
+	checker.Register("redis", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	checker.Register("migrations", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report = checker.Check(context.Background())
+	require.False(t, report.Healthy())
+	require.Equal(t, StatusOK, report.Checks["postgres"])
+	require.Equal(t, "connection refused", report.Checks["redis"])
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks["migrations"])
+}
+
+func TestWrap(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register("redis", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	handler := checker.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	testCases := []struct {
+		name          string
+		path          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Liveness",
+			path: LivenessPath,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Readiness",
+			path: ReadinessPath,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				var report Report
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+				require.Equal(t, StatusFail, report.Status)
+				require.Equal(t, "connection refused", report.Checks["redis"])
+			},
+		},
+		{
+			name: "OtherPath",
+			path: "/accounts",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTeapot, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
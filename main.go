@@ -2,14 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/AnkitNayan83/houseBank/activity"
 	"github.com/AnkitNayan83/houseBank/api"
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
 	"github.com/AnkitNayan83/houseBank/gapi"
+	"github.com/AnkitNayan83/houseBank/health"
+	"github.com/AnkitNayan83/houseBank/metrics"
 	"github.com/AnkitNayan83/houseBank/pb"
+	"github.com/AnkitNayan83/houseBank/ratelimit"
+	"github.com/AnkitNayan83/houseBank/token"
+	"github.com/AnkitNayan83/houseBank/tracing"
 	"github.com/AnkitNayan83/houseBank/util"
 	"github.com/AnkitNayan83/houseBank/workers"
 	"github.com/gin-gonic/gin"
@@ -17,10 +28,17 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rakyll/statik/fs"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -29,6 +47,18 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+const (
+	healthCheckTimeout  = 2 * time.Second
+	healthCheckInterval = 5 * time.Second
+	// how soon a currency a banker changed on another instance is picked up
+	currencyReloadInterval = time.Minute
+)
+
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+}
+
 func main() {
 	config, err := util.LoadConfig(".")
 	if err != nil {
@@ -39,32 +69,118 @@ func main() {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
-	conn, err := pgxpool.New(context.Background(), config.DBSource)
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+
+	shutdownTracing, err := tracing.Init(ctx, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot set up tracing:")
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(config.DBSource)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot parse db source:")
+	}
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	conn, err := pgxpool.NewWithConfig(ctx, poolConfig)
 
 	if err != nil {
 		log.Fatal().Err(err).Msg("Cannot connect to db:")
 	}
 
 	// Run db migrations
-	runDbMigration(config.MigrationURL, config.DBSource)
+	migration := runDbMigration(config.MigrationURL, config.DBSource)
 
 	store := db.NewStore(conn)
 
+	// the validators must know the enabled currencies before the first request
+	if err := db.LoadCurrencyRegistry(ctx, store); err != nil {
+		log.Fatal().Err(err).Msg("cannot load currencies:")
+	}
+
 	redisOpt := asynq.RedisClientOpt{
 		Addr: config.RedisAddress,
 	}
 
 	taskDistributor := workers.NewRedisTaskDistributor(&redisOpt)
 
-	go runTaskProcessor(redisOpt, store)
-	go runGinServer(store, config)
-	go runGatewayServer(store, config, taskDistributor)
-	runGRPCServer(store, config, taskDistributor)
+	// shared by every server so a revocation is visible to all of them at once
+	revocations := token.NewStoreRevocationList(store)
+	if config.RevocationCacheTTL > 0 {
+		revocations = token.NewCachedRevocationList(revocations, config.RevocationCacheTTL)
+	}
+
+	redisClient := asynq.NewClient(&redisOpt)
+	defer redisClient.Close()
+
+	// readiness of the dependencies every server needs
+	checker := health.NewChecker(healthCheckTimeout)
+	checker.Register("postgres", health.PostgresCheck(conn))
+	checker.Register("redis", health.RedisCheck(redisClient))
+
+	migrationCheck, err := health.MigrationCheck(migration, config.MigrationURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create migration health check:")
+	}
+	checker.Register("migrations", migrationCheck)
+
+	taskInspector := workers.NewRedisTaskInspector(&redisOpt)
+
+	// collected on every scrape of /metrics
+	prometheus.MustRegister(metrics.NewPoolCollector(conn), workers.NewQueueCollector(taskInspector))
+
+	limiter, err := newRateLimiter(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create rate limiter:")
+	}
+
+	// the first component to fail cancels ctx, which shuts every other one down
+	waitGroup, ctx := errgroup.WithContext(ctx)
+
+	// shared by the grpc and gateway servers, fed by the notifications of committed transfers
+	activityHub := activity.NewHub()
+	runActivityHub(ctx, waitGroup, activityHub, conn)
+
+	runCurrencyReloader(ctx, waitGroup, store)
+	runTaskProcessor(ctx, waitGroup, config, redisOpt, store, taskDistributor)
+	runTaskScheduler(ctx, waitGroup, redisOpt)
+	runOutboxRelay(ctx, waitGroup, config, store, taskDistributor)
+	runWebhookDispatcher(ctx, waitGroup, config, store, taskDistributor)
+	runGinServer(ctx, waitGroup, config, checker, store, revocations, taskInspector, limiter)
+	runGatewayServer(ctx, waitGroup, config, checker, store, taskDistributor, revocations, limiter, activityHub)
+	runGRPCServer(ctx, waitGroup, config, checker, store, taskDistributor, revocations, limiter, activityHub)
 
+	err = waitGroup.Wait()
+
+	// only close the pool once nothing can use it anymore
+	conn.Close()
+
+	// flush the spans of the requests that were drained
+	tracingCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout(config))
+	defer cancel()
+
+	if err := shutdownTracing(tracingCtx); err != nil {
+		log.Error().Err(err).Msg("cannot flush traces")
+	}
+
+	if err != nil {
+		log.Fatal().Err(err).Msg("error from wait group")
+	}
+
+	log.Info().Msg("shut down gracefully")
+}
+
+// shutdownTimeout is how long in-flight requests and tasks get to finish once shutdown starts
+func shutdownTimeout(config util.Config) time.Duration {
+	if config.ShutdownTimeout > 0 {
+		return config.ShutdownTimeout
+	}
+	return 10 * time.Second
 }
 
-func runTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store) {
-	taskPorcessor := workers.NewRedisTaskPorcessor(&redisOpt, store)
+func runTaskProcessor(ctx context.Context, waitGroup *errgroup.Group, config util.Config, redisOpt asynq.RedisClientOpt, store db.Store, taskDistributor workers.TaskDistributor) {
+	taskPorcessor := workers.NewRedisTaskPorcessor(&redisOpt, store, taskDistributor, shutdownTimeout(config))
 	log.Info().Msg("starting task processor ⌛⌛")
 	err := taskPorcessor.Start()
 
@@ -73,9 +189,117 @@ func runTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store) {
 	}
 
 	log.Info().Msg("task processor started successfully ✅✅")
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown task processor")
+
+		// waits for active tasks, unfinished ones are pushed back to the queue
+		taskPorcessor.Shutdown()
+		log.Info().Msg("task processor is stopped")
+
+		return nil
+	})
+}
+
+// runTaskScheduler enqueues the periodic tasks, such as paying the allowances of children as they fall due
+// and expiring the money requests left unanswered
+func runTaskScheduler(ctx context.Context, waitGroup *errgroup.Group, redisOpt asynq.RedisClientOpt) {
+	scheduler, err := workers.NewRedisTaskScheduler(&redisOpt)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create task scheduler")
+	}
+
+	log.Info().Msg("starting task scheduler")
+	if err := scheduler.Start(); err != nil {
+		log.Fatal().Err(err).Msg("cannot start task scheduler")
+	}
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown task scheduler")
+
+		scheduler.Shutdown()
+		log.Info().Msg("task scheduler is stopped")
+
+		return nil
+	})
+}
+
+func runOutboxRelay(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, taskDistributor workers.TaskDistributor) {
+	interval := config.OutboxRelayInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	relay := workers.NewOutboxRelay(store, taskDistributor, interval)
+	log.Info().Msg("starting outbox relay")
+
+	waitGroup.Go(func() error {
+		err := relay.Start(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("outbox relay stopped")
+			return err
+		}
+
+		log.Info().Msg("outbox relay is stopped")
+		return nil
+	})
+}
+
+func runActivityHub(ctx context.Context, waitGroup *errgroup.Group, activityHub *activity.Hub, conn *pgxpool.Pool) {
+	log.Info().Msg("starting account activity listener")
+
+	waitGroup.Go(func() error {
+		// ends every account stream once ctx is cancelled, so they do not hold up the servers shutdown
+		err := activityHub.Listen(ctx, conn)
+		log.Info().Msg("account activity listener is stopped")
+		return err
+	})
 }
 
-func runDbMigration(migrationURL string, dbSource string) {
+// runCurrencyReloader keeps the currency registry in line with the changes bankers made through other instances
+func runCurrencyReloader(ctx context.Context, waitGroup *errgroup.Group, store db.Store) {
+	waitGroup.Go(func() error {
+		ticker := time.NewTicker(currencyReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := db.LoadCurrencyRegistry(ctx, store); err != nil {
+					log.Error().Err(err).Msg("cannot reload currencies")
+				}
+			}
+		}
+	})
+}
+
+// runWebhookDispatcher turns webhook events into delivery tasks, it polls as often as the outbox relay
+func runWebhookDispatcher(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, taskDistributor workers.TaskDistributor) {
+	interval := config.OutboxRelayInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	dispatcher := workers.NewWebhookDispatcher(store, taskDistributor, interval)
+	log.Info().Msg("starting webhook dispatcher")
+
+	waitGroup.Go(func() error {
+		err := dispatcher.Start(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("webhook dispatcher stopped")
+			return err
+		}
+
+		log.Info().Msg("webhook dispatcher is stopped")
+		return nil
+	})
+}
+
+func runDbMigration(migrationURL string, dbSource string) *migrate.Migrate {
 	migration, err := migrate.New(migrationURL, dbSource)
 
 	if err != nil {
@@ -87,55 +311,115 @@ func runDbMigration(migrationURL string, dbSource string) {
 	}
 
 	log.Info().Msg("db migrated successfully")
+
+	return migration
 }
 
-func runGinServer(store db.Store, config util.Config) {
+func runGinServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, checker *health.Checker, store db.Store, revocations token.RevocationList, taskInspector workers.TaskInspector, limiter *ratelimit.Limiter) {
 	// set gin mode
 	gin.SetMode(gin.ReleaseMode)
 
-	server, err := api.NewServer(store, config)
+	server, err := api.NewServer(store, config, revocations, taskInspector, limiter)
 
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create gin http server:")
 	}
 
-	log.Info().Msg("starting gin server at " + config.GinServerAddress)
-
-	err = server.Start(config.GinServerAddress)
-
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start gin http server:")
+	httpServer := &http.Server{
+		Addr:    config.GinServerAddress,
+		Handler: checker.Wrap(otelhttp.NewHandler(server.Handler(), "gin")),
 	}
+
+	runHTTPServer(ctx, waitGroup, config, "gin", httpServer)
 }
 
-func runGRPCServer(store db.Store, config util.Config, taskDistributor workers.TaskDistributor) {
-	server, err := gapi.NewServer(store, config, taskDistributor)
+func runGRPCServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, checker *health.Checker, store db.Store, taskDistributor workers.TaskDistributor, revocations token.RevocationList, limiter *ratelimit.Limiter, activityHub *activity.Hub) {
+	server, err := gapi.NewServer(store, config, taskDistributor, revocations, activityHub)
 
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create grpc server:")
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	interceptors := grpc.ChainUnaryInterceptor(
+		gapi.GrpcRequestID,
+		gapi.GrpcErrors,
+		gapi.GrpcLogger,
+		gapi.GrpcRecovery,
+		gapi.GrpcMetrics,
+		server.RateLimit(limiter),
+		server.GrpcAuth,
+	)
+	streamInterceptors := grpc.ChainStreamInterceptor(
+		gapi.GrpcStreamRequestID,
+		gapi.GrpcStreamErrors,
+		gapi.GrpcStreamLogger,
+		gapi.GrpcStreamRecovery,
+		gapi.GrpcStreamMetrics,
+		server.GrpcStreamAuth,
+	)
+	// extracts the trace context sent in the grpc metadata
+	tracingHandler := grpc.StatsHandler(otelgrpc.NewServerHandler())
+	grpcServer := grpc.NewServer(interceptors, streamInterceptors, tracingHandler)
 	pb.RegisterHouseBankServer(grpcServer, server)
 	reflection.Register(grpcServer) // this will allow the client to see the available grpc services and how to call them
 
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
 	listener, err := net.Listen("tcp", config.GRPCServerAddress)
 
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create gRPC listener:")
 	}
 
-	log.Info().Msg("starting gRPC server at " + listener.Addr().String())
+	waitGroup.Go(func() error {
+		log.Info().Msg("starting gRPC server at " + listener.Addr().String())
 
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start gRPC server:")
-	}
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			if errors.Is(err, grpc.ErrServerStopped) {
+				return nil
+			}
+			log.Error().Err(err).Msg("gRPC server failed to serve")
+			return err
+		}
+
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		checker.WatchGRPC(ctx, healthServer, healthCheckInterval, pb.HouseBank_ServiceDesc.ServiceName)
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown gRPC server")
+
+		// tell load balancers to stop sending rpcs before draining
+		healthServer.Shutdown()
+
+		// GracefulStop waits for every rpc to finish, force the stop once the deadline is reached
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout(config)):
+			log.Warn().Msg("gRPC server did not stop in time, cancelling in-flight rpcs")
+			grpcServer.Stop()
+		}
+
+		log.Info().Msg("gRPC server is stopped")
+		return nil
+	})
 }
 
-func runGatewayServer(store db.Store, config util.Config, taskDistributor workers.TaskDistributor) {
-	server, err := gapi.NewServer(store, config, taskDistributor)
+func runGatewayServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, checker *health.Checker, store db.Store, taskDistributor workers.TaskDistributor, revocations token.RevocationList, limiter *ratelimit.Limiter, activityHub *activity.Hub) {
+	server, err := gapi.NewServer(store, config, taskDistributor, revocations, activityHub)
 
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create grpc server:")
@@ -150,10 +434,11 @@ func runGatewayServer(store db.Store, config util.Config, taskDistributor worker
 		},
 	})
 
-	grpcMux := runtime.NewServeMux(jsonOpt)
-
-	ctx, cancle := context.WithCancel(context.Background())
-	defer cancle()
+	grpcMux := runtime.NewServeMux(
+		jsonOpt,
+		runtime.WithMiddlewares(gapi.GatewayRoute, server.GatewayRateLimit(limiter), server.GatewayAuth),
+		runtime.WithErrorHandler(gapi.GatewayErrorHandler),
+	)
 
 	err = pb.RegisterHouseBankHandlerServer(ctx, grpcMux, server)
 	if err != nil {
@@ -163,6 +448,17 @@ func runGatewayServer(store db.Store, config util.Config, taskDistributor worker
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
 
+	oauthHandler := server.OAuthHandler()
+	mux.Handle("/oauth/", oauthHandler)
+	mux.Handle("/.well-known/", oauthHandler)
+
+	// the gateway cannot serve streaming rpcs, WatchAccount is served as server-sent events instead
+	mux.Handle(gapi.AccountActivityPath, server.AccountActivityHandler())
+
+	mux.Handle(gapi.VerifyEmailPath, server.VerifyEmailHandler())
+
+	mux.Handle(metrics.Path, metrics.Handler())
+
 	statikFs, err := fs.New()
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create statik fs:")
@@ -171,17 +467,70 @@ func runGatewayServer(store db.Store, config util.Config, taskDistributor worker
 	swaggerHandler := http.StripPrefix("/swagger/", http.FileServer(statikFs))
 	mux.Handle("/swagger/", swaggerHandler)
 
-	listener, err := net.Listen("tcp", config.HttpServerAddress)
-
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create listener:")
+	httpServer := &http.Server{
+		Addr:    config.HttpServerAddress,
+		Handler: checker.Wrap(otelhttp.NewHandler(gapi.HttpRequestID(gapi.HttpLogger(gapi.HttpMetrics(mux))), "gateway")),
 	}
 
-	log.Info().Msg("starting HTTP Gateway server at " + listener.Addr().String())
+	runHTTPServer(ctx, waitGroup, config, "HTTP Gateway", httpServer)
+}
 
-	handler := gapi.HttpLogger(mux)
-	err = http.Serve(listener, handler)
+// newRateLimiter builds the limiter shared by every server, redis shares the buckets between instances
+func newRateLimiter(config util.Config) (*ratelimit.Limiter, error) {
+	value := config.RateLimits
+	if value == "" {
+		value = ratelimit.DefaultPolicies
+	}
+
+	policies, err := ratelimit.ParsePolicies(value)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start http gateway server:")
+		return nil, err
+	}
+
+	var store ratelimit.Store
+	switch config.RateLimitBackend {
+	case "", "memory":
+		store = ratelimit.NewMemoryStore()
+	case "redis":
+		store = ratelimit.NewRedisStore(redis.NewClient(&redis.Options{Addr: config.RedisAddress}))
+	default:
+		return nil, fmt.Errorf("unsupported rate limit backend: %s", config.RateLimitBackend)
 	}
+
+	return ratelimit.NewLimiter(store, policies), nil
+}
+
+// runHTTPServer serves until ctx is cancelled, then stops accepting connections and drains in-flight requests
+func runHTTPServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, name string, httpServer *http.Server) {
+	waitGroup.Go(func() error {
+		log.Info().Msg("starting " + name + " server at " + httpServer.Addr)
+
+		err := httpServer.ListenAndServe()
+		if err != nil {
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			log.Error().Err(err).Msg(name + " server failed to serve")
+			return err
+		}
+
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown " + name + " server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout(config))
+		defer cancel()
+
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to shutdown " + name + " server")
+			return err
+		}
+
+		log.Info().Msg(name + " server is stopped")
+		return nil
+	})
 }
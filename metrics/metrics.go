@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	namespace = "housebank"
+
+	Path = "/metrics"
+)
+
+// Outcomes of a db transaction run by execTx
+const (
+	TxCommit   = "commit"
+	TxRollback = "rollback"
+	TxRetry    = "retry" // rolled back on a serialization failure or deadlock and run again
+)
+
+// Outcomes of a background task attempt
+const (
+	TaskSuccess  = "success"
+	TaskRetry    = "retry"    // failed, will be retried
+	TaskArchived = "archived" // failed its last attempt
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of http requests by server, method, route and status code.",
+	}, []string{"server", "method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of http requests by server, method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "method", "route", "status"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Number of grpc requests by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of grpc requests by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	dbTransactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "transactions_total",
+		Help:      "Number of db transactions by outcome: commit, rollback or retry.",
+	}, []string{"outcome"})
+
+	tasks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "tasks_total",
+		Help:      "Number of background task attempts by type and outcome: success, retry or archived.",
+	}, []string{"type", "outcome"})
+
+	transfers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Number of transfers by currency.",
+	}, []string{"currency"})
+
+	transferVolume = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_volume_total",
+		Help:      "Amount of money transferred by currency.",
+	}, []string{"currency"})
+)
+
+// Handler serves every registered metric in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ObserveHTTPRequest(server string, method string, route string, status int, duration time.Duration) {
+	labels := prometheus.Labels{
+		"server": server,
+		"method": method,
+		"route":  route,
+		"status": strconv.Itoa(status),
+	}
+
+	httpRequests.With(labels).Inc()
+	httpRequestDuration.With(labels).Observe(duration.Seconds())
+}
+
+func ObserveGRPCRequest(method string, code codes.Code, duration time.Duration) {
+	grpcRequests.WithLabelValues(method, code.String()).Inc()
+	grpcRequestDuration.WithLabelValues(method, code.String()).Observe(duration.Seconds())
+}
+
+func ObserveTransaction(outcome string) {
+	dbTransactions.WithLabelValues(outcome).Inc()
+}
+
+func ObserveTask(taskType string, outcome string) {
+	tasks.WithLabelValues(taskType, outcome).Inc()
+}
+
+func ObserveTransfer(currency string, amount int64) {
+	transfers.WithLabelValues(currency).Inc()
+	transferVolume.WithLabelValues(currency).Add(float64(amount))
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector reads the pgxpool stats every time the metrics are scraped
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	totalConns       *prometheus.Desc
+	idleConns        *prometheus.Desc
+	acquiredConns    *prometheus.Desc
+	maxConns         *prometheus.Desc
+	acquires         *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool:             pool,
+		totalConns:       desc("total_connections", "Number of open connections in the pool."),
+		idleConns:        desc("idle_connections", "Number of idle connections in the pool."),
+		acquiredConns:    desc("acquired_connections", "Number of connections currently in use."),
+		maxConns:         desc("max_connections", "Maximum size of the pool."),
+		acquires:         desc("acquires_total", "Number of successful connection acquires."),
+		emptyAcquires:    desc("empty_acquires_total", "Number of acquires that had to wait for a connection."),
+		canceledAcquires: desc("canceled_acquires_total", "Number of acquires cancelled by their context."),
+		acquireDuration:  desc("acquire_duration_seconds_total", "Time spent waiting for a connection."),
+	}
+}
+
+func (collector *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.totalConns
+	ch <- collector.idleConns
+	ch <- collector.acquiredConns
+	ch <- collector.maxConns
+	ch <- collector.acquires
+	ch <- collector.emptyAcquires
+	ch <- collector.canceledAcquires
+	ch <- collector.acquireDuration
+}
+
+func (collector *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := collector.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(collector.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(collector.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(collector.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(collector.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(collector.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(collector.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(collector.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(collector.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,228 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/AnkitNayan83/houseBank/util"
+)
+
+var (
+	ErrCurrencyMismatch  = errors.New("currency mismatch")
+	ErrOverflow          = errors.New("amount out of range")
+	ErrUnknownCurrency   = errors.New("unknown currency")
+	ErrInvalidAllocation = errors.New("invalid allocation")
+)
+
+// Money is an amount of minor units of a currency, 12345 USD is $123.45
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Decimal formats the amount with the minor units of the currency, 12345 USD is "123.45"
+func (m Money) Decimal() string {
+	return util.FormatAmount(m.Amount, m.Currency)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// Neg returns the opposite amount, which does not exist for the smallest int64
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: -%s", ErrOverflow, m)
+	}
+	return New(-m.Amount, m.Currency), nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	sum := m.Amount + other.Amount
+	// the sum of two numbers of the same sign only changes sign when it overflows
+	if (m.Amount >= 0) == (other.Amount >= 0) && (sum >= 0) != (m.Amount >= 0) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m, other)
+	}
+
+	return New(sum, m.Currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	difference := m.Amount - other.Amount
+	// only the difference of numbers of opposite signs can overflow, it then has the sign of other
+	if (m.Amount >= 0) != (other.Amount >= 0) && (difference >= 0) != (m.Amount >= 0) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrOverflow, m, other)
+	}
+
+	return New(difference, m.Currency), nil
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Split divides the amount into n parts which differ by at most one minor unit and add up to the amount
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: cannot split into %d parts", ErrInvalidAllocation, n)
+	}
+
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// Allocate divides the amount in proportion to the ratios, without losing a minor unit:
+// the units left over by rounding down are handed out one by one from the first part on.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("%w: no ratios", ErrInvalidAllocation)
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("%w: negative ratio %d", ErrInvalidAllocation, ratio)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+
+	if total.Sign() == 0 {
+		return nil, fmt.Errorf("%w: ratios add up to zero", ErrInvalidAllocation)
+	}
+
+	// products of the amount and a ratio can overflow int64, the parts themselves cannot
+	amount := big.NewInt(m.Amount)
+	parts := make([]Money, len(ratios))
+	remainder := m.Amount
+
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(ratio))
+		share.Quo(share, total)
+
+		parts[i] = New(share.Int64(), m.Currency)
+		remainder -= parts[i].Amount
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		// parts with a zero ratio stay empty
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].Amount += unit
+		remainder -= unit
+	}
+
+	return parts, nil
+}
+
+// Convert exchanges the money into the currency at rate units of currency per unit of m,
+// rounding half to even to the minor units of currency.
+func (m Money) Convert(currency string, rate *big.Rat) (Money, error) {
+	from, ok := util.LookupCurrency(m.Currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, m.Currency)
+	}
+
+	to, ok := util.LookupCurrency(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, currency)
+	}
+
+	if rate == nil || rate.Sign() <= 0 {
+		return Money{}, fmt.Errorf("exchange rate must be positive")
+	}
+
+	converted := new(big.Rat).SetInt64(m.Amount)
+	converted.Mul(converted, rate)
+	converted.Mul(converted, pow10(to.Exponent-from.Exponent))
+
+	amount, err := roundHalfEven(converted)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %s at %s", err, m, rate.FloatString(8))
+	}
+
+	return New(amount, currency), nil
+}
+
+// pow10 returns 10^exponent, which is a fraction for negative exponents
+func pow10(exponent int32) *big.Rat {
+	power := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(exponent, -exponent))), nil)
+	if exponent < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), power)
+	}
+	return new(big.Rat).SetInt(power)
+}
+
+// roundHalfEven rounds to the nearest integer, and ties to the even one, so rounding errors do not add up in one direction
+func roundHalfEven(r *big.Rat) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+
+	// compare the remainder with half of the denominator, which is always positive
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+
+	switch cmp := twice.Cmp(r.Denom()); {
+	case cmp > 0, cmp == 0 && quotient.Bit(0) == 1:
+		quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrOverflow
+	}
+
+	return quotient.Int64(), nil
+}
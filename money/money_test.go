@@ -0,0 +1,168 @@
+package money
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSub(t *testing.T) {
+	a := New(1050, util.USD)
+	b := New(250, util.USD)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, New(1300, util.USD), sum)
+
+	difference, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, New(-800, util.USD), difference)
+	require.Equal(t, "-8.00 USD", difference.String())
+
+	_, err = a.Add(New(1, util.INR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = a.Sub(New(1, util.INR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestOverflow(t *testing.T) {
+	largest := New(math.MaxInt64, util.USD)
+	smallest := New(math.MinInt64, util.USD)
+
+	_, err := largest.Add(New(1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = smallest.Add(New(-1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = smallest.Sub(New(1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(0, util.USD).Sub(smallest)
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = smallest.Neg()
+	require.ErrorIs(t, err, ErrOverflow)
+
+	sum, err := largest.Add(smallest)
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), sum.Amount)
+}
+
+func TestCmp(t *testing.T) {
+	cmp, err := New(1, util.USD).Cmp(New(2, util.USD))
+	require.NoError(t, err)
+	require.Equal(t, -1, cmp)
+
+	_, err = New(1, util.USD).Cmp(New(1, util.INR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func requireAllocation(t *testing.T, m Money, parts []Money, want ...int64) {
+	var total int64
+	got := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		require.Equal(t, m.Currency, part.Currency)
+		total += part.Amount
+		got = append(got, part.Amount)
+	}
+
+	require.Equal(t, want, got)
+	require.Equal(t, m.Amount, total)
+}
+
+func TestSplit(t *testing.T) {
+	m := New(100, util.USD)
+
+	parts, err := m.Split(3)
+	require.NoError(t, err)
+	requireAllocation(t, m, parts, 34, 33, 33)
+
+	negative := New(-100, util.USD)
+	parts, err = negative.Split(3)
+	require.NoError(t, err)
+	requireAllocation(t, negative, parts, -34, -33, -33)
+
+	_, err = m.Split(0)
+	require.ErrorIs(t, err, ErrInvalidAllocation)
+}
+
+func TestAllocate(t *testing.T) {
+	m := New(5, util.USD)
+
+	parts, err := m.Allocate(70, 30)
+	require.NoError(t, err)
+	requireAllocation(t, m, parts, 4, 1)
+
+	parts, err = m.Allocate(0, 1, 1)
+	require.NoError(t, err)
+	requireAllocation(t, m, parts, 0, 3, 2)
+
+	// the products of the amount and the ratios do not fit in an int64
+	large := New(math.MaxInt64, util.USD)
+	parts, err = large.Allocate(math.MaxInt64, math.MaxInt64)
+	require.NoError(t, err)
+	requireAllocation(t, large, parts, math.MaxInt64/2+1, math.MaxInt64/2)
+
+	_, err = m.Allocate(1, -1)
+	require.ErrorIs(t, err, ErrInvalidAllocation)
+
+	_, err = m.Allocate(0, 0)
+	require.ErrorIs(t, err, ErrInvalidAllocation)
+}
+
+func TestConvert(t *testing.T) {
+	util.SetCurrencies([]util.Currency{
+		{Code: util.USD, Exponent: 2, Symbol: "$", Enabled: true},
+		{Code: util.INR, Exponent: 2, Symbol: "₹", Enabled: true},
+		{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true},
+	})
+	t.Cleanup(func() {
+		util.SetCurrencies([]util.Currency{
+			{Code: util.USD, Exponent: 2, Symbol: "$", Enabled: true},
+			{Code: util.INR, Exponent: 2, Symbol: "₹", Enabled: true},
+		})
+	})
+
+	rate := func(s string) *big.Rat {
+		r, ok := new(big.Rat).SetString(s)
+		require.True(t, ok)
+		return r
+	}
+
+	testCases := []struct {
+		from     Money
+		currency string
+		rate     string
+		want     int64
+	}{
+		{New(100, util.USD), util.INR, "83.25", 8325},
+		{New(3, util.USD), util.INR, "0.125", 0},
+		{New(5, util.USD), util.INR, "0.25", 1},
+		{New(3, util.USD), util.INR, "0.25", 1},
+		// halves are ties, which go to the even minor unit
+		{New(5, util.USD), util.INR, "0.5", 2},
+		{New(7, util.USD), util.INR, "0.5", 4},
+		{New(-7, util.USD), util.INR, "0.5", -4},
+		{New(-5, util.USD), util.INR, "0.5", -2},
+		// the exponents of the currencies differ
+		{New(150, util.USD), "JPY", "150", 225},
+		{New(250, "JPY"), util.USD, "0.0066", 165},
+	}
+
+	for _, tc := range testCases {
+		converted, err := tc.from.Convert(tc.currency, rate(tc.rate))
+		require.NoError(t, err)
+		require.Equal(t, New(tc.want, tc.currency), converted, "%s at %s", tc.from, tc.rate)
+	}
+
+	_, err := New(1, util.USD).Convert("XXX", rate("1"))
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, err = New(math.MaxInt64, util.USD).Convert(util.INR, rate("2"))
+	require.ErrorIs(t, err, ErrOverflow)
+}
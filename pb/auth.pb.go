@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: auth.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthLevel is who can call a method, methods without an auth option require an authenticated caller
+type AuthLevel int32
+
+const (
+	AuthLevel_AUTH_LEVEL_UNSPECIFIED   AuthLevel = 0
+	AuthLevel_AUTH_LEVEL_PUBLIC        AuthLevel = 1
+	AuthLevel_AUTH_LEVEL_AUTHENTICATED AuthLevel = 2
+)
+
+// Enum value maps for AuthLevel.
+var (
+	AuthLevel_name = map[int32]string{
+		0: "AUTH_LEVEL_UNSPECIFIED",
+		1: "AUTH_LEVEL_PUBLIC",
+		2: "AUTH_LEVEL_AUTHENTICATED",
+	}
+	AuthLevel_value = map[string]int32{
+		"AUTH_LEVEL_UNSPECIFIED":   0,
+		"AUTH_LEVEL_PUBLIC":        1,
+		"AUTH_LEVEL_AUTHENTICATED": 2,
+	}
+)
+
+func (x AuthLevel) Enum() *AuthLevel {
+	p := new(AuthLevel)
+	*p = x
+	return p
+}
+
+func (x AuthLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuthLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_auth_proto_enumTypes[0].Descriptor()
+}
+
+func (AuthLevel) Type() protoreflect.EnumType {
+	return &file_auth_proto_enumTypes[0]
+}
+
+func (x AuthLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuthLevel.Descriptor instead.
+func (AuthLevel) EnumDescriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{0}
+}
+
+type AuthRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Level AuthLevel              `protobuf:"varint,1,opt,name=level,proto3,enum=pb.AuthLevel" json:"level,omitempty"`
+	// roles allowed to call the method, any role when empty
+	Roles []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// scopes which let api keys and third-party app tokens call the method, only the user's own tokens when empty
+	Scopes        []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthRule) Reset() {
+	*x = AuthRule{}
+	mi := &file_auth_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRule) ProtoMessage() {}
+
+func (x *AuthRule) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRule.ProtoReflect.Descriptor instead.
+func (*AuthRule) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthRule) GetLevel() AuthLevel {
+	if x != nil {
+		return x.Level
+	}
+	return AuthLevel_AUTH_LEVEL_UNSPECIFIED
+}
+
+func (x *AuthRule) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *AuthRule) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+var file_auth_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*AuthRule)(nil),
+		Field:         50001,
+		Name:          "pb.auth",
+		Tag:           "bytes,50001,opt,name=auth",
+		Filename:      "auth.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional pb.AuthRule auth = 50001;
+	E_Auth = &file_auth_proto_extTypes[0]
+)
+
+var File_auth_proto protoreflect.FileDescriptor
+
+const file_auth_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"auth.proto\x12\x02pb\x1a google/protobuf/descriptor.proto\"]\n" +
+	"\bAuthRule\x12#\n" +
+	"\x05level\x18\x01 \x01(\x0e2\r.pb.AuthLevelR\x05level\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes*\\\n" +
+	"\tAuthLevel\x12\x1a\n" +
+	"\x16AUTH_LEVEL_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11AUTH_LEVEL_PUBLIC\x10\x01\x12\x1c\n" +
+	"\x18AUTH_LEVEL_AUTHENTICATED\x10\x02:B\n" +
+	"\x04auth\x12\x1e.google.protobuf.MethodOptions\x18ц\x03 \x01(\v2\f.pb.AuthRuleR\x04authB&Z$github.com/AnkitNayan83/houseBank/pbb\x06proto3"
+
+var (
+	file_auth_proto_rawDescOnce sync.Once
+	file_auth_proto_rawDescData []byte
+)
+
+func file_auth_proto_rawDescGZIP() []byte {
+	file_auth_proto_rawDescOnce.Do(func() {
+		file_auth_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)))
+	})
+	return file_auth_proto_rawDescData
+}
+
+var file_auth_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_auth_proto_goTypes = []any{
+	(AuthLevel)(0),                     // 0: pb.AuthLevel
+	(*AuthRule)(nil),                   // 1: pb.AuthRule
+	(*descriptorpb.MethodOptions)(nil), // 2: google.protobuf.MethodOptions
+}
+var file_auth_proto_depIdxs = []int32{
+	0, // 0: pb.AuthRule.level:type_name -> pb.AuthLevel
+	2, // 1: pb.auth:extendee -> google.protobuf.MethodOptions
+	1, // 2: pb.auth:type_name -> pb.AuthRule
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	1, // [1:2] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_auth_proto_init() }
+func file_auth_proto_init() {
+	if File_auth_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_auth_proto_goTypes,
+		DependencyIndexes: file_auth_proto_depIdxs,
+		EnumInfos:         file_auth_proto_enumTypes,
+		MessageInfos:      file_auth_proto_msgTypes,
+		ExtensionInfos:    file_auth_proto_extTypes,
+	}.Build()
+	File_auth_proto = out.File
+	file_auth_proto_goTypes = nil
+	file_auth_proto_depIdxs = nil
+}
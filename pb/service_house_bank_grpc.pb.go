@@ -19,9 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	HouseBank_CreateUser_FullMethodName = "/pb.HouseBank/CreateUser"
-	HouseBank_LoginUser_FullMethodName  = "/pb.HouseBank/LoginUser"
-	HouseBank_UpdateUser_FullMethodName = "/pb.HouseBank/UpdateUser"
+	HouseBank_CreateUser_FullMethodName   = "/pb.HouseBank/CreateUser"
+	HouseBank_LoginUser_FullMethodName    = "/pb.HouseBank/LoginUser"
+	HouseBank_UpdateUser_FullMethodName   = "/pb.HouseBank/UpdateUser"
+	HouseBank_WatchAccount_FullMethodName = "/pb.HouseBank/WatchAccount"
 )
 
 // HouseBankClient is the client API for HouseBank service.
@@ -31,6 +32,7 @@ type HouseBankClient interface {
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
 	LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
 	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
+	WatchAccount(ctx context.Context, in *WatchAccountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AccountActivity], error)
 }
 
 type houseBankClient struct {
@@ -71,6 +73,25 @@ func (c *houseBankClient) UpdateUser(ctx context.Context, in *UpdateUserRequest,
 	return out, nil
 }
 
+func (c *houseBankClient) WatchAccount(ctx context.Context, in *WatchAccountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AccountActivity], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &HouseBank_ServiceDesc.Streams[0], HouseBank_WatchAccount_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAccountRequest, AccountActivity]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type HouseBank_WatchAccountClient = grpc.ServerStreamingClient[AccountActivity]
+
 // HouseBankServer is the server API for HouseBank service.
 // All implementations must embed UnimplementedHouseBankServer
 // for forward compatibility.
@@ -78,6 +99,7 @@ type HouseBankServer interface {
 	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
 	LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error)
 	UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error)
+	WatchAccount(*WatchAccountRequest, grpc.ServerStreamingServer[AccountActivity]) error
 	mustEmbedUnimplementedHouseBankServer()
 }
 
@@ -97,6 +119,9 @@ func (UnimplementedHouseBankServer) LoginUser(context.Context, *LoginUserRequest
 func (UnimplementedHouseBankServer) UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
 }
+func (UnimplementedHouseBankServer) WatchAccount(*WatchAccountRequest, grpc.ServerStreamingServer[AccountActivity]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAccount not implemented")
+}
 func (UnimplementedHouseBankServer) mustEmbedUnimplementedHouseBankServer() {}
 func (UnimplementedHouseBankServer) testEmbeddedByValue()                   {}
 
@@ -172,6 +197,17 @@ func _HouseBank_UpdateUser_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HouseBank_WatchAccount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAccountRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HouseBankServer).WatchAccount(m, &grpc.GenericServerStream[WatchAccountRequest, AccountActivity]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type HouseBank_WatchAccountServer = grpc.ServerStreamingServer[AccountActivity]
+
 // HouseBank_ServiceDesc is the grpc.ServiceDesc for HouseBank service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -192,6 +228,12 @@ var HouseBank_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _HouseBank_UpdateUser_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAccount",
+			Handler:       _HouseBank_WatchAccount_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "service_house_bank.proto",
 }
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Headers of the IETF RateLimit header fields draft, Retry-After is only sent with rejected requests
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// Store keeps the token buckets
+type Store interface {
+	// Take removes a token from the bucket of the key, a missing bucket starts full
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Result is the state of a bucket after a request took its token
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // until the next token, when the request was not allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+// newResult builds the result from the tokens left in the bucket
+func newResult(allowed bool, tokens float64, limit Limit) Result {
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) / limit.Rate * float64(time.Second)),
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	}
+
+	return result
+}
+
+// Headers are the rate limit headers of the result, in seconds
+func (result Result) Headers() map[string]string {
+	headers := map[string]string{
+		HeaderLimit:     strconv.Itoa(result.Limit),
+		HeaderRemaining: strconv.Itoa(result.Remaining),
+		HeaderReset:     strconv.Itoa(ceilSeconds(result.Reset)),
+	}
+
+	if !result.Allowed {
+		headers[HeaderRetryAfter] = strconv.Itoa(ceilSeconds(result.RetryAfter))
+	}
+
+	return headers
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+// Client identifies who sent a request, Username is empty for anonymous requests
+type Client struct {
+	IP       string
+	Username string
+}
+
+type Limiter struct {
+	store    Store
+	policies []Policy
+}
+
+func NewLimiter(store Store, policies []Policy) *Limiter {
+	return &Limiter{
+		store:    store,
+		policies: policies,
+	}
+}
+
+// Allow takes a token from the bucket of every policy of the route, and reports the most restrictive one.
+// ok is false when no policy applies. Requests are let through when the store cannot be reached.
+func (limiter *Limiter) Allow(ctx context.Context, route string, client Client) (result Result, ok bool) {
+	for _, policy := range limiter.policies {
+		if policy.Route != route && policy.Route != AnyRoute {
+			continue
+		}
+
+		policyResult, err := limiter.store.Take(ctx, bucketKey(policy, client), policy.Limit)
+		if err != nil {
+			log.Warn().Err(err).Str("route", route).Msg("cannot check rate limit, letting the request through")
+			continue
+		}
+
+		if !ok || moreRestrictive(policyResult, result) {
+			result = policyResult
+		}
+		ok = true
+	}
+
+	return result, ok
+}
+
+func moreRestrictive(result Result, than Result) bool {
+	if result.Allowed != than.Allowed {
+		return !result.Allowed
+	}
+	if !result.Allowed {
+		return result.RetryAfter > than.RetryAfter
+	}
+	return result.Remaining < than.Remaining
+}
+
+func bucketKey(policy Policy, client Client) string {
+	if policy.Key == KeyUser && client.Username != "" {
+		return fmt.Sprintf("ratelimit:%s:user:%s", policy.Route, client.Username)
+	}
+	return fmt.Sprintf("ratelimit:%s:ip:%s", policy.Route, client.IP)
+}
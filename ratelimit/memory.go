@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets which refilled completely are dropped
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     Limit
+}
+
+// refill adds the tokens earned since the last update
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+	b.updatedAt = now
+}
+
+// MemoryStore keeps the buckets of a single process, each instance counts requests on its own
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+func (store *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := store.now()
+	store.sweep(now)
+
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		store.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return newResult(allowed, b.tokens, limit), nil
+}
+
+// sweep drops full buckets so clients which went away do not keep using memory
+func (store *MemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < sweepInterval {
+		return
+	}
+	store.lastSweep = now
+
+	for key, b := range store.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(store.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AnyRoute policies apply to every request on top of the policies of its route
+	AnyRoute = "*"
+
+	KeyIP   = "ip"
+	KeyUser = "user" // anonymous requests are counted by ip instead
+)
+
+// DefaultPolicies protect signup, login and transfers when RATE_LIMITS is not set.
+// Transfers between the accounts of one user, on POST /transfers/own, are left out.
+const DefaultPolicies = "POST /users=ip:5/m," +
+	"POST /users/login=ip:10/m," +
+	"POST /transfers=user:30/m," +
+	"POST /v1/user=ip:5/m," +
+	"POST /v1/user/login=ip:10/m," +
+	"/pb.HouseBank/CreateUser=ip:5/m," +
+	"/pb.HouseBank/LoginUser=ip:10/m"
+
+// Limit is a token bucket: it holds up to Burst tokens and refills Rate tokens per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Policy limits the requests of a route by client ip or by user.
+// Routes are "METHOD /path" for http, with the route template of gin or of the gateway, and the full method for grpc.
+type Policy struct {
+	Route string
+	Key   string
+	Limit Limit
+}
+
+var periods = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// ParsePolicies reads comma separated policies written as <route>=<key>:<requests>/<period>[:<burst>],
+// e.g. "POST /users/login=ip:10/m,*=user:100/s:200". The burst defaults to the number of requests.
+func ParsePolicies(value string) ([]Policy, error) {
+	var policies []Policy
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		policy, err := parsePolicy(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit policy %q: %w", entry, err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func parsePolicy(entry string) (Policy, error) {
+	route, spec, found := strings.Cut(entry, "=")
+	if !found || strings.TrimSpace(route) == "" {
+		return Policy{}, fmt.Errorf("missing route")
+	}
+
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Policy{}, fmt.Errorf("expected <key>:<requests>/<period>[:<burst>]")
+	}
+
+	key := parts[0]
+	if key != KeyIP && key != KeyUser {
+		return Policy{}, fmt.Errorf("unsupported key: %s", key)
+	}
+
+	count, unit, found := strings.Cut(parts[1], "/")
+	if !found {
+		return Policy{}, fmt.Errorf("missing period")
+	}
+
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return Policy{}, fmt.Errorf("requests must be a positive number")
+	}
+
+	period, ok := periods[unit]
+	if !ok {
+		return Policy{}, fmt.Errorf("unsupported period: %s", unit)
+	}
+
+	burst := requests
+	if len(parts) == 3 {
+		burst, err = strconv.Atoi(parts[2])
+		if err != nil || burst <= 0 {
+			return Policy{}, fmt.Errorf("burst must be a positive number")
+		}
+	}
+
+	return Policy{
+		Route: strings.Join(strings.Fields(route), " "),
+		Key:   key,
+		Limit: Limit{
+			Rate:  float64(requests) / period.Seconds(),
+			Burst: burst,
+		},
+	}, nil
+}
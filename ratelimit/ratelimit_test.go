@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("POST /users/login=ip:10/m, *=user:100/s:200")
+	require.NoError(t, err)
+	require.Equal(t, []Policy{
+		{Route: "POST /users/login", Key: KeyIP, Limit: Limit{Rate: 10.0 / 60, Burst: 10}},
+		{Route: AnyRoute, Key: KeyUser, Limit: Limit{Rate: 100, Burst: 200}},
+	}, policies)
+
+	_, err = ParsePolicies(DefaultPolicies)
+	require.NoError(t, err)
+
+	for _, invalid := range []string{
+		"POST /users",
+		"=ip:10/m",
+		"POST /users=session:10/m",
+		"POST /users=ip:10",
+		"POST /users=ip:0/m",
+		"POST /users=ip:10/d",
+		"POST /users=ip:10/m:-1",
+	} {
+		_, err := ParsePolicies(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := 1; i >= 0; i-- {
+		result, err := store.Take(context.Background(), "key", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+	require.Equal(t, "1", result.Headers()[HeaderRetryAfter])
+
+	// other keys have their own bucket
+	result, err = store.Take(context.Background(), "other", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	now = now.Add(time.Second)
+	result, err = store.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+
+	// full buckets are dropped
+	now = now.Add(sweepInterval)
+	_, err = store.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.Len(t, store.buckets, 1)
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("redis is down")
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore(), []Policy{
+		{Route: "POST /transfers", Key: KeyUser, Limit: Limit{Rate: 1, Burst: 1}},
+		{Route: AnyRoute, Key: KeyIP, Limit: Limit{Rate: 1, Burst: 3}},
+	})
+	ctx := context.Background()
+	alice := Client{IP: "10.0.0.1", Username: "alice"}
+	bob := Client{IP: "10.0.0.1", Username: "bob"}
+
+	// the route policy is the most restrictive one
+	result, ok := limiter.Allow(ctx, "POST /transfers", alice)
+	require.True(t, ok)
+	require.True(t, result.Allowed)
+	require.Equal(t, 1, result.Limit)
+
+	result, _ = limiter.Allow(ctx, "POST /transfers", alice)
+	require.False(t, result.Allowed)
+
+	// users have their own bucket even behind the same ip
+	result, _ = limiter.Allow(ctx, "POST /transfers", bob)
+	require.True(t, result.Allowed)
+
+	// the ip bucket is shared by every route
+	result, ok = limiter.Allow(ctx, "GET /accounts", bob)
+	require.True(t, ok)
+	require.False(t, result.Allowed)
+	require.Equal(t, 3, result.Limit)
+
+	// requests are let through when the store fails
+	_, ok = NewLimiter(failingStore{}, []Policy{{Route: AnyRoute, Key: KeyIP, Limit: Limit{Rate: 1, Burst: 1}}}).Allow(ctx, "GET /accounts", alice)
+	require.False(t, ok)
+}
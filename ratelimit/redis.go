@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from the bucket atomically, using the clock of redis so every instance agrees.
+// The bucket expires once it would be full again.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated_at")
+local tokens = tonumber(state[1])
+local updated_at = tonumber(state[2])
+if tokens == nil or updated_at == nil then
+	tokens = burst
+	updated_at = now
+end
+
+tokens = math.min(burst, tokens + (now - updated_at) / 1000 * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated_at", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore shares the buckets between every instance of the servers
+type RedisStore struct {
+	client redis.Scripter
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (store *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := takeScript.Run(ctx, store.client, []string{key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("cannot take rate limit token: %w", err)
+	}
+
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(string)
+
+	tokens, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid rate limit tokens: %w", err)
+	}
+
+	return newResult(allowed == 1, tokens, limit), nil
+}
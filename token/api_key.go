@@ -0,0 +1,180 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	apiKeyTag         = "hb"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+var (
+	ErrInvalidAPIKey   = errors.New("api key is invalid")
+	ErrRevokedAPIKey   = errors.New("api key has been revoked")
+	ErrAPIKeyIPBlocked = errors.New("api key is not allowed from this ip")
+)
+
+// APIKeyPayload is the identity of a machine client authenticated with an api key
+type APIKeyPayload struct {
+	Payload
+	AccountIDs []int64 `json:"account_ids"`
+}
+
+// CanUseAccount reports whether the key is allowed to move money out of the account
+func (payload *APIKeyPayload) CanUseAccount(accountID int64) bool {
+	return len(payload.AccountIDs) == 0 || slices.Contains(payload.AccountIDs, accountID)
+}
+
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string, clientIP string) (*APIKeyPayload, error)
+}
+
+// GenerateAPIKey creates a new key in the form hb_<prefix>_<secret>.
+// Only the prefix and the hashed secret should be stored, the key itself is shown to the user once.
+func GenerateAPIKey() (key string, prefix string, hashedSecret string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", "", fmt.Errorf("cannot generate api key prefix: %w", err)
+	}
+
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", fmt.Errorf("cannot generate api key secret: %w", err)
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	key = strings.Join([]string{apiKeyTag, prefix, secret}, "_")
+
+	return key, prefix, HashAPIKeySecret(secret), nil
+}
+
+// ParseAPIKey splits a key into its lookup prefix and its secret
+func ParseAPIKey(key string) (prefix string, secret string, err error) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag || parts[1] == "" || parts[2] == "" {
+		return "", "", ErrInvalidAPIKey
+	}
+
+	return parts[1], parts[2], nil
+}
+
+// HashAPIKeySecret hashes the secret part of a key. Secrets are random so a fast hash is enough.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAllowedIP checks the client ip against a list of ips and cidr ranges, an empty list allows any ip
+func IsAllowedIP(allowed []string, clientIP string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, entry := range allowed {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			if prefix.Contains(addr) {
+				return true
+			}
+			continue
+		}
+
+		if ip, err := netip.ParseAddr(entry); err == nil && ip.Unmap() == addr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StoreAPIKeyVerifier looks keys up in postgres by their prefix
+type StoreAPIKeyVerifier struct {
+	store db.Querier
+}
+
+func NewStoreAPIKeyVerifier(store db.Querier) APIKeyVerifier {
+	return &StoreAPIKeyVerifier{store: store}
+}
+
+func (verifier *StoreAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string, clientIP string) (*APIKeyPayload, error) {
+	prefix, secret, err := ParseAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := verifier.store.GetApiKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("cannot get api key: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(HashAPIKeySecret(secret)), []byte(apiKey.HashedSecret)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if apiKey.RevokedAt.Valid {
+		return nil, ErrRevokedAPIKey
+	}
+
+	if apiKey.ExpiresAt.Valid && apiKey.ExpiresAt.Time.Before(time.Now()) {
+		return nil, ErrExpiredToken
+	}
+
+	if !IsAllowedIP(apiKey.AllowedIps, clientIP) {
+		return nil, ErrAPIKeyIPBlocked
+	}
+
+	if err := verifier.store.UpdateApiKeyLastUsed(ctx, apiKey.ID); err != nil {
+		return nil, fmt.Errorf("cannot update api key: %w", err)
+	}
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	if apiKey.ExpiresAt.Valid {
+		expiresAt = apiKey.ExpiresAt.Time
+	}
+
+	payload := &APIKeyPayload{
+		Payload: Payload{
+			ID:        apiKey.ID,
+			Username:  apiKey.Username,
+			Role:      util.DepositorRole, // api keys never carry banker privileges
+			IssuedAt:  apiKey.CreatedAt,
+			ExpiresAt: expiresAt,
+			Scopes:    apiKey.Scopes,
+		},
+		AccountIDs: apiKey.AccountIds,
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,156 @@
+package token
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hashedSecret, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	gotPrefix, secret, err := ParseAPIKey(key)
+	require.NoError(t, err)
+	require.Equal(t, prefix, gotPrefix)
+	require.Equal(t, hashedSecret, HashAPIKeySecret(secret))
+	require.NotContains(t, hashedSecret, secret)
+
+	_, _, err = ParseAPIKey("not-a-key")
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestIsAllowedIP(t *testing.T) {
+	require.True(t, IsAllowedIP(nil, "10.0.0.1"))
+	require.True(t, IsAllowedIP([]string{"10.0.0.1"}, "10.0.0.1"))
+	require.True(t, IsAllowedIP([]string{"10.0.0.0/24"}, "10.0.0.42:5555"))
+	require.False(t, IsAllowedIP([]string{"10.0.0.0/24"}, "10.0.1.1"))
+	require.False(t, IsAllowedIP([]string{"10.0.0.1"}, "not-an-ip"))
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	key, prefix, hashedSecret, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	apiKey := db.ApiKey{
+		ID:           uuid.New(),
+		Username:     util.RandomOwner(),
+		Prefix:       prefix,
+		HashedSecret: hashedSecret,
+		Scopes:       []string{ScopeAccountsRead},
+		AccountIds:   []int64{1},
+		AllowedIps:   []string{"127.0.0.1"},
+		CreatedAt:    time.Now(),
+	}
+
+	testCases := []struct {
+		name       string
+		key        string
+		clientIP   string
+		buildStubs func(store *mockDB.MockStore)
+		check      func(t *testing.T, payload *APIKeyPayload, err error)
+	}{
+		{
+			name:     "OK",
+			key:      key,
+			clientIP: "127.0.0.1",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).Times(1).Return(apiKey, nil)
+				store.EXPECT().UpdateApiKeyLastUsed(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(nil)
+			},
+			check: func(t *testing.T, payload *APIKeyPayload, err error) {
+				require.NoError(t, err)
+				require.Equal(t, apiKey.Username, payload.Username)
+				require.Equal(t, util.DepositorRole, payload.Role)
+				require.True(t, payload.HasScope(ScopeAccountsRead))
+				require.False(t, payload.HasScope(ScopeTransfersCreate))
+				require.True(t, payload.CanUseAccount(1))
+				require.False(t, payload.CanUseAccount(2))
+			},
+		},
+		{
+			name:     "WrongSecret",
+			key:      "hb_" + prefix + "_wrong",
+			clientIP: "127.0.0.1",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).Times(1).Return(apiKey, nil)
+				store.EXPECT().UpdateApiKeyLastUsed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, payload *APIKeyPayload, err error) {
+				require.ErrorIs(t, err, ErrInvalidAPIKey)
+			},
+		},
+		{
+			name:     "NotFound",
+			key:      key,
+			clientIP: "127.0.0.1",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).Times(1).Return(db.ApiKey{}, sql.ErrNoRows)
+			},
+			check: func(t *testing.T, payload *APIKeyPayload, err error) {
+				require.ErrorIs(t, err, ErrInvalidAPIKey)
+			},
+		},
+		{
+			name:     "BlockedIP",
+			key:      key,
+			clientIP: "10.0.0.1",
+			buildStubs: func(store *mockDB.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).Times(1).Return(apiKey, nil)
+				store.EXPECT().UpdateApiKeyLastUsed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, payload *APIKeyPayload, err error) {
+				require.ErrorIs(t, err, ErrAPIKeyIPBlocked)
+			},
+		},
+		{
+			name:     "Revoked",
+			key:      key,
+			clientIP: "127.0.0.1",
+			buildStubs: func(store *mockDB.MockStore) {
+				revokedKey := apiKey
+				revokedKey.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).Times(1).Return(revokedKey, nil)
+			},
+			check: func(t *testing.T, payload *APIKeyPayload, err error) {
+				require.ErrorIs(t, err, ErrRevokedAPIKey)
+			},
+		},
+		{
+			name:     "Expired",
+			key:      key,
+			clientIP: "127.0.0.1",
+			buildStubs: func(store *mockDB.MockStore) {
+				expiredKey := apiKey
+				expiredKey.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).Times(1).Return(expiredKey, nil)
+			},
+			check: func(t *testing.T, payload *APIKeyPayload, err error) {
+				require.ErrorIs(t, err, ErrExpiredToken)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockDB.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			verifier := NewStoreAPIKeyVerifier(store)
+			payload, err := verifier.VerifyAPIKey(context.Background(), tc.key, tc.clientIP)
+			tc.check(t, payload, err)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDTokenSigner(t *testing.T) {
+	symmetricKey := util.RandomString(32)
+
+	signer, err := NewIDTokenSigner(symmetricKey)
+	require.NoError(t, err)
+
+	claims := IDTokenClaims{
+		Nonce: util.RandomString(10),
+		Email: util.RandomEmail(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   "https://bank.example",
+			Subject:  util.RandomOwner(),
+			Audience: jwt.ClaimStrings{util.RandomString(12)},
+		},
+	}
+
+	idToken, err := signer.SignIDToken(claims, time.Minute)
+	require.NoError(t, err)
+
+	verified, err := signer.VerifyIDToken(idToken)
+	require.NoError(t, err)
+	require.Equal(t, claims.Subject, verified.Subject)
+	require.Equal(t, claims.Audience, verified.Audience)
+	require.Equal(t, claims.Nonce, verified.Nonce)
+	require.Equal(t, claims.Email, verified.Email)
+
+	// every instance derives the same key from the shared secret
+	other, err := NewIDTokenSigner(symmetricKey)
+	require.NoError(t, err)
+	require.Equal(t, signer.PublicKey(), other.PublicKey())
+
+	other, err = NewIDTokenSigner(util.RandomString(32))
+	require.NoError(t, err)
+	_, err = other.VerifyIDToken(idToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expired, err := signer.SignIDToken(claims, -time.Minute)
+	require.NoError(t, err)
+	_, err = signer.VerifyIDToken(expired)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -28,20 +28,35 @@ func NewJwtMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
+func (maker *JWTMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.CreateScopedToken(username, role, "", nil, duration)
+}
+
+func (maker *JWTMaker) CreateScopedToken(username string, role string, clientID string, scopes []string, duration time.Duration) (string, *Payload, error) {
 	// create the payload
-	payload, err := NewPayload(username, duration)
+	payload, err := NewPayload(username, role, duration)
 	if err != nil {
 		return "", nil, err
 	}
 
-	// create the token
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	payload.ClientID = clientID
+	payload.Scopes = scopes
+
+	claims := jwt.MapClaims{
 		"id":         payload.ID,
 		"username":   payload.Username,
+		"role":       payload.Role,
 		"issued_at":  payload.IssuedAt,
 		"expires_at": payload.ExpiresAt,
-	})
+	}
+
+	if clientID != "" {
+		claims["client_id"] = clientID
+		claims["scopes"] = scopes
+	}
+
+	// create the token
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// sign the token
 	token, err := jwtToken.SignedString([]byte(maker.secretKey))
@@ -88,6 +103,11 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
+	role, ok := claims["role"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
 	issuedAtFloat, ok := claims["issued_at"].(float64)
 	if !ok {
 		return nil, ErrInvalidToken
@@ -98,6 +118,17 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
+	// optional claims of tokens issued to third-party apps
+	clientID, _ := claims["client_id"].(string)
+	var scopes []string
+	if rawScopes, ok := claims["scopes"].([]any); ok {
+		for _, rawScope := range rawScopes {
+			if scope, ok := rawScope.(string); ok {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
 	issuedAt := time.Unix(int64(issuedAtFloat), 0)
 	expiresAt := time.Unix(int64(expiresAtFloat), 0)
 
@@ -108,8 +139,11 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 	payload := &Payload{
 		ID:        id,
 		Username:  username,
+		Role:      role,
 		IssuedAt:  issuedAt,
 		ExpiresAt: expiresAt,
+		ClientID:  clientID,
+		Scopes:    scopes,
 	}
 
 	return payload, nil
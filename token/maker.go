@@ -3,6 +3,8 @@ package token
 import "time"
 
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
+	// CreateScopedToken issues a token to a third-party app which can only act within the given scopes
+	CreateScopedToken(username string, role string, clientID string, scopes []string, duration time.Duration) (string, *Payload, error)
 	VerifyToken(token string) (*Payload, error)
 }
@@ -0,0 +1,57 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// CodeChallengeMethodS256 is the only pkce method accepted, plain challenges are rejected
+const CodeChallengeMethodS256 = "S256"
+
+const (
+	oauthSecretBytes   = 32
+	minCodeVerifierLen = 43
+	maxCodeVerifierLen = 128
+)
+
+// GenerateOAuthSecret creates a random client secret or authorization code.
+// Only the hash is stored, the secret itself is handed to the client once.
+func GenerateOAuthSecret() (secret string, hashedSecret string, err error) {
+	secretBytes := make([]byte, oauthSecretBytes)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", fmt.Errorf("cannot generate oauth secret: %w", err)
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(secretBytes)
+	return secret, HashOAuthSecret(secret), nil
+}
+
+// HashOAuthSecret hashes a client secret or authorization code. Both are random so a fast hash is enough.
+func HashOAuthSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckOAuthSecret compares a secret with a stored hash in constant time
+func CheckOAuthSecret(secret string, hashedSecret string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashOAuthSecret(secret)), []byte(hashedSecret)) == 1
+}
+
+// CodeChallenge derives the pkce S256 challenge of a code verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyCodeChallenge checks a pkce code verifier against the S256 challenge sent with the authorization request
+func VerifyCodeChallenge(verifier string, challenge string) bool {
+	if len(verifier) < minCodeVerifierLen || len(verifier) > maxCodeVerifierLen {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(CodeChallenge(verifier)), []byte(challenge)) == 1
+}
@@ -0,0 +1,35 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOAuthSecret(t *testing.T) {
+	secret, hashedSecret, err := GenerateOAuthSecret()
+	require.NoError(t, err)
+	require.NotEmpty(t, secret)
+	require.NotEqual(t, secret, hashedSecret)
+
+	require.True(t, CheckOAuthSecret(secret, hashedSecret))
+	require.False(t, CheckOAuthSecret(secret+"x", hashedSecret))
+	require.False(t, CheckOAuthSecret("", hashedSecret))
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	verifier := util.RandomString(64)
+	challenge := CodeChallenge(verifier)
+
+	require.True(t, VerifyCodeChallenge(verifier, challenge))
+	require.False(t, VerifyCodeChallenge(util.RandomString(64), challenge))
+	require.False(t, VerifyCodeChallenge(verifier, verifier))
+
+	// verifiers must be 43-128 characters long
+	short := util.RandomString(42)
+	require.False(t, VerifyCodeChallenge(short, CodeChallenge(short)))
+
+	long := util.RandomString(129)
+	require.False(t, VerifyCodeChallenge(long, CodeChallenge(long)))
+}
@@ -26,13 +26,20 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-func (pm *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+func (pm *PasetoMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return pm.CreateScopedToken(username, role, "", nil, duration)
+}
+
+func (pm *PasetoMaker) CreateScopedToken(username string, role string, clientID string, scopes []string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
 
 	if err != nil {
 		return "", payload, err
 	}
 
+	payload.ClientID = clientID
+	payload.Scopes = scopes
+
 	token, err := pm.paseto.Encrypt(pm.symmetricKey, payload, nil)
 
 	if err != nil {
@@ -13,19 +13,49 @@ func TestPaestoMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, _, err := maker.CreateToken(username, duration)
+	token, _, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 
 	payload, err := maker.VerifyToken(token)
 	require.NoError(t, err)
 
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
 	require.NotZero(t, payload.ID)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiresAt, time.Second)
 }
+
+func TestPasetoMakerScopedToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	clientID := util.RandomString(12)
+	scopes := []string{ScopeAccountsRead, ScopeOpenID}
+
+	token, _, err := maker.CreateScopedToken(username, util.DepositorRole, clientID, scopes, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+
+	require.True(t, payload.IsDelegated())
+	require.Equal(t, clientID, payload.ClientID)
+	require.Equal(t, scopes, payload.Scopes)
+	require.True(t, payload.HasScope(ScopeAccountsRead))
+	require.False(t, payload.HasScope(ScopeTransfersCreate))
+
+	token, _, err = maker.CreateToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.False(t, payload.IsDelegated())
+}
@@ -1,6 +1,7 @@
 package token
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,11 +10,14 @@ import (
 type Payload struct {
 	ID        uuid.UUID `json:"id"` // this field will be use to validate leaked tokens
 	Username  string    `json:"username"`
+	Role      string    `json:"role"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiresAt time.Time `json:"expires_at"`
+	ClientID  string    `json:"client_id,omitempty"` // set when the token was issued to a third-party app
+	Scopes    []string  `json:"scopes,omitempty"`
 }
 
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role string, duration time.Duration) (*Payload, error) {
 	tokenId, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -22,6 +26,7 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenId,
 		Username:  username,
+		Role:      role,
 		IssuedAt:  time.Now(),
 		ExpiresAt: time.Now().Add(duration),
 	}
@@ -36,3 +41,17 @@ func (payload *Payload) Valid() error {
 
 	return nil
 }
+
+// IsDelegated reports whether the token was issued to a third-party app acting for the user
+func (payload *Payload) IsDelegated() bool {
+	return payload.ClientID != ""
+}
+
+func (payload *Payload) HasScope(scopes ...string) bool {
+	for _, scope := range scopes {
+		if slices.Contains(payload.Scopes, scope) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/google/uuid"
+)
+
+// RevocationList keeps track of tokens that must be rejected before they expire
+type RevocationList interface {
+	IsRevoked(ctx context.Context, payload *Payload) (bool, error)
+	Revoke(ctx context.Context, payload *Payload, revokedBy string) error
+	RevokeUser(ctx context.Context, username string, revokedBy string) error
+}
+
+// StoreRevocationList keeps revoked tokens in postgres so every server instance sees them
+type StoreRevocationList struct {
+	store db.Querier
+}
+
+func NewStoreRevocationList(store db.Querier) RevocationList {
+	return &StoreRevocationList{store: store}
+}
+
+func (list *StoreRevocationList) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	return list.store.IsTokenRevoked(ctx, db.IsTokenRevokedParams{
+		ID:       payload.ID,
+		Username: payload.Username,
+		IssuedAt: payload.IssuedAt,
+	})
+}
+
+func (list *StoreRevocationList) Revoke(ctx context.Context, payload *Payload, revokedBy string) error {
+	_, err := list.store.CreateRevokedToken(ctx, db.CreateRevokedTokenParams{
+		ID:        payload.ID,
+		Username:  payload.Username,
+		RevokedBy: revokedBy,
+		ExpiredAt: payload.ExpiresAt,
+	})
+	return err
+}
+
+func (list *StoreRevocationList) RevokeUser(ctx context.Context, username string, revokedBy string) error {
+	_, err := list.store.UpsertUserTokenRevocation(ctx, db.UpsertUserTokenRevocationParams{
+		Username:      username,
+		RevokedBy:     revokedBy,
+		RevokedBefore: time.Now(),
+	})
+	return err
+}
+
+// MemoryRevocationList keeps revoked tokens in process memory. It is meant for tests and single instance setups
+type MemoryRevocationList struct {
+	mu            sync.RWMutex
+	tokens        map[uuid.UUID]time.Time
+	revokedBefore map[string]time.Time
+}
+
+func NewMemoryRevocationList() RevocationList {
+	return &MemoryRevocationList{
+		tokens:        make(map[uuid.UUID]time.Time),
+		revokedBefore: make(map[string]time.Time),
+	}
+}
+
+func (list *MemoryRevocationList) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	list.mu.RLock()
+	defer list.mu.RUnlock()
+
+	if _, ok := list.tokens[payload.ID]; ok {
+		return true, nil
+	}
+
+	if before, ok := list.revokedBefore[payload.Username]; ok && !payload.IssuedAt.After(before) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (list *MemoryRevocationList) Revoke(ctx context.Context, payload *Payload, revokedBy string) error {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	now := time.Now()
+	for id, expiredAt := range list.tokens {
+		if expiredAt.Before(now) {
+			delete(list.tokens, id)
+		}
+	}
+
+	list.tokens[payload.ID] = payload.ExpiresAt
+	return nil
+}
+
+func (list *MemoryRevocationList) RevokeUser(ctx context.Context, username string, revokedBy string) error {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	list.revokedBefore[username] = time.Now()
+	return nil
+}
+
+const maxCachedRevocations = 10_000
+
+type cachedRevocation struct {
+	username  string
+	revoked   bool
+	expiredAt time.Time
+}
+
+// CachedRevocationList avoids a database round trip on every authenticated request.
+// A revoked token stays cached until it expires, a valid one is re-checked after ttl,
+// so revocations made by other instances are picked up within ttl.
+type CachedRevocationList struct {
+	next    RevocationList
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedRevocation
+}
+
+func NewCachedRevocationList(next RevocationList, ttl time.Duration) RevocationList {
+	return &CachedRevocationList{
+		next:    next,
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]cachedRevocation),
+	}
+}
+
+func (list *CachedRevocationList) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	now := time.Now()
+
+	list.mu.Lock()
+	entry, ok := list.entries[payload.ID]
+	list.mu.Unlock()
+
+	if ok && now.Before(entry.expiredAt) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := list.next.IsRevoked(ctx, payload)
+	if err != nil {
+		return false, err
+	}
+
+	expiredAt := payload.ExpiresAt
+	if !revoked && now.Add(list.ttl).Before(expiredAt) {
+		expiredAt = now.Add(list.ttl)
+	}
+
+	list.mu.Lock()
+	if len(list.entries) >= maxCachedRevocations {
+		list.evictExpired(now)
+	}
+	list.entries[payload.ID] = cachedRevocation{
+		username:  payload.Username,
+		revoked:   revoked,
+		expiredAt: expiredAt,
+	}
+	list.mu.Unlock()
+
+	return revoked, nil
+}
+
+func (list *CachedRevocationList) Revoke(ctx context.Context, payload *Payload, revokedBy string) error {
+	if err := list.next.Revoke(ctx, payload, revokedBy); err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	list.evictExpired(time.Now())
+	list.entries[payload.ID] = cachedRevocation{
+		username:  payload.Username,
+		revoked:   true,
+		expiredAt: payload.ExpiresAt,
+	}
+
+	return nil
+}
+
+func (list *CachedRevocationList) RevokeUser(ctx context.Context, username string, revokedBy string) error {
+	if err := list.next.RevokeUser(ctx, username, revokedBy); err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	for id, entry := range list.entries {
+		if entry.username == username {
+			delete(list.entries, id)
+		}
+	}
+
+	return nil
+}
+
+func (list *CachedRevocationList) evictExpired(now time.Time) {
+	for id, entry := range list.entries {
+		if entry.expiredAt.Before(now) {
+			delete(list.entries, id)
+		}
+	}
+}
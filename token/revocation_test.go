@@ -0,0 +1,91 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRevocationList(t *testing.T) {
+	list := NewMemoryRevocationList()
+	ctx := context.Background()
+	username := util.RandomOwner()
+
+	payload1, err := NewPayload(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload2, err := NewPayload(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	revoked, err := list.IsRevoked(ctx, payload1)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, list.Revoke(ctx, payload1, username))
+
+	revoked, err = list.IsRevoked(ctx, payload1)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = list.IsRevoked(ctx, payload2)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, list.RevokeUser(ctx, username, username))
+
+	revoked, err = list.IsRevoked(ctx, payload2)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	// tokens issued after the revocation are still valid
+	payload3, err := NewPayload(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	revoked, err = list.IsRevoked(ctx, payload3)
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestCachedRevocationList(t *testing.T) {
+	ctx := context.Background()
+	username := util.RandomOwner()
+
+	backend := NewMemoryRevocationList()
+	list := NewCachedRevocationList(backend, time.Hour)
+
+	payload, err := NewPayload(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	revoked, err := list.IsRevoked(ctx, payload)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// a revocation made by another instance is hidden until the cached entry expires
+	require.NoError(t, backend.Revoke(ctx, payload, username))
+
+	revoked, err = list.IsRevoked(ctx, payload)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// a revocation made through the cache is visible right away
+	require.NoError(t, list.Revoke(ctx, payload, username))
+
+	revoked, err = list.IsRevoked(ctx, payload)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	other, err := NewPayload(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	revoked, err = list.IsRevoked(ctx, other)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, list.RevokeUser(ctx, username, username))
+
+	revoked, err = list.IsRevoked(ctx, other)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
@@ -0,0 +1,21 @@
+package token
+
+// Scopes limit what api keys and tokens issued to third-party apps can do on behalf of a user
+const (
+	ScopeAccountsRead    = "accounts:read"
+	ScopeTransfersRead   = "transfers:read"
+	ScopeTransfersCreate = "transfers:create"
+)
+
+// OpenID Connect scopes, they only grant access to the user's identity
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// APIKeyScopes lists every scope an api key can be granted
+var APIKeyScopes = []string{ScopeAccountsRead, ScopeTransfersRead, ScopeTransfersCreate}
+
+// OAuthScopes lists every scope a third-party app can ask a user for
+var OAuthScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopeAccountsRead, ScopeTransfersRead, ScopeTransfersCreate}
@@ -0,0 +1,29 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const verifyEmailCodeBytes = 32
+
+// GenerateVerifyEmailCode creates the secret code of an email verification link.
+// Only the hash is stored, the code itself is only sent to the email being verified.
+func GenerateVerifyEmailCode() (code string, hashedCode string, err error) {
+	codeBytes := make([]byte, verifyEmailCodeBytes)
+	if _, err = rand.Read(codeBytes); err != nil {
+		return "", "", fmt.Errorf("cannot generate verify email code: %w", err)
+	}
+
+	code = base64.RawURLEncoding.EncodeToString(codeBytes)
+	return code, HashVerifyEmailCode(code), nil
+}
+
+// HashVerifyEmailCode hashes the secret code of an email verification link, it is random so a fast hash is enough
+func HashVerifyEmailCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
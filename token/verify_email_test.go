@@ -0,0 +1,19 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateVerifyEmailCode(t *testing.T) {
+	code, hashedCode, err := GenerateVerifyEmailCode()
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	require.NotEqual(t, code, hashedCode)
+	require.Equal(t, hashedCode, HashVerifyEmailCode(code))
+
+	other, _, err := GenerateVerifyEmailCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code, other)
+}
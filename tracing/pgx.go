@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const sqlcNamePrefix = "-- name: "
+
+// QueryTracer starts a span for every query run through pgx, named after the sqlc query when there is one
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = Tracer().Start(ctx, "db "+queryName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", data.SQL),
+		),
+	)
+
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+
+	span.End()
+}
+
+// queryName reads the name sqlc puts on the first line of every generated query, e.g. "-- name: GetAccountById :one"
+func queryName(sql string) string {
+	if !strings.HasPrefix(sql, sqlcNamePrefix) {
+		return "query"
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(sql, sqlcNamePrefix))
+	if len(fields) == 0 {
+		return "query"
+	}
+
+	return fields[0]
+}
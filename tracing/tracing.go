@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ServiceName = "houseBank"
+
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Tracer is used for the spans the repo creates itself, the instrumentation libraries bring their own
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/AnkitNayan83/houseBank")
+}
+
+// Init installs the global tracer provider and the w3c trace context propagator.
+// Without an exporter configured spans are still propagated but never recorded.
+// The returned function flushes the spans that are still buffered.
+func Init(ctx context.Context, config util.Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch config.TracingExporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracegrpc.Option
+		// otherwise the exporter reads the standard OTEL_EXPORTER_OTLP_* variables
+		if config.OTLPEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(config.OTLPEndpoint))
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", config.TracingExporter)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s trace exporter: %w", config.TracingExporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+		attribute.String("deployment.environment", config.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Inject writes the trace context of ctx into a carrier that can travel with a background task
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx with the remote trace context read from a carrier written by Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
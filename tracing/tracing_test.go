@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	_, err := Init(context.Background(), util.Config{})
+	require.NoError(t, err)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	require.Nil(t, Inject(context.Background()))
+
+	ctx, span := Tracer().Start(context.Background(), "test")
+	defer span.End()
+
+	carrier := Inject(ctx)
+	require.Contains(t, carrier, "traceparent")
+
+	remote := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	require.True(t, remote.IsRemote())
+	require.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	require.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+}
+
+func TestInitUnsupportedExporter(t *testing.T) {
+	_, err := Init(context.Background(), util.Config{TracingExporter: "zipkin"})
+	require.Error(t, err)
+}
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "GetAccountById", queryName("-- name: GetAccountById :one\nSELECT 1"))
+	require.Equal(t, "query", queryName("SELECT 1"))
+	require.Equal(t, "query", queryName("-- name: "))
+}
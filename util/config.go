@@ -17,6 +17,15 @@ type Config struct {
 	REFRESH_TOKEN_DURATION time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
 	Environment            string        `mapstructure:"ENVIRONMENT"`
 	RedisAddress           string        `mapstructure:"REDIS_ADDRESS"`
+	RevocationCacheTTL     time.Duration `mapstructure:"REVOCATION_CACHE_TTL"`
+	OAuthIssuer            string        `mapstructure:"OAUTH_ISSUER"`
+	OutboxRelayInterval    time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	ShutdownTimeout        time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	TracingExporter        string        `mapstructure:"TRACING_EXPORTER"`
+	OTLPEndpoint           string        `mapstructure:"OTLP_ENDPOINT"`
+	RateLimits             string        `mapstructure:"RATE_LIMITS"`
+	RateLimitBackend       string        `mapstructure:"RATE_LIMIT_BACKEND"`
+	MaxAccountsPerUser     int64         `mapstructure:"MAX_ACCOUNTS_PER_USER"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -1,15 +1,108 @@
 package util
 
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Constants for all the supported currencies
 const (
 	USD = "USD"
 	INR = "INR"
 )
 
+// Currency is an ISO 4217 currency, amounts in it are counted in minor units, 10^Exponent of them make one unit
+type Currency struct {
+	Code     string
+	Exponent int32
+	Symbol   string
+	Enabled  bool
+}
+
+// defaultCurrencies are used until the registry is loaded from the currencies table, which is seeded with the same ones
+var defaultCurrencies = []Currency{
+	{Code: USD, Exponent: 2, Symbol: "$", Enabled: true},
+	{Code: INR, Exponent: 2, Symbol: "₹", Enabled: true},
+}
+
+var currencyRegistry = struct {
+	sync.RWMutex
+	byCode map[string]Currency
+}{
+	byCode: currencyMap(defaultCurrencies),
+}
+
+func currencyMap(currencies []Currency) map[string]Currency {
+	byCode := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		byCode[currency.Code] = currency
+	}
+	return byCode
+}
+
+// SetCurrencies replaces every currency of the registry
+func SetCurrencies(currencies []Currency) {
+	byCode := currencyMap(currencies)
+
+	currencyRegistry.Lock()
+	defer currencyRegistry.Unlock()
+	currencyRegistry.byCode = byCode
+}
+
+// LookupCurrency returns the currency with the code, whether it is enabled or not
+func LookupCurrency(code string) (Currency, bool) {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+
+	currency, ok := currencyRegistry.byCode[code]
+	return currency, ok
+}
+
+// SupportedCurrencies returns the sorted codes of the enabled currencies
+func SupportedCurrencies() []string {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+
+	codes := make([]string, 0, len(currencyRegistry.byCode))
+	for code, currency := range currencyRegistry.byCode {
+		if currency.Enabled {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+
+	return codes
+}
+
+// IsSupportedCurrency returns true if the currency is registered and enabled
 func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, INR:
-		return true
-	default:
-		return false
+	c, ok := LookupCurrency(currency)
+	return ok && c.Enabled
+}
+
+// FormatAmount writes an amount of minor units as a decimal string, 12345 USD is "123.45".
+// Amounts of unknown currencies are written as they are.
+func FormatAmount(amount int64, currency string) string {
+	c, ok := LookupCurrency(currency)
+	if !ok || c.Exponent <= 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	magnitude := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = -magnitude
 	}
+
+	digits := strconv.FormatUint(magnitude, 10)
+	exponent := int(c.Exponent)
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	split := len(digits) - exponent
+	return sign + digits[:split] + "." + digits[split:]
 }
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	SetCurrencies([]Currency{
+		{Code: USD, Exponent: 2, Symbol: "$", Enabled: true},
+		{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true},
+		{Code: "KWD", Exponent: 3, Symbol: "KD", Enabled: false},
+	})
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+
+	testCases := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{12345, USD, "123.45"},
+		{5, USD, "0.05"},
+		{0, USD, "0.00"},
+		{-1050, USD, "-10.50"},
+		{1500, "JPY", "1500"},
+		{1, "KWD", "0.001"},
+		{42, "XXX", "42"},
+		{math.MinInt64, USD, "-92233720368547758.08"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, FormatAmount(tc.amount, tc.currency))
+	}
+}
+
+func TestCurrencyRegistry(t *testing.T) {
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+
+	require.True(t, IsSupportedCurrency(USD))
+	require.True(t, IsSupportedCurrency(INR))
+	require.Equal(t, []string{INR, USD}, SupportedCurrencies())
+
+	SetCurrencies([]Currency{
+		{Code: USD, Exponent: 2, Symbol: "$", Enabled: true},
+		{Code: INR, Exponent: 2, Symbol: "₹", Enabled: false},
+		{Code: "EUR", Exponent: 2, Symbol: "€", Enabled: true},
+	})
+
+	require.True(t, IsSupportedCurrency("EUR"))
+	require.False(t, IsSupportedCurrency(INR))
+	require.False(t, IsSupportedCurrency("GBP"))
+	require.Equal(t, []string{"EUR", USD}, SupportedCurrencies())
+
+	// disabled currencies still format the balances of existing accounts
+	currency, ok := LookupCurrency(INR)
+	require.True(t, ok)
+	require.Equal(t, int32(2), currency.Exponent)
+}
@@ -0,0 +1,59 @@
+package util
+
+import "google.golang.org/grpc/codes"
+
+const InternalErrorMessage = "internal server error"
+
+// ErrorResponse is the error model of every REST and gateway response
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Code       string           `json:"code"`
+	Message    string           `json:"message"`
+	Violations []FieldViolation `json:"violations,omitempty"`
+	RequestID  string           `json:"request_id,omitempty"`
+}
+
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+var errorCodes = map[codes.Code]string{
+	codes.OK:                 "ok",
+	codes.Canceled:           "cancelled",
+	codes.Unknown:            "unknown",
+	codes.InvalidArgument:    "invalid_argument",
+	codes.DeadlineExceeded:   "deadline_exceeded",
+	codes.NotFound:           "not_found",
+	codes.AlreadyExists:      "already_exists",
+	codes.PermissionDenied:   "permission_denied",
+	codes.ResourceExhausted:  "resource_exhausted",
+	codes.FailedPrecondition: "failed_precondition",
+	codes.Aborted:            "aborted",
+	codes.OutOfRange:         "out_of_range",
+	codes.Unimplemented:      "unimplemented",
+	codes.Internal:           "internal",
+	codes.Unavailable:        "unavailable",
+	codes.DataLoss:           "data_loss",
+	codes.Unauthenticated:    "unauthenticated",
+}
+
+// ErrorCode is the stable machine-readable name of a grpc code, the same on REST and gRPC, e.g. "invalid_argument"
+func ErrorCode(code codes.Code) string {
+	if name, ok := errorCodes[code]; ok {
+		return name
+	}
+	return errorCodes[codes.Unknown]
+}
+
+// IsInternalCode reports whether errors with the code must not expose their message to clients
+func IsInternalCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss:
+		return true
+	}
+	return false
+}
@@ -44,9 +44,9 @@ func RandomMoney() int64 {
 	return RandomInt(0, 1000000)
 }
 
-// RandomCurrency provides a random currency name
+// RandomCurrency provides a random enabled currency code
 func RandomCurrency() string {
-	currencies := []string{INR, USD}
+	currencies := SupportedCurrencies()
 	n := len(currencies)
 	return currencies[rand.Intn(n)]
 }
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the request id on http requests and responses
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDMetadataKey carries the request id in grpc metadata
+	RequestIDMetadataKey = "x-request-id"
+)
+
+type requestIDKey struct{}
+
+// ids sent by clients are kept when they are short and cannot be used to inject anything into the logs
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID returns the id sent by the client when it is usable, otherwise a new one
+func RequestID(clientID string) string {
+	if requestIDPattern.MatchString(clientID) {
+		return clientID
+	}
+	return uuid.NewString()
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the id of the request being served, or an empty string outside of a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
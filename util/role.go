@@ -0,0 +1,6 @@
+package util
+
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+)
@@ -2,16 +2,15 @@ package workers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
 )
 
+// TaskDistributor sends tasks to the workers, tasks are built with a TaskDefinition
 type TaskDistributor interface {
-	DistributeTaskSendVerifyEmail(
-		ctx context.Context,
-		payload *PayloadSendVerifyEmail,
-		opts ...asynq.Option,
-	) error
+	DistributeTask(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskDistributor struct {
@@ -24,3 +23,19 @@ func NewRedisTaskDistributor(redisOptions *asynq.RedisClientOpt) TaskDistributor
 		client: client,
 	}
 }
+
+func (distributor *RedisTaskDistributor) DistributeTask(ctx context.Context, task *asynq.Task) error {
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("task enqueued")
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package workers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// States of tasks that failed at least once
+const (
+	TaskStateRetry    = "retry"    // failed, will be retried
+	TaskStateArchived = "archived" // ran out of retries, the dead letter queue
+)
+
+const inspectorPageSize = 100
+
+var ErrTaskNotFound = errors.New("task not found")
+
+type TaskInfo struct {
+	ID            string          `json:"id"`
+	Queue         string          `json:"queue"`
+	Type          string          `json:"type"`
+	State         string          `json:"state"`
+	Payload       json.RawMessage `json:"payload"`
+	MaxRetry      int             `json:"max_retry"`
+	Retried       int             `json:"retried"`
+	LastError     string          `json:"last_error"`
+	LastFailedAt  time.Time       `json:"last_failed_at"`
+	NextProcessAt time.Time       `json:"next_process_at"`
+}
+
+type QueueStats struct {
+	Queue          string `json:"queue"`
+	Pending        int    `json:"pending"`
+	Active         int    `json:"active"`
+	Retry          int    `json:"retry"`
+	Archived       int    `json:"archived"`
+	ProcessedTotal int    `json:"processed_total"`
+	FailedTotal    int    `json:"failed_total"`
+}
+
+// TaskInspector looks into the task queue, so failed tasks can be replayed or dropped without going to redis
+type TaskInspector interface {
+	ListFailedTasks(queue string, state string, taskType string, pageID int, pageSize int) ([]TaskInfo, error)
+	GetTask(queue string, id string) (TaskInfo, error)
+	RunTask(queue string, id string) error
+	DeleteTask(queue string, id string) error
+	QueueStats() ([]QueueStats, error)
+}
+
+type RedisTaskInspector struct {
+	inspector *asynq.Inspector
+}
+
+func NewRedisTaskInspector(redisOptions *asynq.RedisClientOpt) TaskInspector {
+	return &RedisTaskInspector{
+		inspector: asynq.NewInspector(redisOptions),
+	}
+}
+
+// ListFailedTasks lists retry or archived tasks of a queue, optionally only the ones of the given type
+func (inspector *RedisTaskInspector) ListFailedTasks(queue string, state string, taskType string, pageID int, pageSize int) ([]TaskInfo, error) {
+	var list func(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+	switch state {
+	case TaskStateRetry:
+		list = inspector.inspector.ListRetryTasks
+	case TaskStateArchived:
+		list = inspector.inspector.ListArchivedTasks
+	default:
+		return nil, fmt.Errorf("unsupported task state: %s", state)
+	}
+
+	// asynq cannot filter by type, so walk the pages and skip tasks of other types
+	skip := (pageID - 1) * pageSize
+	tasks := []TaskInfo{}
+
+	for page := 1; len(tasks) < pageSize; page++ {
+		infos, err := list(queue, asynq.Page(page), asynq.PageSize(inspectorPageSize))
+		if err != nil {
+			if errors.Is(err, asynq.ErrQueueNotFound) {
+				return tasks, nil
+			}
+			return nil, fmt.Errorf("cannot list %s tasks: %w", state, err)
+		}
+
+		for _, info := range infos {
+			if taskType != "" && info.Type != taskType {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(tasks) < pageSize {
+				tasks = append(tasks, newTaskInfo(info))
+			}
+		}
+
+		if len(infos) < inspectorPageSize {
+			break
+		}
+	}
+
+	return tasks, nil
+}
+
+func (inspector *RedisTaskInspector) GetTask(queue string, id string) (TaskInfo, error) {
+	info, err := inspector.inspector.GetTaskInfo(queue, id)
+	if err != nil {
+		return TaskInfo{}, convertInspectorError(err)
+	}
+
+	return newTaskInfo(info), nil
+}
+
+// RunTask moves a retry or archived task back to pending so it is processed right away
+func (inspector *RedisTaskInspector) RunTask(queue string, id string) error {
+	return convertInspectorError(inspector.inspector.RunTask(queue, id))
+}
+
+func (inspector *RedisTaskInspector) DeleteTask(queue string, id string) error {
+	return convertInspectorError(inspector.inspector.DeleteTask(queue, id))
+}
+
+func (inspector *RedisTaskInspector) QueueStats() ([]QueueStats, error) {
+	queues, err := inspector.inspector.Queues()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list queues: %w", err)
+	}
+
+	stats := make([]QueueStats, 0, len(queues))
+	for _, queue := range queues {
+		info, err := inspector.inspector.GetQueueInfo(queue)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get queue %s: %w", queue, err)
+		}
+
+		stats = append(stats, QueueStats{
+			Queue:          info.Queue,
+			Pending:        info.Pending,
+			Active:         info.Active,
+			Retry:          info.Retry,
+			Archived:       info.Archived,
+			ProcessedTotal: info.ProcessedTotal,
+			FailedTotal:    info.FailedTotal,
+		})
+	}
+
+	return stats, nil
+}
+
+func newTaskInfo(info *asynq.TaskInfo) TaskInfo {
+	payload := json.RawMessage(info.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(string(info.Payload))
+	}
+
+	return TaskInfo{
+		ID:            info.ID,
+		Queue:         info.Queue,
+		Type:          info.Type,
+		State:         info.State.String(),
+		Payload:       payload,
+		MaxRetry:      info.MaxRetry,
+		Retried:       info.Retried,
+		LastError:     info.LastErr,
+		LastFailedAt:  info.LastFailedAt,
+		NextProcessAt: info.NextProcessAt,
+	}
+}
+
+func convertInspectorError(err error) error {
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return ErrTaskNotFound
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hibiken/asynq"
+)
+
+// MemoryTaskDistributor keeps tasks in process memory instead of sending them to redis.
+// It is meant for unit tests: tasks can be inspected, or run through handlers registered on it.
+type MemoryTaskDistributor struct {
+	mu       sync.Mutex
+	tasks    []*asynq.Task
+	handlers map[string]func(context.Context, *asynq.Task) error
+}
+
+func NewMemoryTaskDistributor() *MemoryTaskDistributor {
+	return &MemoryTaskDistributor{
+		handlers: make(map[string]func(context.Context, *asynq.Task) error),
+	}
+}
+
+func (distributor *MemoryTaskDistributor) DistributeTask(ctx context.Context, task *asynq.Task) error {
+	distributor.mu.Lock()
+	defer distributor.mu.Unlock()
+
+	distributor.tasks = append(distributor.tasks, task)
+	return nil
+}
+
+// HandleFunc makes the distributor a TaskMux so processors can register their handlers on it
+func (distributor *MemoryTaskDistributor) HandleFunc(pattern string, handler func(context.Context, *asynq.Task) error) {
+	distributor.mu.Lock()
+	defer distributor.mu.Unlock()
+
+	distributor.handlers[pattern] = handler
+}
+
+// Tasks returns the pending tasks of the given type, or every pending task when taskType is empty
+func (distributor *MemoryTaskDistributor) Tasks(taskType string) []*asynq.Task {
+	distributor.mu.Lock()
+	defer distributor.mu.Unlock()
+
+	var tasks []*asynq.Task
+	for _, task := range distributor.tasks {
+		if taskType == "" || task.Type() == taskType {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// RunPending runs every pending task once through its registered handler, in the order they were distributed
+func (distributor *MemoryTaskDistributor) RunPending(ctx context.Context) error {
+	distributor.mu.Lock()
+	tasks := distributor.tasks
+	distributor.tasks = nil
+	distributor.mu.Unlock()
+
+	for _, task := range tasks {
+		distributor.mu.Lock()
+		handler, ok := distributor.handlers[task.Type()]
+		distributor.mu.Unlock()
+
+		if !ok {
+			return fmt.Errorf("no handler registered for task type: %s", task.Type())
+		}
+
+		if err := handler(ctx, task); err != nil {
+			return fmt.Errorf("task %s failed: %w", task.Type(), err)
+		}
+	}
+
+	return nil
+}
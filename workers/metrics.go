@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// handleTaskError counts every failed attempt, and the tasks that ran out of retries and were archived
+func handleTaskError(ctx context.Context, task *asynq.Task, err error) {
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	if retried >= maxRetry || errors.Is(err, asynq.SkipRetry) {
+		metrics.ObserveTask(task.Type(), metrics.TaskArchived)
+
+		log.Error().
+			Err(err).
+			Str("type", task.Type()).
+			Bytes("payload", task.Payload()).
+			Int("retried", retried).
+			Msg("task archived after its last attempt")
+		return
+	}
+
+	metrics.ObserveTask(task.Type(), metrics.TaskRetry)
+
+	log.Warn().
+		Err(err).
+		Str("type", task.Type()).
+		Int("retried", retried).
+		Int("max_retry", maxRetry).
+		Msg("task failed, will be retried")
+}
+
+// QueueCollector reports the depth of every queue each time the metrics are scraped
+type QueueCollector struct {
+	inspector TaskInspector
+	tasks     *prometheus.Desc
+}
+
+func NewQueueCollector(inspector TaskInspector) *QueueCollector {
+	return &QueueCollector{
+		inspector: inspector,
+		tasks: prometheus.NewDesc(
+			"housebank_worker_queue_tasks",
+			"Number of tasks in a queue by state.",
+			[]string{"queue", "state"},
+			nil,
+		),
+	}
+}
+
+func (collector *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.tasks
+}
+
+func (collector *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := collector.inspector.QueueStats()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(collector.tasks, err)
+		return
+	}
+
+	for _, queue := range stats {
+		states := map[string]int{
+			"pending":         queue.Pending,
+			"active":          queue.Active,
+			TaskStateRetry:    queue.Retry,
+			TaskStateArchived: queue.Archived,
+		}
+
+		for state, count := range states {
+			ch <- prometheus.MustNewConstMetric(collector.tasks, prometheus.GaugeValue, float64(count), queue.Queue, state)
+		}
+	}
+}
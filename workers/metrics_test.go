@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type statsInspector struct {
+	TaskInspector
+	stats []QueueStats
+	err   error
+}
+
+func (inspector *statsInspector) QueueStats() ([]QueueStats, error) {
+	return inspector.stats, inspector.err
+}
+
+func TestQueueCollector(t *testing.T) {
+	collector := NewQueueCollector(&statsInspector{
+		stats: []QueueStats{
+			{Queue: QueueueCritical, Pending: 3, Active: 1, Retry: 2, Archived: 4},
+		},
+	})
+
+	expected := `
+# HELP housebank_worker_queue_tasks Number of tasks in a queue by state.
+# TYPE housebank_worker_queue_tasks gauge
+housebank_worker_queue_tasks{queue="critical",state="active"} 1
+housebank_worker_queue_tasks{queue="critical",state="archived"} 4
+housebank_worker_queue_tasks{queue="critical",state="pending"} 3
+housebank_worker_queue_tasks{queue="critical",state="retry"} 2
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestQueueCollectorInspectorError(t *testing.T) {
+	collector := NewQueueCollector(&statsInspector{err: errors.New("redis is down")})
+
+	_, err := testutil.CollectAndLint(collector)
+	require.Error(t, err)
+}
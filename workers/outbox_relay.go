@@ -0,0 +1,118 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	outboxBatchSize       = 100
+	outboxMaxAttempts     = 10
+	outboxRetryBackoff    = time.Minute
+	outboxCleanupInterval = time.Hour
+	outboxRetention       = 7 * 24 * time.Hour
+)
+
+// OutboxRelay publishes the tasks written to the outbox table to the task queue
+type OutboxRelay struct {
+	store       db.Store
+	distributor TaskDistributor
+	interval    time.Duration
+}
+
+func NewOutboxRelay(store db.Store, distributor TaskDistributor, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		store:       store,
+		distributor: distributor,
+		interval:    interval,
+	}
+}
+
+// Start polls the outbox until ctx is cancelled
+func (relay *OutboxRelay) Start(ctx context.Context) error {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// keep going through full batches so a backlog drains without waiting for the next tick
+		for {
+			result, err := relay.PublishPending(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("cannot publish outbox messages")
+				break
+			}
+			if result.Published+result.Failed < outboxBatchSize || result.Failed > 0 {
+				break
+			}
+		}
+
+		if time.Since(lastCleanup) >= outboxCleanupInterval {
+			if err := relay.store.DeletePublishedOutboxMessages(ctx, time.Now().Add(-outboxRetention)); err != nil {
+				log.Error().Err(err).Msg("cannot delete published outbox messages")
+			}
+			lastCleanup = time.Now()
+		}
+	}
+}
+
+// PublishPending publishes one batch of pending outbox messages
+func (relay *OutboxRelay) PublishPending(ctx context.Context) (db.PublishOutboxTxResult, error) {
+	result, err := relay.store.PublishOutboxTx(ctx, db.PublishOutboxTxParams{
+		Limit:       outboxBatchSize,
+		MaxAttempts: outboxMaxAttempts,
+		Backoff:     outboxRetryBackoff,
+		Publish: func(message db.Outbox) error {
+			return relay.publish(ctx, message)
+		},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if result.Published > 0 || result.Failed > 0 {
+		log.Info().
+			Int("published", result.Published).
+			Int("failed", result.Failed).
+			Msg("outbox relayed")
+	}
+
+	if result.Abandoned > 0 {
+		log.Error().
+			Int("abandoned", result.Abandoned).
+			Int("max_attempts", outboxMaxAttempts).
+			Msg("outbox messages failed for the last time and are no longer published")
+	}
+
+	return result, nil
+}
+
+func (relay *OutboxRelay) publish(ctx context.Context, message db.Outbox) error {
+	task := asynq.NewTask(
+		message.TaskType,
+		message.Payload,
+		asynq.Queue(message.Queue),
+		asynq.MaxRetry(int(message.MaxRetry)),
+		// the id lets the queue drop a task published twice when marking it published failed
+		asynq.TaskID(fmt.Sprintf("outbox:%d", message.ID)),
+	)
+
+	err := relay.distributor.DistributeTask(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type failingDistributor struct {
+	err error
+}
+
+func (distributor *failingDistributor) DistributeTask(ctx context.Context, task *asynq.Task) error {
+	return distributor.err
+}
+
+func publishOutboxTx(messages ...db.Outbox) func(ctx context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
+	return func(ctx context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
+		var result db.PublishOutboxTxResult
+		for _, message := range messages {
+			if err := arg.Publish(message); err != nil {
+				result.Failed++
+				continue
+			}
+			result.Published++
+		}
+		return result, nil
+	}
+}
+
+func TestOutboxRelayPublishPending(t *testing.T) {
+	taskPayload := &PayloadSendVerifyEmail{Username: util.RandomOwner()}
+	message, err := SendVerifyEmail.OutboxMessage(context.Background(), taskPayload)
+	require.NoError(t, err)
+
+	outbox := db.Outbox{
+		ID:       util.RandomInt(1, 1000),
+		TaskType: message.TaskType,
+		Payload:  message.Payload,
+		Queue:    message.Queue,
+		MaxRetry: message.MaxRetry,
+	}
+
+	testCases := []struct {
+		name          string
+		distributor   func() TaskDistributor
+		checkResponse func(t *testing.T, distributor TaskDistributor, result db.PublishOutboxTxResult)
+	}{
+		{
+			name: "OK",
+			distributor: func() TaskDistributor {
+				return NewMemoryTaskDistributor()
+			},
+			checkResponse: func(t *testing.T, distributor TaskDistributor, result db.PublishOutboxTxResult) {
+				require.Equal(t, 1, result.Published)
+
+				tasks := distributor.(*MemoryTaskDistributor).Tasks(TaskSendVerifyEmail)
+				require.Len(t, tasks, 1)
+
+				payload, err := SendVerifyEmail.Decode(tasks[0])
+				require.NoError(t, err)
+				require.Equal(t, taskPayload, payload)
+			},
+		},
+		{
+			name: "AlreadyPublished",
+			distributor: func() TaskDistributor {
+				return &failingDistributor{err: asynq.ErrTaskIDConflict}
+			},
+			checkResponse: func(t *testing.T, distributor TaskDistributor, result db.PublishOutboxTxResult) {
+				require.Equal(t, 1, result.Published)
+			},
+		},
+		{
+			name: "QueueUnavailable",
+			distributor: func() TaskDistributor {
+				return &failingDistributor{err: errors.New("redis is down")}
+			},
+			checkResponse: func(t *testing.T, distributor TaskDistributor, result db.PublishOutboxTxResult) {
+				require.Equal(t, 0, result.Published)
+				require.Equal(t, 1, result.Failed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockDB.NewMockStore(ctrl)
+			store.EXPECT().
+				PublishOutboxTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(publishOutboxTx(outbox))
+
+			distributor := tc.distributor()
+			relay := NewOutboxRelay(store, distributor, 0)
+
+			result, err := relay.PublishPending(context.Background())
+			require.NoError(t, err)
+			tc.checkResponse(t, distributor, result)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package workers
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	db "github.com/AnkitNayan83/houseBank/db/sqlc"
 	"github.com/hibiken/asynq"
@@ -14,32 +16,71 @@ const (
 
 type TaskProcessor interface {
 	Start() error
-	ProcessSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	Shutdown()
+	RegisterHandlers(mux TaskMux)
+	ProcessSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail) error
+	ProcessDeliverWebhook(ctx context.Context, payload *PayloadDeliverWebhook) error
+	ProcessPayDueAllowances(ctx context.Context, payload *PayloadPayDueAllowances) error
+	ProcessPayAllowance(ctx context.Context, payload *PayloadPayAllowance) error
+	ProcessExpireMoneyRequests(ctx context.Context, payload *PayloadExpireMoneyRequests) error
+	ProcessExpireMoneyRequest(ctx context.Context, payload *PayloadExpireMoneyRequest) error
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
+	server        *asynq.Server
+	store         db.Store
+	webhookClient *http.Client
+	// enqueues the tasks periodic tasks fan out to
+	distributor TaskDistributor
 }
 
-func NewRedisTaskPorcessor(redisOptions *asynq.RedisClientOpt, store db.Store) TaskProcessor {
+func NewRedisTaskPorcessor(redisOptions *asynq.RedisClientOpt, store db.Store, distributor TaskDistributor, shutdownTimeout time.Duration) TaskProcessor {
 	server := asynq.NewServer(redisOptions, asynq.Config{
 		Concurrency: 10,
 		Queues: map[string]int{
 			QueueueCritical: 10,
 			QueueueDefault:  5,
 		},
+		ErrorHandler:    asynq.ErrorHandlerFunc(handleTaskError),
+		RetryDelayFunc:  retryDelay,
+		ShutdownTimeout: shutdownTimeout,
 	})
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
+		server:        server,
+		store:         store,
+		webhookClient: &http.Client{Timeout: webhookTimeout},
+		distributor:   distributor,
 	}
 }
 
 func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 
-	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessSendVerifyEmail)
+	processor.RegisterHandlers(mux)
 
 	return processor.server.Start(mux)
 }
+
+// Shutdown stops pulling new tasks and waits for active ones up to the shutdown timeout
+func (processor *RedisTaskProcessor) Shutdown() {
+	processor.server.Shutdown()
+}
+
+// RegisterHandlers wires every task definition to its handler
+func (processor *RedisTaskProcessor) RegisterHandlers(mux TaskMux) {
+	SendVerifyEmail.Handle(mux, processor.ProcessSendVerifyEmail)
+	DeliverWebhook.Handle(mux, processor.ProcessDeliverWebhook)
+	PayDueAllowances.Handle(mux, processor.ProcessPayDueAllowances)
+	PayAllowance.Handle(mux, processor.ProcessPayAllowance)
+	ExpireMoneyRequests.Handle(mux, processor.ProcessExpireMoneyRequests)
+	ExpireMoneyRequest.Handle(mux, processor.ProcessExpireMoneyRequest)
+}
+
+// retryDelay backs webhook deliveries off exponentially, so an endpoint that is down gets hours to recover
+func retryDelay(retried int, err error, task *asynq.Task) time.Duration {
+	if task.Type() == TaskDeliverWebhook {
+		return webhookRetryDelay(retried)
+	}
+
+	return asynq.DefaultRetryDelayFunc(retried, err, task)
+}
@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// PeriodicTask is a task the scheduler enqueues on its own, once every interval
+type PeriodicTask struct {
+	Interval time.Duration
+	Task     *asynq.Task
+}
+
+// PeriodicTasks are the tasks run on a schedule rather than for a request.
+// Each one is unique for its interval, so every instance running a scheduler does not make it run more often.
+func PeriodicTasks() ([]PeriodicTask, error) {
+	payDueAllowances, err := PayDueAllowances.NewTask(context.Background(), &PayloadPayDueAllowances{}, asynq.Unique(allowancePayInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	expireMoneyRequests, err := ExpireMoneyRequests.NewTask(context.Background(), &PayloadExpireMoneyRequests{}, asynq.Unique(moneyRequestExpireInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	return []PeriodicTask{
+		{Interval: allowancePayInterval, Task: payDueAllowances},
+		{Interval: moneyRequestExpireInterval, Task: expireMoneyRequests},
+	}, nil
+}
+
+// TaskScheduler enqueues the periodic tasks for the processors to run
+type TaskScheduler interface {
+	Start() error
+	Shutdown()
+}
+
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+}
+
+func NewRedisTaskScheduler(redisOptions *asynq.RedisClientOpt) (TaskScheduler, error) {
+	scheduler := asynq.NewScheduler(redisOptions, &asynq.SchedulerOpts{
+		Location: time.UTC,
+		EnqueueErrorHandler: func(task *asynq.Task, opts []asynq.Option, err error) {
+			// the previous run is still queued or running
+			if errors.Is(err, asynq.ErrDuplicateTask) {
+				return
+			}
+			log.Error().Err(err).Str("type", task.Type()).Msg("cannot enqueue periodic task")
+		},
+	})
+
+	tasks, err := PeriodicTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, periodic := range tasks {
+		if _, err := scheduler.Register(fmt.Sprintf("@every %s", periodic.Interval), periodic.Task); err != nil {
+			return nil, fmt.Errorf("failed to register periodic task %s: %w", periodic.Task.Type(), err)
+		}
+	}
+
+	return &RedisTaskScheduler{scheduler: scheduler}, nil
+}
+
+// Start enqueues the periodic tasks in the background until Shutdown
+func (scheduler *RedisTaskScheduler) Start() error {
+	return scheduler.scheduler.Start()
+}
+
+func (scheduler *RedisTaskScheduler) Shutdown() {
+	scheduler.scheduler.Shutdown()
+}
@@ -0,0 +1,165 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/metrics"
+	"github.com/AnkitNayan83/houseBank/tracing"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceContextField is the payload field holding the w3c trace context of the enqueuing request
+const traceContextField = "trace_context"
+
+// TaskMux is where task handlers get registered, *asynq.ServeMux satisfies it
+type TaskMux interface {
+	HandleFunc(pattern string, handler func(context.Context, *asynq.Task) error)
+}
+
+// TaskDefinition declares a background job once: its type name, payload struct, queue and retry policy.
+// It gives a type-safe way to enqueue the job and to register its handler.
+type TaskDefinition[P any] struct {
+	Type     string
+	Queue    string
+	MaxRetry int
+}
+
+func NewTaskDefinition[P any](taskType string, queue string, maxRetry int) TaskDefinition[P] {
+	return TaskDefinition[P]{
+		Type:     taskType,
+		Queue:    queue,
+		MaxRetry: maxRetry,
+	}
+}
+
+// NewTask builds the asynq task for the payload, opts override the definition's queue and retry policy.
+// The trace context of ctx travels in the payload, so the worker span joins the trace of the request.
+func (def TaskDefinition[P]) NewTask(ctx context.Context, payload *P, opts ...asynq.Option) (*asynq.Task, error) {
+	jsonPayload, err := def.marshal(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := []asynq.Option{
+		asynq.Queue(def.Queue),
+		asynq.MaxRetry(def.MaxRetry),
+	}
+
+	return asynq.NewTask(def.Type, jsonPayload, append(defaults, opts...)...), nil
+}
+
+func (def TaskDefinition[P]) Enqueue(ctx context.Context, distributor TaskDistributor, payload *P, opts ...asynq.Option) error {
+	task, err := def.NewTask(ctx, payload, opts...)
+	if err != nil {
+		return err
+	}
+
+	return distributor.DistributeTask(ctx, task)
+}
+
+// OutboxMessage builds the outbox row for the payload, to be written in the same db transaction as the data it is about
+func (def TaskDefinition[P]) OutboxMessage(ctx context.Context, payload *P) (db.CreateOutboxMessageParams, error) {
+	jsonPayload, err := def.marshal(ctx, payload)
+	if err != nil {
+		return db.CreateOutboxMessageParams{}, err
+	}
+
+	return db.CreateOutboxMessageParams{
+		TaskType: def.Type,
+		Payload:  jsonPayload,
+		Queue:    def.Queue,
+		MaxRetry: int32(def.MaxRetry),
+	}, nil
+}
+
+// marshal encodes the payload with the trace context of ctx added as an extra field, which Decode ignores
+func (def TaskDefinition[P]) marshal(ctx context.Context, payload *P) ([]byte, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	carrier := tracing.Inject(ctx)
+	if carrier == nil {
+		return jsonPayload, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(jsonPayload, &fields); err != nil {
+		return nil, fmt.Errorf("task payload must be a json object: %w", err)
+	}
+
+	fields[traceContextField], err = json.Marshal(carrier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trace context: %w", err)
+	}
+
+	return json.Marshal(fields)
+}
+
+// Decode reads the payload back from a task of this type
+func (def TaskDefinition[P]) Decode(task *asynq.Task) (*P, error) {
+	if task.Type() != def.Type {
+		return nil, fmt.Errorf("unexpected task type: %s", task.Type())
+	}
+
+	var payload P
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return &payload, nil
+}
+
+// Handle registers a typed handler, payloads that cannot be decoded are not retried.
+// Each attempt gets a span in the trace of the request that enqueued the task.
+func (def TaskDefinition[P]) Handle(mux TaskMux, handler func(ctx context.Context, payload *P) error) {
+	mux.HandleFunc(def.Type, func(ctx context.Context, task *asynq.Task) error {
+		ctx, span := tracing.Tracer().Start(taskTraceContext(ctx, task), "task "+task.Type(),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(attribute.String("messaging.system", "asynq")),
+		)
+		defer span.End()
+
+		payload, err := def.Decode(task)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+
+		if err := handler(ctx, payload); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+
+		metrics.ObserveTask(task.Type(), metrics.TaskSuccess)
+
+		log.Info().
+			Str("type", task.Type()).
+			Bytes("payload", task.Payload()).
+			Msg("processed task")
+
+		return nil
+	})
+}
+
+// taskTraceContext returns ctx with the trace context the task was enqueued with, if it has one
+func taskTraceContext(ctx context.Context, task *asynq.Task) context.Context {
+	var fields struct {
+		TraceContext map[string]string `json:"trace_context"`
+	}
+
+	if err := json.Unmarshal(task.Payload(), &fields); err != nil || fields.TraceContext == nil {
+		return ctx
+	}
+
+	return tracing.Extract(ctx, fields.TraceContext)
+}
@@ -0,0 +1,180 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskDeliverWebhook = "task:deliver_webhook"
+
+// Headers of a webhook delivery, receivers check the signature with the secret of their endpoint
+const (
+	WebhookEventHeader     = "X-HouseBank-Event"
+	WebhookDeliveryHeader  = "X-HouseBank-Delivery"
+	WebhookSignatureHeader = "X-HouseBank-Signature"
+)
+
+const (
+	webhookTimeout       = 10 * time.Second
+	webhookMaxRetry      = 8
+	webhookRetryBase     = 30 * time.Second
+	webhookRetryMaxDelay = 6 * time.Hour
+	// only the start of the response body is kept in the delivery log
+	webhookMaxErrorBody = 256
+)
+
+type PayloadDeliverWebhook struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+var DeliverWebhook = NewTaskDefinition[PayloadDeliverWebhook](TaskDeliverWebhook, QueueueDefault, webhookMaxRetry)
+
+// WebhookMessage is the body posted to webhook endpoints, its id stays the same when the event is delivered again
+type WebhookMessage struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// SignWebhook signs the timestamp and the body with the secret of the endpoint, in the format of WebhookSignatureHeader.
+// The timestamp is signed so receivers can reject deliveries replayed long after they were sent.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+// webhookRetryDelay doubles the wait after every failed delivery attempt, up to webhookRetryMaxDelay
+func webhookRetryDelay(retried int) time.Duration {
+	if retried >= 20 {
+		return webhookRetryMaxDelay
+	}
+
+	return min(webhookRetryBase<<retried, webhookRetryMaxDelay)
+}
+
+func (processor *RedisTaskProcessor) ProcessDeliverWebhook(ctx context.Context, payload *PayloadDeliverWebhook) error {
+	delivery, err := processor.store.GetWebhookDelivery(ctx, payload.DeliveryID)
+	if err != nil {
+		// deleted along with its endpoint, or rolled back after it was dispatched
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no webhook delivery found with id %d: %w", payload.DeliveryID, asynq.SkipRetry)
+		}
+
+		return fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	if delivery.Status == db.WebhookDeliverySucceeded {
+		return nil
+	}
+
+	endpoint, err := processor.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	event, err := processor.store.GetWebhookEvent(ctx, delivery.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook event: %w", err)
+	}
+
+	responseStatus, sendErr := processor.sendWebhook(ctx, endpoint, event, delivery)
+
+	status := db.WebhookDeliverySucceeded
+	if sendErr != nil {
+		status = db.WebhookDeliveryPending
+
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried >= maxRetry {
+			status = db.WebhookDeliveryFailed
+		}
+	}
+
+	arg := db.RecordWebhookDeliveryAttemptParams{
+		ID:     delivery.ID,
+		Status: status,
+	}
+	if responseStatus != 0 {
+		arg.ResponseStatus = pgtype.Int4{Int32: int32(responseStatus), Valid: true}
+	}
+	if sendErr != nil {
+		arg.LastError = pgtype.Text{String: sendErr.Error(), Valid: true}
+	}
+
+	if _, err := processor.store.RecordWebhookDeliveryAttempt(ctx, arg); err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", sendErr)
+	}
+
+	log.Info().
+		Int64("delivery_id", delivery.ID).
+		Str("event_type", event.EventType).
+		Int("response_status", responseStatus).
+		Msg("delivered webhook")
+
+	return nil
+}
+
+// sendWebhook posts the event to the endpoint, only a 2xx response counts as delivered
+func (processor *RedisTaskProcessor) sendWebhook(ctx context.Context, endpoint db.WebhookEndpoint, event db.WebhookEvent, delivery db.WebhookDelivery) (responseStatus int, err error) {
+	body, err := json.Marshal(WebhookMessage{
+		ID:        event.ID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot marshal webhook message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("cannot create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "HouseBank-Webhooks")
+	req.Header.Set(WebhookEventHeader, event.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, time.Now(), body))
+
+	res, err := processor.webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(res.Body, webhookMaxErrorBody))
+		return res.StatusCode, fmt.Errorf("unexpected response status %d: %s", res.StatusCode, responseBody)
+	}
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskExpireMoneyRequests = "task:expire_money_requests"
+	TaskExpireMoneyRequest  = "task:expire_money_request"
+)
+
+const (
+	// requests past their expiry cannot be paid anymore, expiring them every minute tells both sides soon enough
+	moneyRequestExpireInterval  = time.Minute
+	moneyRequestExpireBatchSize = 100
+	moneyRequestExpireMaxRetry  = 3
+)
+
+type PayloadExpireMoneyRequests struct{}
+
+type PayloadExpireMoneyRequest struct {
+	MoneyRequestID int64 `json:"money_request_id"`
+}
+
+// ExpireMoneyRequests is the periodic task fanning the expired requests out to an ExpireMoneyRequest task each
+var ExpireMoneyRequests = NewTaskDefinition[PayloadExpireMoneyRequests](TaskExpireMoneyRequests, QueueueDefault, 0)
+
+var ExpireMoneyRequest = NewTaskDefinition[PayloadExpireMoneyRequest](TaskExpireMoneyRequest, QueueueDefault, moneyRequestExpireMaxRetry)
+
+// ProcessExpireMoneyRequests enqueues an ExpireMoneyRequest task for every pending request past its expiry.
+// A request expires once, so its task is enqueued once: one that ran out of retries stays archived, where it can be inspected,
+// and does not hold up the requests after it.
+func (processor *RedisTaskProcessor) ProcessExpireMoneyRequests(ctx context.Context, payload *PayloadExpireMoneyRequests) error {
+	var afterID int64
+	expired, enqueued := 0, 0
+
+	for {
+		ids, err := processor.store.ListExpiredMoneyRequests(ctx, db.ListExpiredMoneyRequestsParams{
+			AfterID:    afterID,
+			LimitCount: moneyRequestExpireBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list expired money requests: %w", err)
+		}
+
+		for _, id := range ids {
+			taskID := fmt.Sprintf("money_request:%d:expire", id)
+
+			err := ExpireMoneyRequest.Enqueue(ctx, processor.distributor, &PayloadExpireMoneyRequest{MoneyRequestID: id}, asynq.TaskID(taskID))
+			if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+				return fmt.Errorf("failed to enqueue money request expiry: %w", err)
+			}
+			if err == nil {
+				enqueued++
+			}
+		}
+
+		expired += len(ids)
+		if len(ids) < moneyRequestExpireBatchSize {
+			break
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	if expired > 0 {
+		log.Info().
+			Int("expired", expired).
+			Int("enqueued", enqueued).
+			Msg("money request expiries enqueued")
+	}
+
+	return nil
+}
+
+// ProcessExpireMoneyRequest expires the request unless it was paid or declined since it was listed
+func (processor *RedisTaskProcessor) ProcessExpireMoneyRequest(ctx context.Context, payload *PayloadExpireMoneyRequest) error {
+	result, err := processor.store.ExpireMoneyRequestTx(ctx, payload.MoneyRequestID)
+	if err != nil {
+		return fmt.Errorf("failed to expire money request: %w", err)
+	}
+
+	log.Info().
+		Int64("money_request_id", payload.MoneyRequestID).
+		Bool("expired", result.Expired).
+		Msg("money request handled")
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessExpireMoneyRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+
+	page := make([]int64, moneyRequestExpireBatchSize)
+	for i := range page {
+		page[i] = int64(i + 1)
+	}
+
+	// a full page is followed by the requests after its last one
+	store.EXPECT().
+		ListExpiredMoneyRequests(gomock.Any(), gomock.Eq(db.ListExpiredMoneyRequestsParams{AfterID: 0, LimitCount: moneyRequestExpireBatchSize})).
+		Times(1).
+		Return(page, nil)
+	store.EXPECT().
+		ListExpiredMoneyRequests(gomock.Any(), gomock.Eq(db.ListExpiredMoneyRequestsParams{AfterID: moneyRequestExpireBatchSize, LimitCount: moneyRequestExpireBatchSize})).
+		Times(1).
+		Return([]int64{moneyRequestExpireBatchSize + 1}, nil)
+
+	distributor := NewMemoryTaskDistributor()
+	processor := &RedisTaskProcessor{store: store, distributor: distributor}
+
+	err := processor.ProcessExpireMoneyRequests(context.Background(), &PayloadExpireMoneyRequests{})
+	require.NoError(t, err)
+	require.Len(t, distributor.Tasks(TaskExpireMoneyRequest), moneyRequestExpireBatchSize+1)
+}
+
+func TestProcessExpireMoneyRequestsListFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().ListExpiredMoneyRequests(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db is down"))
+
+	distributor := NewMemoryTaskDistributor()
+	processor := &RedisTaskProcessor{store: store, distributor: distributor}
+
+	err := processor.ProcessExpireMoneyRequests(context.Background(), &PayloadExpireMoneyRequests{})
+	require.Error(t, err)
+	require.Empty(t, distributor.Tasks(""))
+}
+
+func TestProcessExpireMoneyRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().ExpireMoneyRequestTx(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(db.ExpireMoneyRequestTxResult{Expired: true}, nil)
+	// a failed expiry is retried by asynq, then archived
+	store.EXPECT().ExpireMoneyRequestTx(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(db.ExpireMoneyRequestTxResult{}, errors.New("db is down"))
+
+	processor := &RedisTaskProcessor{store: store}
+	distributor := NewMemoryTaskDistributor()
+	processor.RegisterHandlers(distributor)
+
+	require.NoError(t, ExpireMoneyRequest.Enqueue(context.Background(), distributor, &PayloadExpireMoneyRequest{MoneyRequestID: 1}))
+	require.NoError(t, distributor.RunPending(context.Background()))
+
+	require.NoError(t, ExpireMoneyRequest.Enqueue(context.Background(), distributor, &PayloadExpireMoneyRequest{MoneyRequestID: 2}))
+	require.Error(t, distributor.RunPending(context.Background()))
+}
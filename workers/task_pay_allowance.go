@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskPayDueAllowances = "task:pay_due_allowances"
+	TaskPayAllowance     = "task:pay_allowance"
+)
+
+const (
+	// allowances are due at a given time, checking every minute pays them close enough to it
+	allowancePayInterval  = time.Minute
+	allowancePayBatchSize = 100
+	allowancePayMaxRetry  = 3
+)
+
+type PayloadPayDueAllowances struct{}
+
+type PayloadPayAllowance struct {
+	AllowanceID int64 `json:"allowance_id"`
+}
+
+// PayDueAllowances is the periodic task fanning the due allowances out to a PayAllowance task each
+var PayDueAllowances = NewTaskDefinition[PayloadPayDueAllowances](TaskPayDueAllowances, QueueueDefault, 0)
+
+var PayAllowance = NewTaskDefinition[PayloadPayAllowance](TaskPayAllowance, QueueueDefault, allowancePayMaxRetry)
+
+// ProcessPayDueAllowances enqueues a PayAllowance task for every due allowance.
+// The task of a payment is enqueued once: a payment that ran out of retries stays archived, where it can be inspected,
+// and is not tried again before the allowance is next due, so it does not hold up the allowances after it.
+func (processor *RedisTaskProcessor) ProcessPayDueAllowances(ctx context.Context, payload *PayloadPayDueAllowances) error {
+	var afterID int64
+	due, enqueued := 0, 0
+
+	for {
+		allowances, err := processor.store.ListDueAllowances(ctx, db.ListDueAllowancesParams{
+			AfterID:    afterID,
+			LimitCount: allowancePayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list due allowances: %w", err)
+		}
+
+		for _, allowance := range allowances {
+			taskID := fmt.Sprintf("allowance:%d:%d", allowance.ID, allowance.NextPaymentAt.Unix())
+
+			err := PayAllowance.Enqueue(ctx, processor.distributor, &PayloadPayAllowance{AllowanceID: allowance.ID}, asynq.TaskID(taskID))
+			if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+				return fmt.Errorf("failed to enqueue allowance payment: %w", err)
+			}
+			if err == nil {
+				enqueued++
+			}
+		}
+
+		due += len(allowances)
+		if len(allowances) < allowancePayBatchSize {
+			break
+		}
+		afterID = allowances[len(allowances)-1].ID
+	}
+
+	if due > 0 {
+		log.Info().
+			Int("due", due).
+			Int("enqueued", enqueued).
+			Msg("allowance payments enqueued")
+	}
+
+	return nil
+}
+
+// ProcessPayAllowance pays the allowance if it is still due, an allowance the paying account cannot cover is skipped
+func (processor *RedisTaskProcessor) ProcessPayAllowance(ctx context.Context, payload *PayloadPayAllowance) error {
+	result, err := processor.store.PayAllowanceTx(ctx, payload.AllowanceID)
+	if err != nil {
+		return fmt.Errorf("failed to pay allowance: %w", err)
+	}
+
+	log.Info().
+		Int64("allowance_id", payload.AllowanceID).
+		Bool("paid", result.Paid).
+		Msg("allowance handled")
+
+	return nil
+}
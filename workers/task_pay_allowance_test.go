@@ -0,0 +1,103 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// conflictingDistributor refuses the tasks with ids it already holds, as redis does
+type conflictingDistributor struct {
+	*MemoryTaskDistributor
+	conflicts map[int64]bool
+}
+
+func (distributor *conflictingDistributor) DistributeTask(ctx context.Context, task *asynq.Task) error {
+	payload, err := PayAllowance.Decode(task)
+	if err == nil && distributor.conflicts[payload.AllowanceID] {
+		return fmt.Errorf("failed to enqueue task: %w", asynq.ErrTaskIDConflict)
+	}
+
+	return distributor.MemoryTaskDistributor.DistributeTask(ctx, task)
+}
+
+func dueAllowances(fromID int64, n int) []db.ListDueAllowancesRow {
+	rows := make([]db.ListDueAllowancesRow, n)
+	for i := range rows {
+		rows[i] = db.ListDueAllowancesRow{ID: fromID + int64(i), NextPaymentAt: time.Now()}
+	}
+	return rows
+}
+
+func TestProcessPayDueAllowances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+
+	// a full page is followed by the allowances after its last one
+	store.EXPECT().
+		ListDueAllowances(gomock.Any(), gomock.Eq(db.ListDueAllowancesParams{AfterID: 0, LimitCount: allowancePayBatchSize})).
+		Times(1).
+		Return(dueAllowances(1, allowancePayBatchSize), nil)
+	store.EXPECT().
+		ListDueAllowances(gomock.Any(), gomock.Eq(db.ListDueAllowancesParams{AfterID: allowancePayBatchSize, LimitCount: allowancePayBatchSize})).
+		Times(1).
+		Return(dueAllowances(allowancePayBatchSize+1, 2), nil)
+
+	// the payment of allowance 1 is still queued, or was archived after running out of retries
+	distributor := &conflictingDistributor{MemoryTaskDistributor: NewMemoryTaskDistributor(), conflicts: map[int64]bool{1: true}}
+	processor := &RedisTaskProcessor{store: store, distributor: distributor}
+
+	err := processor.ProcessPayDueAllowances(context.Background(), &PayloadPayDueAllowances{})
+	require.NoError(t, err)
+	require.Len(t, distributor.Tasks(TaskPayAllowance), allowancePayBatchSize+1)
+}
+
+func TestProcessPayDueAllowancesEnqueueFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().ListDueAllowances(gomock.Any(), gomock.Any()).Times(1).Return(dueAllowances(1, 1), nil)
+
+	processor := &RedisTaskProcessor{store: store, distributor: &failingDistributor{err: errors.New("redis is down")}}
+
+	err := processor.ProcessPayDueAllowances(context.Background(), &PayloadPayDueAllowances{})
+	require.Error(t, err)
+}
+
+func TestProcessPayAllowance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().PayAllowanceTx(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(db.PayAllowanceTxResult{Paid: true}, nil)
+	// a failed payment is retried by asynq, then archived
+	store.EXPECT().PayAllowanceTx(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(db.PayAllowanceTxResult{}, errors.New("db is down"))
+
+	processor := &RedisTaskProcessor{store: store}
+	distributor := NewMemoryTaskDistributor()
+	processor.RegisterHandlers(distributor)
+
+	require.NoError(t, PayAllowance.Enqueue(context.Background(), distributor, &PayloadPayAllowance{AllowanceID: 1}))
+	require.NoError(t, distributor.RunPending(context.Background()))
+
+	require.NoError(t, PayAllowance.Enqueue(context.Background(), distributor, &PayloadPayAllowance{AllowanceID: 2}))
+	require.Error(t, distributor.RunPending(context.Background()))
+}
+
+func TestPeriodicTasks(t *testing.T) {
+	tasks, err := PeriodicTasks()
+	require.NoError(t, err)
+
+	types := make([]string, len(tasks))
+	for i, periodic := range tasks {
+		require.Positive(t, periodic.Interval)
+		types[i] = periodic.Task.Type()
+	}
+	require.Contains(t, types, TaskPayDueAllowances)
+	require.Contains(t, types, TaskExpireMoneyRequests)
+}
@@ -3,10 +3,11 @@ package workers
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/token"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
 )
@@ -17,46 +18,10 @@ type PayloadSendVerifyEmail struct {
 	Username string `json:"username"`
 }
 
-func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
-	ctx context.Context,
-	payload *PayloadSendVerifyEmail,
-	opts ...asynq.Option,
-) error {
-
-	jsonPayload, err := json.Marshal(payload)
-
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	task := asynq.NewTask(TaskSendVerifyEmail, jsonPayload, opts...)
-
-	info, err := distributor.client.EnqueueContext(ctx, task)
-	if err != nil {
-		return fmt.Errorf("failed to enqueue task: %w", err)
-	}
-
-	log.Info().
-		Str("type", task.Type()).
-		Bytes("payload", task.Payload()).
-		Str("queue", info.Queue).
-		Int("max_retry", info.MaxRetry).
-		Msg("task enqueued")
-
-	return nil
-
-}
-
-func (processor *RedisTaskProcessor) ProcessSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
-	if task.Type() != TaskSendVerifyEmail {
-		return fmt.Errorf("unexpected task type: %s", task.Type())
-	}
-
-	var payload PayloadSendVerifyEmail
-	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
-	}
+var SendVerifyEmail = NewTaskDefinition[PayloadSendVerifyEmail](TaskSendVerifyEmail, QueueueCritical, 10)
 
+// ProcessSendVerifyEmail sends a verification link to the current email of the user, using it is what verifies the email
+func (processor *RedisTaskProcessor) ProcessSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail) error {
 	user, err := processor.store.GetUserByUsername(ctx, payload.Username)
 
 	if err != nil {
@@ -71,13 +36,23 @@ func (processor *RedisTaskProcessor) ProcessSendVerifyEmail(ctx context.Context,
 		return fmt.Errorf("user %s already verified", payload.Username)
 	}
 
-	// TODO: send email to user
+	secretCode, hashedSecretCode, err := token.GenerateVerifyEmailCode()
+	if err != nil {
+		return err
+	}
+
+	verifyEmail, err := processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
+		Username:         user.Username,
+		Email:            user.Email,
+		HashedSecretCode: hashedSecretCode,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verify email: %w", err)
+	}
 
-	log.Info().
-		Str("type", task.Type()).
-		Bytes("payload", task.Payload()).
-		Str("email", user.Email).
-		Msg("processed task")
+	// TODO: send the email id and the secret code to the email, the code is not logged since it verifies the email
+	_ = secretCode
+	log.Info().Str("email", user.Email).Int64("verify_email_id", verifyEmail.ID).Msg("sending verification email")
 
 	return nil
 }
@@ -0,0 +1,107 @@
+package workers
+
+import (
+	"context"
+	"testing"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/tracing"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTaskDefinition(t *testing.T) {
+	type payloadTest struct {
+		Value string `json:"value"`
+	}
+	definition := NewTaskDefinition[payloadTest]("task:test", QueueueDefault, 3)
+
+	distributor := NewMemoryTaskDistributor()
+
+	value := util.RandomString(8)
+	err := definition.Enqueue(context.Background(), distributor, &payloadTest{Value: value})
+	require.NoError(t, err)
+
+	tasks := distributor.Tasks(definition.Type)
+	require.Len(t, tasks, 1)
+
+	payload, err := definition.Decode(tasks[0])
+	require.NoError(t, err)
+	require.Equal(t, value, payload.Value)
+
+	var handled []string
+	definition.Handle(distributor, func(ctx context.Context, payload *payloadTest) error {
+		handled = append(handled, payload.Value)
+		return nil
+	})
+
+	require.NoError(t, distributor.RunPending(context.Background()))
+	require.Equal(t, []string{value}, handled)
+	require.Empty(t, distributor.Tasks(""))
+
+	// malformed payloads are not retried
+	err = distributor.DistributeTask(context.Background(), asynq.NewTask(definition.Type, []byte("{")))
+	require.NoError(t, err)
+	err = distributor.RunPending(context.Background())
+	require.ErrorIs(t, err, asynq.SkipRetry)
+	require.ErrorContains(t, err, "unexpected end of JSON input")
+}
+
+func TestProcessSendVerifyEmail(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserByUsername(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	store.EXPECT().
+		CreateVerifyEmail(gomock.Any(), gomock.Cond(func(arg db.CreateVerifyEmailParams) bool {
+			return arg.Username == user.Username && arg.Email == user.Email && arg.HashedSecretCode != ""
+		})).
+		Times(1).
+		Return(db.VerifyEmail{ID: 1, Username: user.Username, Email: user.Email}, nil)
+
+	processor := &RedisTaskProcessor{store: store}
+	distributor := NewMemoryTaskDistributor()
+	processor.RegisterHandlers(distributor)
+
+	err := SendVerifyEmail.Enqueue(context.Background(), distributor, &PayloadSendVerifyEmail{Username: user.Username})
+	require.NoError(t, err)
+	require.Len(t, distributor.Tasks(TaskSendVerifyEmail), 1)
+
+	require.NoError(t, distributor.RunPending(context.Background()))
+}
+
+func TestTaskTraceContext(t *testing.T) {
+	_, err := tracing.Init(context.Background(), util.Config{})
+	require.NoError(t, err)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "request")
+	defer span.End()
+
+	distributor := NewMemoryTaskDistributor()
+	err = SendVerifyEmail.Enqueue(ctx, distributor, &PayloadSendVerifyEmail{Username: util.RandomOwner()})
+	require.NoError(t, err)
+
+	var taskSpan trace.SpanContext
+	SendVerifyEmail.Handle(distributor, func(ctx context.Context, payload *PayloadSendVerifyEmail) error {
+		taskSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+
+	require.NoError(t, distributor.RunPending(context.Background()))
+	require.Equal(t, span.SpanContext().TraceID(), taskSpan.TraceID())
+	require.NotEqual(t, span.SpanContext().SpanID(), taskSpan.SpanID())
+}
@@ -0,0 +1,97 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const webhookDispatchBatchSize = 100
+
+// WebhookDispatcher turns the webhook events written along with the data they are about into delivery tasks
+type WebhookDispatcher struct {
+	store       db.Store
+	distributor TaskDistributor
+	interval    time.Duration
+}
+
+func NewWebhookDispatcher(store db.Store, distributor TaskDistributor, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		store:       store,
+		distributor: distributor,
+		interval:    interval,
+	}
+}
+
+// Start polls the webhook events until ctx is cancelled
+func (dispatcher *WebhookDispatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// keep going through full batches so a backlog drains without waiting for the next tick
+		for {
+			result, err := dispatcher.DispatchPending(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("cannot dispatch webhook events")
+				break
+			}
+			if result.Events < webhookDispatchBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// DispatchPending dispatches one batch of pending webhook events
+func (dispatcher *WebhookDispatcher) DispatchPending(ctx context.Context) (db.DispatchWebhookEventsTxResult, error) {
+	result, err := dispatcher.store.DispatchWebhookEventsTx(ctx, db.DispatchWebhookEventsTxParams{
+		Limit: webhookDispatchBatchSize,
+		Dispatch: func(delivery db.WebhookDelivery) error {
+			return dispatcher.dispatch(ctx, delivery)
+		},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if result.Events > 0 {
+		log.Info().
+			Int("events", result.Events).
+			Int("deliveries", result.Deliveries).
+			Msg("webhook events dispatched")
+	}
+
+	return result, nil
+}
+
+func (dispatcher *WebhookDispatcher) dispatch(ctx context.Context, delivery db.WebhookDelivery) error {
+	task, err := DeliverWebhook.NewTask(ctx, &PayloadDeliverWebhook{DeliveryID: delivery.ID},
+		// the id lets the queue drop a delivery dispatched twice when committing the batch failed
+		asynq.TaskID(webhookDeliveryTaskID(delivery.ID)),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = dispatcher.distributor.DistributeTask(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+func webhookDeliveryTaskID(deliveryID int64) string {
+	return fmt.Sprintf("webhook_delivery:%d", deliveryID)
+}
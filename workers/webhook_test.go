@@ -0,0 +1,166 @@
+package workers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockDB "github.com/AnkitNayan83/houseBank/db/mock"
+	db "github.com/AnkitNayan83/houseBank/db/sqlc"
+	"github.com/AnkitNayan83/houseBank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSignWebhook(t *testing.T) {
+	secret := util.RandomString(32)
+	body := []byte(`{"id":1}`)
+	timestamp := time.Unix(1700000000, 0)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("1700000000." + string(body)))
+
+	require.Equal(t, "t=1700000000,v1="+hex.EncodeToString(mac.Sum(nil)), SignWebhook(secret, timestamp, body))
+	require.NotEqual(t, SignWebhook(secret, timestamp, body), SignWebhook(secret, timestamp.Add(time.Second), body))
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	require.Equal(t, webhookRetryBase, webhookRetryDelay(0))
+	require.Equal(t, 2*webhookRetryBase, webhookRetryDelay(1))
+	require.Equal(t, 8*webhookRetryBase, webhookRetryDelay(3))
+	require.Equal(t, webhookRetryMaxDelay, webhookRetryDelay(webhookMaxRetry+10))
+	require.Equal(t, webhookRetryMaxDelay, webhookRetryDelay(100))
+}
+
+func TestProcessDeliverWebhook(t *testing.T) {
+	endpoint := db.WebhookEndpoint{
+		ID:     util.RandomInt(1, 1000),
+		Owner:  util.RandomOwner(),
+		Secret: util.RandomString(32),
+	}
+	event := db.WebhookEvent{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     endpoint.Owner,
+		EventType: db.WebhookEventAccountCreated,
+		Payload:   []byte(`{"id":1}`),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	delivery := db.WebhookDelivery{
+		ID:         util.RandomInt(1, 1000),
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		Status:     db.WebhookDeliveryPending,
+	}
+
+	testCases := []struct {
+		name          string
+		responseCode  int
+		status        string
+		checkResponse func(t *testing.T, err error)
+	}{
+		{
+			name:         "Delivered",
+			responseCode: http.StatusNoContent,
+			status:       db.WebhookDeliverySucceeded,
+			checkResponse: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:         "EndpointError",
+			responseCode: http.StatusInternalServerError,
+			// without retry metadata in the context the attempt counts as the last one
+			status: db.WebhookDeliveryFailed,
+			checkResponse: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "unexpected response status 500")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				// the signature must cover the exact body that was received
+				timestamp := strings.TrimPrefix(strings.Split(r.Header.Get(WebhookSignatureHeader), ",")[0], "t=")
+				mac := hmac.New(sha256.New, []byte(endpoint.Secret))
+				mac.Write([]byte(timestamp + "." + string(body)))
+				require.True(t, strings.HasSuffix(r.Header.Get(WebhookSignatureHeader), ",v1="+hex.EncodeToString(mac.Sum(nil))))
+
+				require.Equal(t, event.EventType, r.Header.Get(WebhookEventHeader))
+
+				var message WebhookMessage
+				require.NoError(t, json.Unmarshal(body, &message))
+				require.Equal(t, event.ID, message.ID)
+				require.JSONEq(t, string(event.Payload), string(message.Data))
+
+				w.WriteHeader(tc.responseCode)
+			}))
+			defer receiver.Close()
+
+			endpoint := endpoint
+			endpoint.Url = receiver.URL
+
+			ctrl := gomock.NewController(t)
+			store := mockDB.NewMockStore(ctrl)
+			store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+			store.EXPECT().GetWebhookEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+			store.EXPECT().
+				RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Cond(func(arg db.RecordWebhookDeliveryAttemptParams) bool {
+					return arg.ID == delivery.ID && arg.Status == tc.status && arg.ResponseStatus.Int32 == int32(tc.responseCode)
+				})).
+				Times(1)
+
+			processor := &RedisTaskProcessor{store: store, webhookClient: receiver.Client()}
+
+			err := processor.ProcessDeliverWebhook(context.Background(), &PayloadDeliverWebhook{DeliveryID: delivery.ID})
+			tc.checkResponse(t, err)
+		})
+	}
+}
+
+func TestWebhookDispatcherDispatchPending(t *testing.T) {
+	deliveries := []db.WebhookDelivery{
+		{ID: util.RandomInt(1, 1000)},
+		{ID: util.RandomInt(1001, 2000)},
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockDB.NewMockStore(ctrl)
+	store.EXPECT().
+		DispatchWebhookEventsTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.DispatchWebhookEventsTxParams) (db.DispatchWebhookEventsTxResult, error) {
+			for _, delivery := range deliveries {
+				require.NoError(t, arg.Dispatch(delivery))
+			}
+			return db.DispatchWebhookEventsTxResult{Events: 1, Deliveries: len(deliveries)}, nil
+		})
+
+	distributor := NewMemoryTaskDistributor()
+	dispatcher := NewWebhookDispatcher(store, distributor, time.Second)
+
+	result, err := dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Events)
+
+	tasks := distributor.Tasks(TaskDeliverWebhook)
+	require.Len(t, tasks, len(deliveries))
+
+	for i, task := range tasks {
+		payload, err := DeliverWebhook.Decode(task)
+		require.NoError(t, err)
+		require.Equal(t, deliveries[i].ID, payload.DeliveryID)
+	}
+}